@@ -3,7 +3,10 @@ package client
 import (
 	"net/http"
 
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 )
 
@@ -16,3 +19,21 @@ func (c *WaspClient) ActivateChain(chainid coretypes.ChainID) error {
 func (c *WaspClient) DeactivateChain(chainid coretypes.ChainID) error {
 	return c.do(http.MethodPost, routes.DeactivateChain(chainid.String()), nil, nil)
 }
+
+// UpdateChainCommittee sends a request to move the chain to another committee in the wasp node
+// accessNodes == nil keeps the access nodes which are already known by the node
+func (c *WaspClient) UpdateChainCommittee(chainid coretypes.ChainID, color balance.Color, addr address.Address, committeeNodes, accessNodes []string) error {
+	return c.do(http.MethodPost, routes.UpdateChainCommittee(chainid.String()), &model.ChainCommittee{
+		Color:          model.NewColor(&color),
+		Address:        model.NewAddress(&addr),
+		CommitteeNodes: committeeNodes,
+		AccessNodes:    accessNodes,
+	}, nil)
+}
+
+// SetChainAccessNodes sends a request to set the access nodes of a chain in the wasp node
+func (c *WaspClient) SetChainAccessNodes(chainid coretypes.ChainID, accessNodes []string) error {
+	return c.do(http.MethodPost, routes.SetChainAccessNodes(chainid.String()), &model.ChainAccessNodes{
+		AccessNodes: accessNodes,
+	}, nil)
+}
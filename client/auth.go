@@ -0,0 +1,84 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/util/auth"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/mr-tron/base58"
+)
+
+// Login logs in with username and password. On success, the client sends the issued token with
+// subsequent requests
+func (c *WaspClient) Login(username, password string) (*model.LoginResponse, error) {
+	res := &model.LoginResponse{}
+	if err := c.do(http.MethodPost, routes.Login(), &model.LoginRequest{Username: username, Password: password}, res); err != nil {
+		return nil, err
+	}
+	c.token = res.Token
+	return res, nil
+}
+
+// LoginWithKeyPair logs in by signing a challenge with the key pair, which must be authorized
+// in the node. On success, the client sends the issued token with subsequent requests
+func (c *WaspClient) LoginWithKeyPair(keyPair *ed25519.KeyPair) (*model.LoginResponse, error) {
+	challenge := &model.LoginChallengeResponse{}
+	if err := c.do(http.MethodGet, routes.LoginChallenge(), nil, challenge); err != nil {
+		return nil, err
+	}
+	req := &model.KeyLoginRequest{
+		PubKey:    keyPair.PublicKey.String(),
+		Challenge: challenge.Challenge,
+		Signature: base58.Encode(keyPair.PrivateKey.Sign(auth.ChallengeMessage(challenge.Challenge)).Bytes()),
+	}
+	res := &model.LoginResponse{}
+	if err := c.do(http.MethodPost, routes.LoginWithKey(), req, res); err != nil {
+		return nil, err
+	}
+	c.token = res.Token
+	return res, nil
+}
+
+// NewAPIKey creates an API key with the role. The key is only returned once
+func (c *WaspClient) NewAPIKey(name string, role auth.Role) (*model.APIKeyInfo, error) {
+	res := &model.APIKeyInfo{}
+	if err := c.do(http.MethodPost, routes.PostAPIKey(), &model.APIKeyRequest{Name: name, Role: role.String()}, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetAPIKeys fetches the list of API keys (without the keys themselves)
+func (c *WaspClient) GetAPIKeys() ([]*model.APIKeyInfo, error) {
+	var res []*model.APIKeyInfo
+	if err := c.do(http.MethodGet, routes.ListAPIKeys(), nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RevokeAPIKey revokes the API key with the hash (base58)
+func (c *WaspClient) RevokeAPIKey(hash string) error {
+	return c.do(http.MethodDelete, routes.RevokeAPIKey(hash), nil, nil)
+}
+
+// AuthorizeKey allows the public key to log in with the role
+func (c *WaspClient) AuthorizeKey(pubKey ed25519.PublicKey, role auth.Role) error {
+	return c.do(http.MethodPut, routes.PutAuthorizedKey(), &model.AuthorizedKey{PubKey: pubKey.String(), Role: role.String()}, nil)
+}
+
+// GetAuthorizedKeys fetches the list of public keys authorized to log in
+func (c *WaspClient) GetAuthorizedKeys() ([]*model.AuthorizedKey, error) {
+	var res []*model.AuthorizedKey
+	if err := c.do(http.MethodGet, routes.ListAuthorizedKeys(), nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RevokeAuthorizedKey removes the authorization of the public key
+func (c *WaspClient) RevokeAuthorizedKey(pubKey ed25519.PublicKey) error {
+	return c.do(http.MethodDelete, routes.RevokeAuthorizedKey(pubKey.String()), nil, nil)
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// ListBlocks fetches the summary of at most count blocks of the chain, from the block with index from backwards.
+// If from is nil the list starts with the latest block. If count is 0, the default number of blocks is returned
+func (c *WaspClient) ListBlocks(chainID *coretypes.ChainID, from *uint32, count uint32) (*model.BlockListResponse, error) {
+	route := routes.ListBlocks(chainID.String())
+	sep := "?"
+	if from != nil {
+		route += fmt.Sprintf("%sfrom=%d", sep, *from)
+		sep = "&"
+	}
+	if count > 0 {
+		route += fmt.Sprintf("%scount=%d", sep, count)
+	}
+	res := &model.BlockListResponse{}
+	if err := c.do(http.MethodGet, route, nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetBlock fetches the block of the chain together with state updates made by each request
+func (c *WaspClient) GetBlock(chainID *coretypes.ChainID, blockIndex uint32) (*model.BlockResponse, error) {
+	res := &model.BlockResponse{}
+	if err := c.do(http.MethodGet, routes.GetBlock(chainID.String(), fmt.Sprintf("%d", blockIndex)), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
@@ -10,8 +11,18 @@ import (
 
 // CallView sends a request to call a view function of a given contract, and returns the result of the call
 func (c *WaspClient) CallView(contractID coretypes.ContractID, fname string, arguments dict.Dict) (dict.Dict, error) {
+	return c.callView(routes.CallView(contractID.Base58(), fname), arguments)
+}
+
+// CallViewAtBlock calls a view function of a given contract on the state of the chain
+// as it was right after the block with the index was committed
+func (c *WaspClient) CallViewAtBlock(contractID coretypes.ContractID, fname string, arguments dict.Dict, blockIndex uint32) (dict.Dict, error) {
+	return c.callView(fmt.Sprintf("%s?atBlock=%d", routes.CallView(contractID.Base58(), fname), blockIndex), arguments)
+}
+
+func (c *WaspClient) callView(route string, arguments dict.Dict) (dict.Dict, error) {
 	var res dict.Dict
-	if err := c.do(http.MethodGet, routes.CallView(contractID.Base58(), fname), arguments, &res); err != nil {
+	if err := c.do(http.MethodGet, route, arguments, &res); err != nil {
 		return nil, err
 	}
 	return res, nil
@@ -0,0 +1,14 @@
+package chainclient
+
+import "github.com/iotaledger/wasp/packages/webapi/model"
+
+// ListBlocks fetches the summary of at most count blocks of the chain, from the block with index from backwards.
+// If from is nil the list starts with the latest block
+func (c *Client) ListBlocks(from *uint32, count uint32) (*model.BlockListResponse, error) {
+	return c.WaspClient.ListBlocks(&c.ChainID, from, count)
+}
+
+// GetBlock fetches the block of the chain together with state updates made by each request
+func (c *Client) GetBlock(blockIndex uint32) (*model.BlockResponse, error) {
+	return c.WaspClient.GetBlock(&c.ChainID, blockIndex)
+}
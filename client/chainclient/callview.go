@@ -9,3 +9,8 @@ import (
 func (c *Client) CallView(contractHname coretypes.Hname, fname string, arguments dict.Dict) (dict.Dict, error) {
 	return c.WaspClient.CallView(coretypes.NewContractID(c.ChainID, contractHname), fname, arguments)
 }
+
+// CallViewAtBlock calls a view function of a given contract on the historical state of the chain at the block index
+func (c *Client) CallViewAtBlock(contractHname coretypes.Hname, fname string, arguments dict.Dict, blockIndex uint32) (dict.Dict, error) {
+	return c.WaspClient.CallViewAtBlock(coretypes.NewContractID(c.ChainID, contractHname), fname, arguments, blockIndex)
+}
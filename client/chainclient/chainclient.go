@@ -4,11 +4,13 @@ import (
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
 
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
 	"github.com/iotaledger/wasp/client"
 	"github.com/iotaledger/wasp/client/level1"
 	"github.com/iotaledger/wasp/packages/apilib"
 	"github.com/iotaledger/wasp/packages/sctransaction"
+	"go.dedis.ch/kyber/v3"
 )
 
 // Client allows to send webapi requests to a specific chain in the node
@@ -17,6 +19,9 @@ type Client struct {
 	WaspClient   *client.WaspClient
 	ChainID      coretypes.ChainID
 	SigScheme    signaturescheme.SignatureScheme
+	// ChainAddress is the address of the committee if the chain was moved to another committee.
+	// nil means the address of the chain ID
+	ChainAddress *address.Address
 }
 
 // New creates a new chainclient.Client
@@ -35,8 +40,11 @@ func New(
 }
 
 type PostRequestParams struct {
-	Transfer coretypes.ColoredBalances
-	Args     requestargs.RequestArgs
+	Transfer  coretypes.ColoredBalances
+	Args      requestargs.RequestArgs
+	GasBudget uint64
+	// EncryptTo, if not nil, are the keys of the committee the Args are encrypted to (see EncryptionKeys)
+	EncryptTo *requestargs.Recipients
 }
 
 // PostRequest sends a request transaction to the chain
@@ -44,6 +52,25 @@ func (c *Client) PostRequest(
 	contractHname coretypes.Hname,
 	entryPoint coretypes.Hname,
 	params ...PostRequestParams,
+) (*sctransaction.Transaction, error) {
+	return c.createRequest(contractHname, entryPoint, false, params...)
+}
+
+// CreateUnsignedRequest builds the request transaction of PostRequest without signing nor posting it,
+// so it can be signed offline by the owner of the sender address. Only the address of SigScheme is used
+func (c *Client) CreateUnsignedRequest(
+	contractHname coretypes.Hname,
+	entryPoint coretypes.Hname,
+	params ...PostRequestParams,
+) (*sctransaction.Transaction, error) {
+	return c.createRequest(contractHname, entryPoint, true, params...)
+}
+
+func (c *Client) createRequest(
+	contractHname coretypes.Hname,
+	entryPoint coretypes.Hname,
+	unsigned bool,
+	params ...PostRequestParams,
 ) (*sctransaction.Transaction, error) {
 	par := PostRequestParams{}
 	if len(params) > 0 {
@@ -56,9 +83,19 @@ func (c *Client) PostRequest(
 		RequestSectionParams: []apilib.RequestSectionParams{{
 			TargetContractID: coretypes.NewContractID(c.ChainID, contractHname),
 			EntryPointCode:   entryPoint,
+			GasBudget:        par.GasBudget,
 			Transfer:         par.Transfer,
 			Args:             par.Args,
+			EncryptTo:        par.EncryptTo,
+			ChainAddress:     c.ChainAddress,
 		}},
-		Post: true,
+		Post:     !unsigned,
+		Unsigned: unsigned,
 	})
 }
+
+// EncryptionKeys fetches the keys of the committee, to be used as PostRequestParams.EncryptTo
+// in order to post requests with confidential arguments
+func (c *Client) EncryptionKeys(group kyber.Group) (*requestargs.Recipients, error) {
+	return c.WaspClient.GetEncryptionKeys(&c.ChainID, group)
+}
@@ -0,0 +1,12 @@
+package chainclient
+
+import (
+	"github.com/iotaledger/wasp/client"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+)
+
+// SubscribeEvents streams the events of the chain which pass the filter into the events channel,
+// until done is closed
+func (c *Client) SubscribeEvents(filter *client.EventFilter, events chan<- *model.ChainEvent, done <-chan bool) error {
+	return c.WaspClient.SubscribeChainEvents(&c.ChainID, filter, events, done)
+}
@@ -0,0 +1,16 @@
+package chainclient
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/vm/core/receipts"
+)
+
+// RequestReceipt fetches the receipt of the processed request: the error, the result,
+// fees charged and events emitted. Use it to get the outcome of the asynchronous PostRequest
+func (c *Client) RequestReceipt(reqID *coretypes.RequestID) (*receipts.RequestReceipt, error) {
+	res, err := c.WaspClient.RequestReceipt(&c.ChainID, reqID)
+	if err != nil {
+		return nil, err
+	}
+	return res.RequestReceipt()
+}
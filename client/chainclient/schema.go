@@ -0,0 +1,24 @@
+package chainclient
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+)
+
+// GetContractSchema fetches the schema published on-chain for the contract.
+// Returns nil if the contract has no schema
+func (c *Client) GetContractSchema(contractHname coretypes.Hname) (*schema.Schema, error) {
+	ret, err := c.CallView(root.Interface.Hname(), root.FuncGetContractSchema, codec.MakeDict(map[string]interface{}{
+		root.ParamHname: contractHname,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	data := ret.MustGet(root.ParamData)
+	if data == nil {
+		return nil, nil
+	}
+	return schema.Parse(data)
+}
@@ -0,0 +1,22 @@
+package chainclient
+
+import (
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/state/merkle"
+)
+
+// StateProof fetches the Merkle proof of inclusion of the state key and the ID of the anchor transaction
+// of the block. The proof can be checked with merkle.Proof.VerifyWithAnchor against the anchor transaction.
+// The optional blockIndex specifies the block, otherwise the latest block is used
+func (c *Client) StateProof(key kv.Key, blockIndex ...uint32) (*merkle.Proof, valuetransaction.ID, error) {
+	res, err := c.WaspClient.StateProof(&c.ChainID, key, blockIndex...)
+	if err != nil {
+		return nil, valuetransaction.ID{}, err
+	}
+	proof, err := merkle.ProofFromBytes(res.Proof.Bytes())
+	if err != nil {
+		return nil, valuetransaction.ID{}, err
+	}
+	return proof, res.StateTxID.ID(), nil
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/iotaledger/wasp/packages/util/auth"
 	"github.com/iotaledger/wasp/packages/webapi/model"
 )
 
@@ -16,6 +17,8 @@ import (
 type WaspClient struct {
 	httpClient http.Client
 	baseURL    string
+	token      string
+	apiKey     string
 }
 
 // NewWaspClient returns a new *WaspClient with the given baseURL and httpClient.
@@ -29,6 +32,32 @@ func NewWaspClient(baseURL string, httpClient ...http.Client) *WaspClient {
 	return &WaspClient{baseURL: baseURL}
 }
 
+// WithToken sets the token sent with each request, as returned by Login
+func (c *WaspClient) WithToken(token string) *WaspClient {
+	c.token = token
+	return c
+}
+
+// WithAPIKey sets the API key sent with each request
+func (c *WaspClient) WithAPIKey(apiKey string) *WaspClient {
+	c.apiKey = apiKey
+	return c
+}
+
+// Token returns the token sent with each request, if any
+func (c *WaspClient) Token() string {
+	return c.token
+}
+
+func (c *WaspClient) setAuthHeader(h http.Header) {
+	if c.token != "" {
+		h.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.apiKey != "" {
+		h.Set(auth.HeaderAPIKey, c.apiKey)
+	}
+}
+
 func processResponse(res *http.Response, decodeTo interface{}) error {
 	resBody, err := ioutil.ReadAll(res.Body)
 	if err != nil {
@@ -78,6 +107,7 @@ func (c *WaspClient) do(method string, route string, reqObj interface{}, resObj
 	if data != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	c.setAuthHeader(req.Header)
 
 	// make the request
 	res, err := c.httpClient.Do(req)
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"encoding/base64"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"go.dedis.ch/kyber/v3"
+)
+
+// GetEncryptionKeys fetches the public key shares of the committee of the chain, which request
+// arguments can be encrypted to. group must be the group of the DKG suite of the node
+func (c *WaspClient) GetEncryptionKeys(chainID *coretypes.ChainID, group kyber.Group) (*requestargs.Recipients, error) {
+	res := &model.EncryptionKeysResponse{}
+	if err := c.do(http.MethodGet, routes.EncryptionKeys(chainID.String()), nil, res); err != nil {
+		return nil, err
+	}
+	ret := &requestargs.Recipients{Group: group, Keys: make([]kyber.Point, len(res.Keys))}
+	for i, s := range res.Keys {
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		ret.Keys[i] = group.Point()
+		if err := ret.Keys[i].UnmarshalBinary(b); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"golang.org/x/net/websocket"
+)
+
+// EventFilter selects the chain events streamed by the node. Zero value selects all events
+type EventFilter struct {
+	// Contract selects only events emitted by the contract, if not nil
+	Contract *coretypes.Hname
+	// Types selects only events of the types, e.g. "state" or "vmmsg", if not empty
+	Types []string
+}
+
+func (f *EventFilter) query() string {
+	q := url.Values{}
+	if f != nil && f.Contract != nil {
+		q.Set("contract", f.Contract.String())
+	}
+	if f != nil && len(f.Types) > 0 {
+		q.Set("type", strings.Join(f.Types, ","))
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
+
+// SubscribeChainEvents opens a WebSocket connection to the node and streams the events of the
+// chain which pass the filter into the events channel, until done is closed.
+// The events channel is closed when the connection is closed
+func (c *WaspClient) SubscribeChainEvents(chainID *coretypes.ChainID, filter *EventFilter, events chan<- *model.ChainEvent, done <-chan bool) error {
+	origin := strings.TrimRight(c.baseURL, "/")
+	wsURL := origin + routes.ChainEvents(chainID.String()) + filter.query()
+	switch {
+	case strings.HasPrefix(wsURL, "https://"):
+		wsURL = "wss://" + strings.TrimPrefix(wsURL, "https://")
+	case strings.HasPrefix(wsURL, "http://"):
+		wsURL = "ws://" + strings.TrimPrefix(wsURL, "http://")
+	}
+	config, err := websocket.NewConfig(wsURL, origin)
+	if err != nil {
+		return err
+	}
+	c.setAuthHeader(config.Header)
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		return fmt.Errorf("can't open websocket %s: %v", wsURL, err)
+	}
+
+	go func() {
+		defer close(events)
+		for {
+			ev := &model.ChainEvent{}
+			if err := websocket.JSON.Receive(ws, ev); err != nil {
+				return
+			}
+			events <- ev
+		}
+	}()
+
+	go func() {
+		<-done
+		ws.Close()
+	}()
+
+	return nil
+}
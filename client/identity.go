@@ -0,0 +1,32 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// GetNodeIdentity fetches the peering identity and the certificate of the node
+func (c *WaspClient) GetNodeIdentity() (*model.NodeIdentity, error) {
+	res := &model.NodeIdentity{}
+	if err := c.do(http.MethodGet, routes.GetNodeIdentity(), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// PutNodeCertificate sets the certificate of the node, issued by the node owner
+func (c *WaspClient) PutNodeCertificate(cert *peering.NodeCertificate) error {
+	return c.do(http.MethodPut, routes.PutNodeCertificate(), model.NewNodeCertificate(cert), nil)
+}
+
+// GetTrustedPeers fetches the list of peers which presented a valid certificate to the node
+func (c *WaspClient) GetTrustedPeers() ([]*model.TrustedPeer, error) {
+	var res []*model.TrustedPeer
+	if err := c.do(http.MethodGet, routes.ListTrustedPeers(), nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
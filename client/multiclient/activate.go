@@ -1,6 +1,8 @@
 package multiclient
 
 import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/client"
 	"github.com/iotaledger/wasp/packages/coretypes"
 )
@@ -18,3 +20,17 @@ func (m *MultiClient) DeactivateChain(chainid coretypes.ChainID) error {
 		return w.DeactivateChain(chainid)
 	})
 }
+
+// UpdateChainCommittee sends a request to move the chain to another committee in all wasp nodes
+func (m *MultiClient) UpdateChainCommittee(chainid coretypes.ChainID, color balance.Color, addr address.Address, committeeNodes, accessNodes []string) error {
+	return m.Do(func(i int, w *client.WaspClient) error {
+		return w.UpdateChainCommittee(chainid, color, addr, committeeNodes, accessNodes)
+	})
+}
+
+// SetChainAccessNodes sends a request to set the access nodes of a chain in all wasp nodes
+func (m *MultiClient) SetChainAccessNodes(chainid coretypes.ChainID, accessNodes []string) error {
+	return m.Do(func(i int, w *client.WaspClient) error {
+		return w.SetChainAccessNodes(chainid, accessNodes)
+	})
+}
@@ -31,6 +31,22 @@ func New(hosts []string, httpClient ...func() http.Client) *MultiClient {
 	return m
 }
 
+// WithToken sets the token sent with each request to all nodes
+func (m *MultiClient) WithToken(token string) *MultiClient {
+	for _, node := range m.nodes {
+		node.WithToken(token)
+	}
+	return m
+}
+
+// WithAPIKey sets the API key sent with each request to all nodes
+func (m *MultiClient) WithAPIKey(apiKey string) *MultiClient {
+	for _, node := range m.nodes {
+		node.WithAPIKey(apiKey)
+	}
+	return m
+}
+
 func (m *MultiClient) Len() int {
 	return len(m.nodes)
 }
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// PostRequest sends a signed request transaction to the wasp node, which forwards it to the ledger.
+// Any node running the chain accepts it, including access nodes
+func (c *WaspClient) PostRequest(chainId *coretypes.ChainID, tx *sctransaction.Transaction) ([]coretypes.RequestID, error) {
+	res := &model.PostRequestResponse{}
+	if err := c.do(http.MethodPost, routes.PostRequest(chainId.String()), &model.PostRequestTx{
+		Tx: model.NewBytes(tx.Bytes()),
+	}, res); err != nil {
+		return nil, err
+	}
+	ret := make([]coretypes.RequestID, len(res.RequestIDs))
+	for i := range res.RequestIDs {
+		ret[i] = res.RequestIDs[i].RequestID()
+	}
+	return ret, nil
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// GetPruningPolicy fetches the effective pruning policy of the chain
+func (c *WaspClient) GetPruningPolicy(chainID coretypes.ChainID) (*model.PruningPolicy, error) {
+	res := &model.PruningPolicy{}
+	if err := c.do(http.MethodGet, routes.PruningPolicy(chainID.String()), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetPruningPolicy sets the pruning policy of the chain
+func (c *WaspClient) SetPruningPolicy(chainID coretypes.ChainID, policy *model.PruningPolicy) error {
+	return c.do(http.MethodPost, routes.PruningPolicy(chainID.String()), policy, nil)
+}
+
+// PruneChain prunes the chain according to its pruning policy. In the dry run mode nothing is removed
+func (c *WaspClient) PruneChain(chainID coretypes.ChainID, dryRun bool) (*model.PruningResult, error) {
+	res := &model.PruningResult{}
+	if err := c.do(http.MethodPost, routes.PruneChain(chainID.String()), &model.PruneParams{DryRun: dryRun}, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
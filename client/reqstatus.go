@@ -47,3 +47,12 @@ func (c *WaspClient) WaitUntilAllRequestsProcessed(tx *sctransaction.Transaction
 	}
 	return nil
 }
+
+// RequestReceipt fetches the receipt of the processed request
+func (c *WaspClient) RequestReceipt(chainId *coretypes.ChainID, reqId *coretypes.RequestID) (*model.RequestReceipt, error) {
+	res := &model.RequestReceipt{}
+	if err := c.do(http.MethodGet, routes.RequestReceipt(chainId.String(), reqId.Base58()), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -5,3 +5,7 @@ import "github.com/iotaledger/wasp/packages/kv/dict"
 func (c *SCClient) CallView(fname string, args dict.Dict) (dict.Dict, error) {
 	return c.ChainClient.CallView(c.ContractHname, fname, args)
 }
+
+func (c *SCClient) CallViewAtBlock(fname string, args dict.Dict, blockIndex uint32) (dict.Dict, error) {
+	return c.ChainClient.CallViewAtBlock(c.ContractHname, fname, args, blockIndex)
+}
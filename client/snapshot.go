@@ -0,0 +1,27 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// ExportSnapshot fetches the snapshot of the solid state of the chain
+func (c *WaspClient) ExportSnapshot(chainID coretypes.ChainID) ([]byte, error) {
+	res := &model.SnapshotData{}
+	if err := c.do(http.MethodGet, routes.ExportSnapshot(chainID.String()), nil, res); err != nil {
+		return nil, err
+	}
+	return res.Data.Bytes(), nil
+}
+
+// ImportSnapshot sends the snapshot of a chain to the node
+func (c *WaspClient) ImportSnapshot(data []byte) (*model.SnapshotInfo, error) {
+	res := &model.SnapshotInfo{}
+	if err := c.do(http.MethodPost, routes.ImportSnapshot(), model.NewSnapshotData(data), res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
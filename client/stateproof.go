@@ -0,0 +1,26 @@
+package client
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// StateProof fetches the Merkle proof of inclusion of the state key into the state of the chain.
+// The optional blockIndex specifies the block, otherwise the latest block is used
+func (c *WaspClient) StateProof(chainID *coretypes.ChainID, key kv.Key, blockIndex ...uint32) (*model.StateProofResponse, error) {
+	route := routes.StateProof(chainID.String(), hex.EncodeToString([]byte(key)))
+	if len(blockIndex) > 0 {
+		route += fmt.Sprintf("?atBlock=%d", blockIndex[0])
+	}
+	res := &model.StateProofResponse{}
+	if err := c.do(http.MethodGet, route, nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
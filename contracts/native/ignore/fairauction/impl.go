@@ -343,7 +343,7 @@ func startAuction(ctx coretypes.Sandbox) error {
 	ctx.PostRequest(coretypes.PostRequestParams{
 		TargetContractID: ctx.ContractID(),
 		EntryPoint:       RequestFinalizeAuction,
-		TimeLock:         uint32(duration * 60),
+		TimeLock:         duration * 60,
 		Params:           args,
 	})
 	//logToSC(ctx, fmt.Sprintf("start auction. For sale %d tokens of color %s. Minimum bid: %di. Duration %d minutes",
@@ -92,7 +92,7 @@ func revokeWarrant(ctx coretypes.Sandbox) (dict.Dict, error) {
 	succ := ctx.PostRequest(coretypes.PostRequestParams{
 		TargetContractID: ctx.ContractID(),
 		EntryPoint:       coretypes.Hn(FuncCloseWarrant),
-		TimeLock:         uint32(revokeDeadline.Unix()),
+		TimeLock:         revokeDeadline.Unix(),
 		Params: codec.MakeDict(map[string]interface{}{
 			ParamPayerAddress:   payerAddr,
 			ParamServiceAddress: serviceAddr,
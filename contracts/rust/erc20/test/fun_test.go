@@ -25,7 +25,7 @@ func deployErc20(t *testing.T) *solo.Chain {
 	)
 	require.NoError(t, err)
 	_, rec := chain.GetInfo()
-	require.EqualValues(t, 5, len(rec))
+	require.EqualValues(t, 8, len(rec))
 
 	res, err := chain.CallView(ScName, ViewTotalSupply)
 	require.NoError(t, err)
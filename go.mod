@@ -4,14 +4,18 @@ go 1.15
 
 require (
 	github.com/bytecodealliance/wasmtime-go v0.21.0
+	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/iotaledger/goshimmer v0.3.7-0.20210214081859-29e3f77b4364
 	github.com/iotaledger/hive.go v0.0.0-20210209113323-87572778f0d9
 	github.com/knadh/koanf v0.14.0
 	github.com/labstack/echo/v4 v4.1.13
+	github.com/mochi-co/mqtt v1.0.1
 	github.com/mr-tron/base58 v1.2.0
 	github.com/pangpanglabs/echoswagger/v2 v2.1.0
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.7.0
 	github.com/prometheus/common v0.10.0
+	github.com/spf13/cobra v1.1.1
 	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.7.0
 	github.com/stretchr/testify v1.6.1
@@ -15,8 +15,11 @@ import (
 	"github.com/iotaledger/wasp/plugins/globals"
 	"github.com/iotaledger/wasp/plugins/gracefulshutdown"
 	"github.com/iotaledger/wasp/plugins/logger"
+	"github.com/iotaledger/wasp/plugins/metrics"
+	"github.com/iotaledger/wasp/plugins/mqtt"
 	"github.com/iotaledger/wasp/plugins/nodeconn"
 	"github.com/iotaledger/wasp/plugins/peering"
+	"github.com/iotaledger/wasp/plugins/pruning"
 	"github.com/iotaledger/wasp/plugins/publisher"
 	"github.com/iotaledger/wasp/plugins/registry"
 	"github.com/iotaledger/wasp/plugins/testplugins/nodeping"
@@ -46,7 +49,10 @@ func main() {
 		dispatcher.Init(),
 		chains.Init(),
 		publisher.Init(),
+		mqtt.Init(),
+		metrics.Init(),
 		dashboard.Init(),
+		pruning.Init(),
 		wasmtimevm.Init(),
 		globals.Init(),
 	)
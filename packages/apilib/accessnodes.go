@@ -0,0 +1,83 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package apilib
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/iotaledger/wasp/client"
+	"github.com/iotaledger/wasp/client/multiclient"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+type AddAccessNodesParams struct {
+	ChainID                coretypes.ChainID
+	CommitteeApiHosts      []string
+	AccessNodeApiHosts     []string
+	AccessNodePeeringHosts []string
+	Textout                io.Writer
+	Prefix                 string
+}
+
+// AddAccessNodes makes the nodes follow the chain as access nodes:
+// - adds the nodes to the access nodes of the chain in the committee nodes
+// - creates and activates the chain record in the new access nodes
+// Access nodes sync the state from the committee and serve it read-only, without taking part in the consensus
+func AddAccessNodes(par AddAccessNodesParams) error {
+	textout := ioutil.Discard
+	if par.Textout != nil {
+		textout = par.Textout
+	}
+
+	chainRecord, err := client.NewWaspClient(par.CommitteeApiHosts[0]).GetChainRecord(par.ChainID)
+	fmt.Fprint(textout, par.Prefix)
+	if err != nil {
+		fmt.Fprintf(textout, "loading chain record.. FAILED: %v\n", err)
+		return err
+	}
+	fmt.Fprint(textout, "loading chain record.. OK.\n")
+
+	accessNodes := append([]string{}, chainRecord.AccessNodes...)
+	for _, netID := range par.AccessNodePeeringHosts {
+		if util.StringInList(netID, chainRecord.CommitteeNodes) {
+			return fmt.Errorf("committee node %s can't be an access node", netID)
+		}
+		if !util.StringInList(netID, accessNodes) {
+			accessNodes = append(accessNodes, netID)
+		}
+	}
+
+	// ------------ committee nodes must accept messages from the access nodes
+	err = multiclient.New(par.CommitteeApiHosts).SetChainAccessNodes(par.ChainID, accessNodes)
+	fmt.Fprint(textout, par.Prefix)
+	if err != nil {
+		fmt.Fprintf(textout, "setting access nodes in the committee.. FAILED: %v\n", err)
+		return err
+	}
+	fmt.Fprint(textout, "setting access nodes in the committee.. OK.\n")
+
+	// ------------ put chain records to access nodes and activate
+	chainRecord.AccessNodes = accessNodes
+	chainRecord.Active = false
+	access := multiclient.New(par.AccessNodeApiHosts)
+	err = access.PutChainRecord(chainRecord)
+	fmt.Fprint(textout, par.Prefix)
+	if err != nil {
+		fmt.Fprintf(textout, "sending chain record to access nodes.. FAILED: %v\n", err)
+		return err
+	}
+	fmt.Fprint(textout, "sending chain record to access nodes.. OK.\n")
+
+	err = access.ActivateChain(par.ChainID)
+	fmt.Fprint(textout, par.Prefix)
+	if err != nil {
+		fmt.Fprintf(textout, "activating chain in access nodes.. FAILED: %v\n", err)
+		return err
+	}
+	fmt.Fprint(textout, "activating chain in access nodes.. OK.\n")
+	return nil
+}
@@ -107,6 +107,9 @@ func CheckDeployment(apiHosts []string, chainID coretypes.ChainID, textout ...io
 	fmt.Fprintf(out, prefix+"checking distributed keys..\n")
 
 	chainAddr := address.Address(chainID)
+	if first != nil {
+		chainAddr = first.Address
+	}
 	dkShares, err := multiclient.New(apiHosts).DKSharesGet(&chainAddr)
 	if err != nil {
 		fmt.Fprintf(out, prefix+"%s\n", err.Error())
@@ -174,6 +177,9 @@ func consistentChainRecords(bd1, bd2 *registry.ChainRecord) bool {
 	if bd1.Color != bd2.Color {
 		return false
 	}
+	if bd1.Address != bd2.Address {
+		return false
+	}
 	if len(bd1.CommitteeNodes) != len(bd2.CommitteeNodes) {
 		return false
 	}
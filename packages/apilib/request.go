@@ -19,9 +19,12 @@ import (
 type RequestSectionParams struct {
 	TargetContractID coretypes.ContractID
 	EntryPointCode   coretypes.Hname
-	TimeLock         uint32
+	TimeLock         int64
+	GasBudget        uint64                    // 0 means default gas budget
 	Transfer         coretypes.ColoredBalances // should not not include request token. It is added automatically
 	Args             requestargs.RequestArgs
+	EncryptTo        *requestargs.Recipients // if not nil, Args are encrypted to the committee
+	ChainAddress     *address.Address        // address of the committee, if the chain was moved. nil means the chain ID
 }
 
 type CreateRequestTransactionParams struct {
@@ -31,6 +34,9 @@ type CreateRequestTransactionParams struct {
 	Mint                 map[address.Address]int64 // free tokens to be minted from IOTA color
 	Post                 bool
 	WaitForConfirmation  bool
+	// Unsigned builds the transaction without signing nor posting it, so it can be signed offline.
+	// Only the address of SenderSigScheme is used
+	Unsigned bool
 }
 
 func CreateRequestTransaction(par CreateRequestTransactionParams) (*sctransaction.Transaction, error) {
@@ -48,9 +54,18 @@ func CreateRequestTransaction(par CreateRequestTransactionParams) (*sctransactio
 	for _, sectPar := range par.RequestSectionParams {
 		reqSect := sctransaction.NewRequestSectionByWallet(sectPar.TargetContractID, sectPar.EntryPointCode).
 			WithTimelock(sectPar.TimeLock).
+			WithGasBudget(sectPar.GasBudget).
 			WithTransfer(sectPar.Transfer)
+		if sectPar.ChainAddress != nil {
+			reqSect.WithChainAddress(*sectPar.ChainAddress)
+		}
 
 		reqSect.WithArgs(sectPar.Args)
+		if sectPar.EncryptTo != nil {
+			if err := reqSect.EncryptArgs(sectPar.EncryptTo); err != nil {
+				return nil, err
+			}
+		}
 
 		err = txb.AddRequestSection(reqSect)
 		if err != nil {
@@ -65,6 +80,9 @@ func CreateRequestTransaction(par CreateRequestTransactionParams) (*sctransactio
 	if err != nil {
 		return nil, err
 	}
+	if par.Unsigned {
+		return tx, nil
+	}
 	tx.Sign(par.SenderSigScheme)
 
 	// semantic check just in case
@@ -0,0 +1,170 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package apilib
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
+	"github.com/iotaledger/wasp/client"
+	"github.com/iotaledger/wasp/client/level1"
+	"github.com/iotaledger/wasp/client/multiclient"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+)
+
+type RotateChainParams struct {
+	Node                     level1.Level1Client
+	ChainID                  coretypes.ChainID
+	CommitteeApiHosts        []string // api hosts of the current committee
+	NewCommitteeApiHosts     []string
+	NewCommitteePeeringHosts []string
+	T                        uint16
+	OwnerSigScheme           signaturescheme.SignatureScheme
+	Textout                  io.Writer
+	Prefix                   string
+}
+
+// RotateChain moves the chain to a new committee:
+// - runs DKG on the new committee nodes and checks all of them hold a key share of the new address
+// - requests the 'root' contract to approve the new committee address. The state transaction
+// which settles the request moves the chain token and all assets of the chain to the new address
+// - updates chain records in the nodes of the old and the new committee
+// At least one node of the new committee must be in the current committee: it provides the state
+// of the chain to the rest of the new committee
+func RotateChain(par RotateChainParams) (*address.Address, error) {
+	var err error
+	textout := ioutil.Discard
+	if par.Textout != nil {
+		textout = par.Textout
+	}
+
+	if !containsAny(par.CommitteeApiHosts, par.NewCommitteeApiHosts) {
+		return nil, fmt.Errorf("at least one node of the new committee must belong to the current committee")
+	}
+
+	// ----------- load the chain record from the current committee
+	chainRecord, err := client.NewWaspClient(par.CommitteeApiHosts[0]).GetChainRecord(par.ChainID)
+	fmt.Fprint(textout, par.Prefix)
+	if err != nil {
+		fmt.Fprintf(textout, "loading chain record.. FAILED: %v\n", err)
+		return nil, err
+	}
+	fmt.Fprintf(textout, "loading chain record.. OK. Current committee address = %s\n", chainRecord.Address.String())
+	oldAddr := chainRecord.Address
+
+	// ----------- run DKG on new committee nodes
+	var dkgInitiatorIndex = rand.Intn(len(par.NewCommitteeApiHosts))
+	var dkShares *model.DKSharesInfo
+	dkShares, err = client.NewWaspClient(par.NewCommitteeApiHosts[dkgInitiatorIndex]).DKSharesPost(&model.DKSharesPostRequest{
+		PeerNetIDs:  par.NewCommitteePeeringHosts,
+		PeerPubKeys: nil,
+		Threshold:   par.T,
+		TimeoutMS:   60000, // 1 min
+	})
+	fmt.Fprint(textout, par.Prefix)
+	if err != nil {
+		fmt.Fprintf(textout, "generating distributed key set.. FAILED: %v\n", err)
+		return nil, err
+	}
+	fmt.Fprintf(textout, "generating distributed key set.. OK. Generated address = %s\n", dkShares.Address)
+	var newAddr address.Address
+	if newAddr, err = address.FromBase58(dkShares.Address); err != nil {
+		return nil, err
+	}
+	// the chain can't be moved to the address which the new committee can't sign for
+	for _, host := range par.NewCommitteeApiHosts {
+		share, err := client.NewWaspClient(host).DKSharesGet(&newAddr)
+		if err == nil && share.PeerIndex == nil {
+			err = fmt.Errorf("the node is not a member of the key sharing group")
+		}
+		if err != nil {
+			fmt.Fprint(textout, par.Prefix)
+			fmt.Fprintf(textout, "checking key shares in %s.. FAILED: %v\n", host, err)
+			return nil, fmt.Errorf("node %s doesn't hold a key share of %s: %v", host, newAddr.String(), err)
+		}
+	}
+	fmt.Fprint(textout, par.Prefix)
+	fmt.Fprintf(textout, "checking key shares in the new committee nodes.. OK\n")
+
+	// ----------- request the root contract to approve the new committee
+	reqTx, err := CreateRequestTransaction(CreateRequestTransactionParams{
+		Level1Client:    par.Node,
+		SenderSigScheme: par.OwnerSigScheme,
+		RequestSectionParams: []RequestSectionParams{{
+			TargetContractID: coretypes.NewContractID(par.ChainID, root.Interface.Hname()),
+			EntryPointCode:   coretypes.Hn(root.FuncRotateCommittee),
+			Args: requestargs.New().AddEncodeSimpleMany(codec.MakeDict(map[string]interface{}{
+				root.ParamChainAddress: newAddr,
+			})),
+			ChainAddress: &oldAddr,
+		}},
+		Post:                true,
+		WaitForConfirmation: true,
+	})
+	fmt.Fprint(textout, par.Prefix)
+	if err != nil {
+		fmt.Fprintf(textout, "posting rotate committee request.. FAILED: %v\n", err)
+		return nil, err
+	}
+	fmt.Fprintf(textout, "posting rotate committee request.. OK. Txid = %s\n", reqTx.ID().String())
+
+	committee := multiclient.New(par.CommitteeApiHosts)
+	if err = committee.WaitUntilAllRequestsProcessed(reqTx, 30*time.Second); err != nil {
+		fmt.Fprintf(textout, "waiting rotate committee request.. FAILED: %v\n", err)
+		return nil, err
+	}
+
+	// ----------- check the new address was approved
+	info, err := client.NewWaspClient(par.CommitteeApiHosts[0]).CallView(
+		coretypes.NewContractID(par.ChainID, root.Interface.Hname()), root.FuncGetChainInfo, nil)
+	if err != nil {
+		return nil, err
+	}
+	approvedAddr, _, err := codec.DecodeAddress(info.MustGet(root.VarChainAddress))
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprint(textout, par.Prefix)
+	if approvedAddr != newAddr {
+		fmt.Fprintf(textout, "rotating committee.. FAILED: the new address wasn't approved by the chain\n")
+		return nil, fmt.Errorf("new committee address %s wasn't approved by the chain", newAddr.String())
+	}
+	fmt.Fprintf(textout, "rotating committee.. OK. Chain moved from %s to %s\n", oldAddr.String(), newAddr.String())
+
+	// ----------- update chain records in the old and the new committee nodes
+	allHosts := append([]string{}, par.CommitteeApiHosts...)
+	for _, host := range par.NewCommitteeApiHosts {
+		if !containsAny(allHosts, []string{host}) {
+			allHosts = append(allHosts, host)
+		}
+	}
+	err = multiclient.New(allHosts).UpdateChainCommittee(par.ChainID, chainRecord.Color, newAddr, par.NewCommitteePeeringHosts, chainRecord.AccessNodes)
+	fmt.Fprint(textout, par.Prefix)
+	if err != nil {
+		fmt.Fprintf(textout, "updating chain records.. FAILED: %v\n", err)
+		return nil, err
+	}
+	fmt.Fprint(textout, "updating chain records.. OK.\n")
+	return &newAddr, nil
+}
+
+func containsAny(lst []string, elems []string) bool {
+	for _, s := range lst {
+		for _, e := range elems {
+			if s == e {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -28,6 +28,7 @@ type Chain interface {
 	SendMsg(targetPeerIndex uint16, msgType byte, msgData []byte) error
 	SendMsgToCommitteePeers(msgType byte, msgData []byte, ts int64) uint16
 	IsAlivePeer(peerIndex uint16) bool
+	IsCommitteeNode() bool
 	ReceiveMessage(msg interface{})
 	InitTestRound()
 	HasQuorum() bool
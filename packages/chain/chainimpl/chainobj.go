@@ -36,6 +36,7 @@ type chainObj struct {
 	onActivation                 func()
 	//
 	chainID         coretypes.ChainID
+	address         address.Address
 	procset         *processors.ProcessorCache
 	color           balance.Color
 	peers           peering.GroupProvider
@@ -46,6 +47,7 @@ type chainObj struct {
 	stateMgr        chain.StateManager
 	operator        chain.Operator
 	isCommitteeNode atomic.Bool
+	movedAway       atomic.Bool
 	//
 	eventRequestProcessed *events.Event
 	log                   *logger.Logger
@@ -70,29 +72,51 @@ func newCommitteeObj(
 	var err error
 	log.Debugw("creating committee", "addr", chr.ChainID.String())
 
-	addr := address.Address(chr.ChainID)
-	if util.ContainsDuplicates(chr.CommitteeNodes) {
-		log.Errorf("can't create chain object for %s: chain record contains duplicate node addresses. Chain nodes: %+v",
-			addr.String(), chr.CommitteeNodes)
+	addr := chr.Address
+	if addr == (address.Address{}) {
+		addr = address.Address(chr.ChainID)
+	}
+	// committee and access nodes can be referred to by identity instead of NetID
+	committeeNodes, err := peering.ResolveNetIDs(netProvider, chr.CommitteeNodes)
+	if err != nil {
+		log.Errorf("can't create chain object for %s: %v", addr.String(), err)
 		return nil
 	}
-	dkshare, err := dksProvider.LoadDKShare(&addr)
+	accessNodes, err := peering.ResolveNetIDs(netProvider, chr.AccessNodes)
 	if err != nil {
-		log.Error(err)
+		log.Errorf("can't create chain object for %s: %v", addr.String(), err)
+		return nil
+	}
+	if util.ContainsDuplicates(committeeNodes) {
+		log.Errorf("can't create chain object for %s: chain record contains duplicate node addresses. Chain nodes: %+v",
+			addr.String(), committeeNodes)
 		return nil
 	}
-	if dkshare.Index == nil || !iAmInTheCommittee(chr.CommitteeNodes, dkshare.N, *dkshare.Index, netProvider) {
+	selfNetID := netProvider.Self().NetID()
+	dkshare, err := dksProvider.LoadDKShare(&addr)
+	isCommitteeNode := err == nil && dkshare.Index != nil && iAmInTheCommittee(committeeNodes, dkshare.N, *dkshare.Index, netProvider)
+	if !isCommitteeNode && !util.StringInList(selfNetID, accessNodes) {
+		if err != nil {
+			log.Error(err)
+		}
 		log.Errorf(
-			"chain record inconsistency: the own node %s is not in the committee for %s: %+v",
-			netProvider.Self().NetID(), addr.String(), chr.CommitteeNodes,
+			"chain record inconsistency: the own node %s is neither in the committee nor an access node for %s: %+v",
+			selfNetID, addr.String(), committeeNodes,
 		)
 		return nil
 	}
+	// first N peers are committee peers, the rest are access peers
+	peerNetIDs := append([]string{}, committeeNodes...)
+	for _, netID := range accessNodes {
+		if !util.StringInList(netID, peerNetIDs) {
+			peerNetIDs = append(peerNetIDs, netID)
+		}
+	}
 	var peers peering.GroupProvider
-	if peers, err = netProvider.Group(chr.CommitteeNodes); err != nil {
+	if peers, err = netProvider.Group(peerNetIDs); err != nil {
 		log.Errorf(
 			"node %s failed to setup committee communication with %+v, reason=%+v",
-			netProvider.Self().NetID(), chr.CommitteeNodes, err,
+			selfNetID, peerNetIDs, err,
 		)
 		return nil
 	}
@@ -101,6 +125,7 @@ func newCommitteeObj(
 		procset:      processors.MustNew(),
 		chMsg:        make(chan interface{}, 100),
 		chainID:      chr.ChainID,
+		address:      addr,
 		color:        chr.Color,
 		peers:        peers,
 		onActivation: onActivation,
@@ -116,13 +141,23 @@ func newCommitteeObj(
 		ret.ReceiveMessage(recv.Msg)
 	})
 
-	ret.ownIndex = *dkshare.Index
-	ret.size = dkshare.N
-	ret.quorum = dkshare.T
+	ret.size = uint16(len(committeeNodes))
+	if isCommitteeNode {
+		ret.ownIndex = *dkshare.Index
+		ret.quorum = dkshare.T
+	} else {
+		// the access node only needs one committee peer to sync the state from
+		ret.ownIndex, _ = peers.PeerIndexByNetID(selfNetID)
+		ret.quorum = 1
+		ret.isReadyConsensus = true
+		chainLog.Infof("the node %s follows the chain as an access node", selfNetID)
+	}
 
 	ret.stateMgr = statemgr.New(ret, ret.log)
-	ret.operator = consensus.NewOperator(ret, dkshare, ret.log)
-	ret.isCommitteeNode.Store(true)
+	if isCommitteeNode {
+		ret.operator = consensus.NewOperator(ret, dkshare, ret.log)
+	}
+	ret.isCommitteeNode.Store(isCommitteeNode)
 	go func() {
 		for msg := range ret.chMsg {
 			ret.dispatchMessage(msg)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/publisher"
 )
 
 func (c *chainObj) dispatchMessage(msg interface{}) {
@@ -26,7 +27,8 @@ func (c *chainObj) dispatchMessage(msg interface{}) {
 		c.stateMgr.EventStateUpdateMsg(msgt)
 
 	case *chain.StateTransitionMsg:
-		if c.operator != nil {
+		c.checkMovedAway(msgt)
+		if c.operator != nil && !c.movedAway.Load() {
 			c.operator.EventStateTransitionMsg(msgt)
 		}
 
@@ -44,7 +46,7 @@ func (c *chainObj) dispatchMessage(msg interface{}) {
 
 	case *chain.RequestMsg:
 		// receive request message
-		if c.operator != nil {
+		if c.operator != nil && !c.movedAway.Load() {
 			c.operator.EventRequestMsg(msgt)
 		}
 
@@ -66,17 +68,38 @@ func (c *chainObj) dispatchMessage(msg interface{}) {
 				c.stateMgr.EventTimerMsg(msgt / 2)
 			}
 		} else {
-			if c.operator != nil {
+			if c.operator != nil && !c.movedAway.Load() {
 				c.operator.EventTimerMsg(msgt / 2)
 			}
 		}
 	}
 }
 
+// checkMovedAway detects the state transition which moved the chain to another committee address.
+// After that the committee stops participating in the consensus. The node keeps the chain object
+// until it is deactivated, so that the state and request statuses can still be queried
+func (c *chainObj) checkMovedAway(msg *chain.StateTransitionMsg) {
+	if c.movedAway.Load() || msg.AnchorTransaction == nil {
+		return
+	}
+	newAddress := *msg.AnchorTransaction.MustProperties().MustChainAddress()
+	if newAddress == c.address {
+		return
+	}
+	c.movedAway.Store(true)
+	c.log.Infof("chain has been moved from committee address %s to %s", c.address.String(), newAddress.String())
+	publisher.Publish("rotated_committee", c.chainID.String(), c.address.String(), newAddress.String())
+}
+
 func (c *chainObj) processPeerMessage(msg *peering.PeerMessage) {
 
 	rdr := bytes.NewReader(msg.MsgData)
 
+	if isConsensusMsg(msg.MsgType) && msg.SenderIndex >= c.size {
+		c.log.Warnf("processPeerMessage: consensus message from the access peer %d", msg.SenderIndex)
+		return
+	}
+
 	switch msg.MsgType {
 
 	case chain.MsgStateIndexPingPong:
@@ -195,3 +218,12 @@ func (c *chainObj) processPeerMessage(msg *peering.PeerMessage) {
 		c.log.Errorf("processPeerMessage: wrong msg type")
 	}
 }
+
+// isConsensusMsg returns true for messages exchanged only among committee peers
+func isConsensusMsg(msgType byte) bool {
+	switch msgType {
+	case chain.MsgNotifyRequests, chain.MsgNotifyFinalResultPosted, chain.MsgStartProcessingRequest, chain.MsgSignedHash:
+		return true
+	}
+	return false
+}
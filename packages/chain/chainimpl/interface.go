@@ -129,7 +129,9 @@ func (c *chainObj) Dismiss() {
 		c.peers.Close()
 
 		c.stateMgr.Close()
-		c.operator.Close()
+		if c.operator != nil {
+			c.operator.Close()
+		}
 	})
 
 	publisher.Publish("dismissed_committee", c.chainID.String())
@@ -148,7 +150,7 @@ func (c *chainObj) Color() *balance.Color {
 }
 
 func (c *chainObj) Address() address.Address {
-	return address.Address(c.chainID)
+	return c.address
 }
 
 func (c *chainObj) Size() uint16 {
@@ -196,8 +198,16 @@ func (c *chainObj) SendMsgToCommitteePeers(msgType byte, msgData []byte, ts int6
 		MsgType:     msgType,
 		MsgData:     msgData,
 	}
-	c.peers.Broadcast(msg, false)
-	return uint16(len(c.peers.OtherNodes())) // TODO: [KP] Reconsider this, we cannot guaranty if they are actually sent.
+	numSent := uint16(0)
+	for i, peer := range c.peers.OtherNodes() {
+		if i >= c.size {
+			// access node
+			continue
+		}
+		peer.SendMsg(msg)
+		numSent++
+	}
+	return numSent // TODO: [KP] Reconsider this, we cannot guaranty if they are actually sent.
 }
 
 // sends message to the peer seq[seqIndex]. If receives error, seqIndex = (seqIndex+1) % size and repeats
@@ -246,7 +256,18 @@ func (c *chainObj) NumPeers() uint16 {
 
 // first N peers are committee peers, the rest are access peers in any
 func (c *chainObj) committeePeers() map[uint16]peering.PeerSender {
-	return c.peers.AllNodes()
+	ret := make(map[uint16]peering.PeerSender)
+	for i, peer := range c.peers.AllNodes() {
+		if i < c.size {
+			ret[i] = peer
+		}
+	}
+	return ret
+}
+
+// IsCommitteeNode is false for the access node, which follows the chain without taking part in the consensus
+func (c *chainObj) IsCommitteeNode() bool {
+	return c.isCommitteeNode.Load()
 }
 
 func (c *chainObj) HasQuorum() bool {
@@ -4,6 +4,7 @@
 package consensus
 
 import (
+	"errors"
 	"time"
 
 	"github.com/iotaledger/wasp/packages/chain"
@@ -33,13 +34,18 @@ func (op *operator) solidifyRequestArgsIfNeeded() {
 	}
 	reqs := op.allRequests()
 	reqs = filterRequests(reqs, func(r *request) bool {
-		return r.hasMessage() && !r.hasSolidArgs()
+		return r.hasMessage() && !r.hasSolidArgs() && !r.argsUndecryptable
 	})
 	for _, req := range reqs {
-		ok, err := req.reqTx.Requests()[req.reqId.Index()].SolidifyArgs(op.chain.BlobCache())
-		if err != nil {
+		ok, err := req.reqTx.Requests()[req.reqId.Index()].SolidifyArgs(op.chain.BlobCache(), op.dkshare)
+		switch {
+		case errors.Is(err, sctransaction.ErrUndecryptableArgs):
+			// retrying would not help. The request is settled with error only if the leader can't decrypt it either
+			req.argsUndecryptable = true
+			req.log.Warnf("request arguments can't be decrypted by the node: %v", err)
+		case err != nil:
 			req.log.Errorf("failed to solidify request arguments: %v", err)
-		} else {
+		default:
 			req.argsSolid = ok
 			if ok {
 				req.log.Infof("solidified request arguments")
@@ -113,6 +119,7 @@ func (op *operator) startCalculationsAsLeader() {
 	}
 	reqIds := takeIds(reqs)
 	reqIdsStr := idsShortStr(reqIds)
+	argsUndecryptable := takeArgsUndecryptable(reqs)
 
 	op.log.Debugf("requests selected to process. Current state: %d, Reqs: %+v", op.mustStateIndex(), reqIdsStr)
 	rewardAddress := op.getFeeDestination()
@@ -123,9 +130,10 @@ func (op *operator) startCalculationsAsLeader() {
 			// timestamp is set by SendMsgToCommitteePeers
 			BlockIndex: op.stateTx.MustState().BlockIndex(),
 		},
-		FeeDestination: rewardAddress,
-		Balances:       op.balances,
-		RequestIds:     reqIds,
+		FeeDestination:    rewardAddress,
+		Balances:          op.balances,
+		RequestIds:        reqIds,
+		ArgsUndecryptable: argsUndecryptable,
 	})
 
 	// determine timestamp. Must be max(local clock, prev timestamp+1).
@@ -150,7 +158,7 @@ func (op *operator) startCalculationsAsLeader() {
 		return
 	}
 	// batchHash uniquely identifies inputs to calculations
-	batchHash := vm.BatchHash(reqIds, ts, op.peerIndex())
+	batchHash := vm.BatchHash(reqIds, argsUndecryptable, ts, op.peerIndex())
 	op.leaderStatus = &leaderStatus{
 		reqs:          reqs,
 		batchHash:     batchHash,
@@ -165,11 +173,12 @@ func (op *operator) startCalculationsAsLeader() {
 	)
 	// process the batch on own (leader) side. Start calculations on VM in a separate thread
 	op.runCalculationsAsync(runCalculationsParams{
-		requests:        reqs,
-		leaderPeerIndex: op.chain.OwnPeerIndex(),
-		balances:        op.balances,
-		timestamp:       ts,
-		accrueFeesTo:    rewardAddress,
+		requests:          reqs,
+		argsUndecryptable: argsUndecryptable,
+		leaderPeerIndex:   op.chain.OwnPeerIndex(),
+		balances:          op.balances,
+		timestamp:         ts,
+		accrueFeesTo:      rewardAddress,
 	})
 	// the LeaderCalculationsStarted stage means at least a quorum of async
 	// calculation tasks has been started: locally and on peers
@@ -110,7 +110,7 @@ func (op *operator) EventStartProcessingBatchMsg(msg *chain.StartProcessingBatch
 
 // eventStartProcessingBatchMsg internal handler
 func (op *operator) eventStartProcessingBatchMsg(msg *chain.StartProcessingBatchMsg) {
-	bh := vm.BatchHash(msg.RequestIds, msg.Timestamp, msg.SenderIndex)
+	bh := vm.BatchHash(msg.RequestIds, msg.ArgsUndecryptable, msg.Timestamp, msg.SenderIndex)
 
 	op.log.Debugw("EventStartProcessingBatchMsg",
 		"sender", msg.SenderIndex,
@@ -134,11 +134,11 @@ func (op *operator) eventStartProcessingBatchMsg(msg *chain.StartProcessingBatch
 		return
 	}
 	numOrig := len(msg.RequestIds)
-	reqs := op.collectProcessableBatch(msg.RequestIds)
+	reqs := op.collectProcessableBatch(msg.RequestIds, msg.ArgsUndecryptable, time.Unix(0, msg.Timestamp))
 	if len(reqs) != numOrig {
-		// some request were filtered out because not messages didn't reach the node yet.
-		// can't happen? Redundant? panic?
-		op.log.Warnf("node can't process the batch: some requests are not known to the node")
+		// some request were filtered out because not messages didn't reach the node yet
+		// or the node can't decrypt arguments which the leader decrypted
+		op.log.Warnf("node can't process the batch: some requests are not known to the node or not solid")
 		return
 	}
 	// TODO remove
@@ -167,11 +167,12 @@ func (op *operator) eventStartProcessingBatchMsg(msg *chain.StartProcessingBatch
 
 	// start async calculation as requested by the leader
 	op.runCalculationsAsync(runCalculationsParams{
-		requests:        reqs,
-		timestamp:       msg.Timestamp,
-		balances:        msg.Balances,
-		accrueFeesTo:    msg.FeeDestination,
-		leaderPeerIndex: msg.SenderIndex,
+		requests:          reqs,
+		argsUndecryptable: msg.ArgsUndecryptable,
+		timestamp:         msg.Timestamp,
+		balances:          msg.Balances,
+		accrueFeesTo:      msg.FeeDestination,
+		leaderPeerIndex:   msg.SenderIndex,
 	})
 	op.setNextConsensusStage(consensusStageSubCalculationsStarted)
 	op.takeAction()
@@ -331,7 +332,7 @@ func timelockedToString(reqs []*request) string {
 		return "[]"
 	}
 	ret := make([]string, len(reqs))
-	nowis := uint32(time.Now().Unix())
+	nowis := time.Now().Unix()
 	for i := range ret {
 		ret[i] = fmt.Sprintf("%s: %d (-%d)", reqs[i].reqId.Short(), reqs[i].timelock(), reqs[i].timelock()-nowis)
 	}
@@ -4,12 +4,16 @@
 package consensus
 
 import (
+	"time"
+
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/util"
 )
 
 // sendRequestNotificationsToLeader sends current leader the backlog of requests
-// it is only possible in the `consensusStageLeaderStarting` stage for non-leader
+// it is only possible in the `consensusStageLeaderStarting` stage for non-leader.
+// Notifications are sent again when time-locked requests become ready after the previous
+// notification, so that due requests (e.g. calls of the scheduler) don't wait for the leader rotation
 func (op *operator) sendRequestNotificationsToLeader() {
 	if len(op.requests) == 0 {
 		return
@@ -17,7 +21,13 @@ func (op *operator) sendRequestNotificationsToLeader() {
 	if op.iAmCurrentLeader() {
 		return
 	}
-	if op.consensusStage != consensusStageSubStarting {
+	switch op.consensusStage {
+	case consensusStageSubStarting:
+	case consensusStageSubNotificationsSent:
+		if len(op.requestsUnlockedSince(op.notificationsSentAt)) == 0 {
+			return
+		}
+	default:
 		return
 	}
 	if !op.chain.HasQuorum() {
@@ -54,6 +64,7 @@ func (op *operator) sendRequestNotificationsToLeader() {
 	if err := op.chain.SendMsg(currentLeaderPeerIndex, chain.MsgNotifyRequests, msgData); err != nil {
 		op.log.Errorf("sending notifications to %d: %v", currentLeaderPeerIndex, err)
 	}
+	op.notificationsSentAt = time.Now()
 	op.setNextConsensusStage(consensusStageSubNotificationsSent)
 }
 
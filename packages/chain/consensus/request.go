@@ -10,6 +10,7 @@ import (
 
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/publisher"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/state"
@@ -36,6 +37,7 @@ func (op *operator) requestFromId(reqId coretypes.RequestID) (*request, bool) {
 	if !ok {
 		ret = op.newRequest(reqId)
 		op.requests[reqId] = ret
+		metrics.ConsensusBacklogSize(op.chain.ID(), len(op.requests))
 		ret.log.Info("NEW REQUEST from id")
 	}
 	return ret, true
@@ -64,6 +66,7 @@ func (op *operator) requestFromMsg(reqMsg *chain.RequestMsg) (*request, bool) {
 		ret.reqTx = reqMsg.Transaction
 		ret.freeTokens = reqMsg.FreeTokens
 		op.requests[*reqId] = ret
+		metrics.ConsensusBacklogSize(op.chain.ID(), len(op.requests))
 		op.addRequestIdConcurrent(reqId)
 		newMsg = true
 	}
@@ -100,12 +103,12 @@ func (req *request) requestCode() coretypes.Hname {
 	return req.reqTx.Requests()[req.reqId.Index()].EntryPointCode()
 }
 
-func (req *request) timelock() uint32 {
+func (req *request) timelock() int64 {
 	return req.reqTx.Requests()[req.reqId.Index()].Timelock()
 }
 
 func (req *request) isTimeLocked(nowis time.Time) bool {
-	return req.timelock() > uint32(nowis.Unix())
+	return req.timelock() > nowis.Unix()
 }
 
 func (req *request) hasMessage() bool {
@@ -116,6 +119,12 @@ func (req *request) hasSolidArgs() bool {
 	return req.argsSolid
 }
 
+// canBeSelected returns true if the leader can put the request into the batch: either the args are solid
+// or the leader proposes to settle the request with error because it can't decrypt the args
+func (req *request) canBeSelected() bool {
+	return req.argsSolid || req.argsUndecryptable
+}
+
 func (op *operator) isRequestProcessed(reqid *coretypes.RequestID) bool {
 	processed, err := state.IsRequestCompleted(op.chain.ID(), reqid)
 	if err != nil {
@@ -142,6 +151,7 @@ func (op *operator) deleteCompletedRequests() error {
 		op.removeRequestIdConcurrent(rid)
 		op.log.Debugf("removed from backlog: processed request %s", rid.String())
 	}
+	metrics.ConsensusBacklogSize(op.chain.ID(), len(op.requests))
 	return nil
 }
 
@@ -173,7 +183,16 @@ func takeIds(reqs []*request) []coretypes.RequestID {
 	return ret
 }
 
-func takeRefs(reqs []*request) []vm.RequestRefWithFreeTokens {
+// takeArgsUndecryptable returns the flags of the leader's proposal to settle requests with error
+func takeArgsUndecryptable(reqs []*request) []bool {
+	ret := make([]bool, len(reqs))
+	for i := range ret {
+		ret[i] = reqs[i].argsUndecryptable
+	}
+	return ret
+}
+
+func takeRefs(reqs []*request, argsUndecryptable []bool) []vm.RequestRefWithFreeTokens {
 	ret := make([]vm.RequestRefWithFreeTokens, len(reqs))
 	for i := range ret {
 		ret[i] = vm.RequestRefWithFreeTokens{
@@ -181,7 +200,8 @@ func takeRefs(reqs []*request) []vm.RequestRefWithFreeTokens {
 				Tx:    reqs[i].reqTx,
 				Index: reqs[i].reqId.Index(),
 			},
-			FreeTokens: reqs[i].freeTokens,
+			FreeTokens:        reqs[i].freeTokens,
+			ArgsUndecryptable: argsUndecryptable[i],
 		}
 	}
 	return ret
@@ -18,11 +18,12 @@ import (
 )
 
 type runCalculationsParams struct {
-	requests        []*request
-	leaderPeerIndex uint16
-	balances        map[valuetransaction.ID][]*balance.Balance
-	accrueFeesTo    coretypes.AgentID
-	timestamp       int64
+	requests          []*request
+	argsUndecryptable []bool
+	leaderPeerIndex   uint16
+	balances          map[valuetransaction.ID][]*balance.Balance
+	accrueFeesTo      coretypes.AgentID
+	timestamp         int64
 }
 
 // runs the VM for requests and posts result to committee's queue
@@ -35,10 +36,11 @@ func (op *operator) runCalculationsAsync(par runCalculationsParams) {
 		Processors:         op.chain.Processors(),
 		ChainID:            *op.chain.ID(),
 		Color:              *op.chain.Color(),
+		ChainAddress:       op.chain.Address(),
 		Entropy:            (hashing.HashValue)(op.stateTx.ID()),
 		Balances:           par.balances,
 		ValidatorFeeTarget: par.accrueFeesTo,
-		Requests:           takeRefs(par.requests),
+		Requests:           takeRefs(par.requests, par.argsUndecryptable),
 		Timestamp:          par.timestamp,
 		VirtualState:       op.currentState,
 		Log:                op.log,
@@ -73,12 +75,14 @@ func (op *operator) sendResultToTheLeader(result *vm.VMTask, leader uint16) {
 	}
 
 	reqids := make([]coretypes.RequestID, len(result.Requests))
+	argsUndecryptable := make([]bool, len(result.Requests))
 	for i := range reqids {
 		reqids[i] = *result.Requests[i].RequestID()
+		argsUndecryptable[i] = result.Requests[i].ArgsUndecryptable
 	}
 
 	essenceHash := hashing.HashData(result.ResultTransaction.EssenceBytes())
-	batchHash := vm.BatchHash(reqids, result.Timestamp, leader)
+	batchHash := vm.BatchHash(reqids, argsUndecryptable, result.Timestamp, leader)
 
 	op.log.Debugw("sendResultToTheLeader",
 		"leader", leader,
@@ -120,11 +124,13 @@ func (op *operator) saveOwnResult(result *vm.VMTask) {
 	}
 
 	reqids := make([]coretypes.RequestID, len(result.Requests))
+	argsUndecryptable := make([]bool, len(result.Requests))
 	for i := range reqids {
 		reqids[i] = *result.Requests[i].RequestID()
+		argsUndecryptable[i] = result.Requests[i].ArgsUndecryptable
 	}
 
-	bh := vm.BatchHash(reqids, result.Timestamp, op.chain.OwnPeerIndex())
+	bh := vm.BatchHash(reqids, argsUndecryptable, result.Timestamp, op.chain.OwnPeerIndex())
 	if bh != op.leaderStatus.batchHash {
 		panic("bh != op.leaderStatus.batchHash")
 	}
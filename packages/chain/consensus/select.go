@@ -54,14 +54,14 @@ func (op *operator) allRequests() []*request {
 
 // all requests from the backlog which:
 // - has known messages
-// - has solid arguments
+// - has solid arguments or arguments the node can't decrypt
 // - are not timelocked
 // sort by arrival time
 func (op *operator) requestCandidateList() []*request {
 	ret := op.allRequests()
 	nowis := time.Now()
 	ret = filterRequests(ret, func(r *request) bool {
-		return r.hasMessage() && !r.isTimeLocked(nowis) && r.hasSolidArgs()
+		return r.hasMessage() && !r.isTimeLocked(nowis) && r.canBeSelected()
 	})
 	sort.Slice(ret, func(i, j int) bool {
 		return ret[i].whenMsgReceived.Before(ret[j].whenMsgReceived)
@@ -85,6 +85,24 @@ func (op *operator) requestsTimeLocked() []*request {
 	return ret
 }
 
+// requestsUnlockedSince returns requests with the message known which were time locked
+// at the moment 'since' and are ready to be processed now
+func (op *operator) requestsUnlockedSince(since time.Time) []*request {
+	ret := make([]*request, 0)
+
+	nowis := time.Now()
+	for _, req := range op.requests {
+		if req.reqTx == nil {
+			continue
+		}
+		if !req.isTimeLocked(since) || req.isTimeLocked(nowis) {
+			continue
+		}
+		ret = append(ret, req)
+	}
+	return ret
+}
+
 type requestWithVotes struct {
 	*request
 	seenTimes uint16
@@ -114,10 +132,20 @@ func (op *operator) filterRequestsNotSeenQuorumTimes(candidates []*request) []*r
 	return ret
 }
 
-func (op *operator) collectProcessableBatch(reqIds []coretypes.RequestID) []*request {
-	nowis := time.Now()
+// collectProcessableBatch takes requests of the batch sent by the leader. Time locks are checked
+// against the timestamp of the batch rather than the local clock: the timestamp of the state
+// transaction can't be earlier than the time lock of any request settled by it.
+// The arguments of the request must be solid unless the leader proposes to settle it with error
+// because it can't decrypt them. The node's own decryption result doesn't change the proposal
+func (op *operator) collectProcessableBatch(reqIds []coretypes.RequestID, argsUndecryptable []bool, ts time.Time) []*request {
+	undecryptable := make(map[coretypes.RequestID]bool)
+	for i := range reqIds {
+		if argsUndecryptable[i] {
+			undecryptable[reqIds[i]] = true
+		}
+	}
 	return filterRequests(op.takeFromIds(reqIds), func(r *request) bool {
-		return r.hasMessage() && !r.isTimeLocked(nowis) && r.hasSolidArgs()
+		return r.hasMessage() && !r.isTimeLocked(ts) && (r.hasSolidArgs() || undecryptable[r.reqId])
 	})
 }
 
@@ -6,6 +6,8 @@ package consensus
 import (
 	"fmt"
 	"time"
+
+	"github.com/iotaledger/wasp/packages/metrics"
 )
 
 // consensus goes through stages on the leader and on the subordinate side
@@ -116,6 +118,7 @@ var stages = map[int]*stageParams{
 		[]int{
 			consensusStageNoSync,
 			consensusStageSubStarting,
+			consensusStageSubNotificationsSent,
 			consensusStageLeaderStarting,
 			consensusStageSubCalculationsStarted,
 			consensusStageSubResultFinalized,
@@ -186,6 +189,10 @@ func (op *operator) setNextConsensusStage(nextStage int) {
 			stages[op.consensusStage].name, nextStageParams.name, leader, op.iAmCurrentLeader())
 	}
 	saveStage := op.consensusStage
+	if !op.consensusStageStarted.IsZero() {
+		metrics.ConsensusStageDuration(op.chain.ID(), stages[saveStage].name, time.Since(op.consensusStageStarted))
+	}
+	op.consensusStageStarted = time.Now()
 	op.consensusStage = nextStage
 	op.consensusStageDeadline = time.Now().Add(nextStageParams.timeout)
 	timeout := "timeout: not set"
@@ -33,8 +33,11 @@ type operator struct {
 	// consensus stage
 	consensusStage         int
 	consensusStageDeadline time.Time
+	consensusStageStarted  time.Time
 	//
 	requestBalancesDeadline time.Time
+	// when the notifications were sent to the leader the last time
+	notificationsSentAt time.Time
 
 	// notifications with future currentState indices
 	notificationsBacklog []*chain.NotifyReqMsg
@@ -103,6 +106,9 @@ type request struct {
 	notifications []bool
 	// true if arguments were decoded/solidified already. If not, the request in not eligible for the batch
 	argsSolid bool
+	// true if the arguments can't be decrypted by the node. As a leader, the node proposes to settle
+	// the request with error. As a subordinate, it processes the request only if the leader proposes so
+	argsUndecryptable bool
 
 	log *logger.Logger
 }
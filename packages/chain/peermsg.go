@@ -97,6 +97,9 @@ func (msg *StartProcessingBatchMsg) Write(w io.Writer) error {
 		if _, err := w.Write(msg.RequestIds[i][:]); err != nil {
 			return err
 		}
+		if err := util.WriteBoolByte(w, msg.ArgsUndecryptable[i]); err != nil {
+			return err
+		}
 	}
 	if _, err := w.Write(msg.FeeDestination[:]); err != nil {
 		return err
@@ -116,10 +119,14 @@ func (msg *StartProcessingBatchMsg) Read(r io.Reader) error {
 		return err
 	}
 	msg.RequestIds = make([]coretypes.RequestID, size)
+	msg.ArgsUndecryptable = make([]bool, size)
 	for i := range msg.RequestIds {
 		if err := msg.RequestIds[i].Read(r); err != nil {
 			return err
 		}
+		if err := util.ReadBoolByte(r, &msg.ArgsUndecryptable[i]); err != nil {
+			return err
+		}
 	}
 	if err := coretypes.ReadAgentID(r, &msg.FeeDestination); err != nil {
 		return err
@@ -191,6 +198,9 @@ func (msg *BlockHeaderMsg) Write(w io.Writer) error {
 	if _, err := w.Write(msg.AnchorTransactionID.Bytes()); err != nil {
 		return err
 	}
+	if err := util.WriteByte(w, msg.BlockVersion); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -204,7 +214,13 @@ func (msg *BlockHeaderMsg) Read(r io.Reader) error {
 	if _, err := r.Read(msg.AnchorTransactionID[:]); err != nil {
 		return err
 	}
-	return nil
+	var err error
+	msg.BlockVersion, err = util.ReadByte(r)
+	if err == io.EOF {
+		msg.BlockVersion = state.BlockVersionLegacy
+		return nil
+	}
+	return err
 }
 
 func (msg *StateUpdateMsg) Write(w io.Writer) error {
@@ -71,6 +71,9 @@ type StartProcessingBatchMsg struct {
 	Timestamp int64
 	// batch of request ids
 	RequestIds []coretypes.RequestID
+	// for each request of the batch: true if the leader can't decrypt its arguments.
+	// The request is settled with error by every node
+	ArgsUndecryptable []bool
 	// reward address
 	FeeDestination coretypes.AgentID
 	// balances/outputs
@@ -104,6 +107,8 @@ type BlockHeaderMsg struct {
 	PeerMsgHeader
 	Size                uint16
 	AnchorTransactionID valuetransaction.ID
+	// version of the block. It is not sent by older nodes, which know legacy blocks only
+	BlockVersion byte
 }
 
 // state update sent to peer. Used in sync process, as part of batch
@@ -11,6 +11,7 @@ import (
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/publisher"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
@@ -18,6 +19,7 @@ import (
 )
 
 func (sm *stateManager) takeAction() {
+	sm.updateSyncMetrics()
 	sm.sendPingsIfNeeded()
 	sm.notifyConsensusOnStateTransitionIfNeeded()
 	if sm.checkStateApproval() {
@@ -86,6 +88,12 @@ func (sm *stateManager) checkStateApproval() bool {
 	}
 
 	// found a pending block which is approved by the nextStateTransaction
+	// Merkle root in the state section must commit to the same state
+	if sm.nextStateTransaction.MustState().MerkleRoot() != pending.nextState.MerkleRoot() {
+		sm.log.Errorf("major inconsistency: Merkle root in the state transaction %s is not equal to the Merkle root of the state #%d",
+			sm.nextStateTransaction.ID().String(), pending.nextState.BlockIndex())
+		return false
+	}
 
 	if pending.block.StateTransactionID() == niltxid {
 		// not committed yet block. Link it to the transaction
@@ -137,6 +145,9 @@ func (sm *stateManager) checkStateApproval() bool {
 	sm.solidState = pending.nextState
 
 	sm.approvingTransaction = sm.nextStateTransaction
+	if err := state.SaveAnchorTransaction(sm.chain.ID(), sm.approvingTransaction); err != nil {
+		sm.log.Errorf("failed to save the anchor transaction of the state #%d: %v", sm.solidState.BlockIndex(), err)
+	}
 
 	// update state manager variables to the new state
 	sm.nextStateTransaction = nil
@@ -168,6 +179,7 @@ func (sm *stateManager) checkStateApproval() bool {
 			strconv.Itoa(int(pending.block.Size())),
 		)
 	}
+	metrics.RequestsProcessed(sm.chain.ID(), len(pending.block.RequestIDs()))
 	return true
 }
 
@@ -224,6 +236,13 @@ func (sm *stateManager) evidenceStateIndex(stateIndex uint32) {
 	}
 }
 
+func (sm *stateManager) updateSyncMetrics() {
+	if sm.solidState == nil {
+		return
+	}
+	metrics.StateSync(sm.chain.ID(), sm.solidState.BlockIndex(), sm.largestEvidencedStateIndex)
+}
+
 func (sm *stateManager) isSynchronized() bool {
 	if sm.solidState == nil {
 		return false // sm.largestEvidencedStateIndex == 0
@@ -339,10 +358,18 @@ func (sm *stateManager) numPongs() uint16 {
 }
 
 func (sm *stateManager) numPongsHasQuorum() bool {
+	if !sm.chain.IsCommitteeNode() {
+		// the access node doesn't count itself
+		return sm.numPongs() >= sm.chain.Quorum()
+	}
 	return sm.numPongs() >= sm.chain.Quorum()-1
 }
 
 func (sm *stateManager) pingPongReceived(senderIndex uint16) {
+	if int(senderIndex) >= len(sm.pingPong) {
+		// pings from access nodes are answered but not counted
+		return
+	}
 	sm.pingPong[senderIndex] = true
 }
 
@@ -56,6 +56,7 @@ func (sm *stateManager) eventGetBlockMsg(msg *chain.GetBlockMsg) {
 		},
 		Size:                block.Size(),
 		AnchorTransactionID: block.StateTransactionID(),
+		BlockVersion:        block.Version(),
 	}))
 	if err != nil {
 		return
@@ -88,6 +89,7 @@ func (sm *stateManager) eventBlockHeaderMsg(msg *chain.BlockHeaderMsg) {
 	if sm.syncedBatch != nil &&
 		sm.syncedBatch.stateIndex == msg.BlockIndex &&
 		sm.syncedBatch.stateTxId == msg.AnchorTransactionID &&
+		sm.syncedBatch.version == msg.BlockVersion &&
 		len(sm.syncedBatch.stateUpdates) == int(msg.Size) {
 		return // no need to start from scratch
 	}
@@ -95,6 +97,7 @@ func (sm *stateManager) eventBlockHeaderMsg(msg *chain.BlockHeaderMsg) {
 		stateIndex:   msg.BlockIndex,
 		stateUpdates: make([]state.StateUpdate, msg.Size),
 		stateTxId:    msg.AnchorTransactionID,
+		version:      msg.BlockVersion,
 	}
 }
 
@@ -144,7 +147,9 @@ func (sm *stateManager) eventStateUpdateMsg(msg *chain.StateUpdateMsg) {
 		sm.syncedBatch = nil
 		return
 	}
-	batch.WithBlockIndex(sm.syncedBatch.stateIndex).WithStateTransaction(sm.syncedBatch.stateTxId)
+	batch.WithBlockIndex(sm.syncedBatch.stateIndex).
+		WithStateTransaction(sm.syncedBatch.stateTxId).
+		WithVersion(sm.syncedBatch.version)
 
 	sm.log.Debugf("EventStateUpdateMsg: reconstructed block %s", batch.String())
 
@@ -82,6 +82,7 @@ type syncedBatch struct {
 	stateIndex   uint32
 	stateUpdates []state.StateUpdate
 	stateTxId    valuetransaction.ID
+	version      byte
 }
 
 type pendingBlock struct {
@@ -148,6 +149,8 @@ func (sm *stateManager) initLoadState() {
 		// pre-origin state. Origin block is empty block.
 		// Will be waiting for the origin transaction to arrive
 		sm.addPendingBlock(state.MustNewOriginBlock(sm.chain.Color()))
+		// the origin of a chain created before the Merkle trie was introduced is anchored with the legacy state hash
+		sm.addPendingBlock(state.MustNewOriginBlock(sm.chain.Color()).WithVersion(state.BlockVersionLegacy))
 
 		sm.log.Info("solid state does not exist: WAITING FOR THE ORIGIN TRANSACTION")
 	}
@@ -8,6 +8,7 @@ package coreutil
 import (
 	"fmt"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 )
@@ -19,6 +20,8 @@ type ContractInterface struct {
 	Description string
 	ProgramHash hashing.HashValue
 	Functions   map[coretypes.Hname]ContractFunctionInterface
+	// State describes the layout of the contract state in the schema
+	State []schema.StateDef
 }
 
 // ContractFunctionInterface represents entry point interface
@@ -26,6 +29,9 @@ type ContractFunctionInterface struct {
 	Name        string
 	Handler     Handler
 	ViewHandler ViewHandler
+	// Params and Results describe the entry point in the schema
+	Params  []schema.FieldDef
+	Results []schema.FieldDef
 }
 
 // Funcs declares init entry point and a list of full and view entry points
@@ -71,6 +77,18 @@ func ViewFunc(name string, handler ViewHandler) ContractFunctionInterface {
 	}
 }
 
+// WithParams declares the parameters of the entry point
+func (f ContractFunctionInterface) WithParams(params ...schema.FieldDef) ContractFunctionInterface {
+	f.Params = params
+	return f
+}
+
+// WithResults declares the results of the entry point
+func (f ContractFunctionInterface) WithResults(results ...schema.FieldDef) ContractFunctionInterface {
+	f.Results = results
+	return f
+}
+
 type Handler func(ctx coretypes.Sandbox) (dict.Dict, error)
 type ViewHandler func(ctx coretypes.SandboxView) (dict.Dict, error)
 
@@ -92,6 +110,26 @@ func (i *ContractInterface) GetDescription() string {
 	return i.Description
 }
 
+// Schema returns the schema of the contract, made of the declarations of its entry points
+func (i *ContractInterface) Schema() *schema.Schema {
+	ret := &schema.Schema{
+		Name:        i.Name,
+		Description: i.Description,
+		Funcs:       make([]schema.FuncDef, 0, len(i.Functions)),
+		State:       i.State,
+	}
+	for _, f := range i.Functions {
+		ret.Funcs = append(ret.Funcs, schema.FuncDef{
+			Name:    f.Name,
+			View:    f.IsView(),
+			Params:  f.Params,
+			Results: f.Results,
+		})
+	}
+	ret.SortFuncs()
+	return ret
+}
+
 // Hname caches the value
 func (i *ContractInterface) Hname() coretypes.Hname {
 	if i.hname == 0 {
@@ -26,6 +26,12 @@ const FuncInit = "init"
 // EntryPointInit is a hashed name of the init function
 var EntryPointInit = Hn(FuncInit)
 
+// FuncMigrate is a name of the optional function called when the contract is upgraded to a new program
+const FuncMigrate = "migrate"
+
+// EntryPointMigrate is a hashed name of the migrate function
+var EntryPointMigrate = Hn(FuncMigrate)
+
 // NewHnameFromBytes constructor, unmarshalling
 func NewHnameFromBytes(data []byte) (ret Hname, err error) {
 	err = ret.Read(bytes.NewReader(data))
@@ -27,6 +27,8 @@ type EntryPoint interface {
 
 var ErrWrongTypeEntryPoint = fmt.Errorf("wrong type of entry point")
 
+var ErrEntryPointNotFound = fmt.Errorf("entry point not found")
+
 // nilEntryPoint is the entry point implementation which does nothing when called
 type nilEntryPoint bool
 
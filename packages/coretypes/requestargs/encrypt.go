@@ -0,0 +1,167 @@
+package requestargs
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/util"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/encrypt/ecies"
+)
+
+// encryptedArgsKey is the only key of encrypted RequestArgs. Its value is the envelope
+const encryptedArgsKey = kv.Key("#")
+
+// Recipients are the public keys the symmetric key of encrypted arguments is wrapped to.
+// Keys[i] is the public key share of the committee member with the DKShare index i
+type Recipients struct {
+	Group kyber.Group
+	Keys  []kyber.Point
+}
+
+// Decrypter recovers the symmetric key of encrypted arguments from the keys wrapped
+// to each recipient. It is implemented by tcrypto.DKShare
+type Decrypter interface {
+	DecryptKey(wrappedKeys [][]byte) ([]byte, error)
+}
+
+// Encrypt returns RequestArgs with the arguments encrypted with a random symmetric key,
+// which is wrapped to each recipient. The arguments are encrypted as they are, i.e. blob
+// references remain blob references after decryption
+func (a RequestArgs) Encrypt(rcpt *Recipients) (RequestArgs, error) {
+	if rcpt == nil || len(rcpt.Keys) == 0 {
+		return nil, fmt.Errorf("no recipients to encrypt request arguments to")
+	}
+	if a.IsEncrypted() {
+		return nil, fmt.Errorf("request arguments are already encrypted")
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := util.WriteUint16(&buf, uint16(len(rcpt.Keys))); err != nil {
+		return nil, err
+	}
+	for _, pub := range rcpt.Keys {
+		wrapped, err := ecies.Encrypt(rcpt.Group, pub, key, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := util.WriteBytes16(&buf, wrapped); err != nil {
+			return nil, err
+		}
+	}
+	var plain bytes.Buffer
+	if err := a.Write(&plain); err != nil {
+		return nil, err
+	}
+	sealed, err := seal(key, plain.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if err := util.WriteBytes32(&buf, sealed); err != nil {
+		return nil, err
+	}
+	ret := New(nil)
+	ret[encryptedArgsKey] = buf.Bytes()
+	return ret, nil
+}
+
+// IsEncrypted returns true if the arguments were encrypted by Encrypt
+func (a RequestArgs) IsEncrypted() bool {
+	_, ok := a[encryptedArgsKey]
+	return ok && len(a) == 1
+}
+
+// Decrypt returns the original arguments of encrypted RequestArgs
+func (a RequestArgs) Decrypt(dec Decrypter) (RequestArgs, error) {
+	if !a.IsEncrypted() {
+		return nil, fmt.Errorf("request arguments are not encrypted")
+	}
+	r := bytes.NewReader(a[encryptedArgsKey])
+	var n uint16
+	if err := util.ReadUint16(r, &n); err != nil {
+		return nil, err
+	}
+	wrappedKeys := make([][]byte, n)
+	for i := range wrappedKeys {
+		var err error
+		if wrappedKeys[i], err = util.ReadBytes16(r); err != nil {
+			return nil, err
+		}
+	}
+	sealed, err := util.ReadBytes32(r)
+	if err != nil {
+		return nil, err
+	}
+	key, err := dec.DecryptKey(wrappedKeys)
+	if err != nil {
+		return nil, fmt.Errorf("can't decrypt the key of request arguments: %v", err)
+	}
+	plain, err := open(key, sealed)
+	if err != nil {
+		return nil, fmt.Errorf("can't decrypt request arguments: %v", err)
+	}
+	ret := New(nil)
+	if err := ret.Read(bytes.NewReader(plain)); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+type decrypter struct {
+	group   kyber.Group
+	index   uint16
+	private kyber.Scalar
+}
+
+// NewDecrypter returns the Decrypter of the recipient with the index and private key
+func NewDecrypter(group kyber.Group, index uint16, private kyber.Scalar) Decrypter {
+	return &decrypter{group, index, private}
+}
+
+func (d *decrypter) DecryptKey(wrappedKeys [][]byte) ([]byte, error) {
+	if int(d.index) >= len(wrappedKeys) {
+		return nil, fmt.Errorf("no key wrapped to recipient #%d", d.index)
+	}
+	return ecies.Decrypt(d.group, d.private, wrappedKeys[d.index], nil)
+}
+
+// seal encrypts with AES-256-GCM. The random nonce is prepended to the ciphertext
+func seal(key, plain []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce := sealed[:aead.NonceSize()]
+	return aead.Open(nil, nonce, sealed[aead.NonceSize():], nil)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
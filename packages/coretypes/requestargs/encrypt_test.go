@@ -0,0 +1,68 @@
+package requestargs
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestEncryptedArgs(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	const n = 4
+	private := make([]kyber.Scalar, n)
+	rcpt := &Recipients{Group: suite, Keys: make([]kyber.Point, n)}
+	for i := range private {
+		private[i] = suite.Scalar().Pick(random.New())
+		rcpt.Keys[i] = suite.Point().Mul(private[i], nil)
+	}
+
+	args := New(nil)
+	args.AddEncodeSimple("secret", []byte("data1"))
+	h := args.AddAsBlobRef("blob", []byte("data2"))
+
+	encrypted, err := args.Encrypt(rcpt)
+	require.NoError(t, err)
+	require.True(t, encrypted.IsEncrypted())
+	require.False(t, args.IsEncrypted())
+	require.Len(t, encrypted, 1)
+
+	for i := range private {
+		back, err := encrypted.Decrypt(NewDecrypter(suite, uint16(i), private[i]))
+		require.NoError(t, err)
+		require.EqualValues(t, args, back)
+	}
+
+	// key of another recipient
+	_, err = encrypted.Decrypt(NewDecrypter(suite, 0, private[1]))
+	require.Error(t, err)
+	// not a recipient
+	_, err = encrypted.Decrypt(NewDecrypter(suite, n, private[0]))
+	require.Error(t, err)
+
+	_, err = encrypted.Encrypt(rcpt)
+	require.Error(t, err)
+	_, err = args.Encrypt(&Recipients{Group: suite})
+	require.Error(t, err)
+
+	log := testutil.NewLogger(t)
+	reg := registry.NewRegistry(nil, log, dbprovider.NewInMemoryDBProvider(log))
+	_, err = reg.PutBlob([]byte("data2"))
+	require.NoError(t, err)
+	_, _, err = encrypted.SolidifyRequestArguments(reg)
+	require.Error(t, err)
+
+	back, err := encrypted.Decrypt(NewDecrypter(suite, 2, private[2]))
+	require.NoError(t, err)
+	solid, ok, err := back.SolidifyRequestArguments(reg)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, "data1", solid.MustGet("secret"))
+	require.EqualValues(t, "data2", solid.MustGet("blob"))
+	require.EqualValues(t, h[:], back["*blob"])
+}
@@ -109,6 +109,7 @@ func (a RequestArgs) Read(r io.Reader) error {
 // each value treated according to the value of the first byte:
 //  - if the value is '*' the data is a content reference. First 32 bytes always treated as data hash.
 //    The rest (if any) is a content address. It will be treated by a downloader
+//  - if the value is '#' the arguments are encrypted and must be decrypted first
 //  - otherwise it is a raw data
 func (a RequestArgs) SolidifyRequestArguments(reg coretypes.BlobCache) (dict.Dict, bool, error) {
 	ret := dict.New()
@@ -123,6 +124,10 @@ func (a RequestArgs) SolidifyRequestArguments(reg coretypes.BlobCache) (dict.Dic
 			err = fmt.Errorf("wrong request argument key '%s'", key)
 			return false
 		}
+		if d[0] == '#' {
+			err = fmt.Errorf("encrypted request arguments must be decrypted first")
+			return false
+		}
 		if d[0] != '*' {
 			ret.Set(kv.Key(d[1:]), value)
 			return true
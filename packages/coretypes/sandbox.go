@@ -28,6 +28,13 @@ type Sandbox interface {
 	State() kv.KVStore
 	// DeployContract deploys contract on the same chain. 'initParams' are passed to the 'init' entry point
 	DeployContract(programHash hashing.HashValue, name string, description string, initParams dict.Dict) error
+	// RemoveProcessor removes the processor of the program from the processor cache of the node.
+	// It is loaded again when it is needed. Only the 'root' contract can call it
+	RemoveProcessor(programHash hashing.HashValue)
+	// SweepContract moves all tokens of the on-chain account of the contract to the 'target' account
+	// and, if 'eraseState' is true, deletes the state of the contract. Returns the moved tokens.
+	// Only the 'root' contract can call it
+	SweepContract(contract Hname, target AgentID, eraseState bool) ColoredBalances
 	// Call calls the entry point of the contract with parameters and transfer.
 	// If the entry point is full entry point, transfer tokens are moved between caller's and
 	// target contract's accounts (if enough). If the entry point is view, 'transfer' has no effect
@@ -55,6 +62,10 @@ type Sandbox interface {
 	TransferToAddress(addr address.Address, transfer ColoredBalances) bool
 	// PostRequest sends cross-chain request
 	PostRequest(par PostRequestParams) bool
+	// GasBurn charges gas from the budget of the current request. Panics when the budget is exhausted
+	GasBurn(gas uint64)
+	// GasRemaining returns how much gas is left in the budget of the current request
+	GasRemaining() uint64
 	// Log interface provides local logging on the machine. It also includes Panicf methods which logs and panics
 	Log() LogInterface
 	// Event publishes "vmmsg" message through Publisher on nanomsg. It also logs locally, but it is not the same thing
@@ -67,7 +78,8 @@ type Sandbox interface {
 type PostRequestParams struct {
 	TargetContractID ContractID
 	EntryPoint       Hname
-	TimeLock         uint32
+	TimeLock         int64
+	GasBudget        uint64 // 0 means default gas budget
 	Params           dict.Dict
 	Transfer         ColoredBalances
 }
@@ -0,0 +1,197 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// package schema describes the interface of a smart contract: entry points with typed
+// parameters and results, and the layout of the contract state.
+// The schema is published on-chain together with the contract, so clients can encode
+// arguments and decode results without hard-coding the keys
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+)
+
+// kinds of state variables
+const (
+	KindVar   = "var"
+	KindMap   = "map"
+	KindArray = "array"
+	KindTlog  = "tlog"
+)
+
+// Schema is the descriptor of the contract interface
+type Schema struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Funcs       []FuncDef  `json:"funcs"`
+	State       []StateDef `json:"state,omitempty"`
+}
+
+// FuncDef describes an entry point
+type FuncDef struct {
+	Name    string     `json:"name"`
+	View    bool       `json:"view,omitempty"`
+	Params  []FieldDef `json:"params,omitempty"`
+	Results []FieldDef `json:"results,omitempty"`
+}
+
+// FieldDef describes a parameter or a result of an entry point
+type FieldDef struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// StateDef describes a variable in the state of the contract.
+// Key is the type of the keys of a map, Type is the type of the values
+type StateDef struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Key  string `json:"key,omitempty"`
+	Type string `json:"type"`
+}
+
+// Field declares a mandatory parameter or result
+func Field(name string, typ string) FieldDef {
+	return FieldDef{Name: name, Type: typ}
+}
+
+// OptionalField declares a parameter or result which may be omitted
+func OptionalField(name string, typ string) FieldDef {
+	return FieldDef{Name: name, Type: typ, Optional: true}
+}
+
+// Parse decodes the JSON representation of the schema and validates it
+func Parse(data []byte) (*Schema, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	ret := &Schema{}
+	if err := dec.Decode(ret); err != nil {
+		return nil, fmt.Errorf("schema: %v", err)
+	}
+	if err := ret.Validate(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Bytes is the JSON representation of the schema, the form in which it is stored on-chain
+func (s *Schema) Bytes() []byte {
+	ret, err := json.Marshal(s)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// Validate checks that names are unique and types and kinds are known
+func (s *Schema) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("schema: missing contract name")
+	}
+	hnames := make(map[coretypes.Hname]string)
+	for i := range s.Funcs {
+		f := &s.Funcs[i]
+		if f.Name == "" {
+			return fmt.Errorf("schema: missing name of function #%d", i)
+		}
+		hname := coretypes.Hn(f.Name)
+		if prev, ok := hnames[hname]; ok {
+			if prev == f.Name {
+				return fmt.Errorf("schema: duplicate function '%s'", f.Name)
+			}
+			return fmt.Errorf("schema: functions '%s' and '%s' have the same hname %s", prev, f.Name, hname.String())
+		}
+		hnames[hname] = f.Name
+		if err := validateFields(f.Params); err != nil {
+			return fmt.Errorf("schema: params of '%s': %v", f.Name, err)
+		}
+		if err := validateFields(f.Results); err != nil {
+			return fmt.Errorf("schema: results of '%s': %v", f.Name, err)
+		}
+	}
+	names := make(map[string]bool)
+	for i := range s.State {
+		v := &s.State[i]
+		if v.Name == "" {
+			return fmt.Errorf("schema: missing name of state variable #%d", i)
+		}
+		if names[v.Name] {
+			return fmt.Errorf("schema: duplicate state variable '%s'", v.Name)
+		}
+		names[v.Name] = true
+		switch v.Kind {
+		case KindMap:
+			if !IsType(v.Key) {
+				return fmt.Errorf("schema: state variable '%s': unknown key type '%s'", v.Name, v.Key)
+			}
+		case KindVar, KindArray, KindTlog:
+			if v.Key != "" {
+				return fmt.Errorf("schema: state variable '%s': key type is only allowed for maps", v.Name)
+			}
+		default:
+			return fmt.Errorf("schema: state variable '%s': unknown kind '%s'", v.Name, v.Kind)
+		}
+		if !IsType(v.Type) {
+			return fmt.Errorf("schema: state variable '%s': unknown type '%s'", v.Name, v.Type)
+		}
+	}
+	return nil
+}
+
+func validateFields(fields []FieldDef) error {
+	names := make(map[string]bool)
+	for i := range fields {
+		f := &fields[i]
+		if f.Name == "" {
+			return fmt.Errorf("missing name of field #%d", i)
+		}
+		if names[f.Name] {
+			return fmt.Errorf("duplicate field '%s'", f.Name)
+		}
+		names[f.Name] = true
+		if !IsType(f.Type) {
+			return fmt.Errorf("field '%s': unknown type '%s'", f.Name, f.Type)
+		}
+	}
+	return nil
+}
+
+// Func returns the descriptor of the entry point
+func (s *Schema) Func(name string) (*FuncDef, bool) {
+	for i := range s.Funcs {
+		if s.Funcs[i].Name == name {
+			return &s.Funcs[i], true
+		}
+	}
+	return nil, false
+}
+
+// CheckEntryPoints checks the schema against the entry points the program really has.
+// The entry points are given as a map of name to view flag. Every function of the schema
+// must exist with the same view flag. Entry points may be left out of the schema
+func (s *Schema) CheckEntryPoints(entryPoints map[string]bool) error {
+	for i := range s.Funcs {
+		f := &s.Funcs[i]
+		view, ok := entryPoints[f.Name]
+		if !ok {
+			return fmt.Errorf("schema: function '%s' is not an entry point of the program", f.Name)
+		}
+		if view != f.View {
+			return fmt.Errorf("schema: function '%s': view flag doesn't match the program", f.Name)
+		}
+	}
+	return nil
+}
+
+// SortFuncs sorts the functions by name, so the schema has a deterministic encoding
+func (s *Schema) SortFuncs() {
+	sort.Slice(s.Funcs, func(i, j int) bool {
+		return s.Funcs[i].Name < s.Funcs[j].Name
+	})
+}
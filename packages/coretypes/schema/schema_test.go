@@ -0,0 +1,144 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+	"name": "test",
+	"funcs": [
+		{"name": "set", "params": [{"name": "n", "type": "int"}, {"name": "s", "type": "string", "optional": true}]},
+		{"name": "get", "view": true, "results": [{"name": "n", "type": "int"}]}
+	],
+	"state": [
+		{"name": "n", "kind": "var", "type": "int"},
+		{"name": "owners", "kind": "map", "key": "agentid", "type": "int"},
+		{"name": "log", "kind": "tlog", "type": "bytes"}
+	]
+}`
+
+func TestParse(t *testing.T) {
+	s, err := Parse([]byte(testSchema))
+	require.NoError(t, err)
+	require.EqualValues(t, "test", s.Name)
+	require.Len(t, s.Funcs, 2)
+	require.Len(t, s.State, 3)
+
+	f, ok := s.Func("get")
+	require.True(t, ok)
+	require.True(t, f.View)
+	_, ok = s.Func("nope")
+	require.False(t, ok)
+
+	s2, err := Parse(s.Bytes())
+	require.NoError(t, err)
+	require.EqualValues(t, s, s2)
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, tc := range []struct{ data, msg string }{
+		{`{"name": "test", "funcs": [`, "unexpected EOF"},
+		{`{"name": "test", "foo": 1}`, "unknown field"},
+		{`{"funcs": []}`, "missing contract name"},
+		{`{"name": "test", "funcs": [{"name": "a"}, {"name": "a"}]}`, "duplicate function 'a'"},
+		{`{"name": "test", "funcs": [{"name": "a", "params": [{"name": "x", "type": "u8"}]}]}`, "unknown type 'u8'"},
+		{`{"name": "test", "funcs": [{"name": "a", "results": [{"name": "x", "type": "int"}, {"name": "x", "type": "int"}]}]}`, "duplicate field 'x'"},
+		{`{"name": "test", "funcs": [], "state": [{"name": "v", "kind": "set", "type": "int"}]}`, "unknown kind 'set'"},
+		{`{"name": "test", "funcs": [], "state": [{"name": "v", "kind": "map", "type": "int"}]}`, "unknown key type"},
+		{`{"name": "test", "funcs": [], "state": [{"name": "v", "kind": "array", "key": "int", "type": "int"}]}`, "only allowed for maps"},
+	} {
+		_, err := Parse([]byte(tc.data))
+		require.Error(t, err, tc.data)
+		require.Contains(t, err.Error(), tc.msg, tc.data)
+	}
+}
+
+func TestValues(t *testing.T) {
+	contractID := coretypes.NewContractID(coretypes.ChainID{1, 2, 3}, coretypes.Hn("test"))
+	agentID := coretypes.NewAgentIDFromContractID(contractID)
+	for typ, value := range map[string]string{
+		TypeInt:        "-42",
+		TypeString:     "hello",
+		TypeBytes:      "3mJr7AoUXx2Wqd",
+		TypeHname:      coretypes.Hn("test").String(),
+		TypeHash:       hashing.HashStrings("test").String(),
+		TypeAgentID:    agentID.String(),
+		TypeColor:      balance.ColorIOTA.String(),
+		TypeChainID:    coretypes.ChainID{1, 2, 3}.String(),
+		TypeContractID: contractID.String(),
+	} {
+		b, err := EncodeValue(typ, value)
+		require.NoError(t, err, typ)
+		s, err := DecodeValue(typ, b)
+		require.NoError(t, err, typ)
+		require.EqualValues(t, value, s, typ)
+	}
+	b, err := EncodeValue(TypeInt, "42")
+	require.NoError(t, err)
+	require.EqualValues(t, codec.EncodeInt64(42), b)
+
+	_, err = EncodeValue(TypeInt, "abc")
+	require.Error(t, err)
+	_, err = EncodeValue("u8", "1")
+	require.Error(t, err)
+}
+
+func TestEncodeParams(t *testing.T) {
+	s, err := Parse([]byte(testSchema))
+	require.NoError(t, err)
+	f, _ := s.Func("set")
+
+	d, err := f.EncodeParams(map[string]string{"n": "7"})
+	require.NoError(t, err)
+	require.EqualValues(t, codec.EncodeInt64(7), d.MustGet("n"))
+	require.False(t, d.MustHas("s"))
+
+	d, err = f.EncodeParams(map[string]string{"n": "7", "s": "x"})
+	require.NoError(t, err)
+	require.EqualValues(t, []byte("x"), d.MustGet("s"))
+
+	_, err = f.EncodeParams(map[string]string{"s": "x"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing parameter 'n'")
+
+	_, err = f.EncodeParams(map[string]string{"n": "7", "z": "x"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown parameter 'z'")
+}
+
+func TestDecodeResults(t *testing.T) {
+	s, err := Parse([]byte(testSchema))
+	require.NoError(t, err)
+	f, _ := s.Func("get")
+
+	res := dict.New()
+	res.Set("n", codec.EncodeInt64(3))
+	res.Set("other", []byte{1, 2})
+	results, err := f.DecodeResults(res)
+	require.NoError(t, err)
+	require.EqualValues(t, map[string]string{"n": "3", "other": "5T"}, results)
+
+	res.Set("n", []byte{1})
+	_, err = f.DecodeResults(res)
+	require.Error(t, err)
+}
+
+func TestCheckEntryPoints(t *testing.T) {
+	s, err := Parse([]byte(testSchema))
+	require.NoError(t, err)
+
+	require.NoError(t, s.CheckEntryPoints(map[string]bool{"set": false, "get": true, "init": false}))
+	err = s.CheckEntryPoints(map[string]bool{"set": false})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "'get' is not an entry point")
+	err = s.CheckEntryPoints(map[string]bool{"set": false, "get": false})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "view flag")
+}
@@ -0,0 +1,240 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/mr-tron/base58"
+)
+
+// types of values. Values are encoded with the codec package
+const (
+	TypeInt        = "int"
+	TypeString     = "string"
+	TypeBytes      = "bytes"
+	TypeHname      = "hname"
+	TypeHash       = "hash"
+	TypeAddress    = "address"
+	TypeAgentID    = "agentid"
+	TypeColor      = "color"
+	TypeChainID    = "chainid"
+	TypeContractID = "contractid"
+	TypeRequestID  = "requestid"
+)
+
+var types = map[string]bool{
+	TypeInt:        true,
+	TypeString:     true,
+	TypeBytes:      true,
+	TypeHname:      true,
+	TypeHash:       true,
+	TypeAddress:    true,
+	TypeAgentID:    true,
+	TypeColor:      true,
+	TypeChainID:    true,
+	TypeContractID: true,
+	TypeRequestID:  true,
+}
+
+// IsType checks if the type is one of the known types
+func IsType(typ string) bool {
+	return types[typ]
+}
+
+// EncodeValue converts the human readable representation of the value of the type to bytes
+func EncodeValue(typ string, s string) ([]byte, error) {
+	switch typ {
+	case TypeInt:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeInt64(n), nil
+	case TypeString:
+		return codec.EncodeString(s), nil
+	case TypeBytes:
+		return base58.Decode(s)
+	case TypeHname:
+		hname, err := coretypes.HnameFromString(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeHname(hname), nil
+	case TypeHash:
+		h, err := hashing.HashValueFromBase58(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeHashValue(h), nil
+	case TypeAddress:
+		addr, err := address.FromBase58(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeAddress(addr), nil
+	case TypeAgentID:
+		agentID, err := coretypes.NewAgentIDFromString(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeAgentID(agentID), nil
+	case TypeColor:
+		col, err := util.ColorFromString(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeColor(col), nil
+	case TypeChainID:
+		chainID, err := coretypes.NewChainIDFromBase58(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeChainID(chainID), nil
+	case TypeContractID:
+		contractID, err := coretypes.NewContractIDFromString(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeContractID(contractID), nil
+	case TypeRequestID:
+		reqID, err := coretypes.NewRequestIDFromBase58(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeRequestID(reqID), nil
+	}
+	return nil, fmt.Errorf("unknown type '%s'", typ)
+}
+
+// DecodeValue converts the bytes of the value of the type to the human readable representation.
+// It is the inverse of EncodeValue
+func DecodeValue(typ string, b []byte) (string, error) {
+	switch typ {
+	case TypeInt:
+		n, _, err := codec.DecodeInt64(b)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(n, 10), nil
+	case TypeString:
+		s, _, err := codec.DecodeString(b)
+		return s, err
+	case TypeBytes:
+		return base58.Encode(b), nil
+	case TypeHname:
+		hname, _, err := codec.DecodeHname(b)
+		if err != nil {
+			return "", err
+		}
+		return hname.String(), nil
+	case TypeHash:
+		h, _, err := codec.DecodeHashValue(b)
+		if err != nil {
+			return "", err
+		}
+		return h.String(), nil
+	case TypeAddress:
+		addr, _, err := codec.DecodeAddress(b)
+		if err != nil {
+			return "", err
+		}
+		return addr.String(), nil
+	case TypeAgentID:
+		agentID, _, err := codec.DecodeAgentID(b)
+		if err != nil {
+			return "", err
+		}
+		return agentID.String(), nil
+	case TypeColor:
+		col, _, err := codec.DecodeColor(b)
+		if err != nil {
+			return "", err
+		}
+		return col.String(), nil
+	case TypeChainID:
+		chainID, _, err := codec.DecodeChainID(b)
+		if err != nil {
+			return "", err
+		}
+		return chainID.String(), nil
+	case TypeContractID:
+		contractID, _, err := codec.DecodeContractID(b)
+		if err != nil {
+			return "", err
+		}
+		return contractID.String(), nil
+	case TypeRequestID:
+		reqID, _, err := codec.DecodeRequestID(b)
+		if err != nil {
+			return "", err
+		}
+		return reqID.Base58(), nil
+	}
+	return "", fmt.Errorf("unknown type '%s'", typ)
+}
+
+// EncodeParams encodes the arguments of the call, given as name -> human readable value.
+// Unknown and missing mandatory parameters are errors
+func (f *FuncDef) EncodeParams(args map[string]string) (dict.Dict, error) {
+	ret := dict.New()
+	for _, p := range f.Params {
+		s, ok := args[p.Name]
+		if !ok {
+			if p.Optional {
+				continue
+			}
+			return nil, fmt.Errorf("'%s': missing parameter '%s'", f.Name, p.Name)
+		}
+		b, err := EncodeValue(p.Type, s)
+		if err != nil {
+			return nil, fmt.Errorf("'%s': parameter '%s': %v", f.Name, p.Name, err)
+		}
+		ret.Set(kv.Key(p.Name), b)
+	}
+	for name := range args {
+		if _, ok := f.param(name); !ok {
+			return nil, fmt.Errorf("'%s': unknown parameter '%s'", f.Name, name)
+		}
+	}
+	return ret, nil
+}
+
+// DecodeResults converts the results of the call to name -> human readable value.
+// Results not described by the schema are returned as base58 encoded bytes
+func (f *FuncDef) DecodeResults(results dict.Dict) (map[string]string, error) {
+	ret := make(map[string]string)
+	for k, v := range results {
+		typ := TypeBytes
+		for _, r := range f.Results {
+			if r.Name == string(k) {
+				typ = r.Type
+				break
+			}
+		}
+		s, err := DecodeValue(typ, v)
+		if err != nil {
+			return nil, fmt.Errorf("'%s': result '%s': %v", f.Name, string(k), err)
+		}
+		ret[string(k)] = s
+	}
+	return ret, nil
+}
+
+func (f *FuncDef) param(name string) (*FieldDef, bool) {
+	for i := range f.Params {
+		if f.Params[i].Name == name {
+			return &f.Params[i], true
+		}
+	}
+	return nil, false
+}
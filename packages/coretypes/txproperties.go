@@ -19,6 +19,9 @@ type SCTransactionProperties interface {
 	IsOrigin() bool
 	// chain ID of the state section or panic if not a state transaction
 	MustChainID() *ChainID
+	// address of the chain token output or panic if not a state transaction.
+	// It differs from the chain ID if the chain was moved to another committee
+	MustChainAddress() *address.Address
 	// color of the state section or panic if not a state transaction
 	MustStateColor() *balance.Color
 	// number of minted tokens which are not request tokens
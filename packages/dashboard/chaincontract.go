@@ -105,6 +105,7 @@ const tplChainContract = `
 				<dt>Description</dt><dd><tt>{{trim 50 $c.Description}}</tt></dd>
 				<dt>Program hash</dt><dd><tt>{{$c.ProgramHash.String}}</tt></dd>
 				{{if $c.HasCreator}}<dt>Creator</dt><dd>{{ template "agentid" (args $chainid $c.Creator) }}</dd>{{end}}
+				{{if $c.Deactivated}}<dt>Status</dt><dd>deactivated</dd>{{end}}
 				<dt>Owner fee</dt><dd>
 					{{- if $c.OwnerFee -}}
 						<tt>{{- $c.OwnerFee }} {{ $rootinfo.FeeColor -}}</tt>
@@ -119,6 +120,12 @@ const tplChainContract = `
 						<tt>{{- $rootinfo.DefaultValidatorFee }} {{ $rootinfo.FeeColor }}</tt> (chain default)
 					{{- end -}}
 				</dd>
+				{{if $c.Exports}}<dt>Entry points</dt><dd>
+					{{- range $i, $e := $c.Exports -}}
+						{{- if $i }}, {{ end -}}
+						<tt>{{- $e.Name -}}</tt>{{- if $e.View }} (view){{ end -}}
+					{{- end -}}
+				</dd>{{end}}
 			</dl>
 		</div>
 
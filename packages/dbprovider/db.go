@@ -65,6 +65,19 @@ func (dbp *DBProvider) GetRegistryPartition() kvstore.KVStore {
 	return dbp.GetPartition(&coretypes.NilChainID)
 }
 
+// Batched returns the batch of mutations of the whole database. It is committed atomically,
+// so it can update several partitions at once. The keys are made with PartitionKey
+func (dbp *DBProvider) Batched() kvstore.BatchedMutations {
+	return dbp.store.Batched()
+}
+
+// PartitionKey returns the key in the whole database of the key in the partition of the chain
+func PartitionKey(chainID *coretypes.ChainID, key []byte) []byte {
+	ret := make([]byte, 0, len(chainID)+len(key))
+	ret = append(ret, chainID[:]...)
+	return append(ret, key...)
+}
+
 func (dbp *DBProvider) Close() {
 	dbp.log.Infof("Syncing database to disk...")
 	if err := dbp.db.Close(); err != nil {
@@ -15,6 +15,15 @@ const (
 	ObjectTypeNodeIdentity
 	ObjectTypeBlobCache
 	ObjectTypeBlobCacheTTL
+	ObjectTypeStateReverseDelta
+	ObjectTypeAPIKey
+	ObjectTypeAuthorizedKey
+	ObjectTypeNodeCertificate
+	ObjectTypeTrustedPeer
+	ObjectTypeAnchorTransaction
+	ObjectTypeFirstBlockIndex
+	ObjectTypePruningPolicy
+	ObjectTypeMerkleNode
 )
 
 // MakeKey makes key within the partition. It consists to one byte for object type
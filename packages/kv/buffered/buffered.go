@@ -122,6 +122,10 @@ func (b *bufferedKVStore) Del(key kv.Key) {
 	b.mutations.Add(NewMutationDel(key))
 }
 
+func (b *bufferedKVStore) DelPrefix(prefix kv.Key) {
+	b.mutations.Add(NewMutationDelPrefix(prefix))
+}
+
 func (b *bufferedKVStore) Get(key kv.Key) ([]byte, error) {
 	mut := b.mutations.Latest(key)
 	if mut != nil {
@@ -152,14 +156,13 @@ func (b *bufferedKVStore) MustHas(key kv.Key) bool {
 }
 
 func (b *bufferedKVStore) Iterate(prefix kv.Key, f func(key kv.Key, value []byte) bool) error {
-	seen, done := b.mutations.IterateValues(prefix, f)
-	if done {
+	if b.mutations.IterateValues(prefix, f) {
 		return nil
 	}
 	return b.db.Iterate([]byte(prefix), func(key kvstore.Key, value kvstore.Value) bool {
 		k := kv.Key(key)
-		_, ok := seen[k]
-		if ok {
+		if b.mutations.Latest(k) != nil {
+			// already visited or deleted
 			return true
 		}
 		return f(k, value)
@@ -171,16 +174,15 @@ func (b *bufferedKVStore) MustIterate(prefix kv.Key, f func(key kv.Key, value []
 }
 
 func (b *bufferedKVStore) IterateKeys(prefix kv.Key, f func(key kv.Key) bool) error {
-	seen, done := b.mutations.IterateValues(prefix, func(key kv.Key, value []byte) bool {
+	if b.mutations.IterateValues(prefix, func(key kv.Key, value []byte) bool {
 		return f(key)
-	})
-	if done {
+	}) {
 		return nil
 	}
 	return b.db.IterateKeys([]byte(prefix), func(key kvstore.Key) bool {
 		k := kv.Key(key)
-		_, ok := seen[k]
-		if ok {
+		if b.mutations.Latest(k) != nil {
+			// already visited or deleted
 			return true
 		}
 		return f(k)
@@ -79,3 +79,41 @@ func TestBufferedKVStore(t *testing.T) {
 		m,
 	)
 }
+
+func TestBufferedKVStoreDelPrefix(t *testing.T) {
+	db := mapdb.NewMapDB()
+	_ = db.Set([]byte("a1"), []byte("v1"))
+	_ = db.Set([]byte("a2"), []byte("v2"))
+	_ = db.Set([]byte("b1"), []byte("v3"))
+
+	b := NewBufferedKVStore(db)
+	b.Set("a3", []byte("v4"))
+	b.DelPrefix("a")
+	b.Set("a2", []byte("v5"))
+
+	assert.Nil(t, b.MustGet("a1"))
+	assert.Nil(t, b.MustGet("a3"))
+	assert.False(t, b.MustHas("a1"))
+	assert.Equal(t, []byte("v5"), b.MustGet("a2"))
+	assert.Equal(t, []byte("v3"), b.MustGet("b1"))
+
+	keys := make(map[kv.Key][]byte)
+	b.MustIterate(kv.EmptyPrefix, func(key kv.Key, value []byte) bool {
+		keys[key] = value
+		return true
+	})
+	assert.EqualValues(t, map[kv.Key][]byte{
+		"a2": []byte("v5"),
+		"b1": []byte("v3"),
+	}, keys)
+
+	n := 0
+	b.MustIterateKeys("a", func(key kv.Key) bool {
+		assert.Equal(t, kv.Key("a2"), key)
+		n++
+		return true
+	})
+	assert.Equal(t, 1, n)
+
+	assert.EqualValues(t, keys, b.DangerouslyDumpToDict())
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/iotaledger/wasp/packages/util"
 )
 
-// Mutation represents a single "set" or "del" operation over a KVStore
+// Mutation represents a single "set", "del" or "delprefix" operation over a KVStore
 type Mutation interface {
 	Read(io.Reader) error
 	Write(io.Writer) error
@@ -17,7 +17,7 @@ type Mutation interface {
 
 	ApplyTo(w kv.KVStoreWriter)
 
-	// Key returns the key that is mutated
+	// Key returns the key that is mutated (the prefix in case of "delprefix")
 	Key() kv.Key
 	// Value returns the value after the mutation (nil if deleted)
 	Value() []byte
@@ -39,9 +39,12 @@ type MutationSequence interface {
 	Iterate(func(mut Mutation) bool)
 	// Iterate over the latest mutation recorded for each key
 	IterateLatest(func(key kv.Key, mut Mutation) bool)
-	// Iterate over the latest value recorded for each non-deleted key
-	IterateValues(prefix kv.Key, f func(key kv.Key, value []byte) bool) (map[kv.Key]bool, bool)
+	// Iterate over the latest value recorded for each non-deleted key. Returns true if interrupted by f
+	IterateValues(prefix kv.Key, f func(key kv.Key, value []byte) bool) bool
+	// Iterate over all prefixes deleted with a "delprefix" mutation
+	IterateDelPrefixes(func(prefix kv.Key) bool)
 
+	// Latest returns the latest mutation affecting the key, or nil if the key was not mutated
 	Latest(key kv.Key) Mutation
 
 	Add(mut Mutation)
@@ -52,11 +55,26 @@ type MutationSequence interface {
 const (
 	mutationMagicSet = iota
 	mutationMagicDel
+	mutationMagicDelPrefix
 )
 
+// MutationKind returns the name of the operation of the mutation, e.g. "set" or "del"
+func MutationKind(mut Mutation) string {
+	switch mut.getMagic() {
+	case mutationMagicSet:
+		return "set"
+	case mutationMagicDel:
+		return "del"
+	case mutationMagicDelPrefix:
+		return "delprefix"
+	}
+	return fmt.Sprintf("unknown(%d)", mut.getMagic())
+}
+
 type mutationSequence struct {
 	muts        []Mutation
 	latestByKey map[kv.Key]*Mutation
+	delPrefixes []kv.Key
 }
 
 func NewMutationSequence() MutationSequence {
@@ -131,19 +149,25 @@ func (ms *mutationSequence) IterateLatest(f func(kv.Key, Mutation) bool) {
 	}
 }
 
-func (ms *mutationSequence) IterateValues(prefix kv.Key, f func(key kv.Key, value []byte) bool) (map[kv.Key]bool, bool) {
-	seen := make(map[kv.Key]bool)
+func (ms *mutationSequence) IterateValues(prefix kv.Key, f func(key kv.Key, value []byte) bool) bool {
 	for key, mut := range ms.latestByKey {
 		if !key.HasPrefix(prefix) {
 			continue
 		}
-		seen[key] = true
 		v := (*mut).Value()
 		if v != nil && !f(key, v) {
-			return seen, true
+			return true
+		}
+	}
+	return false
+}
+
+func (ms *mutationSequence) IterateDelPrefixes(f func(prefix kv.Key) bool) {
+	for _, prefix := range ms.delPrefixes {
+		if !f(prefix) {
+			break
 		}
 	}
-	return seen, false
 }
 
 func (ms *mutationSequence) Len() int {
@@ -152,7 +176,19 @@ func (ms *mutationSequence) Len() int {
 
 func (ms *mutationSequence) Add(mut Mutation) {
 	ms.muts = append(ms.muts, mut)
-	ms.latestByKey[mut.Key()] = &mut
+	if mut.getMagic() != mutationMagicDelPrefix {
+		ms.latestByKey[mut.Key()] = &mut
+		return
+	}
+	prefix := mut.Key()
+	ms.delPrefixes = append(ms.delPrefixes, prefix)
+	// keys mutated earlier are shadowed by the prefix deletion
+	for key := range ms.latestByKey {
+		if key.HasPrefix(prefix) {
+			var del Mutation = NewMutationDel(key)
+			ms.latestByKey[key] = &del
+		}
+	}
 }
 
 func (ms *mutationSequence) ApplyTo(w kv.KVStoreWriter) {
@@ -163,10 +199,15 @@ func (ms *mutationSequence) ApplyTo(w kv.KVStoreWriter) {
 
 func (ms *mutationSequence) Latest(key kv.Key) Mutation {
 	mut, ok := ms.latestByKey[key]
-	if !ok {
-		return nil
+	if ok {
+		return *mut
 	}
-	return *mut
+	for _, prefix := range ms.delPrefixes {
+		if key.HasPrefix(prefix) {
+			return NewMutationDel(key)
+		}
+	}
+	return nil
 }
 
 func (ms *mutationSequence) Clone() MutationSequence {
@@ -174,7 +215,11 @@ func (ms *mutationSequence) Clone() MutationSequence {
 	for k, v := range ms.latestByKey {
 		mapClone[k] = v
 	}
-	return &mutationSequence{muts: ms.muts[:], latestByKey: mapClone}
+	return &mutationSequence{
+		muts:        ms.muts[:],
+		latestByKey: mapClone,
+		delPrefixes: append([]kv.Key(nil), ms.delPrefixes...),
+	}
 }
 
 type mutationSet struct {
@@ -186,12 +231,18 @@ type mutationDel struct {
 	k kv.Key
 }
 
+type mutationDelPrefix struct {
+	prefix kv.Key
+}
+
 func newFromMagic(magic int) (Mutation, error) {
 	switch magic {
 	case mutationMagicSet:
 		return &mutationSet{}, nil
 	case mutationMagicDel:
 		return &mutationDel{}, nil
+	case mutationMagicDelPrefix:
+		return &mutationDelPrefix{}, nil
 	}
 	return nil, fmt.Errorf("Unknown mutation magic %d", magic)
 }
@@ -284,3 +335,40 @@ func (m *mutationDel) Value() []byte {
 func (m *mutationDel) ApplyTo(w kv.KVStoreWriter) {
 	w.Del(m.k)
 }
+
+func NewMutationDelPrefix(prefix kv.Key) *mutationDelPrefix {
+	return &mutationDelPrefix{prefix: prefix}
+}
+
+func (m *mutationDelPrefix) getMagic() int {
+	return mutationMagicDelPrefix
+}
+
+func (m *mutationDelPrefix) Write(w io.Writer) error {
+	return util.WriteBytes16(w, []byte(m.prefix))
+}
+
+func (m *mutationDelPrefix) Read(r io.Reader) error {
+	prefix, err := util.ReadBytes16(r)
+	if err != nil {
+		return err
+	}
+	m.prefix = kv.Key(prefix)
+	return nil
+}
+
+func (m *mutationDelPrefix) String() string {
+	return fmt.Sprintf("DELPREFIX %s", m.prefix)
+}
+
+func (m *mutationDelPrefix) Key() kv.Key {
+	return m.prefix
+}
+
+func (m *mutationDelPrefix) Value() []byte {
+	return nil
+}
+
+func (m *mutationDelPrefix) ApplyTo(w kv.KVStoreWriter) {
+	w.DelPrefix(m.prefix)
+}
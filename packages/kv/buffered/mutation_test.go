@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/stretchr/testify/assert"
@@ -30,6 +31,51 @@ func TestApplyMutationDel(t *testing.T) {
 	assert.Nil(t, v)
 }
 
+func TestApplyMutationDelPrefix(t *testing.T) {
+	vars := dict.New()
+	vars.Set("a1", []byte("v1"))
+	vars.Set("a2", []byte("v2"))
+	vars.Set("b1", []byte("v3"))
+
+	mdel := NewMutationDelPrefix("a")
+	mdel.ApplyTo(vars)
+
+	assert.Nil(t, vars.MustGet("a1"))
+	assert.Nil(t, vars.MustGet("a2"))
+	assert.Equal(t, []byte("v3"), vars.MustGet("b1"))
+}
+
+func TestMutationSequenceDelPrefix(t *testing.T) {
+	ms := NewMutationSequence()
+	ms.Add(NewMutationSet("a1", []byte("v1")))
+	ms.Add(NewMutationSet("b1", []byte("v2")))
+	ms.Add(NewMutationDelPrefix("a"))
+	ms.Add(NewMutationSet("a2", []byte("v3")))
+
+	assert.Nil(t, ms.Latest("a1").Value())
+	assert.Nil(t, ms.Latest("a3").Value())
+	assert.Equal(t, []byte("v2"), ms.Latest("b1").Value())
+	assert.Equal(t, []byte("v3"), ms.Latest("a2").Value())
+	assert.Nil(t, ms.Latest("b2"))
+
+	prefixes := make([]kv.Key, 0)
+	ms.IterateDelPrefixes(func(prefix kv.Key) bool {
+		prefixes = append(prefixes, prefix)
+		return true
+	})
+	assert.Equal(t, []kv.Key{"a"}, prefixes)
+
+	var buf bytes.Buffer
+	err := ms.Write(&buf)
+	assert.NoError(t, err)
+
+	ms2 := NewMutationSequence()
+	err = ms2.Read(bytes.NewBuffer(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.EqualValues(t, util.GetHashValue(ms), util.GetHashValue(ms2))
+	assert.Nil(t, ms2.Latest("a1").Value())
+}
+
 func TestEmptyMutationSequence(t *testing.T) {
 	ms1 := NewMutationSequence()
 	ms2 := NewMutationSequence()
@@ -51,3 +97,9 @@ func TestMutationSequenceMarshaling(t *testing.T) {
 
 	assert.EqualValues(t, util.GetHashValue(ms), util.GetHashValue(ms2))
 }
+
+func TestMutationKind(t *testing.T) {
+	assert.Equal(t, "set", MutationKind(NewMutationSet("k1", []byte("v1"))))
+	assert.Equal(t, "del", MutationKind(NewMutationDel("k1")))
+	assert.Equal(t, "delprefix", MutationKind(NewMutationDelPrefix("k")))
+}
@@ -57,6 +57,10 @@ func Encode(v interface{}) []byte {
 		return EncodeAgentID(vt)
 	case coretypes.Hname:
 		return vt.Bytes()
+	case *coretypes.RequestID:
+		return EncodeRequestID(*vt)
+	case coretypes.RequestID:
+		return EncodeRequestID(vt)
 
 	default:
 		panic(fmt.Sprintf("Can't encode value %v", v))
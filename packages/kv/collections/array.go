@@ -54,6 +54,13 @@ func ArraySizeKey(name string) kv.Key {
 	return kv.Key(buf.Bytes())
 }
 
+func (a *ImmutableArray) getElemPrefix() kv.Key {
+	var buf bytes.Buffer
+	buf.Write([]byte(a.name))
+	buf.WriteByte(arrayElemKeyCode)
+	return kv.Key(buf.Bytes())
+}
+
 func (a *ImmutableArray) getElemKey(idx uint16) kv.Key {
 	return ArrayElemKey(a.name, idx)
 }
@@ -155,15 +162,8 @@ func (a *Array) MustExtend(other *ImmutableArray) {
 	}
 }
 
-// TODO implement with DelPrefix
 func (a *Array) Erase() error {
-	n, err := a.Len()
-	if err != nil {
-		return err
-	}
-	for i := uint16(0); i < n; i++ {
-		a.kvw.Del(a.getElemKey(i))
-	}
+	a.kvw.DelPrefix(a.getElemPrefix())
 	a.setSize(0)
 	return nil
 }
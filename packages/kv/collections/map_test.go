@@ -78,6 +78,27 @@ func TestBasicMap(t *testing.T) {
 	assert.EqualValues(t, v3, v)
 }
 
+func TestMapErase(t *testing.T) {
+	vars := dict.New()
+	m := NewMap(vars, "testMap")
+	other := NewMap(vars, "testMap2")
+
+	m.MustSetAt([]byte("k1"), []byte("datum1"))
+	m.MustSetAt([]byte("k2"), []byte("datum2"))
+	other.MustSetAt([]byte("k1"), []byte("datum3"))
+	assert.EqualValues(t, 2, m.MustLen())
+
+	m.Erase()
+	assert.Zero(t, m.MustLen())
+	assert.False(t, m.MustHasAt([]byte("k1")))
+	assert.False(t, m.MustHasAt([]byte("k2")))
+	assert.EqualValues(t, 1, other.MustLen())
+	assert.EqualValues(t, []byte("datum3"), other.MustGetAt([]byte("k1")))
+
+	m.MustSetAt([]byte("k1"), []byte("datum4"))
+	assert.EqualValues(t, 1, m.MustLen())
+}
+
 func TestIterate(t *testing.T) {
 	vars := dict.New()
 	m := NewMap(vars, "testMap")
@@ -67,10 +67,16 @@ func (l *ImmutableTimestampedLog) getSizeKey() kv.Key {
 	return kv.Key(buf.Bytes())
 }
 
-func (l *ImmutableTimestampedLog) getElemKey(idx uint32) kv.Key {
+func (l *ImmutableTimestampedLog) getElemPrefix() kv.Key {
 	var buf bytes.Buffer
 	buf.Write([]byte(l.name))
 	buf.WriteByte(tslElemKeyCode)
+	return kv.Key(buf.Bytes())
+}
+
+func (l *ImmutableTimestampedLog) getElemKey(idx uint32) kv.Key {
+	var buf bytes.Buffer
+	buf.Write([]byte(l.getElemPrefix()))
 	_ = util.WriteUint32(&buf, idx)
 	return kv.Key(buf.Bytes())
 }
@@ -463,10 +469,10 @@ func (l *ImmutableTimestampedLog) findUpperIdx(ts int64, fromIdx, toIdx uint32)
 	return l.findUpperIdx(ts, fromIdx, middleIdx)
 }
 
-// TODO not finished with Erase
-
+// Erase deletes all records of the log
 func (l *TimestampedLog) Erase() {
-	panic("implement me")
+	l.kvw.DelPrefix(l.getElemPrefix())
+	l.setSize(0)
 }
 
 func (sl *TimeSlice) FromToIndices() (uint32, uint32) {
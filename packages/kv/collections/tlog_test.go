@@ -56,6 +56,22 @@ func TestTlogBasic(t *testing.T) {
 	assert.EqualValues(t, 7, tl.MustLen())
 }
 
+func TestTlogErase(t *testing.T) {
+	vars := dict.New()
+	tl := NewTimestampedLog(vars, "testTlog")
+	initLog(t, tl)
+	assert.EqualValues(t, numPoints, tl.MustLen())
+
+	tl.Erase()
+	assert.Zero(t, tl.MustLen())
+	assert.Zero(t, len(vars))
+
+	nowis := time.Now().UnixNano()
+	tl.MustAppend(nowis, []byte("datum1"))
+	assert.EqualValues(t, 1, tl.MustLen())
+	assert.EqualValues(t, nowis, tl.MustEarliest())
+}
+
 const (
 	numPoints     = 100
 	changeTsEvery = 5
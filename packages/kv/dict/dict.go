@@ -146,6 +146,15 @@ func (d Dict) Del(key kv.Key) {
 	delete(d, key)
 }
 
+// DelPrefix deletes all keys with the prefix
+func (d Dict) DelPrefix(prefix kv.Key) {
+	for k := range d {
+		if k.HasPrefix(prefix) {
+			delete(d, k)
+		}
+	}
+}
+
 // Has checks if key exist
 func (d Dict) Has(key kv.Key) (bool, error) {
 	_, ok := d[key]
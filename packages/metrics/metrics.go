@@ -0,0 +1,219 @@
+// Package metrics collects metrics of the Wasp node and exposes them in the Prometheus text format
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "wasp"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	consensusBacklogSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "consensus",
+		Name:      "backlog_size",
+		Help:      "Number of requests in the backlog of the consensus operator.",
+	}, []string{"chain"})
+
+	consensusStageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "consensus",
+		Name:      "stage_duration_seconds",
+		Help:      "Time spent by the consensus operator in each consensus stage.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"chain", "stage"})
+
+	stateIndex = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "state",
+		Name:      "index",
+		Help:      "Index of the latest solid state of the chain.",
+	}, []string{"chain"})
+
+	stateSyncLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "state",
+		Name:      "sync_lag_blocks",
+		Help:      "Number of blocks the solid state of the chain is behind the largest state index evidenced by peers.",
+	}, []string{"chain"})
+
+	vmBatchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "vm",
+		Name:      "batch_duration_seconds",
+		Help:      "Time of running a batch of requests on the VM.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"chain"})
+
+	vmBatchSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "vm",
+		Name:      "batch_size",
+		Help:      "Number of requests in a batch run on the VM.",
+		Buckets:   []float64{1, 2, 5, 10, 20, 50, 100},
+	}, []string{"chain"})
+
+	requestsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "chain",
+		Name:      "requests_processed_total",
+		Help:      "Number of requests processed by the chain and confirmed by the state transition.",
+	}, []string{"chain"})
+
+	nodeConnReconnects = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "nodeconn",
+		Name:      "reconnects_total",
+		Help:      "Number of attempts to reconnect to the Goshimmer node after the connection was lost or failed.",
+	})
+
+	nodeConnConnected = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "nodeconn",
+		Name:      "connected",
+		Help:      "1 if the node is connected to the Goshimmer node, 0 otherwise.",
+	})
+
+	webAPIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "webapi",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the web API requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "code"})
+
+	peers = &peerCollector{
+		alive: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "peering", "peer_alive"),
+			"1 if the connection with the peer is alive, 0 otherwise.",
+			[]string{"peer", "inbound"}, nil,
+		),
+		users: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "peering", "peer_users"),
+			"Number of users (e.g. chains) of the connection with the peer.",
+			[]string{"peer", "inbound"}, nil,
+		),
+	}
+)
+
+func init() {
+	registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		consensusBacklogSize,
+		consensusStageDuration,
+		stateIndex,
+		stateSyncLag,
+		vmBatchDuration,
+		vmBatchSize,
+		requestsProcessed,
+		nodeConnReconnects,
+		nodeConnConnected,
+		webAPIRequestDuration,
+		peers,
+	)
+}
+
+// Handler returns the HTTP handler serving all metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ConsensusBacklogSize records the number of requests in the backlog of the consensus operator
+func ConsensusBacklogSize(chainID *coretypes.ChainID, size int) {
+	consensusBacklogSize.WithLabelValues(chainID.String()).Set(float64(size))
+}
+
+// ConsensusStageDuration records the time spent by the consensus operator in the stage
+func ConsensusStageDuration(chainID *coretypes.ChainID, stage string, duration time.Duration) {
+	consensusStageDuration.WithLabelValues(chainID.String(), stage).Observe(duration.Seconds())
+}
+
+// StateSync records the index of the solid state of the chain and the largest state index evidenced by peers
+func StateSync(chainID *coretypes.ChainID, solidIndex uint32, largestEvidencedIndex uint32) {
+	lag := float64(0)
+	if largestEvidencedIndex > solidIndex {
+		lag = float64(largestEvidencedIndex - solidIndex)
+	}
+	stateIndex.WithLabelValues(chainID.String()).Set(float64(solidIndex))
+	stateSyncLag.WithLabelValues(chainID.String()).Set(lag)
+}
+
+// VMBatch records the time of running the batch of requests on the VM
+func VMBatch(chainID *coretypes.ChainID, numRequests int, duration time.Duration) {
+	vmBatchDuration.WithLabelValues(chainID.String()).Observe(duration.Seconds())
+	vmBatchSize.WithLabelValues(chainID.String()).Observe(float64(numRequests))
+}
+
+// RequestsProcessed counts requests confirmed by the state transition of the chain
+func RequestsProcessed(chainID *coretypes.ChainID, n int) {
+	requestsProcessed.WithLabelValues(chainID.String()).Add(float64(n))
+}
+
+// NodeConnReconnect counts attempts to reconnect to the Goshimmer node
+func NodeConnReconnect() {
+	nodeConnReconnects.Inc()
+}
+
+// NodeConnConnected records the status of the connection to the Goshimmer node
+func NodeConnConnected(connected bool) {
+	if connected {
+		nodeConnConnected.Set(1)
+	} else {
+		nodeConnConnected.Set(0)
+	}
+}
+
+// WebAPIRequest records the latency of the web API request
+func WebAPIRequest(method string, route string, code int, duration time.Duration) {
+	webAPIRequestDuration.WithLabelValues(method, route, strconv.Itoa(code)).Observe(duration.Seconds())
+}
+
+// SetPeerStatusProvider sets the function which returns the status of the peers of the node.
+// It is called each time the metrics are collected
+func SetPeerStatusProvider(f func() []peering.PeerStatusProvider) {
+	peers.mutex.Lock()
+	defer peers.mutex.Unlock()
+	peers.status = f
+}
+
+// peerCollector collects the connectivity of peers when the metrics are scraped
+type peerCollector struct {
+	mutex  sync.RWMutex
+	status func() []peering.PeerStatusProvider
+	alive  *prometheus.Desc
+	users  *prometheus.Desc
+}
+
+func (p *peerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- p.alive
+	ch <- p.users
+}
+
+func (p *peerCollector) Collect(ch chan<- prometheus.Metric) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.status == nil {
+		return
+	}
+	for _, peer := range p.status() {
+		alive := float64(0)
+		if peer.IsAlive() {
+			alive = 1
+		}
+		inbound := strconv.FormatBool(peer.IsInbound())
+		ch <- prometheus.MustNewConstMetric(p.alive, prometheus.GaugeValue, alive, peer.NetID(), inbound)
+		ch <- prometheus.MustNewConstMetric(p.users, prometheus.GaugeValue, float64(peer.NumUsers()), peer.NetID(), inbound)
+	}
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/stretchr/testify/require"
+)
+
+// peerStatus embeds the interface only to satisfy it; PubKey is not used by the collector
+type peerStatus struct {
+	peering.PeerStatusProvider
+}
+
+func (p *peerStatus) NetID() string   { return "127.0.0.1:4001" }
+func (p *peerStatus) IsInbound() bool { return false }
+func (p *peerStatus) IsAlive() bool   { return true }
+func (p *peerStatus) NumUsers() int   { return 2 }
+
+func scrape(t *testing.T) string {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.EqualValues(t, 200, rec.Code)
+	body, err := ioutil.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	chainID := coretypes.ChainID{1, 2, 3}
+	label := `chain="` + chainID.String() + `"`
+
+	ConsensusBacklogSize(&chainID, 5)
+	ConsensusStageDuration(&chainID, "consensusStageLeaderStarting", 100*time.Millisecond)
+	StateSync(&chainID, 7, 10)
+	VMBatch(&chainID, 3, time.Second)
+	RequestsProcessed(&chainID, 3)
+	NodeConnConnected(true)
+	NodeConnReconnect()
+	WebAPIRequest("GET", "/info", 200, 10*time.Millisecond)
+	SetPeerStatusProvider(func() []peering.PeerStatusProvider {
+		return []peering.PeerStatusProvider{&peerStatus{}}
+	})
+	defer SetPeerStatusProvider(nil)
+
+	out := scrape(t)
+	require.Contains(t, out, "wasp_consensus_backlog_size{"+label+"} 5")
+	require.Contains(t, out, "wasp_consensus_stage_duration_seconds_count{"+label+`,stage="consensusStageLeaderStarting"} 1`)
+	require.Contains(t, out, "wasp_state_index{"+label+"} 7")
+	require.Contains(t, out, "wasp_state_sync_lag_blocks{"+label+"} 3")
+	require.Contains(t, out, "wasp_vm_batch_duration_seconds_sum{"+label+"} 1")
+	require.Contains(t, out, "wasp_vm_batch_size_sum{"+label+"} 3")
+	require.Contains(t, out, "wasp_chain_requests_processed_total{"+label+"} 3")
+	require.Contains(t, out, "wasp_nodeconn_connected 1")
+	require.Contains(t, out, "wasp_nodeconn_reconnects_total 1")
+	require.Contains(t, out, `wasp_webapi_request_duration_seconds_count{code="200",method="GET",route="/info"} 1`)
+	require.Contains(t, out, `wasp_peering_peer_alive{inbound="false",peer="127.0.0.1:4001"} 1`)
+	require.Contains(t, out, `wasp_peering_peer_users{inbound="false",peer="127.0.0.1:4001"} 2`)
+	require.Contains(t, out, "go_goroutines")
+
+	// the state can't be ahead of the evidenced index: the lag is never negative
+	StateSync(&chainID, 11, 10)
+	require.Contains(t, scrape(t), "wasp_state_sync_lag_blocks{"+label+"} 0")
+}
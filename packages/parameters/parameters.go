@@ -1,6 +1,8 @@
 package parameters
 
 import (
+	"time"
+
 	"github.com/iotaledger/wasp/plugins/config"
 	flag "github.com/spf13/pflag"
 )
@@ -30,6 +32,17 @@ const (
 	PeeringPort    = "peering.port"
 
 	NanomsgPublisherPort = "nanomsg.port"
+
+	MqttBindAddress          = "mqtt.bindAddress"
+	MqttWebsocketBindAddress = "mqtt.websocketBindAddress"
+
+	MetricsBindAddress = "metrics.bindAddress"
+
+	PruningInterval     = "pruning.interval"
+	PruningKeepBlocks   = "pruning.keepBlocks"
+	PruningKeepDuration = "pruning.keepDuration"
+
+	WasmEngine = "wasm.engine"
 )
 
 func InitFlags() {
@@ -44,7 +57,7 @@ func InitFlags() {
 	flag.Bool(DatabaseInMemory, false, "whether the database is only kept in memory and not persisted")
 
 	flag.String(WebAPIBindAddress, "127.0.0.1:8080", "the bind address for the web API")
-	flag.StringSlice(WebAPIAdminWhitelist, []string{}, "IP whitelist for /adm wndpoints")
+	flag.StringSlice(WebAPIAdminWhitelist, []string{}, "IP whitelist for /adm endpoints, in addition to the web API authentication")
 	flag.StringToString(WebAPIAuth, nil, "authentication scheme for web API")
 
 	flag.String(DashboardBindAddress, "127.0.0.1:7000", "the bind address for the node dashboard")
@@ -57,6 +70,17 @@ func InitFlags() {
 	flag.String(PeeringMyNetId, "127.0.0.1:4000", "node host address as it is recognized by other peers")
 
 	flag.Int(NanomsgPublisherPort, 5550, "the port for nanomsg even publisher")
+
+	flag.String(MqttBindAddress, "127.0.0.1:1883", "the bind address for the MQTT broker")
+	flag.String(MqttWebsocketBindAddress, "", "the bind address for MQTT over websocket clients (disabled if empty)")
+
+	flag.String(MetricsBindAddress, "127.0.0.1:2112", "the bind address for the Prometheus metrics endpoint")
+
+	flag.Duration(PruningInterval, 10*time.Minute, "how often the pruner runs")
+	flag.Int(PruningKeepBlocks, 0, "default number of the latest blocks retained by the pruner (0 to disable the rule)")
+	flag.Duration(PruningKeepDuration, 0, "default period the blocks are retained by the pruner (0 to disable the rule)")
+
+	flag.String(WasmEngine, "", "engine which runs the Wasm smart contracts: 'wasmtime' or 'interpreter' (default is wasmtime when available)")
 }
 
 func GetBool(name string) bool {
@@ -75,6 +99,10 @@ func GetInt(name string) int {
 	return config.Node.Int(name)
 }
 
+func GetDuration(name string) time.Duration {
+	return config.Node.Duration(name)
+}
+
 func GetStringToString(name string) map[string]string {
 	return config.Node.StringMap(name)
 }
@@ -0,0 +1,214 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package peering
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/mr-tron/base58"
+	"go.dedis.ch/kyber/v3"
+)
+
+// IdentityPrefix marks the peers referred to by identity (peering public key) instead of "host:port".
+const IdentityPrefix = "id:"
+
+const nodeCertificateContext = "wasp-node-cert:"
+
+// NodeCertificate is issued by the owner of the node. The owner signs the peering
+// public key of the node with its ed25519 key, so the node is tied to its owner.
+type NodeCertificate struct {
+	NodePubKey  kyber.Point
+	OwnerPubKey ed25519.PublicKey
+	Issued      time.Time
+	Signature   ed25519.Signature
+}
+
+// TrustedPeer is a peer which presented a valid certificate in the handshake.
+// NetID is the last known network address of the peer.
+type TrustedPeer struct {
+	NetID string
+	Cert  *NodeCertificate
+}
+
+// TrustedPeerStore persists the trusted peers. It is implemented by the registry.
+type TrustedPeerStore interface {
+	GetTrustedPeers() ([]*TrustedPeer, error)
+	SaveTrustedPeer(peer *TrustedPeer) error
+}
+
+// IdentityResolver is implemented by the network providers which are able
+// to find the current NetID of a peer by its identity.
+type IdentityResolver interface {
+	ResolveNetID(peerID string) (string, error)
+}
+
+// NewNodeCertificate signs the public key of the node with the key pair of its owner.
+func NewNodeCertificate(nodePubKey kyber.Point, owner ed25519.KeyPair) (*NodeCertificate, error) {
+	cert := &NodeCertificate{
+		NodePubKey:  nodePubKey,
+		OwnerPubKey: owner.PublicKey,
+		Issued:      time.Unix(0, time.Now().UnixNano()),
+	}
+	essence, err := cert.essence()
+	if err != nil {
+		return nil, err
+	}
+	cert.Signature = owner.PrivateKey.Sign(essence)
+	return cert, nil
+}
+
+// NodeCertificateFromBytes decodes the certificate. The signature is not verified.
+func NodeCertificateFromBytes(data []byte, suite kyber.Group) (*NodeCertificate, error) {
+	cert := new(NodeCertificate)
+	if err := cert.Read(bytes.NewReader(data), suite); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+func (c *NodeCertificate) essence() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(nodeCertificateContext)
+	if err := util.WriteMarshaled(&buf, c.NodePubKey); err != nil {
+		return nil, err
+	}
+	if err := util.WriteTime(&buf, c.Issued); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Verify checks the signature of the owner.
+func (c *NodeCertificate) Verify() error {
+	if c.NodePubKey == nil {
+		return errors.New("node certificate: missing node public key")
+	}
+	essence, err := c.essence()
+	if err != nil {
+		return err
+	}
+	if !c.OwnerPubKey.VerifySignature(essence, c.Signature) {
+		return errors.New("node certificate: invalid owner signature")
+	}
+	return nil
+}
+
+// Identity returns the identity of the certified node.
+func (c *NodeCertificate) Identity() string {
+	return Identity(c.NodePubKey)
+}
+
+func (c *NodeCertificate) Bytes() []byte {
+	return util.MustBytes(c)
+}
+
+func (c *NodeCertificate) Write(w io.Writer) error {
+	if err := util.WriteMarshaled(w, c.NodePubKey); err != nil {
+		return err
+	}
+	return c.WriteOwnerPart(w)
+}
+
+func (c *NodeCertificate) Read(r io.Reader, suite kyber.Group) error {
+	c.NodePubKey = suite.Point()
+	if err := util.ReadMarshaled(r, c.NodePubKey); err != nil {
+		return err
+	}
+	return c.ReadOwnerPart(r)
+}
+
+// WriteOwnerPart writes the certificate without the node public key.
+// It is used when the public key is already known to the reader, e.g. in the handshake.
+func (c *NodeCertificate) WriteOwnerPart(w io.Writer) error {
+	if _, err := w.Write(c.OwnerPubKey.Bytes()); err != nil {
+		return err
+	}
+	if err := util.WriteTime(w, c.Issued); err != nil {
+		return err
+	}
+	_, err := w.Write(c.Signature.Bytes())
+	return err
+}
+
+// ReadOwnerPart is the counterpart of WriteOwnerPart.
+func (c *NodeCertificate) ReadOwnerPart(r io.Reader) error {
+	var pubKey [ed25519.PublicKeySize]byte
+	if _, err := io.ReadFull(r, pubKey[:]); err != nil {
+		return err
+	}
+	c.OwnerPubKey = pubKey
+	if err := util.ReadTime(r, &c.Issued); err != nil {
+		return err
+	}
+	var sig [ed25519.SignatureSize]byte
+	if _, err := io.ReadFull(r, sig[:]); err != nil {
+		return err
+	}
+	c.Signature = sig
+	return nil
+}
+
+func (c *NodeCertificate) String() string {
+	return fmt.Sprintf("node: %s, owner: %s, issued: %s", c.Identity(), c.OwnerPubKey.String(), c.Issued.Format(time.RFC3339))
+}
+
+// Identity is the string referring to the node by its peering public key.
+// It can be used instead of "host:port" in the committee and access node lists of a chain.
+func Identity(pubKey kyber.Point) string {
+	data, err := pubKey.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	return IdentityPrefix + base58.Encode(data)
+}
+
+// IsIdentity checks if the peer is referred to by identity rather than by NetID.
+func IsIdentity(peerID string) bool {
+	return strings.HasPrefix(peerID, IdentityPrefix)
+}
+
+// PubKeyFromIdentity decodes the public key of the node from its identity.
+func PubKeyFromIdentity(peerID string, suite kyber.Group) (kyber.Point, error) {
+	if !IsIdentity(peerID) {
+		return nil, fmt.Errorf("not a node identity: %s", peerID)
+	}
+	data, err := base58.Decode(strings.TrimPrefix(peerID, IdentityPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("wrong node identity %s: %v", peerID, err)
+	}
+	pubKey := suite.Point()
+	if err = pubKey.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("wrong node identity %s: %v", peerID, err)
+	}
+	return pubKey, nil
+}
+
+// ResolveNetIDs replaces the identities in the list with the current NetIDs of the peers.
+// The NetIDs in the list are returned as is.
+func ResolveNetIDs(netProvider NetworkProvider, peerIDs []string) ([]string, error) {
+	ret := make([]string, len(peerIDs))
+	for i, peerID := range peerIDs {
+		if !IsIdentity(peerID) {
+			ret[i] = peerID
+			continue
+		}
+		resolver, ok := netProvider.(IdentityResolver)
+		if !ok {
+			return nil, fmt.Errorf("network provider can't resolve node identity %s", peerID)
+		}
+		netID, err := resolver.ResolveNetID(peerID)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = netID
+	}
+	return ret, nil
+}
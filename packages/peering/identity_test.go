@@ -0,0 +1,56 @@
+package peering_test
+
+import (
+	"testing"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+func TestNodeCertificate(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	nodeKeyPair := key.NewKeyPair(suite)
+	owner := ed25519.GenerateKeyPair()
+
+	cert, err := peering.NewNodeCertificate(nodeKeyPair.Public, owner)
+	require.NoError(t, err)
+	require.NoError(t, cert.Verify())
+
+	back, err := peering.NodeCertificateFromBytes(cert.Bytes(), suite)
+	require.NoError(t, err)
+	require.NoError(t, back.Verify())
+	require.True(t, back.NodePubKey.Equal(nodeKeyPair.Public))
+	require.EqualValues(t, owner.PublicKey, back.OwnerPubKey)
+	require.True(t, cert.Issued.Equal(back.Issued))
+
+	// the certificate is not valid for another node
+	back.NodePubKey = key.NewKeyPair(suite).Public
+	require.Error(t, back.Verify())
+
+	// nor signed by someone else
+	other := ed25519.GenerateKeyPair()
+	forged := *cert
+	forged.OwnerPubKey = other.PublicKey
+	require.Error(t, forged.Verify())
+}
+
+func TestIdentity(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	pubKey := key.NewKeyPair(suite).Public
+
+	id := peering.Identity(pubKey)
+	require.True(t, peering.IsIdentity(id))
+	require.False(t, peering.IsIdentity("localhost:4000"))
+
+	back, err := peering.PubKeyFromIdentity(id, suite)
+	require.NoError(t, err)
+	require.True(t, back.Equal(pubKey))
+
+	_, err = peering.PubKeyFromIdentity("localhost:4000", suite)
+	require.Error(t, err)
+	_, err = peering.PubKeyFromIdentity(peering.IdentityPrefix+"1234", suite)
+	require.Error(t, err)
+}
@@ -6,15 +6,17 @@ package udp
 import (
 	"bytes"
 
+	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/util"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/sign/bls"
 )
 
 type handshakeMsg struct {
-	netID   string      // Their NetID
-	pubKey  kyber.Point // Our PubKey.
-	respond bool        // Do the message asks for a response?
+	netID   string                   // Their NetID
+	pubKey  kyber.Point              // Our PubKey.
+	respond bool                     // Do the message asks for a response?
+	cert    *peering.NodeCertificate // Certificate of our PubKey issued by the node owner, optional.
 }
 
 func (m *handshakeMsg) bytes(secKey kyber.Scalar, suite Suite) ([]byte, error) {
@@ -31,6 +33,15 @@ func (m *handshakeMsg) bytes(secKey kyber.Scalar, suite Suite) ([]byte, error) {
 	if err = util.WriteBoolByte(&payloadBuf, m.respond); err != nil {
 		return nil, err
 	}
+	if m.cert != nil {
+		// The public key is already in the message, only the owner part is sent.
+		if err = util.WriteBoolByte(&payloadBuf, true); err != nil {
+			return nil, err
+		}
+		if err = m.cert.WriteOwnerPart(&payloadBuf); err != nil {
+			return nil, err
+		}
+	}
 	var payload = payloadBuf.Bytes()
 	var signature []byte
 	if signature, err = bls.Sign(suite, secKey, payload); err != nil {
@@ -75,6 +86,19 @@ func handshakeMsgFromBytes(buf []byte, suite Suite) (*handshakeMsg, error) {
 	if err = util.ReadBoolByte(rPayload, &m.respond); err != nil {
 		return nil, err
 	}
+	if rPayload.Len() > 0 {
+		// Nodes without a certificate don't send this part at all.
+		var hasCert bool
+		if err = util.ReadBoolByte(rPayload, &hasCert); err != nil {
+			return nil, err
+		}
+		if hasCert {
+			m.cert = &peering.NodeCertificate{NodePubKey: m.pubKey}
+			if err = m.cert.ReadOwnerPart(rPayload); err != nil {
+				return nil, err
+			}
+		}
+	}
 	//
 	// Verify the signature.
 	if err = bls.Verify(suite, m.pubKey, payload, signature); err != nil {
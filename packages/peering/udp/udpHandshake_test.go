@@ -7,6 +7,8 @@ import (
 	"net"
 	"testing"
 
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3/pairing"
 	"go.dedis.ch/kyber/v3/util/key"
@@ -43,6 +45,30 @@ func TestHandshakeCodec(t *testing.T) {
 	require.Nil(t, c)
 }
 
+func TestHandshakeCodecWithCert(t *testing.T) {
+	var err error
+	suite := pairing.NewSuiteBn256()
+	pair := key.NewKeyPair(suite)
+	cert, err := peering.NewNodeCertificate(pair.Public, ed25519.GenerateKeyPair())
+	require.Nil(t, err)
+	a := handshakeMsg{
+		netID:   "some",
+		pubKey:  pair.Public,
+		respond: false,
+		cert:    cert,
+	}
+	var buf []byte
+	buf, err = a.bytes(pair.Private, suite)
+	require.Nil(t, err)
+	var b *handshakeMsg
+	b, err = handshakeMsgFromBytes(buf, suite)
+	require.Nil(t, err)
+	require.NotNil(t, b.cert)
+	require.Nil(t, b.cert.Verify())
+	require.Equal(t, cert.OwnerPubKey, b.cert.OwnerPubKey)
+	require.True(t, b.cert.NodePubKey.Equal(pair.Public))
+}
+
 func TestUDPAddrString(t *testing.T) {
 	var err error
 	var addr *net.UDPAddr
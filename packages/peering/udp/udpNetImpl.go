@@ -6,6 +6,7 @@ package udp
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"os"
 	"sync"
@@ -39,6 +40,11 @@ type NetImpl struct {
 	nodeKeyPair *key.Pair
 	suite       Suite
 	log         *logger.Logger
+	// Identity related.
+	identityLock *sync.RWMutex
+	nodeCert     *peering.NodeCertificate        // Certificate of this node, optional.
+	trusted      map[string]*peering.TrustedPeer // By identity.
+	trustedStore peering.TrustedPeerStore        // Persists the trusted peers, optional.
 }
 
 // NewNetworkProvider is a constructor for the TCP based
@@ -55,17 +61,19 @@ func NewNetworkProvider(myNetID string, port int, nodeKeyPair *key.Pair, suite S
 		return nil, err
 	}
 	n := NetImpl{
-		myNetID:     myNetID,
-		myUDPConn:   myUDPConn,
-		port:        port,
-		peers:       make(map[string]*peer),
-		peersByAddr: make(map[string]*peer),
-		peersLock:   &sync.RWMutex{},
-		recvEvents:  nil, // Initialized bellow.
-		recvQueue:   make(chan *peering.RecvEvent, recvQueueSize),
-		nodeKeyPair: nodeKeyPair,
-		suite:       suite,
-		log:         log,
+		myNetID:      myNetID,
+		myUDPConn:    myUDPConn,
+		port:         port,
+		peers:        make(map[string]*peer),
+		peersByAddr:  make(map[string]*peer),
+		peersLock:    &sync.RWMutex{},
+		recvEvents:   nil, // Initialized bellow.
+		recvQueue:    make(chan *peering.RecvEvent, recvQueueSize),
+		nodeKeyPair:  nodeKeyPair,
+		suite:        suite,
+		log:          log,
+		identityLock: &sync.RWMutex{},
+		trusted:      make(map[string]*peering.TrustedPeer),
 	}
 	n.recvEvents = events.NewEvent(n.eventHandler)
 	return &n, nil
@@ -195,6 +203,93 @@ func (n *NetImpl) Close() {
 	// We will con close the connection of the own node.
 }
 
+// SetNodeCertificate sets the certificate of this node, issued by the node owner.
+// The certificate is sent to the peers in the handshake.
+func (n *NetImpl) SetNodeCertificate(cert *peering.NodeCertificate) error {
+	if cert != nil {
+		if !cert.NodePubKey.Equal(n.nodeKeyPair.Public) {
+			return errors.New("the certificate is issued for another node")
+		}
+		if err := cert.Verify(); err != nil {
+			return err
+		}
+	}
+	n.identityLock.Lock()
+	n.nodeCert = cert
+	n.identityLock.Unlock()
+	// Let the known peers know the new certificate.
+	n.peersLock.RLock()
+	defer n.peersLock.RUnlock()
+	for _, p := range n.peers {
+		p.sendHandshake(false)
+	}
+	return nil
+}
+
+// UseTrustedPeerStore loads the trusted peers from the store.
+// The peers presenting a valid certificate in the handshake are persisted there.
+func (n *NetImpl) UseTrustedPeerStore(store peering.TrustedPeerStore) error {
+	trustedPeers, err := store.GetTrustedPeers()
+	if err != nil {
+		return err
+	}
+	n.identityLock.Lock()
+	defer n.identityLock.Unlock()
+	n.trustedStore = store
+	for _, tp := range trustedPeers {
+		n.trusted[tp.Cert.Identity()] = tp
+	}
+	return nil
+}
+
+// ResolveNetID implements peering.IdentityResolver.
+// Only the own node and the trusted peers can be resolved.
+func (n *NetImpl) ResolveNetID(peerID string) (string, error) {
+	pubKey, err := peering.PubKeyFromIdentity(peerID, n.suite)
+	if err != nil {
+		return "", err
+	}
+	if pubKey.Equal(n.nodeKeyPair.Public) {
+		return n.myNetID, nil
+	}
+	n.identityLock.RLock()
+	defer n.identityLock.RUnlock()
+	if tp, ok := n.trusted[peerID]; ok {
+		return tp.NetID, nil
+	}
+	return "", fmt.Errorf("unknown or not certified node %s", peerID)
+}
+
+func (n *NetImpl) getNodeCert() *peering.NodeCertificate {
+	n.identityLock.RLock()
+	defer n.identityLock.RUnlock()
+	return n.nodeCert
+}
+
+// trustPeer records the last known NetID of the certified peer.
+func (n *NetImpl) trustPeer(netID string, cert *peering.NodeCertificate) {
+	identity := cert.Identity()
+	n.identityLock.Lock()
+	defer n.identityLock.Unlock()
+	if tp, ok := n.trusted[identity]; ok {
+		if tp.NetID == netID && tp.Cert.OwnerPubKey == cert.OwnerPubKey && tp.Cert.Issued.Equal(cert.Issued) {
+			return
+		}
+		if tp.NetID != netID {
+			n.log.Infof("Trusted peer %v moved from %v to %v", identity, tp.NetID, netID)
+		}
+	} else {
+		n.log.Infof("Trusted peer %v at %v, owner=%v", identity, netID, cert.OwnerPubKey)
+	}
+	tp := &peering.TrustedPeer{NetID: netID, Cert: cert}
+	n.trusted[identity] = tp
+	if n.trustedStore != nil {
+		if err := n.trustedStore.SaveTrustedPeer(tp); err != nil {
+			n.log.Warnf("Unable to save the trusted peer %v, reason=%v", identity, err)
+		}
+	}
+}
+
 func (n *NetImpl) usePeer(remoteNetID string) (peering.PeerSender, error) {
 	var err error
 	if remoteNetID == n.myNetID {
@@ -262,6 +357,12 @@ func (n *NetImpl) receiveLoop(stopCh chan bool) {
 				n.log.Warnf("Error while decoding a UDP handshake, reason=%v", err)
 				continue
 			}
+			if h.cert != nil {
+				if err = h.cert.Verify(); err != nil {
+					n.log.Warnf("Dropping UDP handshake from %v with invalid certificate, reason=%v", h.netID, err)
+					continue
+				}
+			}
 			n.peersLock.Lock()
 			if p, ok := n.peers[h.netID]; ok {
 				if oldUDPAddrStr, newUDPAddrStr := p.handleHandshake(h, peerUDPAddr); oldUDPAddrStr != newUDPAddrStr {
@@ -279,6 +380,9 @@ func (n *NetImpl) receiveLoop(stopCh chan bool) {
 				n.peersByAddr[p.remoteUDPAddr.String()] = p
 			}
 			n.peersLock.Unlock()
+			if h.cert != nil {
+				n.trustPeer(h.netID, h.cert)
+			}
 		case peering.MsgTypeMsgChunk:
 			remoteUDPAddrStr := peerUDPAddr.String()
 			n.peersLock.RLock()
@@ -4,8 +4,11 @@
 package udp_test
 
 import (
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/peering/udp"
@@ -52,3 +55,65 @@ func TestUDPPeeringImpl(t *testing.T) {
 
 	<-doneCh
 }
+
+type trustedPeerStore struct {
+	peers []*peering.TrustedPeer
+	mutex sync.Mutex
+}
+
+func (s *trustedPeerStore) GetTrustedPeers() ([]*peering.TrustedPeer, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*peering.TrustedPeer{}, s.peers...), nil
+}
+
+func (s *trustedPeerStore) SaveTrustedPeer(tp *peering.TrustedPeer) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.peers = append(s.peers, tp)
+	return nil
+}
+
+func TestUDPPeeringIdentity(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	log := testutil.NewLogger(t)
+	defer log.Sync()
+	netIDs := []string{"localhost:9027", "localhost:9028"}
+	keys := []*key.Pair{key.NewKeyPair(suite), key.NewKeyPair(suite)}
+	node0, err := udp.NewNetworkProvider(netIDs[0], 9027, keys[0], suite, log.Named("node0"))
+	require.Nil(t, err)
+	node1, err := udp.NewNetworkProvider(netIDs[1], 9028, keys[1], suite, log.Named("node1"))
+	require.Nil(t, err)
+
+	owner := ed25519.GenerateKeyPair()
+	cert, err := peering.NewNodeCertificate(keys[0].Public, owner)
+	require.Nil(t, err)
+	otherCert, err := peering.NewNodeCertificate(keys[1].Public, owner)
+	require.Nil(t, err)
+	require.NotNil(t, node0.SetNodeCertificate(otherCert))
+	require.Nil(t, node0.SetNodeCertificate(cert))
+	store := &trustedPeerStore{}
+	require.Nil(t, node1.UseTrustedPeerStore(store))
+	go node0.Run(make(<-chan struct{}))
+	go node1.Run(make(<-chan struct{}))
+
+	id0 := peering.Identity(keys[0].Public)
+	_, err = peering.ResolveNetIDs(node1, []string{id0})
+	require.NotNil(t, err) // not certified yet
+
+	p, err := node1.PeerByNetID(netIDs[0])
+	require.Nil(t, err)
+	require.Nil(t, p.Await(5*time.Second))
+	require.Eventually(t, func() bool {
+		_, err := node1.ResolveNetID(id0)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	resolved, err := peering.ResolveNetIDs(node1, []string{id0, netIDs[1], peering.Identity(keys[1].Public)})
+	require.Nil(t, err)
+	require.Equal(t, []string{netIDs[0], netIDs[1], netIDs[1]}, resolved)
+	trusted, err := store.GetTrustedPeers()
+	require.Nil(t, err)
+	require.Len(t, trusted, 1)
+	require.Equal(t, netIDs[0], trusted[0].NetID)
+}
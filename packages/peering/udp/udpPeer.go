@@ -128,6 +128,7 @@ func (p *peer) sendHandshake(respond bool) {
 		netID:   p.net.NetID(),
 		pubKey:  p.net.PubKey(),
 		respond: respond,
+		cert:    p.net.getNodeCert(),
 	}
 	var msgDataBin []byte
 	if msgDataBin, err = handshake.bytes(p.net.nodeKeyPair.Private, p.net.suite); err != nil {
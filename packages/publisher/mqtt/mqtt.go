@@ -0,0 +1,97 @@
+// Package mqtt implements an in-process MQTT broker which republishes the
+// messages of the Wasp publisher to MQTT clients
+package mqtt
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mochi-co/mqtt/server"
+	"github.com/mochi-co/mqtt/server/listeners"
+)
+
+// TopicRoot is the first level of all topics published by the broker
+const TopicRoot = "wasp"
+
+// Topic returns the MQTT topic of the publisher message.
+// Messages are published to topics 'wasp/<chain ID>/<message type>'; all messages
+// of the Wasp publisher have the chain ID as the first part
+func Topic(msgType string, parts []string) string {
+	if len(parts) == 0 {
+		return TopicRoot + "/" + msgType
+	}
+	return TopicRoot + "/" + parts[0] + "/" + msgType
+}
+
+// Payload returns the payload of the MQTT message: the parts of the publisher message
+// which follow the chain ID, separated by spaces
+func Payload(parts []string) []byte {
+	if len(parts) <= 1 {
+		return []byte{}
+	}
+	return []byte(strings.Join(parts[1:], " "))
+}
+
+// Broker is an MQTT broker running in the process of the Wasp node.
+// Clients can subscribe to any topic, but only the node itself can publish
+type Broker struct {
+	server *server.Server
+	mutex  sync.RWMutex
+	closed bool
+}
+
+// NewBroker starts the broker, listening for MQTT clients on the TCP address and, if
+// wsBindAddress is not empty, for MQTT over websocket clients
+func NewBroker(bindAddress string, wsBindAddress string) (*Broker, error) {
+	srv := server.New()
+	config := &listeners.Config{Auth: readOnly{}}
+	if err := srv.AddListener(listeners.NewTCP("tcp", bindAddress), config); err != nil {
+		return nil, fmt.Errorf("mqtt: failed to listen on %s: %v", bindAddress, err)
+	}
+	if wsBindAddress != "" {
+		if err := srv.AddListener(listeners.NewWebsocket("ws", wsBindAddress), config); err != nil {
+			_ = srv.Close()
+			return nil, fmt.Errorf("mqtt: failed to listen on %s: %v", wsBindAddress, err)
+		}
+	}
+	if err := srv.Serve(); err != nil {
+		_ = srv.Close()
+		return nil, err
+	}
+	return &Broker{server: srv}, nil
+}
+
+// Publish publishes the publisher message to the subscribers of its topic
+func (b *Broker) Publish(msgType string, parts []string) error {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if b.closed {
+		return fmt.Errorf("mqtt: broker is closed")
+	}
+	return b.server.Publish(Topic(msgType, parts), Payload(parts), false)
+}
+
+// Close disconnects all clients and stops the broker
+func (b *Broker) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	return b.server.Close()
+}
+
+// readOnly allows any client to connect and subscribe, but not to publish
+type readOnly struct{}
+
+func (readOnly) Authenticate(user, password []byte) bool {
+	return true
+}
+
+func (readOnly) ACL(user []byte, topic string, write bool) bool {
+	return !write
+}
@@ -0,0 +1,111 @@
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopic(t *testing.T) {
+	require.Equal(t, "wasp/chain1/vmmsg", Topic("vmmsg", []string{"chain1", "hname", "hello", "world"}))
+	require.Equal(t, "wasp/chain1/dismissed_committee", Topic("dismissed_committee", []string{"chain1"}))
+	require.Equal(t, "wasp/test", Topic("test", nil))
+
+	require.Equal(t, []byte("hname hello world"), Payload([]string{"chain1", "hname", "hello", "world"}))
+	require.Equal(t, []byte{}, Payload([]string{"chain1"}))
+}
+
+func freeAddress(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// mqttString encodes the string as a length-prefixed MQTT string
+func mqttString(s string) []byte {
+	return append([]byte{byte(len(s) >> 8), byte(len(s))}, s...)
+}
+
+func writePacket(t *testing.T, conn net.Conn, header byte, body []byte) {
+	var buf bytes.Buffer
+	buf.WriteByte(header)
+	// remaining length is encoded as variable length integer
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		buf.WriteByte(b)
+		if n == 0 {
+			break
+		}
+	}
+	buf.Write(body)
+	_, err := conn.Write(buf.Bytes())
+	require.NoError(t, err)
+}
+
+func readPacket(t *testing.T, r *bufio.Reader) (byte, []byte) {
+	header, err := r.ReadByte()
+	require.NoError(t, err)
+	n, mul := 0, 1
+	for {
+		b, err := r.ReadByte()
+		require.NoError(t, err)
+		n += int(b&0x7f) * mul
+		mul *= 128
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	body := make([]byte, n)
+	_, err = io.ReadFull(r, body)
+	require.NoError(t, err)
+	return header, body
+}
+
+func TestBrokerPublish(t *testing.T) {
+	addr := freeAddress(t)
+	broker, err := NewBroker(addr, "")
+	require.NoError(t, err)
+	defer broker.Close()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	r := bufio.NewReader(conn)
+
+	// CONNECT: protocol MQTT 3.1.1, clean session, keep alive 60s
+	connect := append(mqttString("MQTT"), 4, 0x02, 0, 60)
+	writePacket(t, conn, 0x10, append(connect, mqttString("test")...))
+	header, body := readPacket(t, r)
+	require.EqualValues(t, 0x20, header)
+	require.Equal(t, []byte{0, 0}, body)
+
+	// SUBSCRIBE with packet id 1 to all vmmsg messages, QoS 0
+	subscribe := append([]byte{0, 1}, mqttString(TopicRoot+"/+/vmmsg")...)
+	writePacket(t, conn, 0x82, append(subscribe, 0))
+	header, body = readPacket(t, r)
+	require.EqualValues(t, 0x90, header)
+	require.Equal(t, []byte{0, 1, 0}, body)
+
+	require.NoError(t, broker.Publish("state", []string{"chain1", "1"}))
+	require.NoError(t, broker.Publish("vmmsg", []string{"chain1", "hname", "hello"}))
+
+	header, body = readPacket(t, r)
+	require.EqualValues(t, 0x30, header&0xf0)
+	expected := append(mqttString("wasp/chain1/vmmsg"), "hname hello"...)
+	require.Equal(t, expected, body)
+
+	require.NoError(t, broker.Close())
+	require.Error(t, broker.Publish("vmmsg", []string{"chain1", "hname", "hello"}))
+}
@@ -0,0 +1,183 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"time"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/util/auth"
+	"github.com/mr-tron/base58"
+)
+
+// implements auth.KeyStore interface
+
+// APIKeyRecord is the registry record of an API key.
+// The key itself is not stored, only its hash
+type APIKeyRecord struct {
+	Hash    hashing.HashValue
+	Name    string
+	Role    auth.Role
+	Created time.Time
+}
+
+// AuthorizedKey is a public key allowed to log in with a signature
+type AuthorizedKey struct {
+	PubKey ed25519.PublicKey
+	Role   auth.Role
+}
+
+func dbKeyForAPIKey(h hashing.HashValue) []byte {
+	return dbprovider.MakeKey(dbprovider.ObjectTypeAPIKey, h[:])
+}
+
+func dbKeyForAuthorizedKey(pubKey ed25519.PublicKey) []byte {
+	return dbprovider.MakeKey(dbprovider.ObjectTypeAuthorizedKey, pubKey[:])
+}
+
+// NewAPIKey generates a new API key granting the role and stores its hash.
+// The returned key can't be recovered later
+func (r *Impl) NewAPIKey(name string, role auth.Role) (string, *APIKeyRecord, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", nil, err
+	}
+	key := base58.Encode(data)
+	rec := &APIKeyRecord{
+		Hash:    hashing.HashStrings(key),
+		Name:    name,
+		Role:    role,
+		Created: time.Now(),
+	}
+	if err := r.dbProvider.GetRegistryPartition().Set(dbKeyForAPIKey(rec.Hash), util.MustBytes(rec)); err != nil {
+		return "", nil, err
+	}
+	r.log.Infof("API key '%s' with role %s has been created. Hash: %s", name, role, rec.Hash)
+	return key, rec, nil
+}
+
+// APIKeyRole returns the role granted by the API key, if it exists
+func (r *Impl) APIKeyRole(key string) (auth.Role, bool, error) {
+	data, err := r.dbProvider.GetRegistryPartition().Get(dbKeyForAPIKey(hashing.HashStrings(key)))
+	if err == kvstore.ErrKeyNotFound {
+		return auth.RoleNone, false, nil
+	}
+	if err != nil {
+		return auth.RoleNone, false, err
+	}
+	rec := new(APIKeyRecord)
+	if err := rec.Read(bytes.NewReader(data)); err != nil {
+		return auth.RoleNone, false, err
+	}
+	return rec.Role, true, nil
+}
+
+// GetAPIKeys returns the records of all API keys
+func (r *Impl) GetAPIKeys() ([]*APIKeyRecord, error) {
+	ret := make([]*APIKeyRecord, 0)
+	err := r.dbProvider.GetRegistryPartition().Iterate([]byte{dbprovider.ObjectTypeAPIKey}, func(key kvstore.Key, value kvstore.Value) bool {
+		rec := new(APIKeyRecord)
+		if err := rec.Read(bytes.NewReader(value)); err == nil {
+			ret = append(ret, rec)
+		} else {
+			r.log.Warnf("corrupted API key record with key %s", base58.Encode(key))
+		}
+		return true
+	})
+	return ret, err
+}
+
+// RevokeAPIKey deletes the API key with the hash. Returns false if it does not exist
+func (r *Impl) RevokeAPIKey(h hashing.HashValue) (bool, error) {
+	return r.deleteIfExists(dbKeyForAPIKey(h))
+}
+
+// AuthorizeKey allows the public key to log in with the role
+func (r *Impl) AuthorizeKey(pubKey ed25519.PublicKey, role auth.Role) error {
+	if err := r.dbProvider.GetRegistryPartition().Set(dbKeyForAuthorizedKey(pubKey), []byte{byte(role)}); err != nil {
+		return err
+	}
+	r.log.Infof("key %s has been authorized with role %s", pubKey, role)
+	return nil
+}
+
+// AuthorizedKeyRole returns the role of the public key, if it is authorized
+func (r *Impl) AuthorizedKeyRole(pubKey ed25519.PublicKey) (auth.Role, bool, error) {
+	data, err := r.dbProvider.GetRegistryPartition().Get(dbKeyForAuthorizedKey(pubKey))
+	if err == kvstore.ErrKeyNotFound {
+		return auth.RoleNone, false, nil
+	}
+	if err != nil {
+		return auth.RoleNone, false, err
+	}
+	if len(data) != 1 {
+		return auth.RoleNone, false, io.ErrUnexpectedEOF
+	}
+	return auth.Role(data[0]), true, nil
+}
+
+// GetAuthorizedKeys returns all authorized public keys
+func (r *Impl) GetAuthorizedKeys() ([]*AuthorizedKey, error) {
+	ret := make([]*AuthorizedKey, 0)
+	err := r.dbProvider.GetRegistryPartition().Iterate([]byte{dbprovider.ObjectTypeAuthorizedKey}, func(key kvstore.Key, value kvstore.Value) bool {
+		pubKey, _, err := ed25519.PublicKeyFromBytes(key[1:])
+		if err != nil || len(value) != 1 {
+			r.log.Warnf("corrupted authorized key record with key %s", base58.Encode(key))
+			return true
+		}
+		ret = append(ret, &AuthorizedKey{PubKey: pubKey, Role: auth.Role(value[0])})
+		return true
+	})
+	return ret, err
+}
+
+// RevokeKey removes the authorization of the public key. Returns false if it was not authorized
+func (r *Impl) RevokeKey(pubKey ed25519.PublicKey) (bool, error) {
+	return r.deleteIfExists(dbKeyForAuthorizedKey(pubKey))
+}
+
+func (r *Impl) deleteIfExists(dbKey []byte) (bool, error) {
+	partition := r.dbProvider.GetRegistryPartition()
+	exists, err := partition.Has(dbKey)
+	if err != nil || !exists {
+		return false, err
+	}
+	return true, partition.Delete(dbKey)
+}
+
+func (rec *APIKeyRecord) Write(w io.Writer) error {
+	if err := rec.Hash.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteString16(w, rec.Name); err != nil {
+		return err
+	}
+	if err := util.WriteByte(w, byte(rec.Role)); err != nil {
+		return err
+	}
+	return util.WriteTime(w, rec.Created)
+}
+
+func (rec *APIKeyRecord) Read(r io.Reader) error {
+	if err := rec.Hash.Read(r); err != nil {
+		return err
+	}
+	var err error
+	if rec.Name, err = util.ReadString16(r); err != nil {
+		return err
+	}
+	role, err := util.ReadByte(r)
+	if err != nil {
+		return err
+	}
+	rec.Role = auth.Role(role)
+	return util.ReadTime(r, &rec.Created)
+}
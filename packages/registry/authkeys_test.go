@@ -0,0 +1,73 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/util/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys(t *testing.T) {
+	log := testutil.NewLogger(t)
+	reg := NewRegistry(nil, log, dbprovider.NewInMemoryDBProvider(log))
+
+	key, rec, err := reg.NewAPIKey("monitoring", auth.RoleReadOnly)
+	require.NoError(t, err)
+	require.NotEmpty(t, key)
+
+	role, ok, err := reg.APIKeyRole(key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, auth.RoleReadOnly, role)
+
+	_, ok, err = reg.APIKeyRole(key + "x")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	recs, err := reg.GetAPIKeys()
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	require.EqualValues(t, rec.Hash, recs[0].Hash)
+	require.EqualValues(t, "monitoring", recs[0].Name)
+
+	ok, err = reg.RevokeAPIKey(rec.Hash)
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, ok, err = reg.APIKeyRole(key)
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = reg.RevokeAPIKey(rec.Hash)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestAuthorizedKeys(t *testing.T) {
+	log := testutil.NewLogger(t)
+	reg := NewRegistry(nil, log, dbprovider.NewInMemoryDBProvider(log))
+	kp := ed25519.GenerateKeyPair()
+
+	_, ok, err := reg.AuthorizedKeyRole(kp.PublicKey)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, reg.AuthorizeKey(kp.PublicKey, auth.RoleChainAdmin))
+	role, ok, err := reg.AuthorizedKeyRole(kp.PublicKey)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, auth.RoleChainAdmin, role)
+
+	keys, err := reg.GetAuthorizedKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.EqualValues(t, kp.PublicKey, keys[0].PubKey)
+
+	ok, err = reg.RevokeKey(kp.PublicKey)
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, ok, err = reg.AuthorizedKeyRole(kp.PublicKey)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/iotaledger/wasp/packages/dbprovider"
 	"io"
 
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/wasp/packages/coretypes"
@@ -20,10 +21,18 @@ import (
 type ChainRecord struct {
 	ChainID        coretypes.ChainID
 	Color          balance.Color // origin tx hash
-	CommitteeNodes []string      // "host_addr:port"
+	CommitteeNodes []string      // "host_addr:port" or node identity "id:<base58 public key>"
 	Active         bool
+	// Address is the address of the committee currently controlling the chain.
+	// It is equal to the chain ID unless the chain was moved to another committee
+	Address address.Address
+	// AccessNodes are the nodes which follow the chain without taking part in the consensus.
+	// They sync the state from the committee and serve it read-only
+	AccessNodes []string // "host_addr:port" or node identity
 }
 
+var nilAddress address.Address
+
 func dbkeyChainRecord(chainID *coretypes.ChainID) []byte {
 	return dbprovider.MakeKey(dbprovider.ObjectTypeChainRecord, chainID[:])
 }
@@ -35,6 +44,9 @@ func SaveChainRecord(bd *ChainRecord) error {
 	if bd.Color == balance.ColorNew || bd.Color == balance.ColorIOTA {
 		return fmt.Errorf("can't be IOTA or New color")
 	}
+	if bd.Address == nilAddress {
+		bd.Address = address.Address(bd.ChainID)
+	}
 	var buf bytes.Buffer
 	if err := bd.Write(&buf); err != nil {
 		return err
@@ -127,6 +139,12 @@ func (bd *ChainRecord) Write(w io.Writer) error {
 	if err := util.WriteBoolByte(w, bd.Active); err != nil {
 		return err
 	}
+	if _, err := w.Write(bd.Address[:]); err != nil {
+		return err
+	}
+	if err := util.WriteStrings16(w, bd.AccessNodes); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -144,12 +162,34 @@ func (bd *ChainRecord) Read(r io.Reader) error {
 	if err = util.ReadBoolByte(r, &bd.Active); err != nil {
 		return err
 	}
-	return nil
+	// records saved before committee rotation was introduced don't contain the address
+	if err = util.ReadAddress(r, &bd.Address); err == io.EOF {
+		bd.Address = address.Address(bd.ChainID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	bd.AccessNodes, err = util.ReadStrings16(r)
+	return err
 }
 
 func (bd *ChainRecord) String() string {
 	ret := "      Target: " + bd.ChainID.String() + "\n"
 	ret += "      Color: " + bd.Color.String() + "\n"
+	ret += "      Address: " + bd.Address.String() + "\n"
 	ret += fmt.Sprintf("      Committee nodes: %+v\n", bd.CommitteeNodes)
+	ret += fmt.Sprintf("      Access nodes: %+v\n", bd.AccessNodes)
 	return ret
 }
+
+// IsAccessNode returns true if the node with any of the given network IDs or identities
+// follows the chain as an access node
+func (bd *ChainRecord) IsAccessNode(peerIDs ...string) bool {
+	for _, peerID := range peerIDs {
+		if util.StringInList(peerID, bd.AccessNodes) {
+			return true
+		}
+	}
+	return false
+}
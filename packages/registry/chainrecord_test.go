@@ -0,0 +1,47 @@
+package registry
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainRecordMarshal(t *testing.T) {
+	rec := &ChainRecord{
+		ChainID:        coretypes.ChainID{1, 2, 3},
+		Color:          balance.Color{4, 5, 6},
+		CommitteeNodes: []string{"wasp1:4000", "wasp2:4000"},
+		Active:         true,
+		Address:        address.Address{7, 8, 9},
+		AccessNodes:    []string{"wasp3:4000"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, rec.Write(&buf))
+
+	back := new(ChainRecord)
+	require.NoError(t, back.Read(bytes.NewReader(buf.Bytes())))
+	require.EqualValues(t, rec, back)
+	require.True(t, back.IsAccessNode("wasp3:4000"))
+	require.False(t, back.IsAccessNode("wasp1:4000"))
+}
+
+func TestChainRecordReadOld(t *testing.T) {
+	chainID := coretypes.ChainID{1, 2, 3}
+	// record saved without committee address and access nodes
+	var buf bytes.Buffer
+	require.NoError(t, chainID.Write(&buf))
+	buf.Write(balance.Color{4, 5, 6}.Bytes())
+	require.NoError(t, util.WriteStrings16(&buf, []string{"wasp1:4000"}))
+	require.NoError(t, util.WriteBoolByte(&buf, true))
+
+	back := new(ChainRecord)
+	require.NoError(t, back.Read(bytes.NewReader(buf.Bytes())))
+	require.EqualValues(t, address.Address(chainID), back.Address)
+	require.EqualValues(t, 0, len(back.AccessNodes))
+	require.True(t, back.Active)
+}
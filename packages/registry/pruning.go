@@ -0,0 +1,93 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// PruningPolicy is the retention policy of the blocks of the chain in the node. A block is retained if it is one of
+// the last KeepBlocks blocks or if it is newer than KeepDuration. Zero value of the parameter disables the rule.
+// If both rules are disabled, nothing is pruned
+type PruningPolicy struct {
+	KeepBlocks   uint32
+	KeepDuration time.Duration
+}
+
+func (p *PruningPolicy) IsEnabled() bool {
+	return p.KeepBlocks > 0 || p.KeepDuration > 0
+}
+
+func (p *PruningPolicy) String() string {
+	if !p.IsEnabled() {
+		return "keep all blocks"
+	}
+	ret := ""
+	if p.KeepBlocks > 0 {
+		ret = fmt.Sprintf("keep last %d blocks", p.KeepBlocks)
+	}
+	if p.KeepDuration > 0 {
+		if ret != "" {
+			ret += " or "
+		}
+		ret += fmt.Sprintf("keep blocks newer than %v", p.KeepDuration)
+	}
+	return ret
+}
+
+func (p *PruningPolicy) Write(w io.Writer) error {
+	if err := util.WriteUint32(w, p.KeepBlocks); err != nil {
+		return err
+	}
+	return util.WriteInt64(w, int64(p.KeepDuration))
+}
+
+func (p *PruningPolicy) Read(r io.Reader) error {
+	if err := util.ReadUint32(r, &p.KeepBlocks); err != nil {
+		return err
+	}
+	var d int64
+	if err := util.ReadInt64(r, &d); err != nil {
+		return err
+	}
+	p.KeepDuration = time.Duration(d)
+	return nil
+}
+
+func dbKeyForPruningPolicy(chainID *coretypes.ChainID) []byte {
+	return dbprovider.MakeKey(dbprovider.ObjectTypePruningPolicy, chainID[:])
+}
+
+// SavePruningPolicy stores the pruning policy of the chain. It overrides the default policy of the node
+func (r *Impl) SavePruningPolicy(chainID *coretypes.ChainID, policy *PruningPolicy) error {
+	if err := r.dbProvider.GetRegistryPartition().Set(dbKeyForPruningPolicy(chainID), util.MustBytes(policy)); err != nil {
+		return err
+	}
+	r.log.Infof("pruning policy of chain %s has been saved: %s", chainID.String(), policy.String())
+	return nil
+}
+
+// GetPruningPolicy returns the pruning policy of the chain or nil if the chain has no own policy
+func (r *Impl) GetPruningPolicy(chainID *coretypes.ChainID) (*PruningPolicy, error) {
+	data, err := r.dbProvider.GetRegistryPartition().Get(dbKeyForPruningPolicy(chainID))
+	if err == kvstore.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret := &PruningPolicy{}
+	if err = ret.Read(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
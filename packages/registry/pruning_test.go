@@ -0,0 +1,38 @@
+package registry
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestPruningPolicy(t *testing.T) {
+	log := testutil.NewLogger(t)
+	reg := NewRegistry(pairing.NewSuiteBn256(), log, dbprovider.NewInMemoryDBProvider(log))
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+
+	policy, err := reg.GetPruningPolicy(&chainID)
+	require.NoError(t, err)
+	require.Nil(t, policy)
+
+	require.NoError(t, reg.SavePruningPolicy(&chainID, &PruningPolicy{KeepBlocks: 1000, KeepDuration: 30 * 24 * time.Hour}))
+	policy, err = reg.GetPruningPolicy(&chainID)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, policy.KeepBlocks)
+	require.Equal(t, 30*24*time.Hour, policy.KeepDuration)
+}
+
+func TestPruningPolicyBytes(t *testing.T) {
+	p := &PruningPolicy{KeepBlocks: 100, KeepDuration: 72 * time.Hour}
+	var buf bytes.Buffer
+	require.NoError(t, p.Write(&buf))
+	var p2 PruningPolicy
+	require.NoError(t, p2.Read(bytes.NewReader(buf.Bytes())))
+	require.Equal(t, *p, p2)
+}
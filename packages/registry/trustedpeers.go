@@ -0,0 +1,104 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/mr-tron/base58"
+	"go.dedis.ch/kyber/v3"
+)
+
+// implements peering.TrustedPeerStore interface
+
+func dbKeyForNodeCertificate() []byte {
+	return dbprovider.MakeKey(dbprovider.ObjectTypeNodeCertificate)
+}
+
+func dbKeyForTrustedPeer(pubKey kyber.Point) ([]byte, error) {
+	data, err := pubKey.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return dbprovider.MakeKey(dbprovider.ObjectTypeTrustedPeer, data), nil
+}
+
+// SaveNodeCertificate stores the certificate of the own node issued by the node owner
+func (r *Impl) SaveNodeCertificate(cert *peering.NodeCertificate) error {
+	pubKey, err := r.GetNodePublicKey()
+	if err != nil {
+		return err
+	}
+	if !cert.NodePubKey.Equal(pubKey) {
+		return errors.New("the certificate is issued for another node")
+	}
+	if err = cert.Verify(); err != nil {
+		return err
+	}
+	if err = r.dbProvider.GetRegistryPartition().Set(dbKeyForNodeCertificate(), cert.Bytes()); err != nil {
+		return err
+	}
+	r.log.Infof("node certificate has been saved. Owner: %s", cert.OwnerPubKey)
+	return nil
+}
+
+// GetNodeCertificate returns the certificate of the own node or nil if the node has no certificate
+func (r *Impl) GetNodeCertificate() (*peering.NodeCertificate, error) {
+	data, err := r.dbProvider.GetRegistryPartition().Get(dbKeyForNodeCertificate())
+	if err == kvstore.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return peering.NodeCertificateFromBytes(data, r.suite)
+}
+
+// SaveTrustedPeer implements peering.TrustedPeerStore
+func (r *Impl) SaveTrustedPeer(tp *peering.TrustedPeer) error {
+	dbKey, err := dbKeyForTrustedPeer(tp.Cert.NodePubKey)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err = util.WriteString16(&buf, tp.NetID); err != nil {
+		return err
+	}
+	if err = tp.Cert.Write(&buf); err != nil {
+		return err
+	}
+	return r.dbProvider.GetRegistryPartition().Set(dbKey, buf.Bytes())
+}
+
+// GetTrustedPeers implements peering.TrustedPeerStore
+func (r *Impl) GetTrustedPeers() ([]*peering.TrustedPeer, error) {
+	ret := make([]*peering.TrustedPeer, 0)
+	err := r.dbProvider.GetRegistryPartition().Iterate([]byte{dbprovider.ObjectTypeTrustedPeer}, func(key kvstore.Key, value kvstore.Value) bool {
+		if tp, err := r.trustedPeerFromBytes(value); err == nil {
+			ret = append(ret, tp)
+		} else {
+			r.log.Warnf("corrupted trusted peer record with key %s", base58.Encode(key))
+		}
+		return true
+	})
+	return ret, err
+}
+
+func (r *Impl) trustedPeerFromBytes(data []byte) (*peering.TrustedPeer, error) {
+	rdr := bytes.NewReader(data)
+	tp := &peering.TrustedPeer{Cert: new(peering.NodeCertificate)}
+	var err error
+	if tp.NetID, err = util.ReadString16(rdr); err != nil {
+		return nil, err
+	}
+	if err = tp.Cert.Read(rdr, r.suite); err != nil {
+		return nil, err
+	}
+	return tp, nil
+}
@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+func TestNodeCertificate(t *testing.T) {
+	log := testutil.NewLogger(t)
+	suite := pairing.NewSuiteBn256()
+	reg := NewRegistry(suite, log, dbprovider.NewInMemoryDBProvider(log))
+
+	cert, err := reg.GetNodeCertificate()
+	require.NoError(t, err)
+	require.Nil(t, cert)
+
+	owner := ed25519.GenerateKeyPair()
+	otherCert, err := peering.NewNodeCertificate(key.NewKeyPair(suite).Public, owner)
+	require.NoError(t, err)
+	require.Error(t, reg.SaveNodeCertificate(otherCert))
+
+	pubKey, err := reg.GetNodePublicKey()
+	require.NoError(t, err)
+	cert, err = peering.NewNodeCertificate(pubKey, owner)
+	require.NoError(t, err)
+	require.NoError(t, reg.SaveNodeCertificate(cert))
+
+	back, err := reg.GetNodeCertificate()
+	require.NoError(t, err)
+	require.NoError(t, back.Verify())
+	require.EqualValues(t, owner.PublicKey, back.OwnerPubKey)
+}
+
+func TestTrustedPeers(t *testing.T) {
+	log := testutil.NewLogger(t)
+	suite := pairing.NewSuiteBn256()
+	reg := NewRegistry(suite, log, dbprovider.NewInMemoryDBProvider(log))
+
+	cert, err := peering.NewNodeCertificate(key.NewKeyPair(suite).Public, ed25519.GenerateKeyPair())
+	require.NoError(t, err)
+	require.NoError(t, reg.SaveTrustedPeer(&peering.TrustedPeer{NetID: "wasp1:4000", Cert: cert}))
+	// the peer moved to another address
+	require.NoError(t, reg.SaveTrustedPeer(&peering.TrustedPeer{NetID: "wasp2:4000", Cert: cert}))
+
+	trusted, err := reg.GetTrustedPeers()
+	require.NoError(t, err)
+	require.Len(t, trusted, 1)
+	require.EqualValues(t, "wasp2:4000", trusted[0].NetID)
+	require.EqualValues(t, cert.Identity(), trusted[0].Cert.Identity())
+	require.NoError(t, trusted[0].Cert.Verify())
+}
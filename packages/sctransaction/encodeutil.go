@@ -10,6 +10,18 @@ import (
 
 const stateBlockMask = byte(0x80)
 
+// The data payload starts with the version marker followed by the version byte.
+// The marker is never a valid meta byte: a transaction without the state block
+// and without requests can't be encoded. A payload without the marker is
+// in the legacy layout of version 0
+const (
+	payloadVersionMarker = byte(0)
+	payloadVersionLegacy = byte(0)
+	// payloadVersion 1: 64 bit request timelock, request gas budget and target chain address,
+	// chain ID and Merkle root of the state in the state block
+	payloadVersion = byte(1)
+)
+
 func encodeMetaByte(hasState bool, numRequests byte) (byte, error) {
 	if numRequests > 127 {
 		return 0, errors.New("can't be more than 127 requests")
@@ -27,6 +27,13 @@ func (prop *properties) MustChainID() *coretypes.ChainID {
 	return &prop.chainID
 }
 
+func (prop *properties) MustChainAddress() *address.Address {
+	if !prop.isState {
+		panic("MustChainAddress: must be a state transaction")
+	}
+	return &prop.chainAddress
+}
+
 func (prop *properties) MustStateColor() *balance.Color {
 	if !prop.isState {
 		panic("MustStateColor: must be a state transaction")
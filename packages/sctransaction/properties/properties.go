@@ -32,7 +32,8 @@ type properties struct {
 	isOrigin bool
 	// if isState == true: chainID
 	chainID coretypes.ChainID
-	// chainAddress == chainID
+	// if isState == true: address of the chain token output.
+	// Equal to chainID unless the chain was moved to another committee
 	chainAddress address.Address
 	// if isState == true: smart contract color
 	stateColor balance.Color
@@ -149,6 +150,9 @@ func (prop *properties) analyzeStateBlock(tx *sctransaction.Transaction) error {
 	if err != nil {
 		return err
 	}
+	if chainID := stateSection.ChainID(); chainID != coretypes.NilChainID {
+		prop.chainID = chainID
+	}
 	if prop.isOrigin {
 		prop.stateColor = balance.Color(prop.txid)
 	} else {
@@ -169,14 +173,14 @@ func (prop *properties) analyzeRequestBlocks(tx *sctransaction.Transaction) erro
 	}
 	prop.numRequests = len(tx.Requests())
 
-	// sum up transfers of requests by target chain
-	reqTransfersByTargetChain := make(map[coretypes.ChainID]map[balance.Color]int64)
+	// sum up transfers of requests by target chain address
+	reqTransfersByTargetAddress := make(map[address.Address]map[balance.Color]int64)
 	for _, req := range tx.Requests() {
-		chainid := req.Target().ChainID()
-		m, ok := reqTransfersByTargetChain[chainid]
+		targetAddr := req.TargetAddress()
+		m, ok := reqTransfersByTargetAddress[targetAddr]
 		if !ok {
 			m = make(map[balance.Color]int64)
-			reqTransfersByTargetChain[chainid] = m
+			reqTransfersByTargetAddress[targetAddr] = m
 		}
 		req.Transfer().AddToMap(m)
 		// add one request token
@@ -186,7 +190,7 @@ func (prop *properties) analyzeRequestBlocks(tx *sctransaction.Transaction) erro
 	var err error
 	// validate all outputs against request transfers
 	tx.Transaction.Outputs().ForEach(func(addr address.Address, bals []*balance.Balance) bool {
-		m, ok := reqTransfersByTargetChain[coretypes.ChainID(addr)]
+		m, ok := reqTransfersByTargetAddress[addr]
 		if !ok {
 			// ignore outputs to outside addresses
 			return true
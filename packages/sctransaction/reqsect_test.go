@@ -2,7 +2,12 @@ package sctransaction
 
 import (
 	"bytes"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -20,3 +25,91 @@ func TestWriteRead(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, buf1.Bytes(), buf.Bytes())
 }
+
+func TestWriteReadGasBudget(t *testing.T) {
+	cid := coretypes.NewContractID(coretypes.ChainID{}, root.Interface.Hname())
+	rsec := NewRequestSectionByWallet(cid, coretypes.EntryPointInit).WithGasBudget(12345)
+	var buf bytes.Buffer
+	err := rsec.Write(&buf)
+	require.NoError(t, err)
+	rsecBack := &RequestSection{}
+	err = rsecBack.Read(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.EqualValues(t, 12345, rsecBack.GasBudget())
+	require.EqualValues(t, 12345, rsec.Clone().GasBudget())
+}
+
+func TestReadLegacyPayload(t *testing.T) {
+	cid := coretypes.NewContractID(coretypes.ChainID{}, root.Interface.Hname())
+	rsec := NewRequestSectionByWallet(cid, coretypes.EntryPointInit).WithTransfer(nil)
+
+	// version 0 layout: no version marker, 32 bit timelock, no gas budget and no chain address
+	var buf bytes.Buffer
+	buf.WriteByte(1)
+	require.NoError(t, rsec.senderContractHname.Write(&buf))
+	require.NoError(t, rsec.targetContractID.Write(&buf))
+	require.NoError(t, util.WriteUint32(&buf, 1700000000))
+	require.NoError(t, rsec.entryPoint.Write(&buf))
+	require.NoError(t, rsec.args.Write(&buf))
+	require.NoError(t, cbalances.WriteColoredBalances(&buf, rsec.transfer))
+
+	tx := &Transaction{}
+	err := tx.readDataPayload(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, tx.Requests(), 1)
+	back := tx.Requests()[0]
+	require.EqualValues(t, 1700000000, back.Timelock())
+	require.EqualValues(t, 0, back.GasBudget())
+	require.EqualValues(t, cid, back.Target())
+	require.EqualValues(t, address.Address(cid.ChainID()), back.TargetAddress())
+
+	// current layout round trip
+	tx = &Transaction{requestSection: []*RequestSection{rsec.WithGasBudget(777)}}
+	var buf1 bytes.Buffer
+	require.NoError(t, tx.writeDataPayload(&buf1))
+	require.EqualValues(t, []byte{payloadVersionMarker, payloadVersion}, buf1.Bytes()[:2])
+	txBack := &Transaction{}
+	require.NoError(t, txBack.readDataPayload(bytes.NewReader(buf1.Bytes())))
+	require.EqualValues(t, 777, txBack.Requests()[0].GasBudget())
+}
+
+func TestReadLegacyStateSection(t *testing.T) {
+	color := balance.Color(hashing.HashStrings("chain"))
+	stateHash := hashing.HashStrings("state")
+
+	// version 0 layout: no version marker, no chain ID and no Merkle root
+	var buf bytes.Buffer
+	buf.WriteByte(stateBlockMask)
+	buf.Write(color[:])
+	require.NoError(t, util.WriteUint32(&buf, 5))
+	require.NoError(t, util.WriteUint64(&buf, 1700000000))
+	require.NoError(t, stateHash.Write(&buf))
+
+	tx := &Transaction{}
+	require.NoError(t, tx.readDataPayload(bytes.NewReader(buf.Bytes())))
+	stateSection, ok := tx.State()
+	require.True(t, ok)
+	require.EqualValues(t, color, stateSection.Color())
+	require.EqualValues(t, 5, stateSection.BlockIndex())
+	require.EqualValues(t, 1700000000, stateSection.Timestamp())
+	require.EqualValues(t, stateHash, stateSection.StateHash())
+	require.EqualValues(t, coretypes.NilChainID, stateSection.ChainID())
+	require.EqualValues(t, hashing.NilHash, stateSection.MerkleRoot())
+
+	// current layout round trip
+	chainID := coretypes.ChainID{1, 2, 3}
+	merkleRoot := hashing.HashStrings("root")
+	tx = &Transaction{stateSection: NewStateSection(NewStateSectionParams{
+		Color:      color,
+		ChainID:    chainID,
+		BlockIndex: 5,
+		StateHash:  stateHash,
+		MerkleRoot: merkleRoot,
+	})}
+	var buf1 bytes.Buffer
+	require.NoError(t, tx.writeDataPayload(&buf1))
+	txBack := &Transaction{}
+	require.NoError(t, txBack.readDataPayload(bytes.NewReader(buf1.Bytes())))
+	require.EqualValues(t, chainID, txBack.MustState().ChainID())
+	require.EqualValues(t, merkleRoot, txBack.MustState().MerkleRoot())
+}
@@ -1,6 +1,7 @@
 package sctransaction
 
 import (
+	"errors"
 	"fmt"
 	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
 	"io"
@@ -13,11 +14,12 @@ import (
 	"github.com/iotaledger/wasp/packages/util"
 )
 
-// FIXME timelock uint32 ref Year 2038 problem https://en.wikipedia.org/wiki/Year_2038_problem
-// signed int32 can store values uo to 03:14:07 UTC on 19 January 2038
-// But if we use uint32 we should extend the range twice, something like until 2092. Not a problem ???
-// other wise we can use values in 'timelock' field of the request block
-// counting from 2020.01.01 Then it would extend until 2140 or so
+var nilAddress address.Address
+
+// ErrUndecryptableArgs is the error of the encrypted request arguments which can't be decrypted with the key
+// of the node. Other committee nodes may still be able to decrypt them: whether the request is settled with
+// this error is decided by the committee, not by the node
+var ErrUndecryptableArgs = errors.New("request arguments can't be decrypted")
 
 type RequestSection struct {
 	// senderAddress contract index
@@ -26,14 +28,21 @@ type RequestSection struct {
 	senderContractHname coretypes.Hname
 	// ID of the target smart contract
 	targetContractID coretypes.ContractID
+	// address of the target chain the tokens are sent to. Nil address means address of the
+	// target chain ID, i.e. the chain was never moved to another committee
+	chainAddress address.Address
 	// entry point code
 	entryPoint coretypes.Hname
 	// timelock in Unix seconds.
 	// Request will only be processed when time reaches
 	// specified moment. It is guaranteed that timestamp of the state transaction which
 	// settles the request is greater or equal to the request timelock.
-	// 0 timelock naturally means it has no effect
-	timelock uint32
+	// 0 timelock naturally means it has no effect.
+	// It is 64 bit to avoid the Year 2038 problem of the 32 bit Unix time
+	timelock int64
+	// maximum amount of gas the request is allowed to burn in the VM.
+	// 0 means default budget is used
+	gasBudget uint64
 	// request arguments, not decoded yet wrt blobRefs
 	args requestargs.RequestArgs
 	// decoded args, if not nil. If nil, it means it wasn't
@@ -75,7 +84,9 @@ func (req *RequestSection) Clone() *RequestSection {
 	}
 	ret := NewRequestSection(req.senderContractHname, req.targetContractID, req.entryPoint).
 		WithTimelock(req.timelock).
-		WithTransfer(req.transfer)
+		WithGasBudget(req.gasBudget).
+		WithTransfer(req.transfer).
+		WithChainAddress(req.chainAddress)
 	ret.args = req.args.Clone()
 	return ret
 }
@@ -88,6 +99,21 @@ func (req *RequestSection) Target() coretypes.ContractID {
 	return req.targetContractID
 }
 
+// TargetAddress is the address the request token and the transfer are sent to:
+// the current address of the target chain's committee
+func (req *RequestSection) TargetAddress() address.Address {
+	if req.chainAddress == nilAddress {
+		return address.Address(req.targetContractID.ChainID())
+	}
+	return req.chainAddress
+}
+
+// WithChainAddress sets the address of the target chain if it was moved to another committee
+func (req *RequestSection) WithChainAddress(addr address.Address) *RequestSection {
+	req.chainAddress = addr
+	return req
+}
+
 // WithArgs sets encoded args
 func (req *RequestSection) WithArgs(args requestargs.RequestArgs) *RequestSection {
 	req.args = args
@@ -99,12 +125,40 @@ func (req *RequestSection) SolidArgs() dict.Dict {
 	return req.solidArgs
 }
 
-// SolidifyArgs return true if solidified successfully
-func (req *RequestSection) SolidifyArgs(reg coretypes.BlobCache) (bool, error) {
+// EncryptArgs encrypts the args to the recipients, normally the public key shares of the
+// committee of the target chain. Only committee members can decrypt them
+func (req *RequestSection) EncryptArgs(rcpt *requestargs.Recipients) error {
+	encrypted, err := req.args.Encrypt(rcpt)
+	if err != nil {
+		return err
+	}
+	req.args = encrypted
+	return nil
+}
+
+// HasEncryptedArgs returns true if the args are encrypted
+func (req *RequestSection) HasEncryptedArgs() bool {
+	return req.args.IsEncrypted()
+}
+
+// SolidifyArgs return true if solidified successfully.
+// Encrypted args are decrypted first with the decrypter, normally the DKShare of the node.
+// If decryption fails, the returned error wraps ErrUndecryptableArgs and the args remain not solid
+func (req *RequestSection) SolidifyArgs(reg coretypes.BlobCache, dec ...requestargs.Decrypter) (bool, error) {
 	if req.solidArgs != nil {
 		return true, nil
 	}
-	solid, ok, err := req.args.SolidifyRequestArguments(reg)
+	args := req.args
+	if args.IsEncrypted() {
+		if len(dec) == 0 || dec[0] == nil {
+			return false, fmt.Errorf("request arguments are encrypted, but no key is available")
+		}
+		var err error
+		if args, err = args.Decrypt(dec[0]); err != nil {
+			return false, fmt.Errorf("%w: %v", ErrUndecryptableArgs, err)
+		}
+	}
+	solid, ok, err := args.SolidifyRequestArguments(reg)
 	if err != nil || !ok {
 		return ok, err
 	}
@@ -119,19 +173,29 @@ func (req *RequestSection) EntryPointCode() coretypes.Hname {
 	return req.entryPoint
 }
 
-func (req *RequestSection) Timelock() uint32 {
+func (req *RequestSection) Timelock() int64 {
 	return req.timelock
 }
 
+// GasBudget returns gas budget specified by the sender. 0 means default
+func (req *RequestSection) GasBudget() uint64 {
+	return req.gasBudget
+}
+
 func (req *RequestSection) Transfer() coretypes.ColoredBalances {
 	return req.transfer
 }
 
-func (req *RequestSection) WithTimelock(tl uint32) *RequestSection {
+func (req *RequestSection) WithTimelock(tl int64) *RequestSection {
 	req.timelock = tl
 	return req
 }
 
+func (req *RequestSection) WithGasBudget(gasBudget uint64) *RequestSection {
+	req.gasBudget = gasBudget
+	return req
+}
+
 func (req *RequestSection) WithTransfer(transfer coretypes.ColoredBalances) *RequestSection {
 	if transfer == nil {
 		transfer = cbalances.NewFromMap(nil)
@@ -141,7 +205,7 @@ func (req *RequestSection) WithTransfer(transfer coretypes.ColoredBalances) *Req
 }
 
 func (req *RequestSection) WithTimelockUntil(deadline time.Time) *RequestSection {
-	return req.WithTimelock(uint32(deadline.Unix()))
+	return req.WithTimelock(deadline.Unix())
 }
 
 // encoding
@@ -153,9 +217,21 @@ func (req *RequestSection) Write(w io.Writer) error {
 	if err := req.targetContractID.Write(w); err != nil {
 		return err
 	}
-	if err := util.WriteUint32(w, req.timelock); err != nil {
+	if err := util.WriteInt64(w, req.timelock); err != nil {
+		return err
+	}
+	if err := util.WriteUint64(w, req.gasBudget); err != nil {
+		return err
+	}
+	hasChainAddress := req.chainAddress != nilAddress
+	if err := util.WriteBoolByte(w, hasChainAddress); err != nil {
 		return err
 	}
+	if hasChainAddress {
+		if _, err := w.Write(req.chainAddress[:]); err != nil {
+			return err
+		}
+	}
 	if err := req.entryPoint.Write(w); err != nil {
 		return err
 	}
@@ -169,14 +245,42 @@ func (req *RequestSection) Write(w io.Writer) error {
 }
 
 func (req *RequestSection) Read(r io.Reader) error {
+	return req.read(r, payloadVersion)
+}
+
+// read parses the request section in the layout of the given version of the data payload
+func (req *RequestSection) read(r io.Reader, version byte) error {
 	if err := req.senderContractHname.Read(r); err != nil {
 		return err
 	}
 	if err := req.targetContractID.Read(r); err != nil {
 		return err
 	}
-	if err := util.ReadUint32(r, &req.timelock); err != nil {
-		return err
+	req.chainAddress = nilAddress
+	if version == payloadVersionLegacy {
+		// 32 bit timelock, default gas budget, the chain was never moved
+		var timelock uint32
+		if err := util.ReadUint32(r, &timelock); err != nil {
+			return err
+		}
+		req.timelock = int64(timelock)
+		req.gasBudget = 0
+	} else {
+		if err := util.ReadInt64(r, &req.timelock); err != nil {
+			return err
+		}
+		if err := util.ReadUint64(r, &req.gasBudget); err != nil {
+			return err
+		}
+		var hasChainAddress bool
+		if err := util.ReadBoolByte(r, &hasChainAddress); err != nil {
+			return err
+		}
+		if hasChainAddress {
+			if err := util.ReadAddress(r, &req.chainAddress); err != nil {
+				return err
+			}
+		}
 	}
 	if err := req.entryPoint.Read(r); err != nil {
 		return err
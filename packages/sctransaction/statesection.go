@@ -3,6 +3,7 @@ package sctransaction
 import (
 	"fmt"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/util"
 	"io"
@@ -15,6 +16,9 @@ type StateSection struct {
 	// color of the chain which is updated
 	// color contains balance.NEW_COLOR for the origin transaction
 	color balance.Color
+	// chainID of the chain. Nil chain ID means it is the address of the chain token output,
+	// i.e. the chain was never moved to another committee address
+	chainID coretypes.ChainID
 	// blockIndex is 0 for the origin transaction
 	// consensus maintains incremental sequence of state indexes
 	blockIndex uint32
@@ -22,20 +26,26 @@ type StateSection struct {
 	timestamp int64
 	// stateHash is hash of the state it is locked in the transaction
 	stateHash hashing.HashValue
+	// merkleRoot is the root of the Merkle trie of state variables. Used to verify proofs of inclusion
+	merkleRoot hashing.HashValue
 }
 
 type NewStateSectionParams struct {
 	Color      balance.Color
+	ChainID    coretypes.ChainID
 	BlockIndex uint32
 	StateHash  hashing.HashValue
+	MerkleRoot hashing.HashValue
 	Timestamp  int64
 }
 
 func NewStateSection(par NewStateSectionParams) *StateSection {
 	return &StateSection{
 		color:      par.Color,
+		chainID:    par.ChainID,
 		blockIndex: par.BlockIndex,
 		stateHash:  par.StateHash,
+		merkleRoot: par.MerkleRoot,
 		timestamp:  par.Timestamp,
 	}
 }
@@ -46,8 +56,10 @@ func (sb *StateSection) Clone() *StateSection {
 	}
 	return NewStateSection(NewStateSectionParams{
 		Color:      sb.color,
+		ChainID:    sb.chainID,
 		BlockIndex: sb.blockIndex,
 		StateHash:  sb.stateHash,
+		MerkleRoot: sb.merkleRoot,
 		Timestamp:  sb.timestamp,
 	})
 }
@@ -60,6 +72,11 @@ func (sb *StateSection) Color() balance.Color {
 	return sb.color
 }
 
+// ChainID returns the chain ID of the state section or nil chain ID if it is not set
+func (sb *StateSection) ChainID() coretypes.ChainID {
+	return sb.chainID
+}
+
 func (sb *StateSection) BlockIndex() uint32 {
 	return sb.blockIndex
 }
@@ -72,6 +89,15 @@ func (sb *StateSection) StateHash() hashing.HashValue {
 	return sb.stateHash
 }
 
+func (sb *StateSection) MerkleRoot() hashing.HashValue {
+	return sb.merkleRoot
+}
+
+func (sb *StateSection) WithMerkleRoot(root hashing.HashValue) *StateSection {
+	sb.merkleRoot = root
+	return sb
+}
+
 func (sb *StateSection) WithStateParams(stateIndex uint32, h hashing.HashValue, ts int64) *StateSection {
 	sb.blockIndex = stateIndex
 	sb.stateHash = h
@@ -85,6 +111,9 @@ func (sb *StateSection) Write(w io.Writer) error {
 	if _, err := w.Write(sb.color[:]); err != nil {
 		return err
 	}
+	if err := sb.chainID.Write(w); err != nil {
+		return err
+	}
 	if err := util.WriteUint32(w, sb.blockIndex); err != nil {
 		return err
 	}
@@ -94,13 +123,27 @@ func (sb *StateSection) Write(w io.Writer) error {
 	if err := sb.stateHash.Write(w); err != nil {
 		return err
 	}
+	if err := sb.merkleRoot.Write(w); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (sb *StateSection) Read(r io.Reader) error {
+	return sb.read(r, payloadVersion)
+}
+
+func (sb *StateSection) read(r io.Reader, version byte) error {
 	if n, err := r.Read(sb.color[:]); err != nil || n != balance.ColorLength {
 		return fmt.Errorf("error while reading color: %v", err)
 	}
+	sb.chainID = coretypes.NilChainID
+	sb.merkleRoot = hashing.NilHash
+	if version != payloadVersionLegacy {
+		if err := sb.chainID.Read(r); err != nil {
+			return err
+		}
+	}
 	if err := util.ReadUint32(r, &sb.blockIndex); err != nil {
 		return err
 	}
@@ -112,5 +155,12 @@ func (sb *StateSection) Read(r io.Reader) error {
 	if err := sb.stateHash.Read(r); err != nil {
 		return err
 	}
+	if version == payloadVersionLegacy {
+		// no chain ID, the chain was never moved. No Merkle root
+		return nil
+	}
+	if err := sb.merkleRoot.Read(r); err != nil {
+		return err
+	}
 	return nil
 }
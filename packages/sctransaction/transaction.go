@@ -145,6 +145,9 @@ func (tx *Transaction) writeDataPayload(w io.Writer) error {
 	if err != nil {
 		return err
 	}
+	if _, err = w.Write([]byte{payloadVersionMarker, payloadVersion}); err != nil {
+		return err
+	}
 	if err = util.WriteByte(w, b); err != nil {
 		return err
 	}
@@ -161,26 +164,39 @@ func (tx *Transaction) writeDataPayload(w io.Writer) error {
 	return nil
 }
 
-// readDataPayload parses data stream of data payload to value transaction as smart contract meta data
+// readDataPayload parses data stream of data payload to value transaction as smart contract meta data.
+// Payloads of older versions, which may still be on the tangle, are parsed too
 func (tx *Transaction) readDataPayload(r io.Reader) error {
 	var hasState bool
 	var numRequests byte
-	if b, err := util.ReadByte(r); err != nil {
+	b, err := util.ReadByte(r)
+	if err != nil {
 		return err
-	} else {
-		hasState, numRequests = decodeMetaByte(b)
 	}
+	version := payloadVersionLegacy
+	if b == payloadVersionMarker {
+		if version, err = util.ReadByte(r); err != nil {
+			return err
+		}
+		if version == payloadVersionLegacy || version > payloadVersion {
+			return fmt.Errorf("unsupported version of the data payload: %d", version)
+		}
+		if b, err = util.ReadByte(r); err != nil {
+			return err
+		}
+	}
+	hasState, numRequests = decodeMetaByte(b)
 	var stateBlock *StateSection
 	if hasState {
 		stateBlock = &StateSection{}
-		if err := stateBlock.Read(r); err != nil {
+		if err := stateBlock.read(r, version); err != nil {
 			return err
 		}
 	}
 	reqBlks := make([]*RequestSection, numRequests)
 	for i := range reqBlks {
 		reqBlks[i] = &RequestSection{}
-		if err := reqBlks[i].Read(r); err != nil {
+		if err := reqBlks[i].read(r, version); err != nil {
 			return err
 		}
 	}
@@ -82,7 +82,7 @@ func (txb *Builder) SetStateParams(stateIndex uint32, stateHash hashing.HashValu
 // AddRequestSectionWithTransfer adds request block with the request
 // token and adds respective outputs for the colored transfers
 func (txb *Builder) AddRequestSection(req *sctransaction.RequestSection) error {
-	targetAddr := (address.Address)(req.Target().ChainID())
+	targetAddr := req.TargetAddress()
 	if err := txb.MintColoredTokens(targetAddr, balance.ColorIOTA, 1); err != nil {
 		return err
 	}
@@ -117,7 +117,7 @@ func (txb *Builder) mintNewTokens() error {
 			panic("mintNewTokens: internal error")
 		}
 		for _, reqBlk := range txb.requestBlocks {
-			targetAddr := address.Address(reqBlk.Target().ChainID())
+			targetAddr := reqBlk.TargetAddress()
 			if addr == targetAddr {
 				return fmt.Errorf("mintNewTokens: new tokens cannot be minted to the request's target address")
 			}
@@ -0,0 +1,488 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package snapshot implements export and import of the solid state of a chain. A snapshot is
+// a consistent copy of the chain partition of the database at one block index: the state variables,
+// the latest block and the anchor transaction which approved it, together with the chain record and
+// the entries of the blob cache of the registry which hold the blobs of the chain.
+// A node which imports the snapshot starts the chain from that block index instead of replaying its history.
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	_ "github.com/iotaledger/wasp/packages/sctransaction/properties"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/state/merkle"
+	"github.com/iotaledger/wasp/packages/txutil"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm/core/blob"
+)
+
+var nilAddress address.Address
+
+// Version is the version of the snapshot format
+const Version = uint16(1)
+
+var magic = [4]byte{'W', 'S', 'N', 'P'}
+
+const (
+	// attempts to export a consistent snapshot while the state is being updated
+	exportAttempts     = 5
+	exportRetryTimeout = 500 * time.Millisecond
+)
+
+// Snapshot is the solid state of the chain at the block index
+type Snapshot struct {
+	ChainRecord       *registry.ChainRecord
+	SolidState        state.VirtualState // header of the state: block index, timestamp, state hash and Merkle root
+	Block             state.Block
+	AnchorTransaction *sctransaction.Transaction
+	Variables         dict.Dict
+	Blobs             map[hashing.HashValue][]byte
+
+	// the serialized solid state, as stored in the db
+	solidStateData []byte
+	// the Merkle trie of the variables, built on demand
+	trie *merkle.Trie
+}
+
+func (s *Snapshot) ChainID() coretypes.ChainID {
+	return s.ChainRecord.ChainID
+}
+
+func (s *Snapshot) BlockIndex() uint32 {
+	return s.SolidState.BlockIndex()
+}
+
+// Export writes a consistent snapshot of the solid state of the chain. If the state is updated
+// while it is being read, the export is repeated
+func Export(dbp *dbprovider.DBProvider, chainID *coretypes.ChainID, w io.Writer) (*Snapshot, error) {
+	var s *Snapshot
+	var err error
+	for i := 0; i < exportAttempts; i++ {
+		if i > 0 {
+			time.Sleep(exportRetryTimeout)
+		}
+		if s, err = load(dbp, chainID); err != nil {
+			continue
+		}
+		if err = s.Verify(); err == nil {
+			return s, s.Write(w)
+		}
+	}
+	return nil, fmt.Errorf("failed to export the snapshot of chain %s: %v", chainID.String(), err)
+}
+
+// ConfirmedTransactionGetter fetches the transaction from the L1 node by its ID. It returns an error
+// if the transaction is not confirmed
+type ConfirmedTransactionGetter func(txid valuetransaction.ID) (*valuetransaction.Transaction, error)
+
+// Import reads the snapshot, verifies it and stores it in the database. The chain record must be registered
+// in the node and not active, and the chain must not have a solid state in the database.
+// The snapshot is trusted only when its anchor transaction is confirmed on the ledger
+func Import(dbp *dbprovider.DBProvider, r io.Reader, getConfirmedTx ConfirmedTransactionGetter) (*Snapshot, error) {
+	s, err := Read(r)
+	if err != nil {
+		return nil, err
+	}
+	chainID := s.ChainID()
+	chr, err := getChainRecord(dbp, &chainID)
+	if err != nil {
+		return nil, err
+	}
+	if chr == nil {
+		return nil, fmt.Errorf("chain record of %s not found: it must be registered before the import", chainID.String())
+	}
+	if chr.Active {
+		return nil, fmt.Errorf("chain %s is active", chainID.String())
+	}
+	// the chain record of the snapshot comes from the same untrusted source as the rest of it
+	s.ChainRecord = chr
+	if err = s.Verify(); err != nil {
+		return nil, err
+	}
+	if err = s.VerifyAnchor(getConfirmedTx); err != nil {
+		return nil, err
+	}
+	partition := dbp.GetPartition(&chainID)
+	exists, err := partition.Has(dbprovider.MakeKey(dbprovider.ObjectTypeSolidStateIndex))
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("the state of chain %s already exists", chainID.String())
+	}
+	referenced, err := referencedBlobs(s.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	// the state and the blobs are committed atomically
+	batch := dbp.Batched()
+	set := func(chainID *coretypes.ChainID, key, value []byte) {
+		if err == nil {
+			err = batch.Set(dbprovider.PartitionKey(chainID, key), value)
+		}
+	}
+	for h, data := range s.Blobs {
+		if referenced[h] {
+			set(&coretypes.NilChainID, dbprovider.MakeKey(dbprovider.ObjectTypeBlobCache, h[:]), data)
+		}
+	}
+	for k, v := range s.Variables {
+		set(&chainID, dbprovider.MakeKey(dbprovider.ObjectTypeStateVariable, []byte(k)), v)
+	}
+	s.merkleTrie().IterateUpdates(func(k []byte, v []byte) bool {
+		set(&chainID, dbprovider.MakeKey(dbprovider.ObjectTypeMerkleNode, k), v)
+		return true
+	})
+	set(&chainID, dbprovider.MakeKey(dbprovider.ObjectTypeStateUpdateBatch, util.Uint32To4Bytes(s.BlockIndex())), util.MustBytes(s.Block))
+	set(&chainID, dbprovider.MakeKey(dbprovider.ObjectTypeSolidState), s.solidStateData)
+	set(&chainID, dbprovider.MakeKey(dbprovider.ObjectTypeAnchorTransaction), s.AnchorTransaction.Bytes())
+	set(&chainID, dbprovider.MakeKey(dbprovider.ObjectTypeSolidStateIndex), util.Uint32To4Bytes(s.BlockIndex()))
+	// the node has no blocks before the block of the snapshot
+	set(&chainID, dbprovider.MakeKey(dbprovider.ObjectTypeFirstBlockIndex), util.Uint32To4Bytes(s.BlockIndex()))
+	if err != nil {
+		batch.Cancel()
+		return nil, err
+	}
+	if err = batch.Commit(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Verify checks the consistency of the snapshot: the Merkle root of the state variables and the block
+// must match the solid state, and the solid state must be approved by the anchor transaction
+func (s *Snapshot) Verify() error {
+	chainID := s.ChainID()
+	if s.Block.StateIndex() != s.BlockIndex() {
+		return fmt.Errorf("inconsistent snapshot: block #%d doesn't match the state #%d", s.Block.StateIndex(), s.BlockIndex())
+	}
+	root, err := s.merkleTrie().Root()
+	if err != nil {
+		return err
+	}
+	if root != s.SolidState.MerkleRoot() {
+		return fmt.Errorf("inconsistent snapshot: Merkle root of the state variables doesn't match the state #%d", s.BlockIndex())
+	}
+
+	tx := s.AnchorTransaction
+	if tx.ID() != s.Block.StateTransactionID() {
+		return fmt.Errorf("inconsistent snapshot: anchor transaction %s doesn't match the block #%d", tx.ID().String(), s.BlockIndex())
+	}
+	if !tx.SignaturesValid() {
+		return fmt.Errorf("invalid signatures of the anchor transaction %s", tx.ID().String())
+	}
+	stateSection, ok := tx.State()
+	if !ok {
+		return fmt.Errorf("anchor transaction %s has no state section", tx.ID().String())
+	}
+	color := stateSection.Color()
+	if color == balance.ColorNew {
+		// origin transaction
+		color = balance.Color(tx.ID())
+	}
+	if color != s.ChainRecord.Color {
+		return fmt.Errorf("anchor transaction %s doesn't belong to chain %s", tx.ID().String(), chainID.String())
+	}
+	if stateSection.BlockIndex() != s.BlockIndex() {
+		return fmt.Errorf("anchor transaction %s approves block #%d, the snapshot is at block #%d",
+			tx.ID().String(), stateSection.BlockIndex(), s.BlockIndex())
+	}
+	if stateSection.StateHash() != s.SolidState.Hash() {
+		return fmt.Errorf("state hash of the snapshot doesn't match the anchor transaction %s", tx.ID().String())
+	}
+	if stateSection.MerkleRoot() != s.SolidState.MerkleRoot() {
+		return fmt.Errorf("Merkle root of the snapshot doesn't match the anchor transaction %s", tx.ID().String())
+	}
+	return nil
+}
+
+// VerifyAnchor checks the snapshot against the ledger. The anchor transaction must be confirmed and
+// the chain token must be in its output to the address of the committee of the chain
+func (s *Snapshot) VerifyAnchor(getConfirmedTx ConfirmedTransactionGetter) error {
+	txid := s.AnchorTransaction.ID()
+	vtx, err := getConfirmedTx(txid)
+	if err != nil {
+		return fmt.Errorf("anchor transaction %s is not confirmed: %v", txid.String(), err)
+	}
+	if !bytes.Equal(vtx.Bytes(), s.AnchorTransaction.Bytes()) {
+		return fmt.Errorf("anchor transaction %s doesn't match the ledger", txid.String())
+	}
+	addr := s.ChainRecord.Address
+	if addr == nilAddress {
+		addr = address.Address(s.ChainRecord.ChainID)
+	}
+	color := s.ChainRecord.Color
+	if color == balance.Color(txid) {
+		// origin transaction mints the chain token
+		color = balance.ColorNew
+	}
+	bals, ok := s.AnchorTransaction.OutputBalancesByAddress(addr)
+	if !ok || txutil.BalanceOfColor(bals, color) != 1 {
+		return fmt.Errorf("anchor transaction %s doesn't send the chain token to the committee address %s",
+			txid.String(), addr.String())
+	}
+	return nil
+}
+
+// merkleTrie returns the Merkle trie of the state variables of the snapshot
+func (s *Snapshot) merkleTrie() *merkle.Trie {
+	if s.trie == nil {
+		s.trie = merkle.NewTrieFromMap(s.Variables)
+	}
+	return s.trie
+}
+
+// referencedBlobs returns the hashes of the field values of the blobs in the 'blob' contract of the chain.
+// These are the entries of the blob cache of the node which belong to the chain
+func referencedBlobs(vars dict.Dict) (map[hashing.HashValue]bool, error) {
+	ret := make(map[hashing.HashValue]bool)
+	blobState := subrealm.New(vars, kv.Key(blob.Interface.Hname().Bytes()))
+	var err error
+	err2 := blob.GetDirectory(blobState).Iterate(func(elemKey []byte, _ []byte) bool {
+		var blobHash hashing.HashValue
+		if blobHash, _, err = codec.DecodeHashValue(elemKey); err != nil {
+			return false
+		}
+		err = blob.GetBlobValues(blobState, blobHash).Iterate(func(_ []byte, value []byte) bool {
+			ret[hashing.HashData(value)] = true
+			return true
+		})
+		return err == nil
+	})
+	if err2 != nil {
+		return nil, err2
+	}
+	return ret, err
+}
+
+// load reads the snapshot from the database
+func load(dbp *dbprovider.DBProvider, chainID *coretypes.ChainID) (*Snapshot, error) {
+	chr, err := getChainRecord(dbp, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if chr == nil {
+		return nil, fmt.Errorf("chain record of %s not found", chainID.String())
+	}
+	partition := dbp.GetPartition(chainID)
+	stateIndexBin, err := partition.Get(dbprovider.MakeKey(dbprovider.ObjectTypeSolidStateIndex))
+	if err == kvstore.ErrKeyNotFound {
+		return nil, fmt.Errorf("state of chain %s not found", chainID.String())
+	}
+	if err != nil {
+		return nil, err
+	}
+	values, err := util.DbGetMulti(partition, [][]byte{
+		dbprovider.MakeKey(dbprovider.ObjectTypeSolidState),
+		dbprovider.MakeKey(dbprovider.ObjectTypeStateUpdateBatch, stateIndexBin),
+	})
+	if err != nil {
+		return nil, err
+	}
+	txData, err := partition.Get(dbprovider.MakeKey(dbprovider.ObjectTypeAnchorTransaction))
+	if err == kvstore.ErrKeyNotFound {
+		return nil, fmt.Errorf("anchor transaction of the state of chain %s not found", chainID.String())
+	}
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{
+		ChainRecord:    chr,
+		Variables:      dict.New(),
+		Blobs:          make(map[hashing.HashValue][]byte),
+		solidStateData: values[0],
+	}
+	if err = s.parse(values[1], txData); err != nil {
+		return nil, err
+	}
+
+	vars := partition.WithRealm(append(partition.Realm(), dbprovider.ObjectTypeStateVariable))
+	err = vars.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
+		s.Variables.Set(kv.Key(key), value)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	referenced, err := referencedBlobs(s.Variables)
+	if err != nil {
+		return nil, err
+	}
+	for h := range referenced {
+		data, err := dbp.GetRegistryPartition().Get(dbprovider.MakeKey(dbprovider.ObjectTypeBlobCache, h[:]))
+		if err == kvstore.ErrKeyNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.Blobs[h] = data
+	}
+	return s, nil
+}
+
+// parse decodes the solid state, the block and the anchor transaction
+func (s *Snapshot) parse(blockData, txData []byte) error {
+	chainID := s.ChainID()
+	var err error
+	if s.SolidState, err = state.NewVirtualStateFromBytes(nil, &chainID, s.solidStateData); err != nil {
+		return fmt.Errorf("wrong solid state: %v", err)
+	}
+	if s.Block, err = state.NewBlockFromBytes(blockData); err != nil {
+		return fmt.Errorf("wrong block: %v", err)
+	}
+	vtx, _, err := valuetransaction.FromBytes(txData)
+	if err != nil {
+		return fmt.Errorf("wrong anchor transaction: %v", err)
+	}
+	if s.AnchorTransaction, err = sctransaction.ParseValueTransaction(vtx); err != nil {
+		return fmt.Errorf("wrong anchor transaction: %v", err)
+	}
+	return nil
+}
+
+// Write writes the snapshot in the versioned format, followed by the checksum
+func (s *Snapshot) Write(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.Write(magic[:])
+	_ = util.WriteUint16(&buf, Version)
+	_ = util.WriteBytes32(&buf, util.MustBytes(s.ChainRecord))
+	_ = util.WriteBytes32(&buf, s.solidStateData)
+	_ = util.WriteBytes32(&buf, util.MustBytes(s.Block))
+	_ = util.WriteBytes32(&buf, s.AnchorTransaction.Bytes())
+	_ = util.WriteUint32(&buf, uint32(len(s.Variables)))
+	for _, k := range s.Variables.KeysSorted() {
+		_ = util.WriteBytes16(&buf, []byte(k))
+		_ = util.WriteBytes32(&buf, s.Variables[k])
+	}
+	_ = util.WriteUint32(&buf, uint32(len(s.Blobs)))
+	for _, data := range s.Blobs {
+		_ = util.WriteBytes32(&buf, data)
+	}
+	checksum := hashing.HashData(buf.Bytes())
+	buf.Write(checksum[:])
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Read reads the snapshot and checks its checksum. It does not verify the snapshot
+func Read(r io.Reader) (*Snapshot, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < len(magic)+hashing.HashSize || !bytes.Equal(data[:len(magic)], magic[:]) {
+		return nil, errors.New("not a snapshot")
+	}
+	payload := data[:len(data)-hashing.HashSize]
+	var checksum hashing.HashValue
+	copy(checksum[:], data[len(payload):])
+	if hashing.HashData(payload) != checksum {
+		return nil, errors.New("wrong checksum of the snapshot")
+	}
+
+	rdr := bytes.NewReader(payload[len(magic):])
+	var version uint16
+	if err = util.ReadUint16(rdr, &version); err != nil {
+		return nil, err
+	}
+	if version != Version {
+		return nil, fmt.Errorf("unsupported version of the snapshot: %d", version)
+	}
+	s := &Snapshot{
+		ChainRecord: new(registry.ChainRecord),
+		Variables:   dict.New(),
+		Blobs:       make(map[hashing.HashValue][]byte),
+	}
+	chrData, err := util.ReadBytes32(rdr)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.ChainRecord.Read(bytes.NewReader(chrData)); err != nil {
+		return nil, fmt.Errorf("wrong chain record: %v", err)
+	}
+	if s.solidStateData, err = util.ReadBytes32(rdr); err != nil {
+		return nil, err
+	}
+	blockData, err := util.ReadBytes32(rdr)
+	if err != nil {
+		return nil, err
+	}
+	txData, err := util.ReadBytes32(rdr)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.parse(blockData, txData); err != nil {
+		return nil, err
+	}
+	var n uint32
+	if err = util.ReadUint32(rdr, &n); err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < n; i++ {
+		k, err := util.ReadBytes16(rdr)
+		if err != nil {
+			return nil, err
+		}
+		v, err := util.ReadBytes32(rdr)
+		if err != nil {
+			return nil, err
+		}
+		s.Variables.Set(kv.Key(k), v)
+	}
+	if err = util.ReadUint32(rdr, &n); err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < n; i++ {
+		data, err := util.ReadBytes32(rdr)
+		if err != nil {
+			return nil, err
+		}
+		s.Blobs[hashing.HashData(data)] = data
+	}
+	if rdr.Len() != 0 {
+		return nil, errors.New("unexpected data at the end of the snapshot")
+	}
+	return s, nil
+}
+
+func dbkeyChainRecord(chainID *coretypes.ChainID) []byte {
+	return dbprovider.MakeKey(dbprovider.ObjectTypeChainRecord, chainID[:])
+}
+
+func getChainRecord(dbp *dbprovider.DBProvider, chainID *coretypes.ChainID) (*registry.ChainRecord, error) {
+	data, err := dbp.GetRegistryPartition().Get(dbkeyChainRecord(chainID))
+	if err == kvstore.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret := new(registry.ChainRecord)
+	if err = ret.Read(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
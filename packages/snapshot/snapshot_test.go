@@ -0,0 +1,247 @@
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/utxodb"
+	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/sctransaction/origin"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm/core/blob"
+	"github.com/iotaledger/wasp/packages/vm/statetxbuilder"
+	"github.com/stretchr/testify/require"
+)
+
+type testChain struct {
+	u       *utxodb.UtxoDB
+	dbp     *dbprovider.DBProvider
+	chainID coretypes.ChainID
+	chr     *registry.ChainRecord
+	blob    []byte
+}
+
+// getConfirmedTx takes the transactions from the ledger
+func (c *testChain) getConfirmedTx(txid valuetransaction.ID) (*valuetransaction.Transaction, error) {
+	tx, ok := c.u.GetTransaction(txid)
+	if !ok {
+		return nil, fmt.Errorf("transaction %s is not confirmed", txid.String())
+	}
+	return tx, nil
+}
+
+// newChainDB creates the database of a chain with the origin state and the block #1 which stores a blob.
+// Both anchor transactions are confirmed
+func newChainDB(t *testing.T, log *logger.Logger) *testChain {
+	u := utxodb.New()
+	chainSigScheme := signaturescheme.ED25519(ed25519.GenerateKeyPair())
+	originatorSigScheme := signaturescheme.ED25519(ed25519.GenerateKeyPair())
+	_, err := u.RequestFunds(originatorSigScheme.Address())
+	require.NoError(t, err)
+
+	originTx, err := origin.NewOriginTransaction(origin.NewOriginTransactionParams{
+		OriginAddress:             chainSigScheme.Address(),
+		OriginatorSignatureScheme: originatorSigScheme,
+		AllInputs:                 u.GetAddressOutputs(originatorSigScheme.Address()),
+	})
+	require.NoError(t, err)
+	require.NoError(t, u.AddTransaction(originTx.Transaction))
+
+	chainID := coretypes.ChainID(chainSigScheme.Address())
+	color := balance.Color(originTx.ID())
+	dbp := dbprovider.NewInMemoryDBProvider(log)
+
+	vs := state.NewVirtualState(dbp.GetPartition(&chainID), &chainID)
+	block := state.MustNewOriginBlock(&color)
+	require.NoError(t, vs.ApplyBlock(block))
+	require.NoError(t, vs.CommitToDb(block))
+
+	// the block #1 stores the blob in the 'blob' contract
+	blobData := []byte("blob data")
+	blobHash := hashing.HashStrings("blob")
+	vars := dict.New()
+	blobState := subrealm.New(vars, kv.Key(blob.Interface.Hname().Bytes()))
+	blob.GetDirectory(blobState).MustSetAt(blobHash[:], blob.EncodeSize(uint32(len(blobData))))
+	blob.GetBlobValues(blobState, blobHash).MustSetAt([]byte("field"), blobData)
+	su := state.NewStateUpdate(nil)
+	for k, v := range vars {
+		su.Mutations().Add(buffered.NewMutationSet(k, v))
+	}
+	block, err = state.NewBlock([]state.StateUpdate{su})
+	require.NoError(t, err)
+	block.WithBlockIndex(1)
+	require.NoError(t, vs.ApplyBlock(block))
+
+	txb, err := statetxbuilder.New(chainID, address.Address(chainID), color, waspconn.OutputsToBalances(u.GetAddressOutputs(address.Address(chainID))))
+	require.NoError(t, err)
+	require.NoError(t, txb.SetStateParams(1, vs.Hash(), time.Now().UnixNano()))
+	txb.SetMerkleRoot(vs.MerkleRoot())
+	tx, err := txb.Build()
+	require.NoError(t, err)
+	tx.Sign(chainSigScheme)
+	require.NoError(t, u.AddTransaction(tx.Transaction))
+	block.WithStateTransaction(tx.ID())
+	require.NoError(t, vs.CommitToDb(block))
+	require.NoError(t, dbp.GetPartition(&chainID).Set(dbprovider.MakeKey(dbprovider.ObjectTypeAnchorTransaction), tx.Bytes()))
+
+	chr := &registry.ChainRecord{
+		ChainID:        chainID,
+		Color:          color,
+		CommitteeNodes: []string{"wasp1:4000"},
+		Address:        address.Address(chainID),
+		Active:         true,
+	}
+	require.NoError(t, dbp.GetRegistryPartition().Set(dbkeyChainRecord(&chainID), util.MustBytes(chr)))
+	require.NoError(t, dbp.GetRegistryPartition().Set(dbprovider.MakeKey(dbprovider.ObjectTypeBlobCache, hashing.HashData(blobData).Bytes()), blobData))
+	// the blob of another chain
+	other := []byte("other blob")
+	require.NoError(t, dbp.GetRegistryPartition().Set(dbprovider.MakeKey(dbprovider.ObjectTypeBlobCache, hashing.HashData(other).Bytes()), other))
+	return &testChain{u: u, dbp: dbp, chainID: chainID, chr: chr, blob: blobData}
+}
+
+// newNodeDB creates the database of the node which has the chain registered but not active
+func (c *testChain) newNodeDB(t *testing.T, log *logger.Logger) *dbprovider.DBProvider {
+	dbp := dbprovider.NewInMemoryDBProvider(log)
+	chr := *c.chr
+	chr.Active = false
+	require.NoError(t, dbp.GetRegistryPartition().Set(dbkeyChainRecord(&c.chainID), util.MustBytes(&chr)))
+	return dbp
+}
+
+func TestExportImport(t *testing.T) {
+	log := testutil.NewLogger(t)
+	c := newChainDB(t, log)
+
+	var buf bytes.Buffer
+	s, err := Export(c.dbp, &c.chainID, &buf)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, s.BlockIndex())
+	// only the blob of the chain is exported
+	require.Len(t, s.Blobs, 1)
+	require.Equal(t, c.blob, s.Blobs[hashing.HashData(c.blob)])
+
+	dbp2 := c.newNodeDB(t, log)
+	s2, err := Import(dbp2, bytes.NewReader(buf.Bytes()), c.getConfirmedTx)
+	require.NoError(t, err)
+	require.Equal(t, c.chainID, s2.ChainID())
+	require.Equal(t, s.SolidState.Hash(), s2.SolidState.Hash())
+
+	// the imported chain is not activated
+	chr, err := getChainRecord(dbp2, &c.chainID)
+	require.NoError(t, err)
+	require.False(t, chr.Active)
+
+	// the imported state can be exported again
+	var buf2 bytes.Buffer
+	s3, err := Export(dbp2, &c.chainID, &buf2)
+	require.NoError(t, err)
+	require.Equal(t, s.Variables, s3.Variables)
+	require.Equal(t, s.Blobs, s3.Blobs)
+	require.Equal(t, s.Block.EssenceHash(), s3.Block.EssenceHash())
+	require.Equal(t, s.AnchorTransaction.ID(), s3.AnchorTransaction.ID())
+
+	// the state already exists
+	_, err = Import(dbp2, bytes.NewReader(buf.Bytes()), c.getConfirmedTx)
+	require.Error(t, err)
+}
+
+func TestImportNotRegistered(t *testing.T) {
+	log := testutil.NewLogger(t)
+	c := newChainDB(t, log)
+
+	var buf bytes.Buffer
+	_, err := Export(c.dbp, &c.chainID, &buf)
+	require.NoError(t, err)
+
+	// the chain record is not created from the snapshot
+	dbp2 := dbprovider.NewInMemoryDBProvider(log)
+	_, err = Import(dbp2, bytes.NewReader(buf.Bytes()), c.getConfirmedTx)
+	require.Error(t, err)
+	chr, err := getChainRecord(dbp2, &c.chainID)
+	require.NoError(t, err)
+	require.Nil(t, chr)
+}
+
+func TestImportNotConfirmed(t *testing.T) {
+	log := testutil.NewLogger(t)
+	c := newChainDB(t, log)
+
+	var buf bytes.Buffer
+	_, err := Export(c.dbp, &c.chainID, &buf)
+	require.NoError(t, err)
+
+	dbp2 := c.newNodeDB(t, log)
+	notConfirmed := func(txid valuetransaction.ID) (*valuetransaction.Transaction, error) {
+		return nil, fmt.Errorf("transaction %s is not confirmed", txid.String())
+	}
+	_, err = Import(dbp2, bytes.NewReader(buf.Bytes()), notConfirmed)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not confirmed")
+
+	// nothing is stored
+	exists, err := dbp2.GetPartition(&c.chainID).Has(dbprovider.MakeKey(dbprovider.ObjectTypeSolidStateIndex))
+	require.NoError(t, err)
+	require.False(t, exists)
+	_, err = dbp2.GetRegistryPartition().Get(dbprovider.MakeKey(dbprovider.ObjectTypeBlobCache, hashing.HashData(c.blob).Bytes()))
+	require.Equal(t, kvstore.ErrKeyNotFound, err)
+}
+
+func TestImportWrongCommittee(t *testing.T) {
+	log := testutil.NewLogger(t)
+	c := newChainDB(t, log)
+
+	var buf bytes.Buffer
+	_, err := Export(c.dbp, &c.chainID, &buf)
+	require.NoError(t, err)
+
+	// the chain is controlled by another committee according to the node
+	c.chr.Address = signaturescheme.ED25519(ed25519.GenerateKeyPair()).Address()
+	dbp2 := c.newNodeDB(t, log)
+	_, err = Import(dbp2, bytes.NewReader(buf.Bytes()), c.getConfirmedTx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "committee address")
+}
+
+func TestImportCorrupted(t *testing.T) {
+	log := testutil.NewLogger(t)
+	c := newChainDB(t, log)
+
+	var buf bytes.Buffer
+	_, err := Export(c.dbp, &c.chainID, &buf)
+	require.NoError(t, err)
+
+	data := buf.Bytes()
+	data[len(data)/2] ^= 0xff
+	_, err = Import(c.newNodeDB(t, log), bytes.NewReader(data), c.getConfirmedTx)
+	require.Error(t, err)
+}
+
+func TestExportInconsistent(t *testing.T) {
+	log := testutil.NewLogger(t)
+	c := newChainDB(t, log)
+
+	// the state variable is not committed to the Merkle root of the state
+	require.NoError(t, c.dbp.GetPartition(&c.chainID).Set(dbprovider.MakeKey(dbprovider.ObjectTypeStateVariable, []byte("k")), []byte("v")))
+	var buf bytes.Buffer
+	_, err := Export(c.dbp, &c.chainID, &buf)
+	require.Error(t, err)
+}
@@ -11,7 +11,10 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/receipts"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/iotaledger/wasp/packages/vm/core/xchain"
 	"github.com/stretchr/testify/require"
 )
 
@@ -52,6 +55,27 @@ func (ch *Chain) CheckChain() {
 	require.EqualValues(ch.Env.T, eventlog.Interface.ProgramHash, chainlogRec.ProgramHash)
 	require.EqualValues(ch.Env.T, ch.OriginatorAgentID, chainlogRec.Creator)
 
+	receiptsRec, err := ch.FindContract(receipts.Interface.Name)
+	require.NoError(ch.Env.T, err)
+	require.EqualValues(ch.Env.T, receipts.Interface.Name, receiptsRec.Name)
+	require.EqualValues(ch.Env.T, receipts.Interface.Description, receiptsRec.Description)
+	require.EqualValues(ch.Env.T, receipts.Interface.ProgramHash, receiptsRec.ProgramHash)
+	require.EqualValues(ch.Env.T, ch.OriginatorAgentID, receiptsRec.Creator)
+
+	xchainRec, err := ch.FindContract(xchain.Interface.Name)
+	require.NoError(ch.Env.T, err)
+	require.EqualValues(ch.Env.T, xchain.Interface.Name, xchainRec.Name)
+	require.EqualValues(ch.Env.T, xchain.Interface.Description, xchainRec.Description)
+	require.EqualValues(ch.Env.T, xchain.Interface.ProgramHash, xchainRec.ProgramHash)
+	require.EqualValues(ch.Env.T, ch.OriginatorAgentID, xchainRec.Creator)
+
+	schedulerRec, err := ch.FindContract(scheduler.Interface.Name)
+	require.NoError(ch.Env.T, err)
+	require.EqualValues(ch.Env.T, scheduler.Interface.Name, schedulerRec.Name)
+	require.EqualValues(ch.Env.T, scheduler.Interface.Description, schedulerRec.Description)
+	require.EqualValues(ch.Env.T, scheduler.Interface.ProgramHash, schedulerRec.ProgramHash)
+	require.EqualValues(ch.Env.T, ch.OriginatorAgentID, schedulerRec.Creator)
+
 	ch.CheckAccountLedger()
 }
 
@@ -20,10 +20,14 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/receipts"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/iotaledger/wasp/packages/vm/core/xchain"
 	"github.com/iotaledger/wasp/plugins/wasmtimevm"
 	"github.com/stretchr/testify/require"
 	"io/ioutil"
+	"sort"
 )
 
 // String is string representation for main parameters of the chain
@@ -245,6 +249,67 @@ func (ch *Chain) DeployWasmContract(sigScheme signaturescheme.SignatureScheme, n
 	return ch.DeployContract(sigScheme, name, hprog, params...)
 }
 
+// UpgradeContract replaces the program of the contract with the given name by 'programHash' and keeps
+// its state. 'sigScheme' must be the key of the creator of the contract or of the chain owner (nil defaults
+// to chain originator). The 'params' are passed to the 'migrate' entry point of the new program, if it has one
+func (ch *Chain) UpgradeContract(sigScheme signaturescheme.SignatureScheme, name string, programHash hashing.HashValue, params ...interface{}) error {
+	par := []interface{}{root.ParamHname, coretypes.Hn(name), root.ParamProgramHash, programHash}
+	par = append(par, params...)
+	req := NewCallParams(root.Interface.Name, root.FuncUpgradeContract, par...)
+	_, err := ch.PostRequestSync(req, sigScheme)
+	return err
+}
+
+// DeactivateContract freezes the contract with the given name: calls to it are refused until it is
+// activated again. 'sigScheme' must be the key of the creator of the contract or of the chain owner
+// (nil defaults to chain originator)
+func (ch *Chain) DeactivateContract(sigScheme signaturescheme.SignatureScheme, name string) error {
+	req := NewCallParams(root.Interface.Name, root.FuncDeactivateContract, root.ParamHname, coretypes.Hn(name))
+	_, err := ch.PostRequestSync(req, sigScheme)
+	return err
+}
+
+// ActivateContract makes the deactivated contract with the given name callable again
+func (ch *Chain) ActivateContract(sigScheme signaturescheme.SignatureScheme, name string) error {
+	req := NewCallParams(root.Interface.Name, root.FuncActivateContract, root.ParamHname, coretypes.Hn(name))
+	_, err := ch.PostRequestSync(req, sigScheme)
+	return err
+}
+
+// RemoveContract removes the contract with the given name from the chain. The tokens of the contract
+// are moved to the on-chain account of 'target' and its state is erased if 'eraseState' is true.
+// 'sigScheme' must be the key of the creator of the contract or of the chain owner (nil defaults to chain originator)
+func (ch *Chain) RemoveContract(sigScheme signaturescheme.SignatureScheme, name string, target coretypes.AgentID, eraseState bool) error {
+	par := []interface{}{root.ParamHname, coretypes.Hn(name), root.ParamSweepTarget, target}
+	if eraseState {
+		par = append(par, root.ParamEraseState, 1)
+	}
+	req := NewCallParams(root.Interface.Name, root.FuncRemoveContract, par...)
+	_, err := ch.PostRequestSync(req, sigScheme)
+	return err
+}
+
+// RotateCommittee moves the chain to a new committee, represented in Solo by the key 'newChainSigScheme'.
+// The request to the 'root' contract is signed by 'sigScheme' (nil defaults to chain originator), which
+// must be the chain owner. The state transaction which settles the request moves the chain token and
+// all tokens owned by the chain to the new address. From then on state transactions are signed with the new key
+// Note, that requests sent by other chains to the chain ID after it was moved will not be processed
+func (ch *Chain) RotateCommittee(sigScheme, newChainSigScheme signaturescheme.SignatureScheme) error {
+	ch.SetNextCommittee(newChainSigScheme)
+	req := NewCallParams(root.Interface.Name, root.FuncRotateCommittee, root.ParamChainAddress, newChainSigScheme.Address())
+	_, err := ch.PostRequestSync(req, sigScheme)
+	if err != nil {
+		ch.SetNextCommittee(nil)
+	}
+	return err
+}
+
+// SetNextCommittee sets the key of the committee the chain is expected to be moved to. It is needed when
+// the chain is rotated by a smart contract calling the 'root' contract rather than with RotateCommittee
+func (ch *Chain) SetNextCommittee(newChainSigScheme signaturescheme.SignatureScheme) {
+	ch.nextChainSigScheme = newChainSigScheme
+}
+
 type ChainInfo struct {
 	ChainID      coretypes.ChainID
 	ChainOwnerID coretypes.AgentID
@@ -425,3 +490,87 @@ func (ch *Chain) GetEventLogNumRecords(name string) int {
 	require.True(ch.Env.T, ok)
 	return int(ret)
 }
+
+// GetRequestReceipt calls the view in the 'receipts' core smart contract to retrieve
+// the receipt of the processed request
+func (ch *Chain) GetRequestReceipt(reqID coretypes.RequestID) (*receipts.RequestReceipt, error) {
+	res, err := ch.CallView(receipts.Interface.Name, receipts.FuncGetReceipt,
+		receipts.ParamRequestID, reqID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return receipts.DecodeRequestReceipt(res.MustGet(receipts.ParamReceipt))
+}
+
+// GetOutboxMessage calls the view in the 'xchain' core smart contract to retrieve
+// the record of the cross-chain message sent by the chain
+func (ch *Chain) GetOutboxMessage(id int64) (*xchain.Message, error) {
+	res, err := ch.CallView(xchain.Interface.Name, xchain.FuncGetMessage,
+		xchain.ParamMessageID, id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return xchain.DecodeMessage(res.MustGet(xchain.ParamMessage))
+}
+
+// GetOutbox calls the view in the 'xchain' core smart contract to retrieve records of
+// cross-chain messages sent by the chain, ordered by the message ID.
+// If the sender is specified, only messages sent by it are returned
+func (ch *Chain) GetOutbox(sender ...coretypes.AgentID) ([]*xchain.Message, error) {
+	var params []interface{}
+	if len(sender) > 0 {
+		params = []interface{}{xchain.ParamAgentID, sender[0]}
+	}
+	res, err := ch.CallView(xchain.Interface.Name, xchain.FuncGetOutbox, params...)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*xchain.Message, 0, len(res))
+	for _, data := range res {
+		msg, err := xchain.DecodeMessage(data)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, msg)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID < ret[j].ID })
+	return ret, nil
+}
+
+// GetScheduledCall calls the view in the 'scheduler' core smart contract to retrieve
+// the record of the scheduled call. Completed and cancelled calls are not found
+func (ch *Chain) GetScheduledCall(id int64) (*scheduler.ScheduledCall, error) {
+	res, err := ch.CallView(scheduler.Interface.Name, scheduler.FuncGetCall,
+		scheduler.ParamCallID, id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scheduler.DecodeScheduledCall(res.MustGet(scheduler.ParamCall))
+}
+
+// GetScheduledCalls calls the view in the 'scheduler' core smart contract to retrieve records of
+// the calls scheduled on the chain, ordered by the call ID.
+// If the owner is specified, only calls scheduled by it are returned
+func (ch *Chain) GetScheduledCalls(owner ...coretypes.AgentID) ([]*scheduler.ScheduledCall, error) {
+	var params []interface{}
+	if len(owner) > 0 {
+		params = []interface{}{scheduler.ParamAgentID, owner[0]}
+	}
+	res, err := ch.CallView(scheduler.Interface.Name, scheduler.FuncGetCalls, params...)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*scheduler.ScheduledCall, 0, len(res))
+	for _, data := range res {
+		c, err := scheduler.DecodeScheduledCall(data)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, c)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID < ret[j].ID })
+	return ret, nil
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
 	"github.com/iotaledger/wasp/packages/kv/codec"
@@ -31,6 +32,8 @@ type CallParams struct {
 	transfer   coretypes.ColoredBalances
 	mint       map[address.Address]int64
 	args       requestargs.RequestArgs
+	gasBudget  uint64
+	encrypt    bool
 }
 
 func NewCallParamsFromDic(scName, funName string, par dict.Dict) *CallParams {
@@ -99,6 +102,20 @@ func (r *CallParams) WithMinting(mint map[address.Address]int64) *CallParams {
 	return r
 }
 
+// WithGasBudget sets maximum amount of gas the request is allowed to burn.
+// 0 (the default) means the default gas budget of the VM
+func (r *CallParams) WithGasBudget(gasBudget uint64) *CallParams {
+	r.gasBudget = gasBudget
+	return r
+}
+
+// WithEncryption makes the arguments of the request encrypted to the committee of the chain,
+// so that they are readable only by the committee nodes and the VM
+func (r *CallParams) WithEncryption() *CallParams {
+	r.encrypt = true
+	return r
+}
+
 // makes map without hashing
 func toMap(params ...interface{}) map[string]interface{} {
 	par := make(map[string]interface{})
@@ -134,7 +151,15 @@ func (ch *Chain) RequestFromParamsToLedger(req *CallParams, sigScheme signatures
 
 	reqSect := sctransaction.NewRequestSectionByWallet(coretypes.NewContractID(ch.ChainID, req.target), req.entryPoint).
 		WithTransfer(req.transfer).
+		WithGasBudget(req.gasBudget).
 		WithArgs(req.args)
+	if ch.ChainAddress != address.Address(ch.ChainID) {
+		reqSect.WithChainAddress(ch.ChainAddress)
+	}
+	if req.encrypt {
+		err = reqSect.EncryptArgs(ch.EncryptionKeys)
+		require.NoError(ch.Env.T, err)
+	}
 
 	err = txb.AddRequestSection(reqSect)
 	require.NoError(ch.Env.T, err)
@@ -185,7 +210,7 @@ func (ch *Chain) PostRequestSyncTx(req *CallParams, sigScheme signaturescheme.Si
 	ch.reqCounter.Add(1)
 	ret, err := ch.runBatch([]vm.RequestRefWithFreeTokens{r}, "post")
 	if err != nil {
-		return nil, nil, err
+		return tx, nil, err
 	}
 	return tx, ret, nil
 }
@@ -228,6 +253,17 @@ func (ch *Chain) CallView(scName string, funName string, params ...interface{})
 // Otherwise waiting is not necessary because all PostRequestSync calls by the test itself
 // are synchronous and are processed immediately
 func (ch *Chain) WaitForEmptyBacklog(maxWait ...time.Duration) {
+	waitForEmptyBacklog(ch.Log, ch.backlogLen, maxWait...)
+}
+
+// WaitForEmptyBacklogs waits until backlog queues of all chains in the environment become empty.
+// It is useful when requests between chains trigger more requests, for example acknowledgements
+// of cross-chain messages, which can't be awaited chain by chain
+func (env *Solo) WaitForEmptyBacklogs(maxWait ...time.Duration) {
+	waitForEmptyBacklog(env.logger, env.backlogLen, maxWait...)
+}
+
+func waitForEmptyBacklog(log *logger.Logger, backlogLen func() int, maxWait ...time.Duration) {
 	maxw := 5 * time.Second
 	var deadline time.Time
 	if len(maxWait) > 0 {
@@ -237,20 +273,20 @@ func (ch *Chain) WaitForEmptyBacklog(maxWait ...time.Duration) {
 	counter := 0
 	for {
 		if counter%40 == 0 {
-			ch.Log.Infof("backlog length = %d", ch.backlogLen())
+			log.Infof("backlog length = %d", backlogLen())
 		}
 		counter++
 		time.Sleep(200 * time.Millisecond)
-		if ch.backlogLen() > 0 {
+		if backlogLen() > 0 {
 			if time.Now().After(deadline) {
-				ch.Log.Warnf("exit due to timeout of max wait for %v", maxw)
+				log.Warnf("exit due to timeout of max wait for %v", maxw)
 				return
 			}
 		} else {
 			emptyCounter := 0
 			for i := 0; i < 3; i++ {
 				time.Sleep(100 * time.Millisecond)
-				if ch.backlogLen() != 0 {
+				if backlogLen() != 0 {
 					break
 				}
 				emptyCounter++
@@ -4,6 +4,7 @@
 package solo
 
 import (
+	"errors"
 	"fmt"
 	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
 	"github.com/iotaledger/wasp/packages/coretypes"
@@ -33,9 +34,15 @@ func (ch *Chain) runBatch(batch []vm.RequestRefWithFreeTokens, trace string) (di
 
 	ch.validateBatch(batch)
 
-	// solidify arguments
-	for _, reqRef := range batch {
-		if ok, err := reqRef.RequestSection().SolidifyArgs(ch.Env.registry); err != nil || !ok {
+	// solidify arguments. The solo node is the whole committee: if it can't decrypt the arguments,
+	// the request is settled with error
+	for i := range batch {
+		ok, err := batch[i].RequestSection().SolidifyArgs(ch.Env.registry, ch.decrypter)
+		if errors.Is(err, sctransaction.ErrUndecryptableArgs) {
+			batch[i].ArgsUndecryptable = true
+			continue
+		}
+		if err != nil || !ok {
 			return nil, fmt.Errorf("solo inconsistency: failed to solidify request args")
 		}
 	}
@@ -44,6 +51,7 @@ func (ch *Chain) runBatch(batch []vm.RequestRefWithFreeTokens, trace string) (di
 		Processors:         ch.proc,
 		ChainID:            ch.ChainID,
 		Color:              ch.ChainColor,
+		ChainAddress:       ch.ChainAddress,
 		Entropy:            hashing.RandomHash(nil),
 		ValidatorFeeTarget: ch.ValidatorFeeTarget,
 		Balances:           waspconn.OutputsToBalances(ch.Env.utxoDB.GetAddressOutputs(ch.ChainAddress)),
@@ -94,6 +102,15 @@ func (ch *Chain) settleStateTransition(newState state.VirtualState, block state.
 	ch.StateTx = stateTx
 	ch.State = newState
 
+	if chainAddress := *stateTx.MustProperties().MustChainAddress(); chainAddress != ch.ChainAddress {
+		require.True(ch.Env.T, ch.nextChainSigScheme != nil && ch.nextChainSigScheme.Address() == chainAddress,
+			"chain was moved to the address %s, but the key of it is unknown", chainAddress.String())
+		ch.Log.Infof("chain was moved from %s to %s", ch.ChainAddress.String(), chainAddress.String())
+		ch.ChainSigScheme = ch.nextChainSigScheme
+		ch.ChainAddress = chainAddress
+		ch.nextChainSigScheme = nil
+	}
+
 	ch.Log.Infof("state transition #%d --> #%d. Requests in the block: %d. Posted: %d",
 		prevBlockIndex, ch.State.BlockIndex(), len(block.RequestIDs()), len(ch.StateTx.Requests()))
 	ch.Log.Debugf("Batch processed: %s", batchShortStr(block.RequestIDs()))
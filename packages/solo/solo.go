@@ -5,6 +5,7 @@ package solo
 
 import (
 	"go.uber.org/atomic"
+	"os"
 	"sync"
 	"testing"
 	"time"
@@ -17,6 +18,7 @@ import (
 	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
 	"github.com/iotaledger/wasp/packages/dbprovider"
 	"github.com/iotaledger/wasp/packages/registry"
 	"github.com/iotaledger/wasp/packages/sctransaction"
@@ -30,6 +32,8 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/wasmproc"
 	"github.com/iotaledger/wasp/plugins/wasmtimevm"
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -76,7 +80,8 @@ type Chain struct {
 	// ChainID is the ID of the chain (in this version alias of the ChainAddress)
 	ChainID coretypes.ChainID
 
-	// ChainAddress is the alias of ChainSigScheme.Address()
+	// ChainAddress is the alias of ChainSigScheme.Address(). It is equal to ChainID unless
+	// the chain was moved to another committee with RotateCommittee
 	ChainAddress address.Address
 
 	// ChainColor is the color of the non-fungible token of the chain.
@@ -98,12 +103,22 @@ type Chain struct {
 	// State ia an interface to access virtual state of the chain: the collection of key/value pairs
 	State state.VirtualState
 
+	// EncryptionKeys are the keys of the committee the arguments of requests posted WithEncryption
+	// are encrypted to. In Solo the committee consists of a single node
+	EncryptionKeys *requestargs.Recipients
+
 	// Log is the named logger of the chain
 	Log *logger.Logger
 
+	// the key of the new committee, set by RotateCommittee
+	nextChainSigScheme signaturescheme.SignatureScheme
+
 	// processor cache
 	proc *processors.ProcessorCache
 
+	// decrypts the encrypted request arguments
+	decrypter requestargs.Decrypter
+
 	// related to asynchronous backlog processing
 	runVMMutex   *sync.Mutex
 	reqCounter   atomic.Int32
@@ -117,6 +132,12 @@ var (
 	glbLogger *logger.Logger
 )
 
+// WasmEngine is the engine which runs the Wasm contracts deployed after it is set: wasmhost.EngineWasmTime
+// or wasmhost.EngineInterpreter. The default is taken from the environment variable SOLO_WASM_ENGINE,
+// if it is empty the default engine of the build is used.
+// The tests may run the same contract with both engines to check that the results are identical
+var WasmEngine = os.Getenv("SOLO_WASM_ENGINE")
+
 // New creates an instance of the `solo` environment for the test instances.
 //   'debug' parameter 'true' means logging level is 'debug', otherwise 'info'
 //   'printStackTrace' controls printing stack trace in case of errors
@@ -127,7 +148,7 @@ func New(t *testing.T, debug bool, printStackTrace bool) *Solo {
 			glbLogger = testutil.WithLevel(glbLogger, zapcore.InfoLevel, printStackTrace)
 		}
 		wasmtimeConstructor := func(binary []byte) (coretypes.Processor, error) {
-			return wasmproc.GetProcessor(binary, glbLogger)
+			return wasmproc.GetProcessorForEngine(WasmEngine, binary, glbLogger)
 		}
 		err := processors.RegisterVMType(wasmtimevm.VMType, wasmtimeConstructor)
 		require.NoError(t, err)
@@ -195,6 +216,11 @@ func (env *Solo) NewChain(chainOriginator signaturescheme.SignatureScheme, name
 		backlog:      make([]sctransaction.RequestRef, 0),
 		backlogMutex: &sync.RWMutex{},
 	}
+	suite := pairing.NewSuiteBn256()
+	encryptionKey := suite.Scalar().Pick(suite.RandomStream())
+	ret.EncryptionKeys = &requestargs.Recipients{Group: suite, Keys: []kyber.Point{suite.Point().Mul(encryptionKey, nil)}}
+	ret.decrypter = requestargs.NewDecrypter(suite, 0, encryptionKey)
+
 	env.AssertAddressBalance(ret.OriginatorAddress, balance.ColorIOTA, testutil.RequestFundsAmount)
 	var err error
 	ret.StateTx, err = origin.NewOriginTransaction(origin.NewOriginTransactionParams{
@@ -296,7 +322,7 @@ func (ch *Chain) addToBacklog(r sctransaction.RequestRef) {
 	if tl == 0 {
 		ch.Log.Infof("added to backlog: %s len: %d", r.RequestID().String(), len(ch.backlog))
 	} else {
-		tlTime := time.Unix(int64(tl), 0)
+		tlTime := time.Unix(tl, 0)
 		ch.Log.Infof("added to backlog: %s. Time locked for: %v",
 			r.RequestID().Short(), tlTime.Sub(ch.Env.LogicalTime()))
 	}
@@ -312,7 +338,7 @@ func (ch *Chain) collateBatch() []vm.RequestRefWithFreeTokens {
 	remain := ch.backlog[:0]
 	for _, ref := range ch.backlog {
 		// using logical clock
-		if int64(ref.RequestSection().Timelock()) <= ch.Env.LogicalTime().Unix() {
+		if ref.RequestSection().Timelock() <= ch.Env.LogicalTime().Unix() {
 			if ref.RequestSection().Timelock() != 0 {
 				ch.Log.Infof("unlocked time-locked request %s", ref.RequestID().String())
 			}
@@ -340,7 +366,31 @@ func (ch *Chain) batchLoop() {
 	}
 }
 
-// backlogLen is a thread-safe function to return size of the current backlog
+// backlogLen is a thread-safe function to return size of the current backlog.
+// Requests time locked beyond the logical clock are not counted: they are only processed
+// after the clock is advanced
 func (ch *Chain) backlogLen() int {
-	return int(ch.reqCounter.Load())
+	ch.backlogMutex.RLock()
+	defer ch.backlogMutex.RUnlock()
+
+	ret := int(ch.reqCounter.Load())
+	nowis := ch.Env.LogicalTime().Unix()
+	for _, ref := range ch.backlog {
+		if ref.RequestSection().Timelock() > nowis {
+			ret--
+		}
+	}
+	return ret
+}
+
+// backlogLen is the total length of backlogs of all chains
+func (env *Solo) backlogLen() int {
+	env.glbMutex.RLock()
+	defer env.glbMutex.RUnlock()
+
+	ret := 0
+	for _, ch := range env.chains {
+		ret += ch.backlogLen()
+	}
+	return ret
 }
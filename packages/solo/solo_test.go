@@ -1,9 +1,24 @@
 package solo
 
 import (
+	"bytes"
+	"errors"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
+	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/blob"
+	"github.com/iotaledger/wasp/packages/vm/runvm"
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"sync"
 	"testing"
 )
 
@@ -23,3 +38,124 @@ func TestPutBlobData(t *testing.T) {
 	require.Len(env.T, sargs, 1)
 	require.EqualValues(env.T, data, sargs.MustGet("dataName"))
 }
+
+func TestEncryptedRequest(t *testing.T) {
+	env := New(t, false, false)
+	chain := env.NewChain(nil, "ch1")
+
+	secret := []byte("the secret data")
+	req := NewCallParams(blob.Interface.Name, blob.FuncStoreBlob, "field", secret).WithEncryption()
+	tx, ret, err := chain.PostRequestSyncTx(req, nil)
+	require.NoError(t, err)
+
+	reqSect := tx.Requests()[0]
+	require.True(t, reqSect.HasEncryptedArgs())
+	require.False(t, bytes.Contains(tx.Bytes(), secret))
+
+	h, ok, err := codec.DecodeHashValue(ret.MustGet(blob.ParamHash))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, blob.MustGetBlobHash(codec.MakeDict(map[string]interface{}{"field": secret})), h)
+	info, ok := chain.GetBlobInfo(h)
+	require.True(t, ok)
+	require.EqualValues(t, len(secret), info["field"])
+}
+
+func TestEncryptedRequestWrongKey(t *testing.T) {
+	env := New(t, false, false)
+	chain := env.NewChain(nil, "ch1")
+
+	// the arguments are encrypted to another committee, the chain can't decrypt them
+	suite := pairing.NewSuiteBn256()
+	otherKey := suite.Scalar().Pick(suite.RandomStream())
+	chain.EncryptionKeys = &requestargs.Recipients{Group: suite, Keys: []kyber.Point{suite.Point().Mul(otherKey, nil)}}
+
+	user := env.NewSignatureSchemeWithFunds()
+	req := NewCallParams(blob.Interface.Name, blob.FuncStoreBlob, "field", []byte("the secret data")).
+		WithEncryption().
+		WithTransfer(balance.ColorIOTA, 42)
+	_, err := chain.PostRequestSync(req, user)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "decrypt")
+
+	// the request is settled and the tokens are returned to the sender
+	chain.WaitForEmptyBacklog()
+	env.AssertAddressBalance(user.Address(), balance.ColorIOTA, testutil.RequestFundsAmount-1)
+	chain.AssertAccountBalance(coretypes.NewAgentIDFromAddress(user.Address()), balance.ColorIOTA, 1)
+}
+
+func TestEncryptedRequestMembersDisagree(t *testing.T) {
+	env := New(t, false, false)
+	chain := env.NewChain(nil, "ch1")
+
+	// the key wrapped to member #0 is the key of the chain, the one wrapped to member #1 is not
+	// the key member #1 holds
+	suite := pairing.NewSuiteBn256()
+	otherKey := suite.Scalar().Pick(suite.RandomStream())
+	chain.EncryptionKeys = &requestargs.Recipients{
+		Group: suite,
+		Keys:  []kyber.Point{chain.EncryptionKeys.Keys[0], suite.Point().Mul(otherKey, nil)},
+	}
+	member1 := requestargs.NewDecrypter(suite, 1, suite.Scalar().Pick(suite.RandomStream()))
+
+	user := env.NewSignatureSchemeWithFunds()
+	req := NewCallParams(blob.Interface.Name, blob.FuncStoreBlob, "field", []byte("the secret data")).
+		WithEncryption().
+		WithTransfer(balance.ColorIOTA, 42)
+	tx := chain.RequestFromParamsToLedger(req, user)
+
+	// each member has its own copy of the request transaction
+	tx0, err := sctransaction.ParseValueTransaction(tx.Transaction)
+	require.NoError(t, err)
+	tx1, err := sctransaction.ParseValueTransaction(tx.Transaction)
+	require.NoError(t, err)
+
+	ok, err := tx0.Requests()[0].SolidifyArgs(env.registry, chain.decrypter)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// member #1 can't decrypt: the request is not solid and is processed only if the leader proposes
+	// to settle it with error
+	ok, err = tx1.Requests()[0].SolidifyArgs(env.registry, member1)
+	require.True(t, errors.Is(err, sctransaction.ErrUndecryptableArgs))
+	require.False(t, ok)
+	require.Nil(t, tx1.Requests()[0].SolidArgs())
+
+	// with the same proposal of the leader both members compute the same result
+	ts := env.LogicalTime().UnixNano()
+	essence0 := chain.runAsMember(tx0, true, ts)
+	essence1 := chain.runAsMember(tx1, true, ts)
+	require.EqualValues(t, essence0, essence1)
+
+	// member #0 alone would compute another result
+	require.NotEqualValues(t, essence0, chain.runAsMember(tx0, false, ts))
+}
+
+// runAsMember runs the request on a copy of the state the way a committee member does and returns
+// the essence of the resulting transaction, which the member signs
+func (ch *Chain) runAsMember(tx *sctransaction.Transaction, argsUndecryptable bool, ts int64) []byte {
+	ref := vm.RequestRefWithFreeTokens{ArgsUndecryptable: argsUndecryptable}
+	ref.Tx = tx
+	task := &vm.VMTask{
+		Processors:         ch.proc,
+		ChainID:            ch.ChainID,
+		Color:              ch.ChainColor,
+		ChainAddress:       ch.ChainAddress,
+		Entropy:            hashing.HashStrings(ch.Name),
+		ValidatorFeeTarget: ch.ValidatorFeeTarget,
+		Balances:           waspconn.OutputsToBalances(ch.Env.utxoDB.GetAddressOutputs(ch.ChainAddress)),
+		Requests:           []vm.RequestRefWithFreeTokens{ref},
+		Timestamp:          ts,
+		VirtualState:       ch.State.Clone(),
+		Log:                ch.Log,
+	}
+	var wg sync.WaitGroup
+	task.OnFinish = func(_ dict.Dict, _ error, err error) {
+		require.NoError(ch.Env.T, err)
+		wg.Done()
+	}
+	wg.Add(1)
+	require.NoError(ch.Env.T, runvm.RunComputationsAsync(task))
+	wg.Wait()
+	return task.ResultTransaction.EssenceBytes()
+}
@@ -19,8 +19,16 @@ type block struct {
 	stateIndex   uint32
 	stateTxId    valuetransaction.ID
 	stateUpdates []StateUpdate
+	version      byte
 }
 
+// the version of the block. The state hash of the blocks of version 0 doesn't commit to the Merkle root
+// of the state: these blocks are anchored with the state hash calculated before the Merkle trie was introduced
+const (
+	BlockVersionLegacy = byte(0)
+	BlockVersion       = byte(1)
+)
+
 // validates, enumerates and creates a block from array of state updates
 func NewBlock(stateUpdates []StateUpdate) (Block, error) {
 	if len(stateUpdates) == 0 {
@@ -35,6 +43,7 @@ func NewBlock(stateUpdates []StateUpdate) (Block, error) {
 	}
 	return &block{
 		stateUpdates: stateUpdates,
+		version:      BlockVersion,
 	}, nil
 }
 
@@ -95,6 +104,15 @@ func (b *block) WithStateTransaction(vtxid valuetransaction.ID) Block {
 	return b
 }
 
+func (b *block) Version() byte {
+	return b.version
+}
+
+func (b *block) WithVersion(version byte) Block {
+	b.version = version
+	return b
+}
+
 func (b *block) ForEach(fun func(uint16, StateUpdate) bool) {
 	for i, su := range b.stateUpdates {
 		if !fun(uint16(i), su) {
@@ -131,6 +149,10 @@ func (b *block) Write(w io.Writer) error {
 	if _, err := w.Write(b.stateTxId.Bytes()); err != nil {
 		return err
 	}
+	// the fields of version 0 are followed by the version byte
+	if err := util.WriteByte(w, b.version); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -156,6 +178,18 @@ func (b *block) Read(r io.Reader) error {
 	if _, err := r.Read(b.stateTxId[:]); err != nil {
 		return err
 	}
+	var err error
+	b.version, err = util.ReadByte(r)
+	if err == io.EOF {
+		b.version = BlockVersionLegacy
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if b.version > BlockVersion {
+		return fmt.Errorf("unsupported version of the block: %d", b.version)
+	}
 	return nil
 }
 
@@ -0,0 +1,163 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/state/merkle"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// Historical state is rebuilt from reverse deltas. The reverse delta of the block is a sequence of
+// mutations which restores the state variables touched by the block to values they had before the block
+// was committed. It is saved to the db atomically with the block, so the state at block N is the latest
+// solid state with reverse deltas of blocks latest..N+1 applied on top of it
+
+func dbkeyReverseDelta(blockIndex uint32) []byte {
+	return dbprovider.MakeKey(dbprovider.ObjectTypeStateReverseDelta, util.Uint32To4Bytes(blockIndex))
+}
+
+// reverseDelta calculates reverse delta of the uncommitted mutations. Must be called before the commit
+func (vs *virtualState) reverseDelta() (buffered.MutationSequence, error) {
+	ret := buffered.NewMutationSequence()
+	var err error
+	vs.variables.Mutations().IterateLatest(func(k kv.Key, _ buffered.Mutation) bool {
+		var prev []byte
+		prev, err = vs.db.Get(dbkeyStateVariable(k))
+		switch err {
+		case nil:
+			ret.Add(buffered.NewMutationSet(k, prev))
+		case kvstore.ErrKeyNotFound:
+			err = nil
+			ret.Add(buffered.NewMutationDel(k))
+		default:
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = vs.iterateDeletedByPrefix(func(k kv.Key, value []byte) bool {
+		ret.Add(buffered.NewMutationSet(k, value))
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func loadReverseDelta(db kvstore.KVStore, blockIndex uint32) (buffered.MutationSequence, error) {
+	data, err := db.Get(dbkeyReverseDelta(blockIndex))
+	if err == kvstore.ErrKeyNotFound {
+		return nil, fmt.Errorf("history of the block #%d is not available", blockIndex)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret := buffered.NewMutationSequence()
+	if err := ret.Read(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// commitLocks exclude the commits of the solid state of the chain while its historical state is read.
+// The historical state is the latest solid state with reverse deltas applied on top of it, so the solid
+// state must not change until the reading is over
+var commitLocks = struct {
+	sync.Mutex
+	m map[coretypes.ChainID]*sync.RWMutex
+}{m: make(map[coretypes.ChainID]*sync.RWMutex)}
+
+func commitLock(chainID *coretypes.ChainID) *sync.RWMutex {
+	commitLocks.Lock()
+	defer commitLocks.Unlock()
+	ret, ok := commitLocks.m[*chainID]
+	if !ok {
+		ret = &sync.RWMutex{}
+		commitLocks.m[*chainID] = ret
+	}
+	return ret
+}
+
+// WithStateAtBlock calls f with the state variables of the chain as they were right after the block
+// with the index was committed, together with the block itself. The commits of the chain wait until f
+// returns, so the variables must not be used after that. Changes to the variables are never committed
+// to the database
+func WithStateAtBlock(chainID *coretypes.ChainID, blockIndex uint32, f func(variables buffered.BufferedKVStore, block Block) error) error {
+	return withStateAtBlock(getSCPartition(chainID), chainID, blockIndex, f)
+}
+
+func withStateAtBlock(db kvstore.KVStore, chainID *coretypes.ChainID, blockIndex uint32, f func(variables buffered.BufferedKVStore, block Block) error) error {
+	lock := commitLock(chainID)
+	lock.RLock()
+	defer lock.RUnlock()
+
+	vs, block, err := loadStateAtBlock(db, chainID, blockIndex)
+	if err != nil {
+		return err
+	}
+	return f(vs.Variables(), block)
+}
+
+// LoadStateProof returns the proof of inclusion of the key in the state of the chain right after
+// the block with the index was committed, together with the block itself
+func LoadStateProof(chainID *coretypes.ChainID, key kv.Key, blockIndex uint32) (*merkle.Proof, Block, error) {
+	lock := commitLock(chainID)
+	lock.RLock()
+	defer lock.RUnlock()
+
+	vs, block, err := loadStateAtBlock(getSCPartition(chainID), chainID, blockIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err := vs.MerkleProof(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, block, nil
+}
+
+// loadStateAtBlock loads the solid state and rewinds it to the block. Only the state variables and the
+// block index are rewound: the Merkle trie is updated with the rewound variables when it is used.
+// The rewound state is never committed to the database. The caller holds the commit lock of the chain
+// while the rewound state is used
+func loadStateAtBlock(db kvstore.KVStore, chainID *coretypes.ChainID, blockIndex uint32) (*virtualState, Block, error) {
+	solidState, _, ok, err := loadSolidState(db, chainID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("solid state not found for chain %s", chainID.String())
+	}
+	vs := solidState.(*virtualState)
+	if blockIndex > vs.BlockIndex() {
+		return nil, nil, fmt.Errorf("block #%d does not exist yet. Latest block is #%d", blockIndex, vs.BlockIndex())
+	}
+	blockData, err := db.Get(dbkeyBatch(blockIndex))
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading block #%d: %v", blockIndex, err)
+	}
+	block, err := NewBlockFromBytes(blockData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading block #%d: %v", blockIndex, err)
+	}
+	// going back from the latest block. The older reverse delta overwrites the newer one
+	for i := vs.BlockIndex(); i > blockIndex; i-- {
+		delta, err := loadReverseDelta(db, i)
+		if err != nil {
+			return nil, nil, err
+		}
+		delta.ApplyTo(vs.variables)
+	}
+	vs.blockIndex = blockIndex
+	return vs, block, nil
+}
@@ -0,0 +1,173 @@
+package state
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/goshimmer/packages/database"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/state/merkle"
+	"github.com/stretchr/testify/assert"
+)
+
+// commitBlocks commits the blocks and returns the Merkle roots of the states after them
+func commitBlocks(t *testing.T, vs VirtualState, mutations [][]buffered.Mutation) []hashing.HashValue {
+	ret := make([]hashing.HashValue, len(mutations))
+	for i, muts := range mutations {
+		ret[i] = commitBlock(t, vs, uint32(i), muts)
+	}
+	return ret
+}
+
+func commitBlock(t *testing.T, vs VirtualState, blockIndex uint32, muts []buffered.Mutation) hashing.HashValue {
+	txid := (transaction.ID)(hashing.HashStrings(fmt.Sprintf("test string %d", blockIndex)))
+	reqid := coretypes.NewRequestID(txid, 0)
+	su := NewStateUpdate(&reqid)
+	for _, mut := range muts {
+		su.Mutations().Add(mut)
+	}
+	block, err := NewBlock([]StateUpdate{su})
+	assert.NoError(t, err)
+	block.WithBlockIndex(blockIndex)
+
+	assert.NoError(t, vs.ApplyBlock(block))
+	assert.NoError(t, vs.CommitToDb(block))
+	return vs.MerkleRoot()
+}
+
+func TestLoadStateAtBlock(t *testing.T) {
+	tmpdb, _ := database.NewMemDB()
+	db := tmpdb.NewStore()
+	partition := db.WithRealm([]byte("2"))
+
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	vs := NewVirtualState(partition, &chainID)
+
+	// block #0 sets x, #1 changes x and sets y, #2 deletes x, #3 changes y
+	mutations := [][]buffered.Mutation{
+		{buffered.NewMutationSet("x", []byte{0})},
+		{buffered.NewMutationSet("x", []byte{1}), buffered.NewMutationSet("y", []byte{1})},
+		{buffered.NewMutationDel("x")},
+		{buffered.NewMutationSet("y", []byte{3})},
+	}
+	roots := commitBlocks(t, vs, mutations)
+
+	expected := []map[kv.Key][]byte{
+		{"x": {0}},
+		{"x": {1}, "y": {1}},
+		{"y": {1}},
+		{"y": {3}},
+	}
+	for i, exp := range expected {
+		vs, block, err := loadStateAtBlock(partition, &chainID, uint32(i))
+		assert.NoError(t, err)
+		assert.EqualValues(t, i, block.StateIndex())
+		for _, k := range []kv.Key{"x", "y"} {
+			v, err := vs.Variables().Get(k)
+			assert.NoError(t, err)
+			assert.EqualValues(t, exp[k], v)
+
+			// the proof is checked against the Merkle root committed with the block
+			proof, err := vs.MerkleProof(k)
+			if exp[k] == nil {
+				assert.Error(t, err)
+				continue
+			}
+			assert.NoError(t, err)
+			assert.EqualValues(t, i, proof.BlockIndex)
+			assert.NoError(t, proof.Verify(roots[i]))
+		}
+	}
+
+	// the solid state itself is not affected
+	v, _ := partition.Get(dbkeyStateVariable("y"))
+	assert.Equal(t, []byte{3}, v)
+
+	_, _, err := loadStateAtBlock(partition, &chainID, uint32(len(expected)))
+	assert.Error(t, err)
+}
+
+func TestCommitDelPrefix(t *testing.T) {
+	tmpdb, _ := database.NewMemDB()
+	db := tmpdb.NewStore()
+	partition := db.WithRealm([]byte("2"))
+
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	vs := NewVirtualState(partition, &chainID)
+
+	// block #0 sets a1, a2 and b1, #1 deletes all keys with prefix "a" and sets a2 again
+	roots := commitBlocks(t, vs, [][]buffered.Mutation{
+		{
+			buffered.NewMutationSet("a1", []byte{1}),
+			buffered.NewMutationSet("a2", []byte{2}),
+			buffered.NewMutationSet("b1", []byte{3}),
+		},
+		{buffered.NewMutationDelPrefix("a"), buffered.NewMutationSet("a2", []byte{4})},
+	})
+
+	expected := map[kv.Key][]byte{"a2": {4}, "b1": {3}}
+	for _, k := range []kv.Key{"a1", "a2", "b1"} {
+		v, err := partition.Get(dbkeyStateVariable(k))
+		if expected[k] == nil {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected[k], v)
+	}
+	root, err := merkle.NewTrieFromMap(expected).Root()
+	assert.NoError(t, err)
+	assert.EqualValues(t, root, roots[1])
+
+	vs0, _, err := loadStateAtBlock(partition, &chainID, 0)
+	assert.NoError(t, err)
+	vars := vs0.Variables()
+	assert.EqualValues(t, []byte{1}, vars.MustGet("a1"))
+	assert.EqualValues(t, []byte{2}, vars.MustGet("a2"))
+	assert.EqualValues(t, []byte{3}, vars.MustGet("b1"))
+	proof, err := vs0.MerkleProof("a1")
+	assert.NoError(t, err)
+	assert.NoError(t, proof.Verify(roots[0]))
+}
+
+func TestCommitWaitsForHistoricalRead(t *testing.T) {
+	tmpdb, _ := database.NewMemDB()
+	partition := tmpdb.NewStore().WithRealm([]byte("2"))
+
+	chainID := coretypes.ChainID{1, 3, 3, 8}
+	vs := NewVirtualState(partition, &chainID)
+	commitBlocks(t, vs, [][]buffered.Mutation{
+		{buffered.NewMutationSet("x", []byte{0})},
+		{buffered.NewMutationSet("x", []byte{1})},
+	})
+
+	lock := commitLock(&chainID)
+	lock.RLock()
+	committed := make(chan struct{})
+	go func() {
+		commitBlock(t, vs, 2, []buffered.Mutation{buffered.NewMutationSet("x", []byte{2})})
+		close(committed)
+	}()
+	select {
+	case <-committed:
+		t.Fatal("the commit didn't wait for the reader")
+	case <-time.After(100 * time.Millisecond):
+	}
+	v, err := partition.Get(dbkeyStateVariable("x"))
+	assert.NoError(t, err)
+	assert.EqualValues(t, []byte{1}, v)
+	lock.RUnlock()
+	<-committed
+
+	err = withStateAtBlock(partition, &chainID, 0, func(variables buffered.BufferedKVStore, block Block) error {
+		assert.EqualValues(t, 0, block.StateIndex())
+		assert.EqualValues(t, []byte{0}, variables.MustGet("x"))
+		return nil
+	})
+	assert.NoError(t, err)
+}
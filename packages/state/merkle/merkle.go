@@ -0,0 +1,308 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package merkle implements Merkle commitment to the key/value pairs of the virtual state
+// and proofs of inclusion of a single key/value pair in it.
+// The commitment is a binary trie over the hashes of the keys: the bit i of the hash of the key
+// selects the child at the depth i. The hash of an empty subtrie is hashing.NilHash, the hash of
+// a subtrie with exactly one key/value pair is the hash of the pair and the hash of any other
+// subtrie is the hash of the hashes of its children. The shape of the trie depends only on the keys,
+// so the root doesn't depend on the order of updates.
+// The nodes of the trie are stored by their position. An update of one key only touches the nodes
+// on the path to it, so the root is maintained incrementally from block to block
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+const (
+	leafPrefix = byte(0)
+	nodePrefix = byte(1)
+	// the maximal depth of the trie: the number of bits of the key hash
+	maxDepth = hashing.HashSize * 8
+)
+
+// node of the trie. A leaf holds one key/value pair, an inner node holds hashes of its children
+type node struct {
+	leaf bool
+	// leaf only
+	key  kv.Key
+	hash hashing.HashValue
+	// inner node only
+	children [2]hashing.HashValue
+}
+
+func (n *node) Hash() hashing.HashValue {
+	switch {
+	case n == nil:
+		return hashing.NilHash
+	case n.leaf:
+		return n.hash
+	}
+	return NodeHash(n.children[0], n.children[1])
+}
+
+func (n *node) Bytes() []byte {
+	if n.leaf {
+		return append(append([]byte{leafPrefix}, n.hash[:]...), n.key...)
+	}
+	return append(append([]byte{nodePrefix}, n.children[0][:]...), n.children[1][:]...)
+}
+
+func nodeFromBytes(data []byte) (*node, error) {
+	r := bytes.NewReader(data)
+	prefix, err := util.ReadByte(r)
+	if err != nil {
+		return nil, err
+	}
+	ret := &node{}
+	switch prefix {
+	case leafPrefix:
+		ret.leaf = true
+		if err := util.ReadHashValue(r, &ret.hash); err != nil {
+			return nil, err
+		}
+		ret.key = kv.Key(data[1+hashing.HashSize:])
+	case nodePrefix:
+		if err := util.ReadHashValue(r, &ret.children[0]); err != nil {
+			return nil, err
+		}
+		if err := util.ReadHashValue(r, &ret.children[1]); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("wrong trie node prefix %d", prefix)
+	}
+	return ret, nil
+}
+
+// Trie is the Merkle trie of the key/value pairs. The committed nodes are read from the store,
+// updated nodes are kept in memory until they are committed by the owner of the store
+type Trie struct {
+	store kv.KVStoreReader
+	// updated nodes by their keys. Nil means the node is deleted
+	dirty map[string]*node
+}
+
+// NewTrie creates the trie over the committed nodes in the store. Nil store means the empty trie
+func NewTrie(store kv.KVStoreReader) *Trie {
+	return &Trie{
+		store: store,
+		dirty: make(map[string]*node),
+	}
+}
+
+// NewTrieFromMap builds the trie from the key/value pairs of the map. All nodes are not committed
+func NewTrieFromMap(values map[kv.Key][]byte) *Trie {
+	ret := NewTrie(nil)
+	for k, v := range values {
+		if err := ret.Update(k, v); err != nil {
+			// no store, no errors
+			panic(err)
+		}
+	}
+	return ret
+}
+
+// Clone returns the copy of the trie with the same store and its own updates
+func (t *Trie) Clone() *Trie {
+	ret := NewTrie(t.store)
+	for k, n := range t.dirty {
+		// nodes are never changed in place
+		ret.dirty[k] = n
+	}
+	return ret
+}
+
+// Root returns the root of the trie
+func (t *Trie) Root() (hashing.HashValue, error) {
+	n, err := t.get(hashing.NilHash, 0)
+	if err != nil {
+		return hashing.NilHash, err
+	}
+	return n.Hash(), nil
+}
+
+// Update sets the value of the key in the trie. Nil value deletes the key
+func (t *Trie) Update(key kv.Key, value []byte) error {
+	keyHash := KeyHash(key)
+	var leaf *node
+	if value != nil {
+		leaf = &node{leaf: true, key: key, hash: LeafHash(key, value)}
+	}
+	_, err := t.update(keyHash, 0, leaf, key)
+	return err
+}
+
+// update sets the leaf in the subtrie at the depth and returns the new node of the subtrie
+func (t *Trie) update(keyHash hashing.HashValue, depth int, leaf *node, key kv.Key) (*node, error) {
+	n, err := t.get(keyHash, depth)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case n == nil:
+		if leaf != nil {
+			t.set(keyHash, depth, leaf)
+		}
+		return leaf, nil
+
+	case n.leaf && n.key == key:
+		t.set(keyHash, depth, leaf)
+		return leaf, nil
+
+	case n.leaf:
+		if leaf == nil {
+			// the key is not in the trie
+			return n, nil
+		}
+		if depth >= maxDepth {
+			return nil, errors.New("key hash collision")
+		}
+		// the leaf of another key is pushed one level down, the new inner node takes its place
+		otherHash := KeyHash(n.key)
+		t.set(otherHash, depth+1, n)
+		inner := &node{}
+		inner.children[bit(otherHash, depth)] = n.hash
+		n = inner
+	}
+
+	// inner node
+	b := bit(keyHash, depth)
+	child, err := t.update(keyHash, depth+1, leaf, key)
+	if err != nil {
+		return nil, err
+	}
+	sibling := n.children[1-b]
+	if leaf == nil && (child == nil || child.leaf) {
+		// a single leaf in the subtrie is moved up
+		if sibling == hashing.NilHash {
+			t.set(keyHash, depth+1, nil)
+			t.set(keyHash, depth, child)
+			return child, nil
+		}
+		if child == nil {
+			siblingHash := keyHash
+			siblingHash[depth/8] ^= 0x80 >> (depth % 8)
+			sib, err := t.get(siblingHash, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if sib.leaf {
+				t.set(siblingHash, depth+1, nil)
+				t.set(keyHash, depth, sib)
+				return sib, nil
+			}
+		}
+	}
+	ret := &node{}
+	ret.children[b] = child.Hash()
+	ret.children[1-b] = sibling
+	t.set(keyHash, depth, ret)
+	return ret, nil
+}
+
+// Proof returns the proof of inclusion of the key/value pair in the trie. The block index is
+// only recorded in the proof and is not interpreted by the trie
+func (t *Trie) Proof(key kv.Key, value []byte, blockIndex uint32) (*Proof, error) {
+	keyHash := KeyHash(key)
+	path := make([]hashing.HashValue, 0)
+	for depth := 0; ; depth++ {
+		n, err := t.get(keyHash, depth)
+		if err != nil {
+			return nil, err
+		}
+		if n == nil || (n.leaf && n.key != key) {
+			return nil, fmt.Errorf("key not found in the state: %x", []byte(key))
+		}
+		if n.leaf {
+			if n.hash != LeafHash(key, value) {
+				return nil, fmt.Errorf("value of the key doesn't match the trie: %x", []byte(key))
+			}
+			break
+		}
+		path = append(path, n.children[1-bit(keyHash, depth)])
+	}
+	// the path goes from the leaf up to the root
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return &Proof{
+		BlockIndex: blockIndex,
+		Key:        key,
+		Value:      value,
+		Path:       path,
+	}, nil
+}
+
+// IterateUpdates iterates over the nodes updated since the trie was created over the store.
+// The value is nil for the deleted nodes. The owner of the store commits them to make them persistent
+func (t *Trie) IterateUpdates(f func(key []byte, value []byte) bool) {
+	for k, n := range t.dirty {
+		var value []byte
+		if n != nil {
+			value = n.Bytes()
+		}
+		if !f([]byte(k), value) {
+			return
+		}
+	}
+}
+
+func (t *Trie) get(keyHash hashing.HashValue, depth int) (*node, error) {
+	k := nodeKey(keyHash, depth)
+	if n, ok := t.dirty[string(k)]; ok {
+		return n, nil
+	}
+	if t.store == nil {
+		return nil, nil
+	}
+	data, err := t.store.Get(kv.Key(k))
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return nodeFromBytes(data)
+}
+
+func (t *Trie) set(keyHash hashing.HashValue, depth int, n *node) {
+	t.dirty[string(nodeKey(keyHash, depth))] = n
+}
+
+// nodeKey is the position of the node: its depth and the first depth bits of the key hash
+func nodeKey(keyHash hashing.HashValue, depth int) []byte {
+	n := (depth + 7) / 8
+	ret := make([]byte, 2+n)
+	copy(ret, util.Uint16To2Bytes(uint16(depth)))
+	copy(ret[2:], keyHash[:n])
+	if depth%8 != 0 {
+		ret[len(ret)-1] &= ^byte(0xff >> (depth % 8))
+	}
+	return ret
+}
+
+// bit returns the bit of the key hash which selects the child of the node at the depth
+func bit(keyHash hashing.HashValue, depth int) int {
+	return int(keyHash[depth/8]>>(7-depth%8)) & 1
+}
+
+// KeyHash is the hash of the key which determines its position in the trie
+func KeyHash(key kv.Key) hashing.HashValue {
+	return hashing.HashData([]byte(key))
+}
+
+// LeafHash is a hash of the key/value pair
+func LeafHash(key kv.Key, value []byte) hashing.HashValue {
+	return hashing.HashData([]byte{leafPrefix}, util.Uint32To4Bytes(uint32(len(key))), []byte(key), value)
+}
+
+// NodeHash is a hash of the inner node of the trie
+func NodeHash(left, right hashing.HashValue) hashing.HashValue {
+	return hashing.HashData([]byte{nodePrefix}, left[:], right[:])
+}
@@ -0,0 +1,157 @@
+package merkle
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmptyTrie(t *testing.T) {
+	trie := NewTrie(nil)
+	root, err := trie.Root()
+	require.NoError(t, err)
+	require.EqualValues(t, hashing.NilHash, root)
+	_, err = trie.Proof("a", []byte("1"), 0)
+	require.Error(t, err)
+}
+
+func TestProofs(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 8, 13, 100} {
+		t.Run(fmt.Sprintf("%d leaves", n), func(t *testing.T) {
+			values := make(map[kv.Key][]byte)
+			for i := 0; i < n; i++ {
+				values[kv.Key(fmt.Sprintf("key%d", i))] = []byte(fmt.Sprintf("value%d", i))
+			}
+			trie := NewTrieFromMap(values)
+			root, err := trie.Root()
+			require.NoError(t, err)
+
+			for k, v := range values {
+				proof, err := trie.Proof(k, v, 7)
+				require.NoError(t, err)
+				require.EqualValues(t, v, proof.Value)
+				require.NoError(t, proof.Verify(root))
+
+				back, err := ProofFromBytes(proof.Bytes())
+				require.NoError(t, err)
+				require.EqualValues(t, proof, back)
+				require.NoError(t, back.Verify(root))
+
+				back.Value = []byte("wrong")
+				require.Error(t, back.Verify(root))
+			}
+			_, err = trie.Proof("key0", []byte("wrong"), 7)
+			require.Error(t, err)
+			_, err = trie.Proof("missing", []byte("value"), 7)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestRootDependsOnContent(t *testing.T) {
+	trie1 := NewTrieFromMap(map[kv.Key][]byte{"a": []byte("1"), "b": []byte("2")})
+	root1, err := trie1.Root()
+	require.NoError(t, err)
+
+	trie2 := trie1.Clone()
+	require.NoError(t, trie2.Update("b", []byte("3")))
+	root2, err := trie2.Root()
+	require.NoError(t, err)
+	require.NotEqual(t, root1, root2)
+	// the clone doesn't change the original
+	root, err := trie1.Root()
+	require.NoError(t, err)
+	require.EqualValues(t, root1, root)
+
+	require.NoError(t, trie2.Update("b", []byte("2")))
+	root3, err := trie2.Root()
+	require.NoError(t, err)
+	require.EqualValues(t, root1, root3)
+}
+
+// the root maintained by the updates is the same as the root of the trie built from scratch
+func TestIncrementalUpdates(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	values := make(map[kv.Key][]byte)
+	trie := NewTrie(nil)
+	for i := 0; i < 2000; i++ {
+		k := kv.Key(fmt.Sprintf("key%d", rnd.Intn(300)))
+		if rnd.Intn(3) == 0 {
+			delete(values, k)
+			require.NoError(t, trie.Update(k, nil))
+		} else {
+			v := []byte(fmt.Sprintf("value%d", i))
+			values[k] = v
+			require.NoError(t, trie.Update(k, v))
+		}
+		if i%100 == 0 {
+			root, err := trie.Root()
+			require.NoError(t, err)
+			expected, err := NewTrieFromMap(values).Root()
+			require.NoError(t, err)
+			require.EqualValues(t, expected, root)
+		}
+	}
+	for k := range values {
+		require.NoError(t, trie.Update(k, nil))
+	}
+	root, err := trie.Root()
+	require.NoError(t, err)
+	require.EqualValues(t, hashing.NilHash, root)
+	numNodes := 0
+	trie.IterateUpdates(func(_ []byte, value []byte) bool {
+		if value != nil {
+			numNodes++
+		}
+		return true
+	})
+	require.Zero(t, numNodes)
+}
+
+// the updates committed to the store are continued by the new trie over the store
+func TestCommittedTrie(t *testing.T) {
+	store := dict.New()
+	commit := func(trie *Trie) {
+		trie.IterateUpdates(func(key []byte, value []byte) bool {
+			if value == nil {
+				store.Del(kv.Key(key))
+			} else {
+				store.Set(kv.Key(key), value)
+			}
+			return true
+		})
+	}
+	values := make(map[kv.Key][]byte)
+	trie := NewTrie(store)
+	for i := 0; i < 50; i++ {
+		values[kv.Key(fmt.Sprintf("key%d", i))] = []byte(fmt.Sprintf("value%d", i))
+		require.NoError(t, trie.Update(kv.Key(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	commit(trie)
+
+	trie = NewTrie(store)
+	for i := 0; i < 50; i += 2 {
+		delete(values, kv.Key(fmt.Sprintf("key%d", i)))
+		require.NoError(t, trie.Update(kv.Key(fmt.Sprintf("key%d", i)), nil))
+	}
+	values["new"] = []byte("new")
+	require.NoError(t, trie.Update("new", []byte("new")))
+	commit(trie)
+
+	trie = NewTrie(store)
+	root, err := trie.Root()
+	require.NoError(t, err)
+	expected, err := NewTrieFromMap(values).Root()
+	require.NoError(t, err)
+	require.EqualValues(t, expected, root)
+	for k, v := range values {
+		proof, err := trie.Proof(k, v, 0)
+		require.NoError(t, err)
+		require.NoError(t, proof.Verify(root))
+	}
+}
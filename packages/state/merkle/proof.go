@@ -0,0 +1,127 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// Proof is a proof of inclusion of the key/value pair into the state of the chain at the specific block index
+type Proof struct {
+	BlockIndex uint32
+	Key        kv.Key
+	Value      []byte
+	// hashes of siblings from the leaf up to the root. The length of the path is the depth of the leaf
+	Path []hashing.HashValue
+}
+
+// Root calculates the Merkle root from the proof
+func (p *Proof) Root() (hashing.HashValue, error) {
+	if len(p.Path) > maxDepth {
+		return hashing.NilHash, fmt.Errorf("proof path is too long")
+	}
+	keyHash := KeyHash(p.Key)
+	h := LeafHash(p.Key, p.Value)
+	for i, sibling := range p.Path {
+		if bit(keyHash, len(p.Path)-1-i) == 0 {
+			h = NodeHash(h, sibling)
+		} else {
+			h = NodeHash(sibling, h)
+		}
+	}
+	return h, nil
+}
+
+// Verify checks if the proof is consistent with the Merkle root
+func (p *Proof) Verify(root hashing.HashValue) error {
+	r, err := p.Root()
+	if err != nil {
+		return err
+	}
+	if r != root {
+		return fmt.Errorf("proof is not valid: Merkle root mismatch")
+	}
+	return nil
+}
+
+// VerifyWithAnchor checks the proof against the Merkle root committed in the state section
+// of the anchor transaction. It is all a light client needs to validate the value of the state key
+func (p *Proof) VerifyWithAnchor(tx *sctransaction.Transaction) error {
+	stateSection, ok := tx.State()
+	if !ok {
+		return fmt.Errorf("not an anchor transaction: state section is missing in %s", tx.ID().String())
+	}
+	if stateSection.BlockIndex() != p.BlockIndex {
+		return fmt.Errorf("block index mismatch: proof is for #%d, anchor transaction is for #%d",
+			p.BlockIndex, stateSection.BlockIndex())
+	}
+	return p.Verify(stateSection.MerkleRoot())
+}
+
+func (p *Proof) Bytes() []byte {
+	var buf bytes.Buffer
+	_ = p.Write(&buf)
+	return buf.Bytes()
+}
+
+func ProofFromBytes(data []byte) (*Proof, error) {
+	ret := &Proof{}
+	if err := ret.Read(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (p *Proof) Write(w io.Writer) error {
+	if err := util.WriteUint32(w, p.BlockIndex); err != nil {
+		return err
+	}
+	if err := util.WriteBytes32(w, []byte(p.Key)); err != nil {
+		return err
+	}
+	if err := util.WriteBytes32(w, p.Value); err != nil {
+		return err
+	}
+	if err := util.WriteUint16(w, uint16(len(p.Path))); err != nil {
+		return err
+	}
+	for i := range p.Path {
+		if err := p.Path[i].Write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Proof) Read(r io.Reader) error {
+	if err := util.ReadUint32(r, &p.BlockIndex); err != nil {
+		return err
+	}
+	key, err := util.ReadBytes32(r)
+	if err != nil {
+		return err
+	}
+	p.Key = kv.Key(key)
+	if p.Value, err = util.ReadBytes32(r); err != nil {
+		return err
+	}
+	var pathLen uint16
+	if err := util.ReadUint16(r, &pathLen); err != nil {
+		return err
+	}
+	p.Path = make([]hashing.HashValue, pathLen)
+	for i := range p.Path {
+		if err := util.ReadHashValue(r, &p.Path[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
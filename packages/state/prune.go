@@ -0,0 +1,167 @@
+package state
+
+import (
+	"time"
+
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// Pruning removes the old blocks together with their reverse deltas and the markers of the requests
+// processed in them. The solid state and the latest block are never pruned.
+// The markers of processed requests are used to deduplicate requests which are delivered again from L1,
+// so a block is not pruned until it is older than MinPruningAge, whatever the policy is, and until its anchor
+// transaction is confirmed on the ledger and consumes the outputs of all requests of the block. Then no messages
+// about the requests are in flight and the requests can't be delivered again.
+// After pruning, the history of the state before the first retained block is not available and the
+// processing status of the requests of the pruned blocks is unknown
+
+// MinPruningAge is the minimum age of the block which can be pruned
+const MinPruningAge = 24 * time.Hour
+
+// ConfirmedTransactionGetter fetches the transaction from the L1 node by its ID. It returns an error
+// if the transaction is not confirmed
+type ConfirmedTransactionGetter func(txid valuetransaction.ID) (*valuetransaction.Transaction, error)
+
+// PruningResult is the report of the pruning
+type PruningResult struct {
+	FirstBlockIndex   uint32 // the first block retained after pruning
+	NumBlocks         int    // number of pruned blocks
+	NumRequestMarkers int    // number of pruned markers of processed requests
+	ReclaimedBytes    int64  // total size of the removed keys and values
+	DryRun            bool   // nothing was removed
+}
+
+func dbkeyFirstBlockIndex() []byte {
+	return dbprovider.MakeKey(dbprovider.ObjectTypeFirstBlockIndex)
+}
+
+// Prune removes the blocks of the chain at the time 'now', except the last keepBlocks blocks and
+// the blocks younger than keepDuration. Zero values mean no limit, when both are zero nothing is pruned.
+// Pruning stops at the first block whose anchor transaction is not confirmed by getConfirmedTx.
+// In the dry run mode it only reports what would be removed
+func Prune(chainID *coretypes.ChainID, keepBlocks uint32, keepDuration time.Duration, now time.Time, dryRun bool, getConfirmedTx ConfirmedTransactionGetter) (*PruningResult, error) {
+	return prune(getSCPartition(chainID), keepBlocks, keepDuration, now, dryRun, getConfirmedTx)
+}
+
+func prune(db kvstore.KVStore, keepBlocks uint32, keepDuration time.Duration, now time.Time, dryRun bool, getConfirmedTx ConfirmedTransactionGetter) (*PruningResult, error) {
+	ret := &PruningResult{DryRun: dryRun}
+	data, err := db.Get(dbkeyFirstBlockIndex())
+	switch err {
+	case nil:
+		if ret.FirstBlockIndex, err = util.Uint32From4Bytes(data); err != nil {
+			return nil, err
+		}
+	case kvstore.ErrKeyNotFound:
+	default:
+		return nil, err
+	}
+	data, err = db.Get(dbprovider.MakeKey(dbprovider.ObjectTypeSolidStateIndex))
+	if err == kvstore.ErrKeyNotFound {
+		return ret, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	solidIndex, err := util.Uint32From4Bytes(data)
+	if err != nil {
+		return nil, err
+	}
+	if keepBlocks == 0 && keepDuration == 0 {
+		return ret, nil
+	}
+
+	for i := ret.FirstBlockIndex; i < solidIndex; i++ {
+		if keepBlocks > 0 && solidIndex-i < keepBlocks {
+			break
+		}
+		blockData, err := db.Get(dbkeyBatch(i))
+		if err != nil && err != kvstore.ErrKeyNotFound {
+			return nil, err
+		}
+		// each block is removed atomically with its reverse delta and markers of the requests
+		keys := make([][]byte, 0)
+		size := int64(0)
+		if blockData != nil {
+			block, err := NewBlockFromBytes(blockData)
+			if err != nil {
+				return nil, err
+			}
+			age := now.Sub(time.Unix(0, block.Timestamp()))
+			if age < MinPruningAge || (keepDuration > 0 && age < keepDuration) {
+				break
+			}
+			if !isConsumedByAnchor(block, getConfirmedTx) {
+				break
+			}
+			keys = append(keys, dbkeyBatch(i))
+			size += int64(len(dbkeyBatch(i)) + len(blockData))
+			for _, rid := range block.RequestIDs() {
+				if rid == nil {
+					// origin block
+					continue
+				}
+				exists, err := db.Has(dbkeyRequest(rid))
+				if err != nil {
+					return nil, err
+				}
+				if exists {
+					keys = append(keys, dbkeyRequest(rid))
+					size += int64(len(dbkeyRequest(rid)) + 1)
+					ret.NumRequestMarkers++
+				}
+			}
+			ret.NumBlocks++
+		}
+		// blocks missing in the db, e.g. before the block of the imported snapshot, are skipped
+		deltaData, err := db.Get(dbkeyReverseDelta(i))
+		switch err {
+		case nil:
+			keys = append(keys, dbkeyReverseDelta(i))
+			size += int64(len(dbkeyReverseDelta(i)) + len(deltaData))
+		case kvstore.ErrKeyNotFound:
+		default:
+			return nil, err
+		}
+		ret.FirstBlockIndex = i + 1
+		ret.ReclaimedBytes += size
+		if dryRun {
+			continue
+		}
+		values := make([][]byte, len(keys), len(keys)+1)
+		keys = append(keys, dbkeyFirstBlockIndex())
+		values = append(values, util.Uint32To4Bytes(ret.FirstBlockIndex))
+		if err = util.DbSetMulti(db, keys, values); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// isConsumedByAnchor checks if the anchor transaction of the block is confirmed and consumes
+// the outputs of all requests of the block
+func isConsumedByAnchor(block Block, getConfirmedTx ConfirmedTransactionGetter) bool {
+	rids := block.RequestIDs()
+	if len(rids) == 1 && rids[0] == nil {
+		// origin block
+		return true
+	}
+	tx, err := getConfirmedTx(block.StateTransactionID())
+	if err != nil {
+		return false
+	}
+	consumed := make(map[valuetransaction.ID]bool)
+	tx.Inputs().ForEach(func(outputID valuetransaction.OutputID) bool {
+		consumed[outputID.TransactionID()] = true
+		return true
+	})
+	for _, rid := range rids {
+		if rid != nil && !consumed[*rid.TransactionID()] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+package state
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/goshimmer/packages/database"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/stretchr/testify/assert"
+)
+
+// testLedger holds the confirmed transactions
+type testLedger map[transaction.ID]*transaction.Transaction
+
+func (l testLedger) getConfirmedTx(txid transaction.ID) (*transaction.Transaction, error) {
+	if tx, ok := l[txid]; ok {
+		return tx, nil
+	}
+	return nil, fmt.Errorf("transaction %s is not confirmed", txid.String())
+}
+
+// commitBlocksAt commits a block with one request for each of the timestamps.
+// The anchor transaction of each block consumes the output of the request and is confirmed in the ledger
+func commitBlocksAt(t *testing.T, vs VirtualState, timestamps []time.Time, ledger testLedger) []coretypes.RequestID {
+	ret := make([]coretypes.RequestID, len(timestamps))
+	for i, ts := range timestamps {
+		txid := (transaction.ID)(hashing.HashStrings(fmt.Sprintf("test string %d", i)))
+		ret[i] = coretypes.NewRequestID(txid, 0)
+		su := NewStateUpdate(&ret[i]).WithTimestamp(ts.UnixNano())
+		su.Mutations().Add(buffered.NewMutationSet("x", []byte{byte(i)}))
+		block, err := NewBlock([]StateUpdate{su})
+		assert.NoError(t, err)
+		block.WithBlockIndex(uint32(i))
+
+		var chainAddress address.Address
+		anchor := transaction.New(
+			transaction.NewInputs(transaction.NewOutputID(chainAddress, txid)),
+			transaction.NewOutputs(map[address.Address][]*balance.Balance{
+				chainAddress: {balance.New(balance.ColorIOTA, 1)},
+			}),
+		)
+		block.WithStateTransaction(anchor.ID())
+		ledger[anchor.ID()] = anchor
+
+		assert.NoError(t, vs.ApplyBlock(block))
+		assert.NoError(t, vs.CommitToDb(block))
+	}
+	return ret
+}
+
+func newPruneTestState(t *testing.T, now time.Time, ages []time.Duration) (kvstore.KVStore, []coretypes.RequestID, testLedger) {
+	tmpdb, _ := database.NewMemDB()
+	partition := tmpdb.NewStore().WithRealm([]byte("2"))
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	timestamps := make([]time.Time, len(ages))
+	for i, age := range ages {
+		timestamps[i] = now.Add(-age)
+	}
+	ledger := make(testLedger)
+	return partition, commitBlocksAt(t, NewVirtualState(partition, &chainID), timestamps, ledger), ledger
+}
+
+func TestPruneKeepBlocks(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	db, reqids, ledger := newPruneTestState(t, now, []time.Duration{10 * day, 9 * day, 8 * day, 7 * day, 6 * day})
+
+	res, err := prune(db, 2, 0, now, true, ledger.getConfirmedTx)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, res.FirstBlockIndex)
+	assert.EqualValues(t, 3, res.NumBlocks)
+	assert.EqualValues(t, 3, res.NumRequestMarkers)
+	assert.True(t, res.ReclaimedBytes > 0)
+	// nothing is removed in the dry run
+	has, _ := db.Has(dbkeyBatch(0))
+	assert.True(t, has)
+
+	res2, err := prune(db, 2, 0, now, false, ledger.getConfirmedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, res.FirstBlockIndex, res2.FirstBlockIndex)
+	assert.Equal(t, res.ReclaimedBytes, res2.ReclaimedBytes)
+	for i := range reqids {
+		has, _ := db.Has(dbkeyBatch(uint32(i)))
+		assert.Equal(t, i >= 3, has)
+		has, _ = db.Has(dbkeyRequest(&reqids[i]))
+		assert.Equal(t, i >= 3, has)
+	}
+	_, _, err = loadStateAtBlock(db, &coretypes.ChainID{1, 3, 3, 7}, 3)
+	assert.NoError(t, err)
+
+	// already pruned
+	res, err = prune(db, 2, 0, now, false, ledger.getConfirmedTx)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, res.FirstBlockIndex)
+	assert.EqualValues(t, 0, res.NumBlocks)
+}
+
+func TestPruneKeepDuration(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	db, reqids, ledger := newPruneTestState(t, now, []time.Duration{10 * day, 9 * day, 8 * day, 2 * day, day})
+
+	res, err := prune(db, 0, 5*day, now, false, ledger.getConfirmedTx)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, res.FirstBlockIndex)
+	assert.EqualValues(t, 3, res.NumBlocks)
+	has, _ := db.Has(dbkeyRequest(&reqids[2]))
+	assert.False(t, has)
+	has, _ = db.Has(dbkeyRequest(&reqids[3]))
+	assert.True(t, has)
+}
+
+func TestPruneSafetyPeriod(t *testing.T) {
+	now := time.Now()
+	db, reqids, ledger := newPruneTestState(t, now, []time.Duration{48 * time.Hour, time.Hour, time.Minute, time.Second})
+
+	// the blocks younger than MinPruningAge are retained whatever the policy is
+	res, err := prune(db, 1, 0, now, false, ledger.getConfirmedTx)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, res.FirstBlockIndex)
+	for i := range reqids {
+		has, _ := db.Has(dbkeyRequest(&reqids[i]))
+		assert.Equal(t, i >= 1, has)
+	}
+
+	// the solid block is never pruned
+	res, err = prune(db, 1, 0, now.Add(365*24*time.Hour), false, ledger.getConfirmedTx)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, res.FirstBlockIndex)
+	has, _ := db.Has(dbkeyBatch(3))
+	assert.True(t, has)
+}
+
+func TestPruneDisabled(t *testing.T) {
+	now := time.Now()
+	db, _, ledger := newPruneTestState(t, now, []time.Duration{48 * time.Hour, 47 * time.Hour, 46 * time.Hour})
+
+	res, err := prune(db, 0, 0, now, false, ledger.getConfirmedTx)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, res.NumBlocks)
+	has, _ := db.Has(dbkeyBatch(0))
+	assert.True(t, has)
+}
+
+func TestPruneUnconfirmedAnchor(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	db, reqids, ledger := newPruneTestState(t, now, []time.Duration{10 * day, 9 * day, 8 * day, 7 * day})
+
+	// the anchor of the block #1 is not confirmed: the markers of its requests are retained
+	data, err := db.Get(dbkeyBatch(1))
+	assert.NoError(t, err)
+	block1, err := NewBlockFromBytes(data)
+	assert.NoError(t, err)
+	anchor := ledger[block1.StateTransactionID()]
+	delete(ledger, block1.StateTransactionID())
+	res, err := prune(db, 1, 0, now, false, ledger.getConfirmedTx)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, res.FirstBlockIndex)
+	has, _ := db.Has(dbkeyRequest(&reqids[1]))
+	assert.True(t, has)
+
+	// the confirmed anchor of the block #2 doesn't consume its request
+	ledger[block1.StateTransactionID()] = anchor
+	data, err = db.Get(dbkeyBatch(2))
+	assert.NoError(t, err)
+	block2, err := NewBlockFromBytes(data)
+	assert.NoError(t, err)
+	ledger[block2.StateTransactionID()] = anchor
+	res, err = prune(db, 1, 0, now, false, ledger.getConfirmedTx)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, res.FirstBlockIndex)
+	has, _ = db.Has(dbkeyRequest(&reqids[2]))
+	assert.True(t, has)
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/state/merkle"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/plugins/database"
 )
@@ -22,18 +24,38 @@ type virtualState struct {
 	timestamp  int64
 	empty      bool
 	stateHash  hashing.HashValue
+	merkleRoot hashing.HashValue
 	variables  buffered.BufferedKVStore
+	// Merkle trie of the state variables. It is updated with the mutations of the variables
+	// up to trieSynced when the block index is applied and committed together with the variables
+	trie       *merkle.Trie
+	trieSynced int
+	// the state was stored without the Merkle root
+	legacy bool
 }
 
+// the version of the serialized virtual state. Version 0 has no Merkle root
+const virtualStateVersion = byte(1)
+
 func NewVirtualState(db kvstore.KVStore, chainID *coretypes.ChainID) *virtualState {
 	return &virtualState{
 		chainID:   *chainID,
 		db:        db,
 		variables: buffered.NewBufferedKVStore(subRealm(db, []byte{dbprovider.ObjectTypeStateVariable})),
+		trie:      newMerkleTrie(db),
 		empty:     true,
 	}
 }
 
+// newMerkleTrie creates the Merkle trie over the nodes committed to the db
+func newMerkleTrie(db kvstore.KVStore) *merkle.Trie {
+	if db == nil {
+		// no backing database (calculation of the origin state)
+		return merkle.NewTrie(nil)
+	}
+	return merkle.NewTrie(buffered.NewBufferedKVStore(subRealm(db, []byte{dbprovider.ObjectTypeMerkleNode})))
+}
+
 func NewEmptyVirtualState(chainID *coretypes.ChainID) *virtualState {
 	return NewVirtualState(getSCPartition(chainID), chainID)
 }
@@ -49,6 +71,16 @@ func subRealm(db kvstore.KVStore, realm []byte) kvstore.KVStore {
 	return db.WithRealm(append(db.Realm(), realm...))
 }
 
+// NewVirtualStateFromBytes restores the virtual state from the form it is stored in the db by CommitToDb.
+// The state variables are read from the db
+func NewVirtualStateFromBytes(db kvstore.KVStore, chainID *coretypes.ChainID, data []byte) (VirtualState, error) {
+	vs := NewVirtualState(db, chainID)
+	if err := vs.Read(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return vs, nil
+}
+
 func (vs *virtualState) Clone() VirtualState {
 	return &virtualState{
 		chainID:    vs.chainID,
@@ -57,7 +89,11 @@ func (vs *virtualState) Clone() VirtualState {
 		timestamp:  vs.timestamp,
 		empty:      vs.empty,
 		stateHash:  vs.stateHash,
+		merkleRoot: vs.merkleRoot,
 		variables:  vs.variables.Clone(),
+		trie:       vs.trie.Clone(),
+		trieSynced: vs.trieSynced,
+		legacy:     vs.legacy,
 	}
 }
 
@@ -78,9 +114,22 @@ func (vs *virtualState) BlockIndex() uint32 {
 	return vs.blockIndex
 }
 
+// ApplyBlockIndex closes the block: updates Merkle root of the state variables
+// and commits it into the state hash together with the block index
 func (vs *virtualState) ApplyBlockIndex(blockIndex uint32) {
+	vs.applyBlockIndex(blockIndex, BlockVersion)
+}
+
+// applyBlockIndex closes the block of the version. The state hash of the legacy block
+// doesn't commit to the Merkle root, the same way the block was anchored
+func (vs *virtualState) applyBlockIndex(blockIndex uint32, version byte) {
 	vh := vs.Hash()
-	vs.stateHash = hashing.HashData(vh[:], util.Uint32To4Bytes(blockIndex))
+	vs.merkleRoot = vs.mustSyncMerkleTrie()
+	if version == BlockVersionLegacy {
+		vs.stateHash = hashing.HashData(vh[:], util.Uint32To4Bytes(blockIndex))
+	} else {
+		vs.stateHash = hashing.HashData(vh[:], util.Uint32To4Bytes(blockIndex), vs.merkleRoot[:])
+	}
 	vs.empty = false
 	vs.blockIndex = blockIndex
 }
@@ -105,7 +154,7 @@ func (vs *virtualState) ApplyBlock(batch Block) error {
 		vs.ApplyStateUpdate(stateUpd)
 		return true
 	})
-	vs.ApplyBlockIndex(batch.StateIndex())
+	vs.applyBlockIndex(batch.StateIndex(), batch.Version())
 	return nil
 }
 
@@ -123,6 +172,101 @@ func (vs *virtualState) Hash() hashing.HashValue {
 	return vs.stateHash
 }
 
+func (vs *virtualState) MerkleRoot() hashing.HashValue {
+	return vs.merkleRoot
+}
+
+// MerkleProof returns the proof of inclusion of the key in the state variables
+func (vs *virtualState) MerkleProof(key kv.Key) (*merkle.Proof, error) {
+	if _, err := vs.syncMerkleTrie(); err != nil {
+		return nil, err
+	}
+	value, err := vs.variables.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, fmt.Errorf("key not found in the state: %x", []byte(key))
+	}
+	return vs.trie.Proof(key, value, vs.blockIndex)
+}
+
+// syncMerkleTrie updates the Merkle trie with the keys mutated since the last update
+// and returns the new Merkle root. Only the paths to these keys in the trie are updated
+func (vs *virtualState) syncMerkleTrie() (hashing.HashValue, error) {
+	muts := vs.variables.Mutations()
+	mutated := make(map[kv.Key]bool)
+	var err error
+	i := 0
+	muts.Iterate(func(mut buffered.Mutation) bool {
+		i++
+		if i <= vs.trieSynced {
+			return true
+		}
+		if buffered.MutationKind(mut) != "delprefix" {
+			mutated[mut.Key()] = true
+			return true
+		}
+		// all keys which may have existed under the prefix
+		prefix := mut.Key()
+		muts.IterateLatest(func(k kv.Key, _ buffered.Mutation) bool {
+			if k.HasPrefix(prefix) {
+				mutated[k] = true
+			}
+			return true
+		})
+		if vs.db == nil {
+			return true
+		}
+		err = subRealm(vs.db, []byte{dbprovider.ObjectTypeStateVariable}).IterateKeys([]byte(prefix), func(k kvstore.Key) bool {
+			mutated[kv.Key(k)] = true
+			return true
+		})
+		return err == nil
+	})
+	if err != nil {
+		return hashing.NilHash, err
+	}
+	for k := range mutated {
+		value, err := vs.variables.Get(k)
+		if err != nil {
+			return hashing.NilHash, err
+		}
+		if err = vs.trie.Update(k, value); err != nil {
+			return hashing.NilHash, err
+		}
+	}
+	vs.trieSynced = muts.Len()
+	return vs.trie.Root()
+}
+
+func (vs *virtualState) mustSyncMerkleTrie() hashing.HashValue {
+	ret, err := vs.syncMerkleTrie()
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// rebuildMerkleTrie builds the Merkle trie from all state variables. It is needed for the legacy
+// state which was stored without the Merkle trie
+func (vs *virtualState) rebuildMerkleTrie() (hashing.HashValue, error) {
+	vs.trie = newMerkleTrie(nil)
+	var err error
+	err2 := vs.variables.Iterate(kv.EmptyPrefix, func(k kv.Key, value []byte) bool {
+		err = vs.trie.Update(k, value)
+		return err == nil
+	})
+	if err2 != nil {
+		return hashing.NilHash, err2
+	}
+	if err != nil {
+		return hashing.NilHash, err
+	}
+	vs.trieSynced = vs.variables.Mutations().Len()
+	return vs.trie.Root()
+}
+
 func (vs *virtualState) Write(w io.Writer) error {
 	if _, err := w.Write(util.Uint32To4Bytes(vs.blockIndex)); err != nil {
 		return err
@@ -133,6 +277,13 @@ func (vs *virtualState) Write(w io.Writer) error {
 	if _, err := w.Write(vs.stateHash[:]); err != nil {
 		return err
 	}
+	// the fields of version 0 are followed by the version byte and the fields added later
+	if err := util.WriteByte(w, virtualStateVersion); err != nil {
+		return err
+	}
+	if _, err := w.Write(vs.merkleRoot[:]); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -145,11 +296,27 @@ func (vs *virtualState) Read(r io.Reader) error {
 		return err
 	}
 	vs.timestamp = int64(ts)
-	if _, err := r.Read(vs.stateHash[:]); err != nil {
+	if err := util.ReadHashValue(r, &vs.stateHash); err != nil {
 		return err
 	}
 	// after reading something, the state is not empty
 	vs.empty = false
+	version, err := util.ReadByte(r)
+	if err == io.EOF {
+		// version 0
+		vs.merkleRoot = hashing.NilHash
+		vs.legacy = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if version != virtualStateVersion {
+		return fmt.Errorf("unsupported version of the virtual state: %d", version)
+	}
+	if err := util.ReadHashValue(r, &vs.merkleRoot); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -173,6 +340,16 @@ func (vs *virtualState) CommitToDb(b Block) error {
 	keys := [][]byte{varStateDbkey, batchDbKey, solidStateKey}
 	values := [][]byte{varStateData, batchData, solidStateValue}
 
+	// store updated nodes of the Merkle trie
+	if _, err = vs.syncMerkleTrie(); err != nil {
+		return err
+	}
+	vs.trie.IterateUpdates(func(k []byte, v []byte) bool {
+		keys = append(keys, dbkeyMerkleNode(k))
+		values = append(values, v)
+		return true
+	})
+
 	// store processed request IDs
 	// TODO store request IDs in the 'log' contract
 	for _, rid := range b.RequestIDs() {
@@ -180,6 +357,18 @@ func (vs *virtualState) CommitToDb(b Block) error {
 		values = append(values, []byte{0})
 	}
 
+	// store reverse delta of the block for the access to the historical state
+	reverseDelta, err := vs.reverseDelta()
+	if err != nil {
+		return err
+	}
+	reverseDeltaData, err := util.Bytes(reverseDelta)
+	if err != nil {
+		return err
+	}
+	keys = append(keys, dbkeyReverseDelta(b.StateIndex()))
+	values = append(values, reverseDeltaData)
+
 	// store uncommitted mutations
 	vs.variables.Mutations().IterateLatest(func(k kv.Key, mut buffered.Mutation) bool {
 		keys = append(keys, dbkeyStateVariable(k))
@@ -188,15 +377,55 @@ func (vs *virtualState) CommitToDb(b Block) error {
 		values = append(values, mut.Value())
 		return true
 	})
+	err = vs.iterateDeletedByPrefix(func(k kv.Key, _ []byte) bool {
+		keys = append(keys, dbkeyStateVariable(k))
+		values = append(values, nil)
+		return true
+	})
+	if err != nil {
+		return err
+	}
 
+	// the readers of the historical state of the chain must not see the commit
+	lock := commitLock(&vs.chainID)
+	lock.Lock()
 	err = util.DbSetMulti(vs.db, keys, values)
+	lock.Unlock()
 	if err != nil {
 		return err
 	}
 	vs.variables.ClearMutations()
+	vs.trie = newMerkleTrie(vs.db)
+	vs.trieSynced = 0
 	return nil
 }
 
+// iterateDeletedByPrefix iterates over the committed state variables which are deleted by the
+// uncommitted "delprefix" mutations and were not mutated individually afterwards
+func (vs *virtualState) iterateDeletedByPrefix(f func(k kv.Key, value []byte) bool) error {
+	muts := vs.variables.Mutations()
+	mutated := make(map[kv.Key]bool)
+	muts.IterateLatest(func(k kv.Key, _ buffered.Mutation) bool {
+		mutated[k] = true
+		return true
+	})
+	db := subRealm(vs.db, []byte{dbprovider.ObjectTypeStateVariable})
+	var err error
+	muts.IterateDelPrefixes(func(prefix kv.Key) bool {
+		err = db.Iterate([]byte(prefix), func(key kvstore.Key, value kvstore.Value) bool {
+			k := kv.Key(key)
+			if mutated[k] {
+				return true
+			}
+			// the same key may be covered by several prefixes
+			mutated[k] = true
+			return f(k, value)
+		})
+		return err == nil
+	})
+	return err
+}
+
 func LoadSolidState(chainID *coretypes.ChainID) (VirtualState, Block, bool, error) {
 	return loadSolidState(getSCPartition(chainID), chainID)
 }
@@ -217,8 +446,8 @@ func loadSolidState(db kvstore.KVStore, chainID *coretypes.ChainID) (VirtualStat
 		return nil, nil, false, err
 	}
 
-	vs := NewVirtualState(db, chainID)
-	if err = vs.Read(bytes.NewReader(values[0])); err != nil {
+	vs, err := NewVirtualStateFromBytes(db, chainID, values[0])
+	if err != nil {
 		return nil, nil, false, fmt.Errorf("loading variable state: %v", err)
 	}
 
@@ -229,17 +458,72 @@ func loadSolidState(db kvstore.KVStore, chainID *coretypes.ChainID) (VirtualStat
 	if vs.BlockIndex() != batch.StateIndex() {
 		return nil, nil, false, fmt.Errorf("inconsistent solid state: state indices must be equal")
 	}
+	vsImpl := vs.(*virtualState)
+	if vsImpl.legacy {
+		if err := vsImpl.migrateLegacy(); err != nil {
+			return nil, nil, false, fmt.Errorf("migrating legacy solid state: %v", err)
+		}
+	}
+	root, err := vsImpl.trie.Root()
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if root != vs.MerkleRoot() {
+		return nil, nil, false, fmt.Errorf("inconsistent solid state: Merkle root mismatch")
+	}
 	return vs, batch, true, nil
 }
 
+// migrateLegacy builds the Merkle trie of the solid state stored without it and stores the trie
+// together with the Merkle root. The state hash of the legacy state doesn't commit to the root
+func (vs *virtualState) migrateLegacy() error {
+	root, err := vs.rebuildMerkleTrie()
+	if err != nil {
+		return err
+	}
+	vs.merkleRoot = root
+	vs.legacy = false
+	varStateData, err := util.Bytes(vs)
+	if err != nil {
+		return err
+	}
+	keys := [][]byte{dbprovider.MakeKey(dbprovider.ObjectTypeSolidState)}
+	values := [][]byte{varStateData}
+	vs.trie.IterateUpdates(func(k []byte, v []byte) bool {
+		keys = append(keys, dbkeyMerkleNode(k))
+		values = append(values, v)
+		return true
+	})
+	if err := util.DbSetMulti(vs.db, keys, values); err != nil {
+		return err
+	}
+	vs.trie = newMerkleTrie(vs.db)
+	vs.trieSynced = 0
+	return nil
+}
+
 func dbkeyStateVariable(key kv.Key) []byte {
 	return dbprovider.MakeKey(dbprovider.ObjectTypeStateVariable, []byte(key))
 }
 
+func dbkeyMerkleNode(key []byte) []byte {
+	return dbprovider.MakeKey(dbprovider.ObjectTypeMerkleNode, key)
+}
+
 func dbkeyRequest(reqid *coretypes.RequestID) []byte {
 	return dbprovider.MakeKey(dbprovider.ObjectTypeProcessedRequestId, reqid[:])
 }
 
+func dbkeyAnchorTransaction() []byte {
+	return dbprovider.MakeKey(dbprovider.ObjectTypeAnchorTransaction)
+}
+
+// SaveAnchorTransaction stores the transaction which approved the solid state of the chain.
+// It is needed to verify the state exported in snapshots of the chain
+func SaveAnchorTransaction(chainID *coretypes.ChainID, tx *sctransaction.Transaction) error {
+	return getSCPartition(chainID).Set(dbkeyAnchorTransaction(), tx.Bytes())
+}
+
 func IsRequestCompleted(addr *coretypes.ChainID, reqid *coretypes.RequestID) (bool, error) {
 	return getSCPartition(addr).Has(dbkeyRequest(reqid))
 }
@@ -1,12 +1,15 @@
 package state
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/goshimmer/packages/database"
 	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/buffered"
@@ -228,3 +231,132 @@ func TestCommit(t *testing.T) {
 	v, _ = partition.Get(dbkeyStateVariable(kv.Key([]byte("x"))))
 	assert.Nil(t, v)
 }
+
+func TestMerkleRoot(t *testing.T) {
+	tmpdb, _ := database.NewMemDB()
+	db := tmpdb.NewStore()
+	partition := db.WithRealm([]byte("2"))
+
+	txid1 := (transaction.ID)(hashing.HashStrings("test string 1"))
+	reqid1 := coretypes.NewRequestID(txid1, 5)
+	su1 := NewStateUpdate(&reqid1)
+	su1.Mutations().Add(buffered.NewMutationSet("x", []byte{1}))
+	su1.Mutations().Add(buffered.NewMutationSet("y", []byte{2}))
+
+	batch1, err := NewBlock([]StateUpdate{su1})
+	assert.NoError(t, err)
+
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	vs1 := NewVirtualState(partition, &chainID)
+	assert.EqualValues(t, hashing.NilHash, vs1.MerkleRoot())
+	err = vs1.ApplyBlock(batch1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hashing.NilHash, vs1.MerkleRoot())
+
+	err = vs1.CommitToDb(batch1)
+	assert.NoError(t, err)
+
+	vs1_2, _, _, err := loadSolidState(partition, &chainID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, vs1.MerkleRoot(), vs1_2.MerkleRoot())
+
+	proof, err := vs1_2.MerkleProof("y")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{2}, proof.Value)
+	assert.NoError(t, proof.Verify(vs1.MerkleRoot()))
+}
+
+func TestLoadLegacySolidState(t *testing.T) {
+	tmpdb, _ := database.NewMemDB()
+	partition := tmpdb.NewStore().WithRealm([]byte("2"))
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+
+	vs := NewVirtualState(partition, &chainID)
+	su := NewStateUpdate(nil)
+	su.Mutations().Add(buffered.NewMutationSet("x", []byte{1}))
+	su.Mutations().Add(buffered.NewMutationSet("y", []byte{2}))
+	block, err := NewBlock([]StateUpdate{su})
+	assert.NoError(t, err)
+	assert.NoError(t, vs.ApplyBlock(block))
+	assert.NoError(t, vs.CommitToDb(block))
+	root := vs.MerkleRoot()
+
+	// the state stored in version 0 layout, without the Merkle root and the trie
+	var buf bytes.Buffer
+	assert.NoError(t, util.WriteUint32(&buf, vs.BlockIndex()))
+	assert.NoError(t, util.WriteUint64(&buf, uint64(vs.Timestamp())))
+	h := vs.Hash()
+	assert.NoError(t, h.Write(&buf))
+	assert.NoError(t, partition.Set(dbprovider.MakeKey(dbprovider.ObjectTypeSolidState), buf.Bytes()))
+	assert.NoError(t, partition.DeletePrefix([]byte{dbprovider.ObjectTypeMerkleNode}))
+
+	// the trie is rebuilt and stored on the first load
+	for i := 0; i < 2; i++ {
+		vs1, _, ok, err := loadSolidState(partition, &chainID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.EqualValues(t, vs.Hash(), vs1.Hash())
+		assert.EqualValues(t, root, vs1.MerkleRoot())
+		proof, err := vs1.MerkleProof("x")
+		assert.NoError(t, err)
+		assert.NoError(t, proof.Verify(root))
+	}
+}
+
+func TestReplayLegacyChain(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+
+	// the blocks of the chain stored before the upgrade, without the version byte
+	legacyBlocks := make([][]byte, 3)
+	for i := range legacyBlocks {
+		su := NewStateUpdate(nil).WithTimestamp(int64(i + 1))
+		su.Mutations().Add(buffered.NewMutationSet(kv.Key(fmt.Sprintf("k%d", i)), []byte{byte(i)}))
+		block, err := NewBlock([]StateUpdate{su})
+		assert.NoError(t, err)
+		data := util.MustBytes(block.WithBlockIndex(uint32(i)))
+		legacyBlocks[i] = data[:len(data)-1]
+	}
+
+	// the state hashes the blocks were anchored with
+	anchored := make([]hashing.HashValue, len(legacyBlocks))
+	ref := NewVirtualState(mapdb.NewMapDB(), &chainID)
+	for i, data := range legacyBlocks {
+		block, err := NewBlockFromBytes(data)
+		assert.NoError(t, err)
+		block.ForEach(func(_ uint16, su StateUpdate) bool {
+			ref.ApplyStateUpdate(su)
+			return true
+		})
+		vh := ref.Hash()
+		ref.stateHash = hashing.HashData(vh[:], util.Uint32To4Bytes(uint32(i)))
+		ref.empty = false
+		ref.blockIndex = uint32(i)
+		anchored[i] = ref.stateHash
+	}
+
+	vs := NewVirtualState(mapdb.NewMapDB(), &chainID)
+	for i, data := range legacyBlocks {
+		block, err := NewBlockFromBytes(data)
+		assert.NoError(t, err)
+		assert.Equal(t, BlockVersionLegacy, block.Version())
+		assert.NoError(t, vs.ApplyBlock(block))
+		assert.EqualValues(t, anchored[i], vs.Hash())
+	}
+
+	// the first block after the upgrade commits to the Merkle root
+	su := NewStateUpdate(nil).WithTimestamp(int64(len(legacyBlocks) + 1))
+	su.Mutations().Add(buffered.NewMutationSet("x", []byte{1}))
+	block, err := NewBlock([]StateUpdate{su})
+	assert.NoError(t, err)
+	block.WithBlockIndex(uint32(len(legacyBlocks)))
+	block, err = NewBlockFromBytes(util.MustBytes(block))
+	assert.NoError(t, err)
+	assert.Equal(t, BlockVersion, block.Version())
+
+	prev := vs.Clone()
+	prev.ApplyStateUpdate(su)
+	vh := prev.Hash()
+	assert.NoError(t, vs.ApplyBlock(block))
+	root := vs.MerkleRoot()
+	assert.EqualValues(t, hashing.HashData(vh[:], util.Uint32To4Bytes(block.StateIndex()), root[:]), vs.Hash())
+}
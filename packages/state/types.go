@@ -6,7 +6,9 @@ import (
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/state/merkle"
 )
 
 // represents an interface to the mutable state of the smart contract
@@ -14,6 +16,7 @@ type VirtualState interface {
 	// index of the current state. State index is incremented when state transition occurs
 	// index 0 means origin state
 	BlockIndex() uint32
+	// closes the block of the current version with the block index
 	ApplyBlockIndex(uint32)
 	// timestamp
 	Timestamp() int64
@@ -26,6 +29,11 @@ type VirtualState interface {
 	// return hash of the variable state. It is a root of the Merkle chain of all
 	// state updates starting from the origin
 	Hash() hashing.HashValue
+	// return Merkle root of the state variables as of the last applied block index.
+	// It is committed into the state hash and into the state section of the anchor transaction
+	MerkleRoot() hashing.HashValue
+	// proof of inclusion of the key in the current state variables
+	MerkleProof(key kv.Key) (*merkle.Proof, error)
 	// the storage of variable/value pairs
 	Variables() buffered.BufferedKVStore
 	Clone() VirtualState
@@ -57,6 +65,9 @@ type Block interface {
 	WithBlockIndex(uint32) Block
 	StateTransactionID() valuetransaction.ID
 	WithStateTransaction(valuetransaction.ID) Block
+	// version of the block. It defines how the state hash is calculated
+	Version() byte
+	WithVersion(byte) Block
 	Timestamp() int64
 	Size() uint16
 	RequestIDs() []*coretypes.RequestID
@@ -5,6 +5,7 @@ package tcrypto
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
@@ -12,6 +13,7 @@ import (
 	"github.com/iotaledger/wasp/packages/tcrypto/tbdn"
 	"github.com/iotaledger/wasp/packages/util"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/encrypt/ecies"
 	"go.dedis.ch/kyber/v3/share"
 	"go.dedis.ch/kyber/v3/sign/bdn"
 )
@@ -242,3 +244,15 @@ func (s *DKShare) RecoverFullSignature(sigShares [][]byte, data []byte) (signatu
 	}
 	return finalSignature, nil
 }
+
+// DecryptKey decrypts the key wrapped to the public share of this node with the private share.
+// It implements requestargs.Decrypter, so that committee members can decrypt encrypted request arguments
+func (s *DKShare) DecryptKey(wrappedKeys [][]byte) ([]byte, error) {
+	if s.Index == nil || s.PrivateShare == nil {
+		return nil, fmt.Errorf("the node is not a member of the group sharing the key")
+	}
+	if int(*s.Index) >= len(wrappedKeys) {
+		return nil, fmt.Errorf("no key wrapped to committee member #%d", *s.Index)
+	}
+	return ecies.Decrypt(s.suite, s.PrivateShare, wrappedKeys[*s.Index], nil)
+}
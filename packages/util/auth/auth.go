@@ -1,30 +1,388 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/dgrijalva/jwt-go"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
+	"github.com/mr-tron/base58"
 )
 
-func AddAuthentication(e *echo.Echo, config map[string]string) {
-	if len(config) == 0 {
-		return
+const (
+	// SchemeNone disables authentication: every caller is granted the node-admin role
+	SchemeNone = "none"
+	// SchemeBasic accepts the configured username/password and API keys
+	SchemeBasic = "basic"
+	// SchemeJWT additionally accepts signed tokens, issued by password or key signature login
+	SchemeJWT = "jwt"
+
+	// HeaderAPIKey is the HTTP header carrying an API key
+	HeaderAPIKey = "X-API-Key"
+
+	DefaultTokenDuration = 24 * time.Hour
+
+	challengeTTL = 1 * time.Minute
+
+	contextKeyRole  = "auth.role"
+	contextKeyBasic = "auth.basic"
+)
+
+var (
+	// ErrInvalidCredentials is returned (wrapped) when the caller's credentials are rejected
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrTokensNotEnabled is returned when tokens are requested but the scheme is not SchemeJWT
+	ErrTokensNotEnabled = errors.New("token authentication is not enabled")
+)
+
+// KeyStore provides the roles of the API keys and of the ed25519 public keys
+// authorized to log in. It is implemented by the registry
+type KeyStore interface {
+	APIKeyRole(key string) (Role, bool, error)
+	AuthorizedKeyRole(pubKey ed25519.PublicKey) (Role, bool, error)
+}
+
+// Token is a signed JWT issued to an authenticated caller
+type Token struct {
+	Value   string
+	Role    Role
+	Expires time.Time
+}
+
+type claims struct {
+	jwt.StandardClaims
+	Role Role `json:"role"`
+}
+
+// Authenticator identifies the callers of an echo server and assigns them a role.
+// It is configured with a map of options:
+//
+//	scheme:    "none" (default), "basic" or "jwt"
+//	username:  username of the password login, if any
+//	password:  password of the password login
+//	role:      role granted by the password login (default "node-admin")
+//	anonymous: role granted to callers without credentials (default "none")
+//	secret:    secret signing the tokens (jwt only, random by default)
+//	duration:  validity of the issued tokens (jwt only, default 24h)
+type Authenticator struct {
+	scheme    string
+	username  string
+	password  string
+	userRole  Role
+	anonymous Role
+	secret    []byte
+	duration  time.Duration
+	keys      KeyStore
+
+	challengesMutex sync.Mutex
+	challenges      map[string]time.Time
+}
+
+// New creates an Authenticator from the configuration. keys may be nil, then API keys
+// and key signature login are rejected
+func New(config map[string]string, keys KeyStore) (*Authenticator, error) {
+	a := &Authenticator{
+		scheme:     config["scheme"],
+		keys:       keys,
+		challenges: make(map[string]time.Time),
 	}
-	scheme, ok := config["scheme"]
-	if !ok {
-		return
+	if a.scheme == "" {
+		a.scheme = SchemeNone
 	}
-	switch scheme {
-	case "basic":
-		addBasicAuth(e, config["username"], config["password"])
+	switch a.scheme {
+	case SchemeNone:
+		a.anonymous = RoleNodeAdmin
+		return a, nil
+	case SchemeBasic, SchemeJWT:
 	default:
-		panic(fmt.Sprintf("Unknown auth scheme %s", scheme))
+		return nil, fmt.Errorf("unknown auth scheme %s", a.scheme)
+	}
+
+	var err error
+	a.username = config["username"]
+	a.password = config["password"]
+	if a.userRole, err = parseRoleOption(config, "role", RoleNodeAdmin); err != nil {
+		return nil, err
+	}
+	if a.anonymous, err = parseRoleOption(config, "anonymous", RoleNone); err != nil {
+		return nil, err
+	}
+	if a.scheme != SchemeJWT {
+		return a, nil
+	}
+
+	if s, ok := config["secret"]; ok && s != "" {
+		a.secret = []byte(s)
+	} else {
+		a.secret = make([]byte, 32)
+		if _, err := rand.Read(a.secret); err != nil {
+			return nil, err
+		}
+	}
+	a.duration = DefaultTokenDuration
+	if s, ok := config["duration"]; ok && s != "" {
+		if a.duration, err = time.ParseDuration(s); err != nil {
+			return nil, fmt.Errorf("invalid token duration: %v", err)
+		}
+	}
+	return a, nil
+}
+
+func parseRoleOption(config map[string]string, key string, def Role) (Role, error) {
+	s, ok := config[key]
+	if !ok || s == "" {
+		return def, nil
+	}
+	return ParseRole(s)
+}
+
+// AddAuthentication creates an Authenticator and installs its middleware in the server.
+// It panics if the configuration is invalid
+func AddAuthentication(e *echo.Echo, config map[string]string, keys KeyStore) *Authenticator {
+	a, err := New(config, keys)
+	if err != nil {
+		panic(err)
+	}
+	e.Use(a.Middleware())
+	return a
+}
+
+// Scheme returns the configured authentication scheme
+func (a *Authenticator) Scheme() string {
+	return a.scheme
+}
+
+// Middleware identifies the caller and stores its role in the context.
+// Requests with invalid credentials are rejected, the rest is up to RequireRole
+func (a *Authenticator) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if a.username != "" {
+				c.Set(contextKeyBasic, true)
+			}
+			role, err := a.Authenticate(c.Request())
+			if errors.Is(err, ErrInvalidCredentials) {
+				return unauthorized(c, err.Error())
+			}
+			if err != nil {
+				return err
+			}
+			c.Set(contextKeyRole, role)
+			return next(c)
+		}
 	}
 }
 
-func addBasicAuth(e *echo.Echo, username string, password string) {
-	e.Use(middleware.BasicAuth(func(u, p string, c echo.Context) (bool, error) {
-		return u == username && p == password, nil
-	}))
+// RequireRole rejects the requests of callers which are not granted at least the role
+func RequireRole(role Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			r := GetRole(c)
+			if r.Includes(role) {
+				return next(c)
+			}
+			if r == RoleNone {
+				return unauthorized(c, "authentication required")
+			}
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("role %s required", role))
+		}
+	}
+}
+
+// GetRole returns the role of the caller, as stored by the middleware
+func GetRole(c echo.Context) Role {
+	if r, ok := c.Get(contextKeyRole).(Role); ok {
+		return r
+	}
+	return RoleNone
+}
+
+func unauthorized(c echo.Context, msg string) error {
+	if basic, _ := c.Get(contextKeyBasic).(bool); basic {
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="wasp"`)
+	}
+	return echo.NewHTTPError(http.StatusUnauthorized, msg)
+}
+
+// Authenticate returns the role of the caller of the request. The credentials are taken from
+// the Authorization header (Bearer token or Basic username/password) or from the API key header.
+// Callers without credentials get the anonymous role
+func (a *Authenticator) Authenticate(req *http.Request) (Role, error) {
+	if a.scheme == SchemeNone {
+		return a.anonymous, nil
+	}
+	if h := req.Header.Get(echo.HeaderAuthorization); h != "" {
+		switch {
+		case hasPrefixFold(h, "Bearer "):
+			return a.ValidateToken(strings.TrimSpace(h[len("Bearer "):]))
+		case hasPrefixFold(h, "Basic "):
+			username, password, ok := req.BasicAuth()
+			if !ok {
+				return RoleNone, fmt.Errorf("%w: malformed basic credentials", ErrInvalidCredentials)
+			}
+			return a.checkPassword(username, password)
+		default:
+			return RoleNone, fmt.Errorf("%w: unsupported authorization type", ErrInvalidCredentials)
+		}
+	}
+	if key := req.Header.Get(HeaderAPIKey); key != "" {
+		return a.checkAPIKey(key)
+	}
+	return a.anonymous, nil
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+func (a *Authenticator) checkPassword(username, password string) (Role, error) {
+	if a.username == "" {
+		return RoleNone, fmt.Errorf("%w: password authentication is not enabled", ErrInvalidCredentials)
+	}
+	userOk := subtle.ConstantTimeCompare([]byte(username), []byte(a.username)) == 1
+	passOk := subtle.ConstantTimeCompare([]byte(password), []byte(a.password)) == 1
+	if !userOk || !passOk {
+		return RoleNone, fmt.Errorf("%w: wrong username or password", ErrInvalidCredentials)
+	}
+	return a.userRole, nil
+}
+
+func (a *Authenticator) checkAPIKey(key string) (Role, error) {
+	if a.keys == nil {
+		return RoleNone, fmt.Errorf("%w: API keys are not enabled", ErrInvalidCredentials)
+	}
+	role, ok, err := a.keys.APIKeyRole(key)
+	if err != nil {
+		return RoleNone, err
+	}
+	if !ok {
+		return RoleNone, fmt.Errorf("%w: unknown API key", ErrInvalidCredentials)
+	}
+	return role, nil
+}
+
+// IssueToken signs a token granting the role to the subject
+func (a *Authenticator) IssueToken(subject string, role Role) (*Token, error) {
+	if a.secret == nil {
+		return nil, ErrTokensNotEnabled
+	}
+	now := time.Now()
+	expires := now.Add(a.duration)
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, &claims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   subject,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expires.Unix(),
+		},
+		Role: role,
+	})
+	s, err := t.SignedString(a.secret)
+	if err != nil {
+		return nil, err
+	}
+	return &Token{Value: s, Role: role, Expires: expires}, nil
+}
+
+// ValidateToken checks the signature and expiration of the token and returns the role it grants
+func (a *Authenticator) ValidateToken(token string) (Role, error) {
+	if a.secret == nil {
+		return RoleNone, fmt.Errorf("%w: %v", ErrInvalidCredentials, ErrTokensNotEnabled)
+	}
+	c := &claims{}
+	_, err := jwt.ParseWithClaims(token, c, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		return a.secret, nil
+	})
+	if err != nil {
+		return RoleNone, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	return c.Role, nil
+}
+
+// LoginWithPassword checks the username and password and issues a token
+func (a *Authenticator) LoginWithPassword(username, password string) (*Token, error) {
+	if a.secret == nil {
+		return nil, ErrTokensNotEnabled
+	}
+	role, err := a.checkPassword(username, password)
+	if err != nil {
+		return nil, err
+	}
+	return a.IssueToken(username, role)
+}
+
+// ChallengeMessage returns the data to be signed for the key signature login with the challenge
+func ChallengeMessage(challenge string) []byte {
+	return []byte("wasp-login:" + challenge)
+}
+
+// NewChallenge returns a random one-time challenge for the key signature login.
+// It must be used within a minute
+func (a *Authenticator) NewChallenge() (string, error) {
+	if a.secret == nil {
+		return "", ErrTokensNotEnabled
+	}
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	challenge := base58.Encode(nonce)
+
+	a.challengesMutex.Lock()
+	defer a.challengesMutex.Unlock()
+
+	now := time.Now()
+	for ch, deadline := range a.challenges {
+		if now.After(deadline) {
+			delete(a.challenges, ch)
+		}
+	}
+	a.challenges[challenge] = now.Add(challengeTTL)
+	return challenge, nil
+}
+
+func (a *Authenticator) useChallenge(challenge string) bool {
+	a.challengesMutex.Lock()
+	defer a.challengesMutex.Unlock()
+
+	deadline, ok := a.challenges[challenge]
+	if !ok {
+		return false
+	}
+	delete(a.challenges, challenge)
+	return time.Now().Before(deadline)
+}
+
+// LoginWithSignature checks the signature of the challenge with an authorized public key
+// and issues a token with the role of the key
+func (a *Authenticator) LoginWithSignature(pubKey ed25519.PublicKey, challenge string, signature ed25519.Signature) (*Token, error) {
+	if a.secret == nil {
+		return nil, ErrTokensNotEnabled
+	}
+	if !a.useChallenge(challenge) {
+		return nil, fmt.Errorf("%w: unknown or expired challenge", ErrInvalidCredentials)
+	}
+	if !pubKey.VerifySignature(ChallengeMessage(challenge), signature) {
+		return nil, fmt.Errorf("%w: wrong signature", ErrInvalidCredentials)
+	}
+	if a.keys == nil {
+		return nil, fmt.Errorf("%w: key login is not enabled", ErrInvalidCredentials)
+	}
+	role, ok, err := a.keys.AuthorizedKeyRole(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: key %s is not authorized", ErrInvalidCredentials, pubKey)
+	}
+	return a.IssueToken(pubKey.String(), role)
 }
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type keyStore struct {
+	apiKeys map[string]Role
+	pubKeys map[ed25519.PublicKey]Role
+}
+
+func (ks *keyStore) APIKeyRole(key string) (Role, bool, error) {
+	r, ok := ks.apiKeys[key]
+	return r, ok, nil
+}
+
+func (ks *keyStore) AuthorizedKeyRole(pubKey ed25519.PublicKey) (Role, bool, error) {
+	r, ok := ks.pubKeys[pubKey]
+	return r, ok, nil
+}
+
+func TestRoles(t *testing.T) {
+	for _, r := range []Role{RoleNone, RoleReadOnly, RoleChainAdmin, RoleNodeAdmin} {
+		back, err := ParseRole(r.String())
+		require.NoError(t, err)
+		require.EqualValues(t, r, back)
+	}
+	_, err := ParseRole("root")
+	require.Error(t, err)
+
+	require.True(t, RoleNodeAdmin.Includes(RoleChainAdmin))
+	require.True(t, RoleReadOnly.Includes(RoleReadOnly))
+	require.False(t, RoleReadOnly.Includes(RoleChainAdmin))
+}
+
+func TestConfig(t *testing.T) {
+	a, err := New(nil, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, SchemeNone, a.Scheme())
+
+	_, err = New(map[string]string{"scheme": "digest"}, nil)
+	require.Error(t, err)
+	_, err = New(map[string]string{"scheme": "basic", "role": "root"}, nil)
+	require.Error(t, err)
+	_, err = New(map[string]string{"scheme": "jwt", "duration": "forever"}, nil)
+	require.Error(t, err)
+}
+
+func TestTokens(t *testing.T) {
+	a, err := New(map[string]string{"scheme": "jwt", "secret": "s3cr3t", "username": "wasp", "password": "wasp", "role": "chain-admin"}, nil)
+	require.NoError(t, err)
+
+	_, err = a.LoginWithPassword("wasp", "nope")
+	require.True(t, errors.Is(err, ErrInvalidCredentials))
+
+	token, err := a.LoginWithPassword("wasp", "wasp")
+	require.NoError(t, err)
+	require.EqualValues(t, RoleChainAdmin, token.Role)
+
+	role, err := a.ValidateToken(token.Value)
+	require.NoError(t, err)
+	require.EqualValues(t, RoleChainAdmin, role)
+
+	other, err := New(map[string]string{"scheme": "jwt", "secret": "other"}, nil)
+	require.NoError(t, err)
+	_, err = other.ValidateToken(token.Value)
+	require.True(t, errors.Is(err, ErrInvalidCredentials))
+
+	a.duration = -time.Minute
+	expired, err := a.IssueToken("wasp", RoleNodeAdmin)
+	require.NoError(t, err)
+	_, err = a.ValidateToken(expired.Value)
+	require.True(t, errors.Is(err, ErrInvalidCredentials))
+
+	basic, err := New(map[string]string{"scheme": "basic", "username": "wasp", "password": "wasp"}, nil)
+	require.NoError(t, err)
+	_, err = basic.IssueToken("wasp", RoleNodeAdmin)
+	require.EqualValues(t, ErrTokensNotEnabled, err)
+}
+
+func TestSignatureLogin(t *testing.T) {
+	kp := ed25519.GenerateKeyPair()
+	ks := &keyStore{pubKeys: map[ed25519.PublicKey]Role{kp.PublicKey: RoleNodeAdmin}}
+	a, err := New(map[string]string{"scheme": "jwt"}, ks)
+	require.NoError(t, err)
+
+	challenge, err := a.NewChallenge()
+	require.NoError(t, err)
+	token, err := a.LoginWithSignature(kp.PublicKey, challenge, kp.PrivateKey.Sign(ChallengeMessage(challenge)))
+	require.NoError(t, err)
+	require.EqualValues(t, RoleNodeAdmin, token.Role)
+
+	// challenges can be used only once
+	_, err = a.LoginWithSignature(kp.PublicKey, challenge, kp.PrivateKey.Sign(ChallengeMessage(challenge)))
+	require.True(t, errors.Is(err, ErrInvalidCredentials))
+
+	// wrong signature
+	challenge, err = a.NewChallenge()
+	require.NoError(t, err)
+	_, err = a.LoginWithSignature(kp.PublicKey, challenge, kp.PrivateKey.Sign([]byte(challenge)))
+	require.True(t, errors.Is(err, ErrInvalidCredentials))
+
+	// key not authorized
+	other := ed25519.GenerateKeyPair()
+	challenge, err = a.NewChallenge()
+	require.NoError(t, err)
+	_, err = a.LoginWithSignature(other.PublicKey, challenge, other.PrivateKey.Sign(ChallengeMessage(challenge)))
+	require.True(t, errors.Is(err, ErrInvalidCredentials))
+}
+
+func TestMiddleware(t *testing.T) {
+	ks := &keyStore{apiKeys: map[string]Role{"monitoring": RoleReadOnly}}
+	e := echo.New()
+	a := AddAuthentication(e, map[string]string{"scheme": "jwt", "username": "wasp", "password": "wasp", "anonymous": "read-only"}, ks)
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/pub", ok, RequireRole(RoleReadOnly))
+	e.GET("/adm", ok, RequireRole(RoleChainAdmin))
+
+	token, err := a.IssueToken("test", RoleChainAdmin)
+	require.NoError(t, err)
+
+	do := func(path string, setHeader func(h http.Header)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		setHeader(req.Header)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	anonymous := func(h http.Header) {}
+	apiKey := func(h http.Header) { h.Set(HeaderAPIKey, "monitoring") }
+	bearer := func(h http.Header) { h.Set(echo.HeaderAuthorization, "Bearer "+token.Value) }
+	basic := func(h http.Header) { h.Set(echo.HeaderAuthorization, "Basic d2FzcDp3YXNw") }
+	wrong := func(h http.Header) { h.Set(HeaderAPIKey, "wrong") }
+
+	require.EqualValues(t, http.StatusOK, do("/pub", anonymous).Code)
+	rec := do("/adm", anonymous)
+	require.EqualValues(t, http.StatusForbidden, rec.Code)
+	require.EqualValues(t, http.StatusForbidden, do("/adm", apiKey).Code)
+	require.EqualValues(t, http.StatusOK, do("/adm", bearer).Code)
+	require.EqualValues(t, http.StatusOK, do("/adm", basic).Code)
+	rec = do("/pub", wrong)
+	require.EqualValues(t, http.StatusUnauthorized, rec.Code)
+	require.NotEmpty(t, rec.Header().Get(echo.HeaderWWWAuthenticate))
+}
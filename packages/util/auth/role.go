@@ -0,0 +1,57 @@
+package auth
+
+import "fmt"
+
+// Role is the access level granted to an authenticated caller.
+// Roles are ordered: each role includes all the permissions of the lower ones
+type Role byte
+
+const (
+	// RoleNone is the role of a caller which is not authenticated
+	RoleNone Role = iota
+	// RoleReadOnly grants access to the public endpoints and the dashboard
+	RoleReadOnly
+	// RoleChainAdmin additionally grants access to the chain administration endpoints
+	RoleChainAdmin
+	// RoleNodeAdmin grants access to everything, including node shutdown, DKG and key management
+	RoleNodeAdmin
+)
+
+var roleNames = map[Role]string{
+	RoleNone:       "none",
+	RoleReadOnly:   "read-only",
+	RoleChainAdmin: "chain-admin",
+	RoleNodeAdmin:  "node-admin",
+}
+
+func (r Role) String() string {
+	if s, ok := roleNames[r]; ok {
+		return s
+	}
+	return fmt.Sprintf("Role(%d)", byte(r))
+}
+
+// Includes returns true if the role grants at least the permissions of other
+func (r Role) Includes(other Role) bool {
+	return r >= other
+}
+
+// ParseRole parses the string representation of the role, as returned by String
+func ParseRole(s string) (Role, error) {
+	for r, name := range roleNames {
+		if name == s {
+			return r, nil
+		}
+	}
+	return RoleNone, fmt.Errorf("unknown role '%s'", s)
+}
+
+func (r Role) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Role) UnmarshalText(text []byte) error {
+	var err error
+	*r, err = ParseRole(string(text))
+	return err
+}
@@ -20,14 +20,23 @@ func ContainsDuplicates(lst []string) bool {
 	return false
 }
 
-func NanoSecToUnixSec(ts int64) uint32 {
-	return uint32(ts / int64(time.Second))
+func StringInList(s string, lst []string) bool {
+	for _, e := range lst {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func NanoSecToUnixSec(ts int64) int64 {
+	return ts / int64(time.Second)
 }
 
-func UnixAfterSec(sec int) uint32 {
-	return TimeNowUnix() + uint32(sec)
+func UnixAfterSec(sec int) int64 {
+	return TimeNowUnix() + int64(sec)
 }
 
-func TimeNowUnix() uint32 {
-	return uint32(time.Now().Unix())
+func TimeNowUnix() int64 {
+	return time.Now().Unix()
 }
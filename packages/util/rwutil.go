@@ -6,6 +6,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/hashing"
@@ -307,6 +308,17 @@ func ReadColor(r io.Reader, color *balance.Color) error {
 	return nil
 }
 
+func ReadAddress(r io.Reader, addr *address.Address) error {
+	n, err := r.Read(addr[:])
+	if err != nil {
+		return err
+	}
+	if n != address.Length {
+		return errors.New("error while reading address")
+	}
+	return nil
+}
+
 func ReadHashValue(r io.Reader, h *hashing.HashValue) error {
 	n, err := r.Read(h[:])
 	if err != nil {
@@ -2,6 +2,7 @@ package accounts
 
 import (
 	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
 	"github.com/iotaledger/wasp/packages/hashing"
 )
 
@@ -20,10 +21,12 @@ var (
 
 func init() {
 	Interface.WithFunctions(initialize, []coreutil.ContractFunctionInterface{
-		coreutil.ViewFunc(FuncBalance, getBalance),
+		coreutil.ViewFunc(FuncBalance, getBalance).
+			WithParams(schema.Field(ParamAgentID, schema.TypeAgentID)),
 		coreutil.ViewFunc(FuncTotalAssets, getTotalAssets),
 		coreutil.ViewFunc(FuncAccounts, getAccounts),
-		coreutil.Func(FuncDeposit, deposit),
+		coreutil.Func(FuncDeposit, deposit).
+			WithParams(schema.OptionalField(ParamAgentID, schema.TypeAgentID)),
 		coreutil.Func(FuncWithdrawToAddress, withdrawToAddress),
 		coreutil.Func(FuncWithdrawToChain, withdrawToChain),
 	})
@@ -2,6 +2,7 @@ package blob
 
 import (
 	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
 	"github.com/iotaledger/wasp/packages/hashing"
 )
 
@@ -20,9 +21,16 @@ var (
 
 func init() {
 	Interface.WithFunctions(initialize, []coreutil.ContractFunctionInterface{
-		coreutil.Func(FuncStoreBlob, storeBlob),
-		coreutil.ViewFunc(FuncGetBlobInfo, getBlobInfo),
-		coreutil.ViewFunc(FuncGetBlobField, getBlobField),
+		coreutil.Func(FuncStoreBlob, storeBlob).
+			WithResults(schema.Field(ParamHash, schema.TypeHash)),
+		coreutil.ViewFunc(FuncGetBlobInfo, getBlobInfo).
+			WithParams(schema.Field(ParamHash, schema.TypeHash)),
+		coreutil.ViewFunc(FuncGetBlobField, getBlobField).
+			WithParams(
+				schema.Field(ParamHash, schema.TypeHash),
+				schema.Field(ParamField, schema.TypeString),
+			).
+			WithResults(schema.Field(ParamBytes, schema.TypeBytes)),
 		coreutil.ViewFunc(FuncListBlobs, listBlobs),
 	})
 }
@@ -38,6 +46,8 @@ const (
 	VarFieldProgramBinary      = "p"
 	VarFieldVMType             = "v"
 	VarFieldProgramDescription = "d"
+	// optional JSON of the schema of the program, see package schema
+	VarFieldProgramSchema = "s"
 
 	// function names
 	FuncGetBlobInfo  = "getBlobInfo"
@@ -2,6 +2,7 @@ package eventlog
 
 import (
 	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
 	"github.com/iotaledger/wasp/packages/hashing"
 )
 
@@ -20,8 +21,15 @@ var (
 
 func init() {
 	Interface.WithFunctions(initialize, []coreutil.ContractFunctionInterface{
-		coreutil.ViewFunc(FuncGetRecords, getRecords),
-		coreutil.ViewFunc(FuncGetNumRecords, getNumRecords),
+		coreutil.ViewFunc(FuncGetRecords, getRecords).WithParams(
+			schema.Field(ParamContractHname, schema.TypeHname),
+			schema.OptionalField(ParamMaxLastRecords, schema.TypeInt),
+			schema.OptionalField(ParamFromTs, schema.TypeInt),
+			schema.OptionalField(ParamToTs, schema.TypeInt),
+		),
+		coreutil.ViewFunc(FuncGetNumRecords, getNumRecords).
+			WithParams(schema.Field(ParamContractHname, schema.TypeHname)).
+			WithResults(schema.Field(ParamNumRecords, schema.TypeInt)),
 	})
 }
 
@@ -7,7 +7,10 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/receipts"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/iotaledger/wasp/packages/vm/core/xchain"
 )
 
 const (
@@ -27,6 +30,15 @@ func GetProcessor(programHash hashing.HashValue) (coretypes.Processor, error) {
 
 	case eventlog.Interface.ProgramHash:
 		return eventlog.Interface, nil
+
+	case receipts.Interface.ProgramHash:
+		return receipts.Interface, nil
+
+	case xchain.Interface.ProgramHash:
+		return xchain.Interface, nil
+
+	case scheduler.Interface.ProgramHash:
+		return scheduler.Interface, nil
 	}
 	return nil, fmt.Errorf("can't find builtin processor with hash %s", programHash.String())
 }
@@ -0,0 +1,35 @@
+// 'receipts' is a core contract on the chain. It keeps the receipt of each request processed by the chain:
+// the block and the position of the request in it, the error, the result, the fees charged and
+// the events emitted. Receipts are stored by the VM, the contract only provides the view
+package receipts
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+)
+
+// initialize is mandatory
+func initialize(ctx coretypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("receipts.initialize.success hname = %s", Interface.Hname().String())
+	return nil, nil
+}
+
+// getReceipt returns the receipt of the processed request
+// Parameters:
+//	- ParamRequestID ID of the request
+// Returns:
+//	- ParamReceipt encoded RequestReceipt
+func getReceipt(ctx coretypes.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	reqID := params.MustGetRequestID(ParamRequestID)
+	data := getReceiptBytes(ctx.State(), &reqID)
+	if data == nil {
+		return nil, fmt.Errorf("receipt not found for request %s", reqID.String())
+	}
+	ret := dict.New()
+	ret.Set(ParamReceipt, data)
+	return ret, nil
+}
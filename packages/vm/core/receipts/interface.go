@@ -0,0 +1,168 @@
+package receipts
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+const (
+	Name        = "receipts"
+	description = "Request receipts Contract"
+)
+
+var (
+	Interface = &coreutil.ContractInterface{
+		Name:        Name,
+		Description: description,
+		ProgramHash: hashing.HashStrings(Name),
+	}
+)
+
+func init() {
+	Interface.WithFunctions(initialize, []coreutil.ContractFunctionInterface{
+		coreutil.ViewFunc(FuncGetReceipt, getReceipt).
+			WithParams(schema.Field(ParamRequestID, schema.TypeRequestID)).
+			WithResults(schema.Field(ParamReceipt, schema.TypeBytes)),
+	})
+}
+
+const (
+	// request parameters
+	ParamRequestID = "requestID"
+	ParamReceipt   = "receipt"
+
+	// function names
+	FuncGetReceipt = "getReceipt"
+)
+
+// RequestReceipt is the outcome of the request processed by the chain
+type RequestReceipt struct {
+	RequestID    coretypes.RequestID
+	BlockIndex   uint32
+	RequestIndex uint16 // index of the request in the block
+	Error        string // empty if the request was processed successfully
+	Result       dict.Dict
+	FeeColor     balance.Color
+	Fee          int64 // owner, validator and gas fees charged
+	GasUsed      uint64
+	Events       []Event // events emitted while processing the request
+}
+
+// Event is the event emitted by the contract
+type Event struct {
+	Contract coretypes.Hname
+	Message  string
+}
+
+// IsOk returns true if the request was processed without an error
+func (rec *RequestReceipt) IsOk() bool {
+	return rec.Error == ""
+}
+
+// serde
+func (rec *RequestReceipt) Write(w io.Writer) error {
+	if err := rec.RequestID.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteUint32(w, rec.BlockIndex); err != nil {
+		return err
+	}
+	if err := util.WriteUint16(w, rec.RequestIndex); err != nil {
+		return err
+	}
+	if err := util.WriteBytes32(w, []byte(rec.Error)); err != nil {
+		return err
+	}
+	result := rec.Result
+	if result == nil {
+		result = dict.New()
+	}
+	if err := result.Write(w); err != nil {
+		return err
+	}
+	if _, err := w.Write(rec.FeeColor[:]); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, rec.Fee); err != nil {
+		return err
+	}
+	if err := util.WriteUint64(w, rec.GasUsed); err != nil {
+		return err
+	}
+	if err := util.WriteUint16(w, uint16(len(rec.Events))); err != nil {
+		return err
+	}
+	for i := range rec.Events {
+		if err := rec.Events[i].Contract.Write(w); err != nil {
+			return err
+		}
+		if err := util.WriteBytes32(w, []byte(rec.Events[i].Message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rec *RequestReceipt) Read(r io.Reader) error {
+	if err := rec.RequestID.Read(r); err != nil {
+		return err
+	}
+	if err := util.ReadUint32(r, &rec.BlockIndex); err != nil {
+		return err
+	}
+	if err := util.ReadUint16(r, &rec.RequestIndex); err != nil {
+		return err
+	}
+	e, err := util.ReadBytes32(r)
+	if err != nil {
+		return err
+	}
+	rec.Error = string(e)
+	rec.Result = dict.New()
+	if err := rec.Result.Read(r); err != nil {
+		return err
+	}
+	if err := util.ReadColor(r, &rec.FeeColor); err != nil {
+		return err
+	}
+	if err := util.ReadInt64(r, &rec.Fee); err != nil {
+		return err
+	}
+	if err := util.ReadUint64(r, &rec.GasUsed); err != nil {
+		return err
+	}
+	var numEvents uint16
+	if err := util.ReadUint16(r, &numEvents); err != nil {
+		return err
+	}
+	rec.Events = make([]Event, numEvents)
+	for i := range rec.Events {
+		if err := rec.Events[i].Contract.Read(r); err != nil {
+			return err
+		}
+		msg, err := util.ReadBytes32(r)
+		if err != nil {
+			return err
+		}
+		rec.Events[i].Message = string(msg)
+	}
+	return nil
+}
+
+func EncodeRequestReceipt(rec *RequestReceipt) []byte {
+	return util.MustBytes(rec)
+}
+
+func DecodeRequestReceipt(data []byte) (*RequestReceipt, error) {
+	ret := new(RequestReceipt)
+	err := ret.Read(bytes.NewReader(data))
+	return ret, err
+}
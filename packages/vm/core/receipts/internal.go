@@ -0,0 +1,27 @@
+package receipts
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+)
+
+const varReceipts = "r"
+
+// StoreReceipt saves the receipt of the request. Called by the VM after the request is processed
+func StoreReceipt(state kv.KVStore, rec *RequestReceipt) {
+	collections.NewMap(state, varReceipts).MustSetAt(rec.RequestID[:], EncodeRequestReceipt(rec))
+}
+
+// GetReceipt returns the receipt of the request or nil if the request has not been processed yet
+func GetReceipt(state kv.KVStoreReader, reqID *coretypes.RequestID) (*RequestReceipt, error) {
+	data := getReceiptBytes(state, reqID)
+	if data == nil {
+		return nil, nil
+	}
+	return DecodeRequestReceipt(data)
+}
+
+func getReceiptBytes(state kv.KVStoreReader, reqID *coretypes.RequestID) []byte {
+	return collections.NewMapReadOnly(state, varReceipts).MustGetAt(reqID[:])
+}
@@ -2,6 +2,7 @@
 // - initial setup of the chain during chain deployment
 // - maintaining of core parameters of the chain
 // - maintaining (setting, delegating) chain owner ID
+// - approving the address of the new committee when the chain is moved to another committee
 // - maintaining (granting, revoking) smart contract deployment rights
 // - deployment of smart contracts on the chain and maintenance of contract registry
 package root
@@ -18,6 +19,9 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/receipts"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/iotaledger/wasp/packages/vm/core/xchain"
 )
 
 // initialize handles constructor, the "init" request. This is the first call to the chain
@@ -25,7 +29,7 @@ import (
 // - stores chain ID and chain description in the state
 // - sets state ownership to the caller
 // - creates record in the registry for the 'root' itself
-// - deploys other core contracts: 'accounts', 'blob', 'eventlog' by creating records in the registry and calling constructors
+// - deploys other core contracts: 'accounts', 'blob', 'eventlog', 'receipts', 'xchain' by creating records in the registry and calling constructors
 // Input:
 // - ParamChainID coretypes.ChainID. ID of the chain. Cannot be changed
 // - ParamChainColor balance.Color
@@ -72,6 +76,21 @@ func initialize(ctx coretypes.Sandbox) (dict.Dict, error) {
 	err = storeAndInitContract(ctx, &rec, nil)
 	a.Require(err == nil, "root.init.fail: %v", err)
 
+	// deploy receipts
+	rec = NewContractRecord(receipts.Interface, ctx.Caller())
+	err = storeAndInitContract(ctx, &rec, nil)
+	a.Require(err == nil, "root.init.fail: %v", err)
+
+	// deploy xchain
+	rec = NewContractRecord(xchain.Interface, ctx.Caller())
+	err = storeAndInitContract(ctx, &rec, nil)
+	a.Require(err == nil, "root.init.fail: %v", err)
+
+	// deploy scheduler
+	rec = NewContractRecord(scheduler.Interface, ctx.Caller())
+	err = storeAndInitContract(ctx, &rec, nil)
+	a.Require(err == nil, "root.init.fail: %v", err)
+
 	state.Set(VarStateInitialized, []byte{0xFF})
 	state.Set(VarChainID, codec.EncodeChainID(chainID))
 	state.Set(VarChainColor, codec.EncodeColor(chainColor))
@@ -85,6 +104,9 @@ func initialize(ctx coretypes.Sandbox) (dict.Dict, error) {
 	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", blob.Interface.Name, blob.Interface.Hname().String())
 	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", accounts.Interface.Name, accounts.Interface.Hname().String())
 	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", eventlog.Interface.Name, eventlog.Interface.Hname().String())
+	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", receipts.Interface.Name, receipts.Interface.Hname().String())
+	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", xchain.Interface.Name, xchain.Interface.Hname().String())
+	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", scheduler.Interface.Name, scheduler.Interface.Hname().String())
 	ctx.Log().Debugf("root.initialize.success")
 	return nil, nil
 }
@@ -117,8 +139,14 @@ func deployContract(ctx coretypes.Sandbox) (dict.Dict, error) {
 			initParams.Set(key, value)
 		}
 	}
+	// checks the Wasm binary before it is loaded
+	exports, err := validateProgram(ctx, progHash)
+	a.Require(err == nil, "root.deployContract.fail: %v", err)
+	err = validateSchema(ctx, progHash, exports)
+	a.Require(err == nil, "root.deployContract.fail: %v", err)
+
 	// calls to loads VM from binary to check if it loads successfully
-	err := ctx.DeployContract(progHash, "", "", nil)
+	err = ctx.DeployContract(progHash, "", "", nil)
 	a.Require(err == nil, "root.deployContract.fail: %v", err)
 
 	// VM loaded successfully. Storing contract in the registry and calling constructor
@@ -127,6 +155,7 @@ func deployContract(ctx coretypes.Sandbox) (dict.Dict, error) {
 		Description: description,
 		Name:        name,
 		Creator:     ctx.Caller(),
+		Exports:     exports,
 	}, initParams)
 	a.Require(err == nil, "root.deployContract.fail: %v", err)
 
@@ -135,6 +164,126 @@ func deployContract(ctx coretypes.Sandbox) (dict.Dict, error) {
 	return nil, nil
 }
 
+// upgradeContract replaces the program of the contract and keeps its state.
+// The new program is validated and loaded the same way as in deployContract.
+// Then the 'migrate' entry point of the new program, if it exists, is called with all params
+// not consumed by upgradeContract. It can convert the state left by the old program.
+// If 'migrate' fails the upgrade is reverted.
+// The processor of the old program is removed from the processor cache of the node.
+// Only the creator of the contract or the chain owner can upgrade it. Core contracts can't be upgraded.
+// A deactivated contract can't be upgraded, it must be activated first, because 'migrate' is called on it
+// Inputs:
+// - ParamHname Hname of the contract
+// - ParamProgramHash HashValue of the new program
+func upgradeContract(ctx coretypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("root.upgradeContract.begin")
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	a := assert2.NewAssert(ctx.Log())
+
+	hname := params.MustGetHname(ParamHname)
+	progHash := params.MustGetHashValue(ParamProgramHash)
+	rec := mustFindManagedContract(ctx, FuncUpgradeContract, hname)
+	a.Require(!rec.Deactivated, "root.upgradeContract.fail: '%s' is deactivated, activate it before upgrading", rec.Name)
+	a.Require(progHash != rec.ProgramHash, "root.upgradeContract.fail: '%s' already has program %s", rec.Name, progHash.String())
+
+	migrateParams := dict.New()
+	for key, value := range ctx.Params() {
+		if key != ParamHname && key != ParamProgramHash {
+			migrateParams.Set(key, value)
+		}
+	}
+	exports, err := validateProgram(ctx, progHash)
+	a.Require(err == nil, "root.upgradeContract.fail: %v", err)
+	err = validateSchema(ctx, progHash, exports)
+	a.Require(err == nil, "root.upgradeContract.fail: %v", err)
+	err = ctx.DeployContract(progHash, "", "", nil)
+	a.Require(err == nil, "root.upgradeContract.fail: %v", err)
+
+	oldProgHash := rec.ProgramHash
+	rec.ProgramHash = progHash
+	rec.Exports = exports
+	collections.NewMap(ctx.State(), VarContractRegistry).MustSetAt(hname.Bytes(), EncodeContractRecord(rec))
+
+	_, err = ctx.Call(hname, coretypes.EntryPointMigrate, migrateParams, nil)
+	if err == coretypes.ErrEntryPointNotFound {
+		err = nil
+	}
+	a.Require(err == nil, "root.upgradeContract.fail: contract '%s': calling 'migrate': %v", rec.Name, err)
+
+	ctx.RemoveProcessor(oldProgHash)
+	ctx.Event(fmt.Sprintf("[upgrade] name: %s hname: %s, progHash: %s -> %s",
+		rec.Name, hname, oldProgHash.String(), progHash.String()))
+	return nil, nil
+}
+
+// deactivateContract freezes the contract: all calls to it are refused until it is activated again.
+// Tokens sent with requests to the deactivated contract are returned to the sender.
+// Only the creator of the contract or the chain owner can deactivate it. Core contracts can't be deactivated
+// Inputs:
+// - ParamHname Hname of the contract
+func deactivateContract(ctx coretypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("root.deactivateContract.begin")
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	a := assert2.NewAssert(ctx.Log())
+
+	hname := params.MustGetHname(ParamHname)
+	rec := mustFindManagedContract(ctx, FuncDeactivateContract, hname)
+	a.Require(!rec.Deactivated, "root.deactivateContract.fail: '%s' is already deactivated", rec.Name)
+
+	rec.Deactivated = true
+	collections.NewMap(ctx.State(), VarContractRegistry).MustSetAt(hname.Bytes(), EncodeContractRecord(rec))
+	ctx.Event(fmt.Sprintf("[deactivate] name: %s hname: %s", rec.Name, hname))
+	return nil, nil
+}
+
+// activateContract makes the deactivated contract callable again
+// Inputs:
+// - ParamHname Hname of the contract
+func activateContract(ctx coretypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("root.activateContract.begin")
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	a := assert2.NewAssert(ctx.Log())
+
+	hname := params.MustGetHname(ParamHname)
+	rec := mustFindManagedContract(ctx, FuncActivateContract, hname)
+	a.Require(rec.Deactivated, "root.activateContract.fail: '%s' is not deactivated", rec.Name)
+
+	rec.Deactivated = false
+	collections.NewMap(ctx.State(), VarContractRegistry).MustSetAt(hname.Bytes(), EncodeContractRecord(rec))
+	ctx.Event(fmt.Sprintf("[activate] name: %s hname: %s", rec.Name, hname))
+	return nil, nil
+}
+
+// removeContract deletes the contract from the registry, so it can't be called anymore.
+// All tokens in the on-chain account of the contract are moved to the target account.
+// The state of the contract is kept, unless erasing it is requested.
+// The contract may be active or deactivated. Its name can be used again to deploy another contract.
+// Only the creator of the contract or the chain owner can remove it. Core contracts can't be removed
+// Inputs:
+// - ParamHname Hname of the contract
+// - ParamSweepTarget AgentID which receives the tokens of the contract. Defaults to the caller
+// - ParamEraseState int64, if not 0 the state of the contract is erased. Defaults to 0
+func removeContract(ctx coretypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("root.removeContract.begin")
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	a := assert2.NewAssert(ctx.Log())
+
+	hname := params.MustGetHname(ParamHname)
+	target := params.MustGetAgentID(ParamSweepTarget, ctx.Caller())
+	eraseState := params.MustGetInt64(ParamEraseState, 0) != 0
+	rec := mustFindManagedContract(ctx, FuncRemoveContract, hname)
+	contractAgentID := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(ctx.ContractID().ChainID(), hname))
+	a.Require(target != contractAgentID, "root.removeContract.fail: '%s' can't be the target of its own tokens", rec.Name)
+
+	swept := ctx.SweepContract(hname, target, eraseState)
+	collections.NewMap(ctx.State(), VarContractRegistry).MustDelAt(hname.Bytes())
+	ctx.RemoveProcessor(rec.ProgramHash)
+
+	ctx.Event(fmt.Sprintf("[remove] name: %s hname: %s, progHash: %s, tokens to: %s, %d colors, state erased: %v",
+		rec.Name, hname, rec.ProgramHash.String(), target, swept.Len(), eraseState))
+	return nil, nil
+}
+
 // findContract view finds and returns encoded record of the contract
 // Input:
 // - ParamHname
@@ -156,6 +305,33 @@ func findContract(ctx coretypes.SandboxView) (dict.Dict, error) {
 	return ret, nil
 }
 
+// getContractSchema view returns the schema of the contract. The schema of a core contract
+// is built in, the schema of a contract deployed from a blob is stored in the blob
+// Input:
+// - ParamHname
+// Output:
+// - ParamData: JSON of the schema. Missing if the contract has no schema
+func getContractSchema(ctx coretypes.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params())
+	hname, err := params.GetHname(ParamHname)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := FindContract(ctx.State(), hname)
+	if err != nil {
+		return nil, err
+	}
+	ret := dict.New()
+	if itf, ok := getCoreInterface(rec.ProgramHash); ok {
+		ret.Set(ParamData, itf.Schema().Bytes())
+		return ret, nil
+	}
+	if data, ok := getBlobFieldView(ctx, rec.ProgramHash, blob.VarFieldProgramSchema); ok {
+		ret.Set(ParamData, data)
+	}
+	return ret, nil
+}
+
 // getChainInfo view returns general info about the chain: chain ID, chain owner ID,
 // description and the whole contract registry
 // Input: none
@@ -175,6 +351,7 @@ func getChainInfo(ctx coretypes.SandboxView) (dict.Dict, error) {
 	ret.Set(VarFeeColor, codec.EncodeColor(info.FeeColor))
 	ret.Set(VarDefaultOwnerFee, codec.EncodeInt64(info.DefaultOwnerFee))
 	ret.Set(VarDefaultValidatorFee, codec.EncodeInt64(info.DefaultValidatorFee))
+	ret.Set(VarGasPrice, codec.EncodeInt64(info.GasPrice))
 
 	src := collections.NewMapReadOnly(ctx.State(), VarContractRegistry)
 	dst := collections.NewMap(ret, VarContractRegistry)
@@ -222,12 +399,37 @@ func claimChainOwnership(ctx coretypes.Sandbox) (dict.Dict, error) {
 	return nil, nil
 }
 
+// rotateCommittee approves the address of the new committee of the chain, normally the address
+// of the distributed key generated by the new committee nodes. The state transaction which settles
+// the request moves the chain token and all tokens owned by the chain to the new address,
+// so from then on only the new committee is able to advance the chain.
+// Only the chain owner can rotate the committee
+// Input:
+// - ParamChainAddress address.Address address of the new committee
+func rotateCommittee(ctx coretypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("root.rotateCommittee.begin")
+	a := assert2.NewAssert(ctx.Log())
+	a.Require(CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()), "root.rotateCommittee: not authorized")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	newAddress := params.MustGetAddress(ParamChainAddress)
+	stateDecoder := kvdecoder.New(ctx.State(), ctx.Log())
+	currentAddress := stateDecoder.MustGetAddress(VarChainAddress)
+	a.Require(newAddress != currentAddress, "root.rotateCommittee: chain is already controlled by %s", newAddress.String())
+
+	ctx.State().Set(VarChainAddress, codec.EncodeAddress(newAddress))
+	ctx.Event(fmt.Sprintf("[rotate] chain address %s -> %s", currentAddress.String(), newAddress.String()))
+	ctx.Log().Debugf("root.rotateCommittee.success: chain address %s -> %s", currentAddress.String(), newAddress.String())
+	return nil, nil
+}
+
 // getFeeInfo returns fee information for the contract.
 // Input:
 // - ParamHname coretypes.Hname contract id
 // Output:
 // - ParamFeeColor balance.Color color of tokens accepted for fees
 // - ParamValidatorFee int64 minimum fee for contract
+// - ParamGasPrice int64 number of fee tokens per vm.GasPriceUnit of gas
 // Note: return default chain values if contract doesn't exist
 func getFeeInfo(ctx coretypes.SandboxView) (dict.Dict, error) {
 	params := kvdecoder.New(ctx.Params())
@@ -240,6 +442,7 @@ func getFeeInfo(ctx coretypes.SandboxView) (dict.Dict, error) {
 	ret.Set(ParamFeeColor, codec.EncodeColor(feeColor))
 	ret.Set(ParamOwnerFee, codec.EncodeInt64(ownerFee))
 	ret.Set(ParamValidatorFee, codec.EncodeInt64(validatorFee))
+	ret.Set(ParamGasPrice, codec.EncodeInt64(GetGasPrice(ctx.State())))
 	return ret, nil
 }
 
@@ -247,6 +450,7 @@ func getFeeInfo(ctx coretypes.SandboxView) (dict.Dict, error) {
 // Input:
 // - ParamOwnerFee int64 non-negative value of the owner fee. May be skipped, then it is not set
 // - ParamValidatorFee int64 non-negative value of the contract fee. May be skipped, then it is not set
+// - ParamGasPrice int64 non-negative number of fee tokens per vm.GasPriceUnit of gas. May be skipped, then it is not set
 func setDefaultFee(ctx coretypes.Sandbox) (dict.Dict, error) {
 	a := assert2.NewAssert(ctx.Log())
 	a.Require(CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()), "root.setDefaultFee: not authorized")
@@ -257,8 +461,10 @@ func setDefaultFee(ctx coretypes.Sandbox) (dict.Dict, error) {
 	ownerFeeSet := ownerFee >= 0
 	validatorFee := params.MustGetInt64(ParamValidatorFee, -1)
 	validatorFeeSet := validatorFee >= 0
+	gasPrice := params.MustGetInt64(ParamGasPrice, -1)
+	gasPriceSet := gasPrice >= 0
 
-	a.Require(ownerFeeSet || validatorFeeSet, "root.setDefaultFee: wrong parameters")
+	a.Require(ownerFeeSet || validatorFeeSet || gasPriceSet, "root.setDefaultFee: wrong parameters")
 
 	if ownerFeeSet {
 		if ownerFee > 0 {
@@ -274,6 +480,13 @@ func setDefaultFee(ctx coretypes.Sandbox) (dict.Dict, error) {
 			ctx.State().Del(VarDefaultValidatorFee)
 		}
 	}
+	if gasPriceSet {
+		if gasPrice > 0 {
+			ctx.State().Set(VarGasPrice, codec.EncodeInt64(gasPrice))
+		} else {
+			ctx.State().Del(VarGasPrice)
+		}
+	}
 	return nil, nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"io"
 
 	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/util"
 )
@@ -29,17 +30,83 @@ var (
 
 func init() {
 	Interface.WithFunctions(initialize, []coreutil.ContractFunctionInterface{
-		coreutil.Func(FuncDeployContract, deployContract),
-		coreutil.ViewFunc(FuncFindContract, findContract),
+		coreutil.Func(FuncDeployContract, deployContract).WithParams(
+			schema.Field(ParamName, schema.TypeString),
+			schema.OptionalField(ParamDescription, schema.TypeString),
+			schema.Field(ParamProgramHash, schema.TypeHash),
+		),
+		coreutil.Func(FuncUpgradeContract, upgradeContract).WithParams(
+			schema.Field(ParamHname, schema.TypeHname),
+			schema.Field(ParamProgramHash, schema.TypeHash),
+		),
+		coreutil.Func(FuncDeactivateContract, deactivateContract).
+			WithParams(schema.Field(ParamHname, schema.TypeHname)),
+		coreutil.Func(FuncActivateContract, activateContract).
+			WithParams(schema.Field(ParamHname, schema.TypeHname)),
+		coreutil.Func(FuncRemoveContract, removeContract).WithParams(
+			schema.Field(ParamHname, schema.TypeHname),
+			schema.OptionalField(ParamSweepTarget, schema.TypeAgentID),
+			schema.OptionalField(ParamEraseState, schema.TypeInt),
+		),
+		coreutil.ViewFunc(FuncFindContract, findContract).
+			WithParams(schema.Field(ParamHname, schema.TypeHname)).
+			WithResults(schema.Field(ParamData, schema.TypeBytes)),
+		coreutil.ViewFunc(FuncGetContractSchema, getContractSchema).
+			WithParams(schema.Field(ParamHname, schema.TypeHname)).
+			WithResults(schema.OptionalField(ParamData, schema.TypeString)),
 		coreutil.Func(FuncClaimChainOwnership, claimChainOwnership),
-		coreutil.Func(FuncDelegateChainOwnership, delegateChainOwnership),
-		coreutil.ViewFunc(FuncGetChainInfo, getChainInfo),
-		coreutil.ViewFunc(FuncGetFeeInfo, getFeeInfo),
-		coreutil.Func(FuncSetDefaultFee, setDefaultFee),
-		coreutil.Func(FuncSetContractFee, setContractFee),
-		coreutil.Func(FuncGrantDeploy, grantDeployPermission),
-		coreutil.Func(FuncRevokeDeploy, revokeDeployPermission),
+		coreutil.Func(FuncDelegateChainOwnership, delegateChainOwnership).
+			WithParams(schema.Field(ParamChainOwner, schema.TypeAgentID)),
+		coreutil.ViewFunc(FuncGetChainInfo, getChainInfo).WithResults(
+			schema.Field(VarChainID, schema.TypeChainID),
+			schema.Field(VarChainOwnerID, schema.TypeAgentID),
+			schema.Field(VarChainColor, schema.TypeColor),
+			schema.Field(VarChainAddress, schema.TypeAddress),
+			schema.Field(VarDescription, schema.TypeString),
+			schema.Field(VarFeeColor, schema.TypeColor),
+			schema.Field(VarDefaultOwnerFee, schema.TypeInt),
+			schema.Field(VarDefaultValidatorFee, schema.TypeInt),
+			schema.Field(VarGasPrice, schema.TypeInt),
+		),
+		coreutil.ViewFunc(FuncGetFeeInfo, getFeeInfo).
+			WithParams(schema.Field(ParamHname, schema.TypeHname)).
+			WithResults(
+				schema.Field(ParamFeeColor, schema.TypeColor),
+				schema.Field(ParamOwnerFee, schema.TypeInt),
+				schema.Field(ParamValidatorFee, schema.TypeInt),
+				schema.Field(ParamGasPrice, schema.TypeInt),
+			),
+		coreutil.Func(FuncSetDefaultFee, setDefaultFee).WithParams(
+			schema.OptionalField(ParamOwnerFee, schema.TypeInt),
+			schema.OptionalField(ParamValidatorFee, schema.TypeInt),
+			schema.OptionalField(ParamGasPrice, schema.TypeInt),
+		),
+		coreutil.Func(FuncSetContractFee, setContractFee).WithParams(
+			schema.Field(ParamHname, schema.TypeHname),
+			schema.OptionalField(ParamOwnerFee, schema.TypeInt),
+			schema.OptionalField(ParamValidatorFee, schema.TypeInt),
+		),
+		coreutil.Func(FuncGrantDeploy, grantDeployPermission).
+			WithParams(schema.Field(ParamDeployer, schema.TypeAgentID)),
+		coreutil.Func(FuncRevokeDeploy, revokeDeployPermission).
+			WithParams(schema.Field(ParamDeployer, schema.TypeAgentID)),
+		coreutil.Func(FuncRotateCommittee, rotateCommittee).
+			WithParams(schema.Field(ParamChainAddress, schema.TypeAddress)),
 	})
+	Interface.State = []schema.StateDef{
+		{Name: VarChainID, Kind: schema.KindVar, Type: schema.TypeChainID},
+		{Name: VarChainColor, Kind: schema.KindVar, Type: schema.TypeColor},
+		{Name: VarChainAddress, Kind: schema.KindVar, Type: schema.TypeAddress},
+		{Name: VarChainOwnerID, Kind: schema.KindVar, Type: schema.TypeAgentID},
+		{Name: VarChainOwnerIDDelegated, Kind: schema.KindVar, Type: schema.TypeAgentID},
+		{Name: VarDescription, Kind: schema.KindVar, Type: schema.TypeString},
+		{Name: VarFeeColor, Kind: schema.KindVar, Type: schema.TypeColor},
+		{Name: VarDefaultOwnerFee, Kind: schema.KindVar, Type: schema.TypeInt},
+		{Name: VarDefaultValidatorFee, Kind: schema.KindVar, Type: schema.TypeInt},
+		{Name: VarGasPrice, Kind: schema.KindVar, Type: schema.TypeInt},
+		{Name: VarContractRegistry, Kind: schema.KindMap, Key: schema.TypeHname, Type: schema.TypeBytes},
+		{Name: VarDeployPermissions, Kind: schema.KindMap, Key: schema.TypeAgentID, Type: schema.TypeBytes},
+	}
 }
 
 // state variables
@@ -56,6 +123,7 @@ const (
 	VarContractRegistry      = "r"
 	VarDescription           = "d"
 	VarDeployPermissions     = "dep"
+	VarGasPrice              = "gp"
 )
 
 // param variables
@@ -73,12 +141,20 @@ const (
 	ParamOwnerFee     = "$$ownerfee$$"
 	ParamValidatorFee = "$$validatorfee$$"
 	ParamDeployer     = "$$deployer$$"
+	ParamGasPrice     = "$$gasprice$$"
+	ParamSweepTarget  = "$$target$$"
+	ParamEraseState   = "$$erase$$"
 )
 
 // function names
 const (
 	FuncDeployContract         = "deployContract"
+	FuncUpgradeContract        = "upgradeContract"
+	FuncDeactivateContract     = "deactivateContract"
+	FuncActivateContract       = "activateContract"
+	FuncRemoveContract         = "removeContract"
 	FuncFindContract           = "findContract"
+	FuncGetContractSchema      = "getContractSchema"
 	FuncGetChainInfo           = "getChainInfo"
 	FuncDelegateChainOwnership = "delegateChainOwnership"
 	FuncClaimChainOwnership    = "claimChainOwnership"
@@ -87,6 +163,7 @@ const (
 	FuncSetContractFee         = "setContractFee"
 	FuncGrantDeploy            = "grantDeployPermission"
 	FuncRevokeDeploy           = "revokeDeployPermission"
+	FuncRotateCommittee        = "rotateCommittee"
 )
 
 // ContractRecord is a structure which contains metadata of the deployed contract instance
@@ -110,6 +187,17 @@ type ContractRecord struct {
 	// The agentID of the entity which deployed the instance. It can be interpreted as
 	// an priviledged user of the instance, however it is up to the smart contract.
 	Creator coretypes.AgentID
+	// The entry points of the contract discovered when its program was validated at deployment.
+	// Empty for builtin contracts
+	Exports []ContractExport
+	// A deactivated contract refuses all calls. The tokens sent with requests to it are returned to the sender
+	Deactivated bool
+}
+
+// ContractExport is an entry point exported by the program of the contract
+type ContractExport struct {
+	Name string
+	View bool
 }
 
 // ChainInfo is an API structure which contains main properties of the chain in on place
@@ -122,6 +210,7 @@ type ChainInfo struct {
 	FeeColor            balance.Color
 	DefaultOwnerFee     int64
 	DefaultValidatorFee int64
+	GasPrice            int64
 }
 
 func (p *ContractRecord) Hname() coretypes.Hname {
@@ -148,6 +237,20 @@ func (p *ContractRecord) Write(w io.Writer) error {
 	if _, err := w.Write(p.Creator[:]); err != nil {
 		return err
 	}
+	if err := util.WriteUint16(w, uint16(len(p.Exports))); err != nil {
+		return err
+	}
+	for _, exp := range p.Exports {
+		if err := util.WriteString16(w, exp.Name); err != nil {
+			return err
+		}
+		if err := util.WriteBoolByte(w, exp.View); err != nil {
+			return err
+		}
+	}
+	if err := util.WriteBoolByte(w, p.Deactivated); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -171,6 +274,32 @@ func (p *ContractRecord) Read(r io.Reader) error {
 	if err := coretypes.ReadAgentID(r, &p.Creator); err != nil {
 		return err
 	}
+	var numExports uint16
+	if err := util.ReadUint16(r, &numExports); err != nil {
+		if err == io.EOF {
+			// the record was stored before the exports were introduced
+			return nil
+		}
+		return err
+	}
+	if numExports > 0 {
+		p.Exports = make([]ContractExport, numExports)
+		for i := range p.Exports {
+			if p.Exports[i].Name, err = util.ReadString16(r); err != nil {
+				return err
+			}
+			if err := util.ReadBoolByte(r, &p.Exports[i].View); err != nil {
+				return err
+			}
+		}
+	}
+	if err := util.ReadBoolByte(r, &p.Deactivated); err != nil {
+		if err == io.EOF {
+			// the record was stored before deactivation was introduced
+			return nil
+		}
+		return err
+	}
 	return nil
 }
 
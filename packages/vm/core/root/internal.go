@@ -4,11 +4,22 @@ import (
 	"fmt"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	assert2 "github.com/iotaledger/wasp/packages/coretypes/assert"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/blob"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/receipts"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/iotaledger/wasp/packages/vm/core/xchain"
+	"github.com/iotaledger/wasp/packages/vm/wasmvalidate"
 )
 
 // FindContract is an internal utility function which finds a contract in the KVStore
@@ -45,10 +56,18 @@ func MustGetChainInfo(state kv.KVStoreReader) ChainInfo {
 		FeeColor:            d.MustGetColor(VarFeeColor, balance.ColorIOTA),
 		DefaultOwnerFee:     d.MustGetInt64(VarDefaultOwnerFee, 0),
 		DefaultValidatorFee: d.MustGetInt64(VarDefaultValidatorFee, 0),
+		GasPrice:            d.MustGetInt64(VarGasPrice, 0),
 	}
 	return ret
 }
 
+// GetGasPrice returns number of fee tokens charged for each vm.GasPriceUnit of gas burned by the request
+// 0 means gas is not charged, only the gas budget is enforced
+func GetGasPrice(state kv.KVStoreReader) int64 {
+	d := kvdecoder.New(state)
+	return d.MustGetInt64(VarGasPrice, 0)
+}
+
 // GetFeeInfo is an internal utility function which returns fee info for the contract
 // It is called from within the 'root' contract as well as VMContext and viewcontext objects
 // It is not exposed to the sandbox
@@ -151,6 +170,20 @@ func storeAndInitContract(ctx coretypes.Sandbox, rec *ContractRecord, initParams
 	return err
 }
 
+// mustFindManagedContract finds the contract which the caller upgrades, deactivates or removes.
+// Core contracts can't be managed. Only the creator of the contract or the chain owner can do it
+func mustFindManagedContract(ctx coretypes.Sandbox, funcName string, hname coretypes.Hname) *ContractRecord {
+	a := assert2.NewAssert(ctx.Log())
+	rec, err := FindContract(ctx.State(), hname)
+	a.Require(err == nil, "root.%s.fail: contract %s: %v", funcName, hname.String(), err)
+	_, isCore := getCoreInterface(rec.ProgramHash)
+	a.Require(!isCore, "root.%s.fail: core contract '%s' can't be managed", funcName, rec.Name)
+	caller := ctx.Caller()
+	a.Require(caller == rec.Creator || caller == ctx.ChainOwnerID(),
+		"root.%s.fail: '%s' not permitted for: %s", funcName, rec.Name, caller)
+	return rec
+}
+
 // isAuthorizedToDeploy checks if caller is authorized to deploy smart contract
 func isAuthorizedToDeploy(ctx coretypes.Sandbox) bool {
 	caller := ctx.Caller()
@@ -165,3 +198,94 @@ func isAuthorizedToDeploy(ctx coretypes.Sandbox) bool {
 
 	return collections.NewMap(ctx.State(), VarDeployPermissions).MustHasAt(caller[:])
 }
+
+// validateProgram checks the Wasm binary of the program with the hash and returns the entry points
+// it exports. Builtin programs and programs of other VM types are not checked
+func validateProgram(ctx coretypes.Sandbox, progHash hashing.HashValue) ([]ContractExport, error) {
+	binary, ok := getBlobField(ctx, progHash, blob.VarFieldProgramBinary)
+	if !ok {
+		// not a blob, the builtin program is located when the VM is loaded
+		return nil, nil
+	}
+	vmtype := wasmvalidate.VMType
+	if v, ok := getBlobField(ctx, progHash, blob.VarFieldVMType); ok {
+		vmtype = string(v)
+	}
+	if vmtype != wasmvalidate.VMType {
+		return nil, nil
+	}
+	entryPoints, err := wasmvalidate.Validate(binary)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Wasm program %s: %v", progHash.String(), err)
+	}
+	ret := make([]ContractExport, len(entryPoints))
+	for i, ep := range entryPoints {
+		ret[i] = ContractExport{Name: ep.Name, View: ep.View}
+	}
+	return ret, nil
+}
+
+func getBlobField(ctx coretypes.Sandbox, hash hashing.HashValue, field string) ([]byte, bool) {
+	ret, err := ctx.Call(blob.Interface.Hname(), coretypes.Hn(blob.FuncGetBlobField), codec.MakeDict(map[string]interface{}{
+		blob.ParamHash:  hash,
+		blob.ParamField: field,
+	}), nil)
+	if err != nil {
+		return nil, false
+	}
+	return ret.MustGet(blob.ParamBytes), true
+}
+
+// validateSchema checks the schema of the program with the hash, if it has one.
+// The functions of the schema must be entry points of the program.
+// Entry points are only known for validated Wasm programs
+func validateSchema(ctx coretypes.Sandbox, progHash hashing.HashValue, exports []ContractExport) error {
+	data, ok := getBlobField(ctx, progHash, blob.VarFieldProgramSchema)
+	if !ok {
+		return nil
+	}
+	s, err := schema.Parse(data)
+	if err != nil {
+		return fmt.Errorf("program %s: %v", progHash.String(), err)
+	}
+	if exports == nil {
+		return nil
+	}
+	entryPoints := make(map[string]bool)
+	for _, ep := range exports {
+		entryPoints[ep.Name] = ep.View
+	}
+	if err = s.CheckEntryPoints(entryPoints); err != nil {
+		return fmt.Errorf("program %s: %v", progHash.String(), err)
+	}
+	return nil
+}
+
+// getCoreInterface returns the interface of the core contract with the program hash
+func getCoreInterface(progHash hashing.HashValue) (*coreutil.ContractInterface, bool) {
+	for _, itf := range []*coreutil.ContractInterface{
+		Interface,
+		blob.Interface,
+		accounts.Interface,
+		eventlog.Interface,
+		receipts.Interface,
+		xchain.Interface,
+		scheduler.Interface,
+	} {
+		if itf.ProgramHash == progHash {
+			return itf, true
+		}
+	}
+	return nil, false
+}
+
+func getBlobFieldView(ctx coretypes.SandboxView, hash hashing.HashValue, field string) ([]byte, bool) {
+	ret, err := ctx.Call(blob.Interface.Hname(), coretypes.Hn(blob.FuncGetBlobField), codec.MakeDict(map[string]interface{}{
+		blob.ParamHash:  hash,
+		blob.ParamField: field,
+	}))
+	if err != nil {
+		return nil, false
+	}
+	return ret.MustGet(blob.ParamBytes), true
+}
@@ -0,0 +1,269 @@
+// 'scheduler' is a core contract on the chain. It makes calls to the contracts of the chain
+// at a given time, once or recurring with a fixed interval. Each scheduled call has the budget,
+// prepaid by whoever scheduled it, which pays for the calls.
+// When the call is due, the time-locked 'trigger' request the scheduler posted to itself is
+// selected for the batch by the consensus, and the scheduler posts the request to the target
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/assert"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+)
+
+// initialize is mandatory
+func initialize(ctx coretypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("scheduler.initialize.success hname = %s", Interface.Hname().String())
+	return nil, nil
+}
+
+// scheduleCall registers the call and posts the first trigger request.
+// The incoming transfer, in iotas, is the budget of the call.
+// Parameters:
+//   - ParamTargetHname the contract on the chain. Default is the caller, if it is a contract of the chain
+//   - ParamEntryPoint the entry point of the target contract
+//   - ParamArgs encoded dict.Dict with the parameters of the call (optional)
+//   - ParamTime the Unix seconds of the first call. Default is the interval from now
+//   - ParamInterval the seconds between recurring calls. Default is 0: the call is made once
+//   - ParamFee the iotas sent with each call to pay the fees of the chain. Default is DefaultFee
+//
+// Returns:
+//   - ParamCallID the ID of the scheduled call
+func scheduleCall(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	caller := ctx.Caller()
+	target := params.MustGetHname(ParamTargetHname, 0)
+	if target == 0 {
+		a.Require(!caller.IsAddress() && caller.MustContractID().ChainID() == ctx.ContractID().ChainID(),
+			"scheduler.scheduleCall: the target contract must be specified")
+		target = caller.MustContractID().Hname()
+	}
+	entryPoint := params.MustGetHname(ParamEntryPoint)
+	interval := params.MustGetInt64(ParamInterval, 0)
+	a.Require(interval >= 0, "scheduler.scheduleCall: wrong interval %d", interval)
+	due := params.MustGetInt64(ParamTime, unixSeconds(ctx)+interval)
+	fee := params.MustGetInt64(ParamFee, DefaultFee)
+	a.Require(fee >= 1, "scheduler.scheduleCall: the fee must be at least 1 iota")
+	args := dict.New()
+	if data := params.MustGetBytes(ParamArgs, nil); data != nil {
+		err := args.Read(bytes.NewReader(data))
+		a.Require(err == nil, "scheduler.scheduleCall: wrong args: %v", err)
+	}
+	budget, ok := iotasOnly(ctx.IncomingTransfer())
+	a.Require(ok, "scheduler.scheduleCall: the budget must be transferred in iotas")
+
+	c := &ScheduledCall{
+		ID:         nextCallID(ctx.State()),
+		Owner:      caller,
+		Target:     target,
+		EntryPoint: entryPoint,
+		Args:       args,
+		Due:        due,
+		Interval:   interval,
+		Fee:        fee,
+		Budget:     budget,
+	}
+	a.Require(c.Budget >= c.Cost(), "scheduler.scheduleCall: the budget of %d iota(s) doesn't cover the cost of the call: %d",
+		c.Budget, c.Cost())
+	postTrigger(ctx, a, c)
+
+	ctx.Log().Debugf("scheduler.scheduleCall.success: #%d %s::%s at %d, interval %d",
+		c.ID, c.Target.String(), c.EntryPoint.String(), c.Due, c.Interval)
+	ret := dict.New()
+	ret.Set(ParamCallID, codec.EncodeInt64(c.ID))
+	return ret, nil
+}
+
+// trigger is the time-locked request posted by the scheduler to itself. It posts the request to
+// the target with the fee taken from the budget. The request token of the call is paid with the iota
+// of the request token of the trigger, accrued back to the scheduler. If the call fails, the fee
+// is returned to the owner of the call, see ParamCallOwner.
+// If the call is recurring and the budget covers the next call, the next trigger is posted,
+// otherwise the call is completed and the rest of the budget is returned to the account of the owner
+// Parameters:
+//   - ParamCallID the ID of the scheduled call
+func trigger(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	a.Require(ctx.Caller() == coretypes.NewAgentIDFromContractID(ctx.ContractID()),
+		"scheduler.trigger: can only be posted by the scheduler")
+	id := params.MustGetInt64(ParamCallID)
+	c, err := GetCall(ctx.State(), id)
+	a.RequireNoError(err)
+	if c == nil {
+		ctx.Log().Debugf("scheduler.trigger: call #%d was cancelled", id)
+		return nil, nil
+	}
+	now := unixSeconds(ctx)
+	a.Require(c.Due <= now, "scheduler.trigger: call #%d is not due yet", id)
+
+	args := c.Args.Clone()
+	args.Set(ParamCallOwner, codec.EncodeAgentID(c.Owner))
+	succ := ctx.PostRequest(coretypes.PostRequestParams{
+		TargetContractID: coretypes.NewContractID(ctx.ContractID().ChainID(), c.Target),
+		EntryPoint:       c.EntryPoint,
+		Params:           args,
+		Transfer:         cbalances.NewIotasOnly(c.Fee),
+	})
+	a.Require(succ, "scheduler.trigger: failed to post the request of call #%d", id)
+	c.Budget -= c.Fee
+	c.NumCalls++
+	c.LastCall = now
+
+	if c.Interval == 0 || c.Budget < c.Cost() {
+		deleteCall(ctx.State(), c.ID)
+		refund(ctx, a, c)
+		ctx.Log().Debugf("scheduler.trigger.success: call #%d completed after %d call(s)", c.ID, c.NumCalls)
+		return nil, nil
+	}
+	// the calls missed while the chain was not running are skipped
+	c.Due += ((now-c.Due)/c.Interval + 1) * c.Interval
+	postTrigger(ctx, a, c)
+
+	ctx.Log().Debugf("scheduler.trigger.success: call #%d, next at %d", c.ID, c.Due)
+	return nil, nil
+}
+
+// addBudget adds the incoming transfer, in iotas, to the budget of the scheduled call
+// Parameters:
+//   - ParamCallID the ID of the scheduled call
+func addBudget(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	id := params.MustGetInt64(ParamCallID)
+	c, err := GetCall(ctx.State(), id)
+	a.RequireNoError(err)
+	a.Require(c != nil, "scheduler.addBudget: call #%d not found", id)
+	amount, ok := iotasOnly(ctx.IncomingTransfer())
+	a.Require(ok, "scheduler.addBudget: the budget must be transferred in iotas")
+	c.Budget += amount
+	storeCall(ctx.State(), c)
+
+	ctx.Log().Debugf("scheduler.addBudget.success: call #%d, budget %d", c.ID, c.Budget)
+	return nil, nil
+}
+
+// cancelCall removes the scheduled call and returns the rest of the budget to the account of the owner.
+// Can only be called by the owner of the call
+// Parameters:
+//   - ParamCallID the ID of the scheduled call
+func cancelCall(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	id := params.MustGetInt64(ParamCallID)
+	c, err := GetCall(ctx.State(), id)
+	a.RequireNoError(err)
+	a.Require(c != nil, "scheduler.cancelCall: call #%d not found", id)
+	a.Require(ctx.Caller() == c.Owner, "scheduler.cancelCall: only the owner can cancel call #%d", id)
+	deleteCall(ctx.State(), c.ID)
+	refund(ctx, a, c)
+
+	ctx.Log().Debugf("scheduler.cancelCall.success: call #%d", c.ID)
+	return nil, nil
+}
+
+// getCall returns the record of the scheduled call
+// Parameters:
+//   - ParamCallID the ID of the call
+//
+// Returns:
+//   - ParamCall encoded ScheduledCall
+func getCall(ctx coretypes.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	id := params.MustGetInt64(ParamCallID)
+	data := getCallBytes(ctx.State(), id)
+	if data == nil {
+		return nil, fmt.Errorf("call #%d not found", id)
+	}
+	ret := dict.New()
+	ret.Set(ParamCall, data)
+	return ret, nil
+}
+
+// getCalls returns records of all scheduled calls which are not completed nor cancelled
+// Parameters:
+//   - ParamAgentID return only calls owned by the agent (optional)
+//
+// Returns: map of encoded call ID -> encoded ScheduledCall
+func getCalls(ctx coretypes.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	var owner *coretypes.AgentID
+	if ok, _ := ctx.Params().Has(ParamAgentID); ok {
+		agentID := params.MustGetAgentID(ParamAgentID)
+		owner = &agentID
+	}
+	ret := dict.New()
+	var err error
+	collections.NewMapReadOnly(ctx.State(), varCalls).MustIterate(func(elemKey []byte, value []byte) bool {
+		var c *ScheduledCall
+		if c, err = DecodeScheduledCall(value); err != nil {
+			return false
+		}
+		if owner == nil || c.Owner == *owner {
+			ret.Set(kv.Key(elemKey), value)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// postTrigger takes 1 iota for the request token from the budget and posts the trigger,
+// time-locked until the call is due
+func postTrigger(ctx coretypes.Sandbox, a assert.Assert, c *ScheduledCall) {
+	c.Budget--
+	storeCall(ctx.State(), c)
+	succ := ctx.PostRequest(coretypes.PostRequestParams{
+		TargetContractID: ctx.ContractID(),
+		EntryPoint:       coretypes.Hn(FuncTrigger),
+		TimeLock:         c.Due,
+		Params: codec.MakeDict(map[string]interface{}{
+			ParamCallID: c.ID,
+		}),
+	})
+	a.Require(succ, "scheduler: failed to post the trigger of call #%d", c.ID)
+}
+
+// refund returns the rest of the budget to the account of the owner
+func refund(ctx coretypes.Sandbox, a assert.Assert, c *ScheduledCall) {
+	if c.Budget <= 0 {
+		return
+	}
+	err := accounts.Accrue(ctx, c.Owner, cbalances.NewIotasOnly(c.Budget))
+	a.Require(err == nil, "scheduler: failed to return the budget of call #%d: %v", c.ID, err)
+}
+
+// iotasOnly returns the number of iotas in the transfer and false if it contains other colors
+func iotasOnly(transfer coretypes.ColoredBalances) (int64, bool) {
+	if transfer == nil {
+		return 0, true
+	}
+	ok := true
+	transfer.Iterate(func(color balance.Color, bal int64) bool {
+		ok = color == balance.ColorIOTA
+		return ok
+	})
+	return transfer.Balance(balance.ColorIOTA), ok
+}
+
+func unixSeconds(ctx coretypes.Sandbox) int64 {
+	return ctx.GetTimestamp() / int64(time.Second)
+}
@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+const (
+	Name        = "scheduler"
+	description = "Scheduler Contract"
+)
+
+var (
+	Interface = &coreutil.ContractInterface{
+		Name:        Name,
+		Description: description,
+		ProgramHash: hashing.HashStrings(Name),
+	}
+)
+
+func init() {
+	Interface.WithFunctions(initialize, []coreutil.ContractFunctionInterface{
+		coreutil.Func(FuncScheduleCall, scheduleCall).
+			WithParams(
+				schema.OptionalField(ParamTargetHname, schema.TypeHname),
+				schema.Field(ParamEntryPoint, schema.TypeHname),
+				schema.OptionalField(ParamArgs, schema.TypeBytes),
+				schema.OptionalField(ParamTime, schema.TypeInt),
+				schema.OptionalField(ParamInterval, schema.TypeInt),
+				schema.OptionalField(ParamFee, schema.TypeInt),
+			).
+			WithResults(schema.Field(ParamCallID, schema.TypeInt)),
+		coreutil.Func(FuncTrigger, trigger).
+			WithParams(schema.Field(ParamCallID, schema.TypeInt)),
+		coreutil.Func(FuncAddBudget, addBudget).
+			WithParams(schema.Field(ParamCallID, schema.TypeInt)),
+		coreutil.Func(FuncCancelCall, cancelCall).
+			WithParams(schema.Field(ParamCallID, schema.TypeInt)),
+		coreutil.ViewFunc(FuncGetCall, getCall).
+			WithParams(schema.Field(ParamCallID, schema.TypeInt)).
+			WithResults(schema.Field(ParamCall, schema.TypeBytes)),
+		coreutil.ViewFunc(FuncGetCalls, getCalls).
+			WithParams(schema.OptionalField(ParamAgentID, schema.TypeAgentID)),
+	})
+}
+
+const (
+	// request parameters
+	ParamTargetHname = "t"
+	ParamEntryPoint  = "e"
+	ParamArgs        = "p"
+	ParamTime        = "tm"
+	ParamInterval    = "i"
+	ParamFee         = "f"
+	ParamCallID      = "id"
+	ParamAgentID     = "a"
+	ParamCall        = "c"
+
+	// ParamCallOwner is the owner of the scheduled call. It is added to the parameters
+	// of the request of the call. If the call fails, the tokens sent with it are returned
+	// to the owner instead of the scheduler
+	ParamCallOwner = "scheduler.owner"
+
+	// function names
+	FuncScheduleCall = "scheduleCall"
+	FuncTrigger      = "trigger"
+	FuncAddBudget    = "addBudget"
+	FuncCancelCall   = "cancelCall"
+	FuncGetCall      = "getCall"
+	FuncGetCalls     = "getCalls"
+)
+
+// DefaultFee is the number of iotas sent with each call if the fee is not specified
+const DefaultFee = 1
+
+// ScheduledCall is the record of the call registered in the scheduler.
+// Each call costs Fee iotas, sent with the request to the target, plus 1 iota for the
+// request token of the next trigger. The costs are paid from the budget
+type ScheduledCall struct {
+	ID         int64
+	Owner      coretypes.AgentID // who scheduled the call and gets the rest of the budget back
+	Target     coretypes.Hname   // contract on the chain
+	EntryPoint coretypes.Hname
+	Args       dict.Dict
+	Due        int64 // Unix seconds of the next call
+	Interval   int64 // seconds between recurring calls, 0 for a one-time call
+	Fee        int64 // iotas sent with each call to pay the fees of the request
+	Budget     int64 // iotas left to pay for the calls
+	NumCalls   int64 // number of calls made so far
+	LastCall   int64 // Unix seconds of the last call, 0 if not called yet
+}
+
+// Cost is the number of iotas taken from the budget for each call
+func (c *ScheduledCall) Cost() int64 {
+	return c.Fee + 1
+}
+
+// serde
+func (c *ScheduledCall) Write(w io.Writer) error {
+	if err := util.WriteInt64(w, c.ID); err != nil {
+		return err
+	}
+	if _, err := w.Write(c.Owner[:]); err != nil {
+		return err
+	}
+	if err := c.Target.Write(w); err != nil {
+		return err
+	}
+	if err := c.EntryPoint.Write(w); err != nil {
+		return err
+	}
+	args := c.Args
+	if args == nil {
+		args = dict.New()
+	}
+	if err := args.Write(w); err != nil {
+		return err
+	}
+	for _, v := range []int64{c.Due, c.Interval, c.Fee, c.Budget, c.NumCalls, c.LastCall} {
+		if err := util.WriteInt64(w, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ScheduledCall) Read(r io.Reader) error {
+	if err := util.ReadInt64(r, &c.ID); err != nil {
+		return err
+	}
+	if err := coretypes.ReadAgentID(r, &c.Owner); err != nil {
+		return err
+	}
+	if err := c.Target.Read(r); err != nil {
+		return err
+	}
+	if err := c.EntryPoint.Read(r); err != nil {
+		return err
+	}
+	c.Args = dict.New()
+	if err := c.Args.Read(r); err != nil {
+		return err
+	}
+	for _, v := range []*int64{&c.Due, &c.Interval, &c.Fee, &c.Budget, &c.NumCalls, &c.LastCall} {
+		if err := util.ReadInt64(r, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func EncodeScheduledCall(c *ScheduledCall) []byte {
+	return util.MustBytes(c)
+}
+
+func DecodeScheduledCall(data []byte) (*ScheduledCall, error) {
+	ret := new(ScheduledCall)
+	err := ret.Read(bytes.NewReader(data))
+	return ret, err
+}
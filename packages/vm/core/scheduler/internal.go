@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+)
+
+const (
+	// scheduled calls: call ID -> encoded ScheduledCall
+	varCalls = "c"
+	// number of calls ever scheduled, i.e. the ID of the next call
+	varNumCalls = "n"
+)
+
+func nextCallID(state kv.KVStore) int64 {
+	id, _, _ := codec.DecodeInt64(state.MustGet(varNumCalls))
+	state.Set(varNumCalls, codec.EncodeInt64(id+1))
+	return id
+}
+
+func storeCall(state kv.KVStore, c *ScheduledCall) {
+	collections.NewMap(state, varCalls).MustSetAt(codec.EncodeInt64(c.ID), EncodeScheduledCall(c))
+}
+
+func deleteCall(state kv.KVStore, id int64) {
+	collections.NewMap(state, varCalls).MustDelAt(codec.EncodeInt64(id))
+}
+
+// GetCall returns the record of the scheduled call or nil if it does not exist,
+// i.e. it was completed or cancelled
+func GetCall(state kv.KVStoreReader, id int64) (*ScheduledCall, error) {
+	data := getCallBytes(state, id)
+	if data == nil {
+		return nil, nil
+	}
+	return DecodeScheduledCall(data)
+}
+
+func getCallBytes(state kv.KVStoreReader, id int64) []byte {
+	return collections.NewMapReadOnly(state, varCalls).MustGetAt(codec.EncodeInt64(id))
+}
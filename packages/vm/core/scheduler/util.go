@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// CallParams are the parameters of the call scheduled with ScheduleCall
+type CallParams struct {
+	Target     coretypes.Hname // 0 means the calling contract
+	EntryPoint coretypes.Hname
+	Args       dict.Dict
+	Time       int64 // Unix seconds of the first call, 0 means the interval from now
+	Interval   int64 // seconds between recurring calls, 0 for a one-time call
+	Fee        int64 // iotas sent with each call, 0 means DefaultFee
+}
+
+// ScheduleCall calls "scheduleCall" entry point of the scheduler contract. The budget is taken from
+// the account of the caller. Returns the ID of the scheduled call.
+// Can only be called from full sandbox context
+func ScheduleCall(ctx coretypes.Sandbox, par CallParams, budget int64) (int64, error) {
+	p := codec.MakeDict(map[string]interface{}{
+		ParamEntryPoint: par.EntryPoint,
+		ParamInterval:   par.Interval,
+	})
+	if par.Target != 0 {
+		p.Set(ParamTargetHname, codec.EncodeHname(par.Target))
+	}
+	if par.Args != nil {
+		p.Set(ParamArgs, util.MustBytes(par.Args))
+	}
+	if par.Time != 0 {
+		p.Set(ParamTime, codec.EncodeInt64(par.Time))
+	}
+	if par.Fee != 0 {
+		p.Set(ParamFee, codec.EncodeInt64(par.Fee))
+	}
+	ret, err := ctx.Call(Interface.Hname(), coretypes.Hn(FuncScheduleCall), p, cbalances.NewIotasOnly(budget))
+	if err != nil {
+		return 0, err
+	}
+	id, ok, err := codec.DecodeInt64(ret.MustGet(ParamCallID))
+	if err != nil || !ok {
+		return 0, fmt.Errorf("scheduler.ScheduleCall: wrong call ID returned")
+	}
+	return id, nil
+}
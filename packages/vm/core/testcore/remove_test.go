@@ -0,0 +1,102 @@
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeactivateContract(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	name := "counter"
+	require.NoError(t, chain.DeployContract(nil, name, upgradeV1.ProgramHash))
+	err := chain.DeactivateContract(nil, name)
+	require.NoError(t, err)
+	err = chain.DeactivateContract(nil, name)
+	require.Error(t, err)
+
+	// the call is refused and the tokens are returned to the sender
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+	_, err = chain.PostRequestSync(solo.NewCallParams(name, upFuncInc).WithTransfer(balance.ColorIOTA, 42), user)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "deactivated")
+	env.AssertAddressBalance(user.Address(), balance.ColorIOTA, testutil.RequestFundsAmount-1)
+	chain.AssertAccountBalance(userAgentID, balance.ColorIOTA, 1)
+
+	_, err = chain.CallView(name, upFuncCounter)
+	require.Error(t, err)
+	rec, err := chain.FindContract(name)
+	require.NoError(t, err)
+	require.True(t, rec.Deactivated)
+
+	// only the creator or the chain owner can activate it again
+	err = chain.ActivateContract(user, name)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not permitted")
+	err = chain.ActivateContract(nil, name)
+	require.NoError(t, err)
+	_, err = chain.PostRequestSync(solo.NewCallParams(name, upFuncInc), user)
+	require.NoError(t, err)
+	checkUpgradeCounter(t, chain, name, 1)
+
+	err = chain.DeactivateContract(nil, accounts.Interface.Name)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't be managed")
+}
+
+func TestRemoveContract(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	name := "counter"
+	contractAgentID := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain.ChainID, coretypes.Hn(name)))
+	require.NoError(t, chain.DeployContract(nil, name, upgradeV1.ProgramHash))
+	_, err := chain.PostRequestSync(solo.NewCallParams(name, upFuncInc).WithTransfer(balance.ColorIOTA, 42), nil)
+	require.NoError(t, err)
+	chain.AssertAccountBalance(contractAgentID, balance.ColorIOTA, 42)
+
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+	err = chain.RemoveContract(user, name, userAgentID, true)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not permitted")
+	err = chain.RemoveContract(nil, name, contractAgentID, true)
+	require.Error(t, err)
+
+	// the state is kept, the contract deployed again with the same name continues with it
+	err = chain.RemoveContract(nil, name, userAgentID, false)
+	require.NoError(t, err)
+	chain.AssertAccountBalance(contractAgentID, balance.ColorIOTA, 0)
+	// 1 iota is the request token of the failed request of the user
+	chain.AssertAccountBalance(userAgentID, balance.ColorIOTA, 42+1)
+	_, err = chain.FindContract(name)
+	require.Error(t, err)
+	_, err = chain.PostRequestSync(solo.NewCallParams(name, upFuncInc).WithTransfer(balance.ColorIOTA, 7), user)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not exist")
+
+	require.NoError(t, chain.DeployContract(nil, name, upgradeV1.ProgramHash))
+	checkUpgradeCounter(t, chain, name, 1)
+
+	// the state is erased
+	err = chain.DeactivateContract(nil, name)
+	require.NoError(t, err)
+	err = chain.RemoveContract(nil, name, userAgentID, true)
+	require.NoError(t, err)
+	require.NoError(t, chain.DeployContract(nil, name, upgradeV1.ProgramHash))
+	checkUpgradeCounter(t, chain, name, 0)
+
+	err = chain.RemoveContract(nil, accounts.Interface.Name, userAgentID, false)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't be managed")
+}
@@ -4,14 +4,29 @@
 package testcore
 
 import (
-	"github.com/iotaledger/wasp/packages/vm/core/testcore/sbtests/sbtestsc"
+	"io/ioutil"
 	"testing"
 
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
+	"github.com/iotaledger/wasp/packages/vm/core/testcore/sbtests/sbtestsc"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/contracts/native"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/solo"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/receipts"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/iotaledger/wasp/packages/vm/core/xchain"
 	"github.com/stretchr/testify/require"
 )
 
@@ -47,7 +62,7 @@ func TestGetInfo(t *testing.T) {
 	require.EqualValues(t, chain.ChainColor, info.ChainColor)
 	require.EqualValues(t, chain.ChainAddress, info.ChainAddress)
 	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
-	require.EqualValues(t, 4, len(contracts))
+	require.EqualValues(t, 7, len(contracts))
 
 	_, ok := contracts[root.Interface.Hname()]
 	require.True(t, ok)
@@ -74,7 +89,7 @@ func TestDeployExample(t *testing.T) {
 
 	require.EqualValues(t, chain.ChainID, info.ChainID)
 	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
-	require.EqualValues(t, 5, len(contracts))
+	require.EqualValues(t, 8, len(contracts))
 
 	_, ok := contracts[root.Interface.Hname()]
 	require.True(t, ok)
@@ -97,6 +112,143 @@ func TestDeployExample(t *testing.T) {
 	require.EqualValues(t, root.EncodeContractRecord(recFind), root.EncodeContractRecord(rec))
 }
 
+func TestDeployWasmValidated(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	progHash, err := chain.UploadWasm(nil, []byte("not a wasm binary"))
+	require.NoError(t, err)
+	err = chain.DeployContract(nil, "invalid", progHash)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid Wasm program")
+	_, err = chain.FindContract("invalid")
+	require.Error(t, err)
+
+	name := "testWasm"
+	err = chain.DeployWasmContract(nil, name, "sbtests/sbtestsc/testcore_bg.wasm")
+	require.NoError(t, err)
+
+	rec, err := chain.FindContract(name)
+	require.NoError(t, err)
+	require.Contains(t, rec.Exports, root.ContractExport{Name: sbtestsc.FuncDoNothing})
+	require.Contains(t, rec.Exports, root.ContractExport{Name: sbtestsc.FuncJustView, View: true})
+
+	// builtin contracts have no exports
+	err = chain.DeployContract(nil, "testInc", sbtestsc.Interface.ProgramHash)
+	require.NoError(t, err)
+	rec, err = chain.FindContract("testInc")
+	require.NoError(t, err)
+	require.Empty(t, rec.Exports)
+}
+
+func TestContractSchema(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	getSchema := func(name string) *schema.Schema {
+		res, err := chain.CallView(root.Interface.Name, root.FuncGetContractSchema, root.ParamHname, coretypes.Hn(name))
+		require.NoError(t, err)
+		data := res.MustGet(root.ParamData)
+		if data == nil {
+			return nil
+		}
+		ret, err := schema.Parse(data)
+		require.NoError(t, err)
+		return ret
+	}
+
+	// the schemas of the core contracts are built in
+	for _, itf := range []*coreutil.ContractInterface{
+		root.Interface, blob.Interface, accounts.Interface, eventlog.Interface,
+		receipts.Interface, xchain.Interface, scheduler.Interface,
+	} {
+		s := getSchema(itf.Name)
+		require.NotNil(t, s)
+		require.Len(t, s.Funcs, len(itf.Functions))
+	}
+	s := getSchema(root.Interface.Name)
+	require.NotNil(t, s)
+	require.EqualValues(t, root.Interface.Name, s.Name)
+	f, ok := s.Func(root.FuncDeployContract)
+	require.True(t, ok)
+	require.False(t, f.View)
+	require.Contains(t, f.Params, schema.Field(root.ParamProgramHash, schema.TypeHash))
+	f, ok = s.Func(root.FuncGetChainInfo)
+	require.True(t, ok)
+	require.True(t, f.View)
+	s = getSchema(blob.Interface.Name)
+	require.NotNil(t, s)
+	_, ok = s.Func(blob.FuncGetBlobField)
+	require.True(t, ok)
+
+	// builtin contract deployed without schema
+	err := chain.DeployContract(nil, "testInc", sbtestsc.Interface.ProgramHash)
+	require.NoError(t, err)
+	require.Nil(t, getSchema("testInc"))
+
+	binary, err := ioutil.ReadFile("sbtests/sbtestsc/testcore_bg.wasm")
+	require.NoError(t, err)
+	deploy := func(name string, sch string) error {
+		progHash, err := chain.UploadBlob(nil,
+			blob.VarFieldVMType, "wasmtimevm",
+			blob.VarFieldProgramBinary, binary,
+			blob.VarFieldProgramSchema, sch,
+		)
+		require.NoError(t, err)
+		return chain.DeployContract(nil, name, progHash)
+	}
+
+	err = deploy("badJson", `{"name": "testcore", "funcs": [`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "schema")
+
+	err = deploy("badFunc", `{"name": "testcore", "funcs": [{"name": "noSuchFunc"}]}`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "noSuchFunc")
+
+	err = deploy("badView", `{"name": "testcore", "funcs": [{"name": "justView"}]}`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "view flag")
+
+	err = deploy("testWasm", `{"name": "testcore", "funcs": [
+		{"name": "doNothing"},
+		{"name": "justView", "view": true},
+		{"name": "setInt", "params": [{"name": "intParamName", "type": "string"}, {"name": "intParamValue", "type": "int"}]},
+		{"name": "incCounter"},
+		{"name": "getCounter", "view": true, "results": [{"name": "counter", "type": "int"}]}
+	]}`)
+	require.NoError(t, err)
+	s = getSchema("testWasm")
+	require.NotNil(t, s)
+	require.Len(t, s.Funcs, 5)
+
+	// encode the arguments and decode the results with the schema
+	f, ok = s.Func(sbtestsc.FuncSetInt)
+	require.True(t, ok)
+	args, err := f.EncodeParams(map[string]string{
+		sbtestsc.ParamIntParamName:  "ppp",
+		sbtestsc.ParamIntParamValue: "314",
+	})
+	require.NoError(t, err)
+	_, err = chain.PostRequestSync(solo.NewCallParamsFromDic("testWasm", sbtestsc.FuncSetInt, args), nil)
+	require.NoError(t, err)
+	res, err := chain.CallView("testWasm", sbtestsc.FuncGetInt, sbtestsc.ParamIntParamName, "ppp")
+	require.NoError(t, err)
+	require.EqualValues(t, codec.EncodeInt64(314), res.MustGet("ppp"))
+
+	_, err = chain.PostRequestSync(solo.NewCallParams("testWasm", sbtestsc.FuncIncCounter), nil)
+	require.NoError(t, err)
+	f, ok = s.Func(sbtestsc.FuncGetCounter)
+	require.True(t, ok)
+	res, err = chain.CallView("testWasm", sbtestsc.FuncGetCounter)
+	require.NoError(t, err)
+	results, err := f.DecodeResults(res)
+	require.NoError(t, err)
+	require.EqualValues(t, map[string]string{sbtestsc.VarCounter: "1"}, results)
+}
+
 func TestDeployDouble(t *testing.T) {
 	env := solo.New(t, false, false)
 	chain := env.NewChain(nil, "chain1")
@@ -113,7 +265,7 @@ func TestDeployDouble(t *testing.T) {
 
 	require.EqualValues(t, chain.ChainID, info.ChainID)
 	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
-	require.EqualValues(t, 5, len(contracts))
+	require.EqualValues(t, 8, len(contracts))
 
 	_, ok := contracts[root.Interface.Hname()]
 	require.True(t, ok)
@@ -168,3 +320,113 @@ func TestChangeOwnerUnauthorized(t *testing.T) {
 	info, _ := chain.GetInfo()
 	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
 }
+
+func TestRotateCommittee(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+	req := solo.NewCallParams(accounts.Interface.Name, accounts.FuncDeposit).
+		WithTransfer(balance.ColorIOTA, 42)
+	_, err := chain.PostRequestSync(req, user)
+	require.NoError(t, err)
+
+	oldAddress := chain.ChainAddress
+	newCommittee := signaturescheme.ED25519(ed25519.GenerateKeyPair())
+	err = chain.RotateCommittee(nil, newCommittee)
+	require.NoError(t, err)
+
+	require.EqualValues(t, newCommittee.Address(), chain.ChainAddress)
+	info, _ := chain.GetInfo()
+	require.EqualValues(t, newCommittee.Address(), info.ChainAddress)
+	require.EqualValues(t, chain.ChainID, info.ChainID)
+
+	env.AssertAddressBalance(oldAddress, chain.ChainColor, 0)
+	env.AssertAddressBalance(oldAddress, balance.ColorIOTA, 0)
+	env.AssertAddressBalance(newCommittee.Address(), chain.ChainColor, 1)
+	chain.AssertAccountBalance(userAgentID, balance.ColorIOTA, 42+1)
+
+	// the chain keeps working with the new committee
+	req = solo.NewCallParams(accounts.Interface.Name, accounts.FuncDeposit).
+		WithTransfer(balance.ColorIOTA, 10)
+	_, err = chain.PostRequestSync(req, user)
+	require.NoError(t, err)
+	chain.AssertAccountBalance(userAgentID, balance.ColorIOTA, 42+1+10+1)
+	chain.CheckChain()
+}
+
+func TestRotateCommitteeUnauthorized(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	oldAddress := chain.ChainAddress
+	notOwner := env.NewSignatureSchemeWithFunds()
+	newCommittee := signaturescheme.ED25519(ed25519.GenerateKeyPair())
+	err := chain.RotateCommittee(notOwner, newCommittee)
+	require.Error(t, err)
+
+	require.EqualValues(t, oldAddress, chain.ChainAddress)
+	info, _ := chain.GetInfo()
+	require.EqualValues(t, oldAddress, info.ChainAddress)
+	env.AssertAddressBalance(oldAddress, chain.ChainColor, 1)
+}
+
+// rotatetest is the contract which owns the chain and rotates its committee by calling the 'root' contract
+var rotatetest = &coreutil.ContractInterface{
+	Name:        "rotatetest",
+	Description: "Committee rotation test contract",
+	ProgramHash: hashing.HashStrings("rotatetest"),
+}
+
+const (
+	rtFuncClaim  = "claim"
+	rtFuncRotate = "rotate"
+)
+
+func init() {
+	rotatetest.WithFunctions(func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		return nil, nil
+	}, []coreutil.ContractFunctionInterface{
+		coreutil.Func(rtFuncClaim, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			return ctx.Call(root.Interface.Hname(), coretypes.Hn(root.FuncClaimChainOwnership), nil, nil)
+		}),
+		coreutil.Func(rtFuncRotate, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			return ctx.Call(root.Interface.Hname(), coretypes.Hn(root.FuncRotateCommittee), ctx.Params(), nil)
+		}),
+	})
+	native.AddProcessor(rotatetest)
+}
+
+func TestRotateCommitteeIndirect(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	err := chain.DeployContract(nil, rotatetest.Name, rotatetest.ProgramHash)
+	require.NoError(t, err)
+	contractAgentID := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain.ChainID, rotatetest.Hname()))
+	req := solo.NewCallParams(root.Interface.Name, root.FuncDelegateChainOwnership, root.ParamChainOwner, contractAgentID)
+	_, err = chain.PostRequestSync(req, nil)
+	require.NoError(t, err)
+	_, err = chain.PostRequestSync(solo.NewCallParams(rotatetest.Name, rtFuncClaim), nil)
+	require.NoError(t, err)
+
+	// the request is sent to the contract, which calls the 'root' contract
+	oldAddress := chain.ChainAddress
+	newCommittee := signaturescheme.ED25519(ed25519.GenerateKeyPair())
+	chain.SetNextCommittee(newCommittee)
+	req = solo.NewCallParams(rotatetest.Name, rtFuncRotate, root.ParamChainAddress, newCommittee.Address())
+	_, err = chain.PostRequestSync(req, nil)
+	require.NoError(t, err)
+
+	// the chain is moved together with the change of the address in the 'root' contract
+	require.EqualValues(t, newCommittee.Address(), chain.ChainAddress)
+	info, _ := chain.GetInfo()
+	require.EqualValues(t, newCommittee.Address(), info.ChainAddress)
+	env.AssertAddressBalance(oldAddress, chain.ChainColor, 0)
+	env.AssertAddressBalance(newCommittee.Address(), chain.ChainColor, 1)
+	chain.CheckChain()
+}
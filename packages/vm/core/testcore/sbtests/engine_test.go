@@ -0,0 +1,85 @@
+package sbtests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/testcore/sbtests/sbtestsc"
+	"github.com/iotaledger/wasp/packages/vm/wasmhost"
+	"github.com/stretchr/testify/require"
+)
+
+// runs the same requests on the Wasm test contract with each of the Wasm engines
+// and checks that the engines produce identical results
+func TestWasmEnginesDeterminism(t *testing.T) {
+	engines := wasmhost.Engines()
+	if len(engines) < 2 {
+		t.Skipf("only Wasm engine(s) %v available in this build", engines)
+	}
+	saveEngine := solo.WasmEngine
+	defer func() { solo.WasmEngine = saveEngine }()
+
+	results := make(map[string][]dict.Dict)
+	for _, engine := range engines {
+		t.Run(engine, func(t *testing.T) {
+			solo.WasmEngine = engine
+			results[engine] = runEngineScenario(t)
+		})
+	}
+	for _, engine := range engines[1:] {
+		require.EqualValues(t, results[engines[0]], results[engine], "results of '%s' and '%s' differ", engines[0], engine)
+	}
+}
+
+func runEngineScenario(t *testing.T) []dict.Dict {
+	_, chain := setupChain(t, nil)
+	cID, _ := setupTestSandboxSC(t, chain, nil, true)
+
+	var ret []dict.Dict
+	collect := func(res dict.Dict, err error, panicMsg string) {
+		if panicMsg != "" {
+			require.Error(t, err)
+			require.EqualValues(t, 1, strings.Count(err.Error(), panicMsg))
+			res = dict.New()
+			res.Set("panic", []byte(panicMsg))
+		} else {
+			require.NoError(t, err)
+		}
+		ret = append(ret, res)
+	}
+
+	req := solo.NewCallParams(SandboxSCName, sbtestsc.FuncSetInt,
+		sbtestsc.ParamIntParamName, "ppp",
+		sbtestsc.ParamIntParamValue, 314,
+	)
+	res, err := chain.PostRequestSync(req, nil)
+	collect(res, err, "")
+
+	res, err = chain.CallView(SandboxSCName, sbtestsc.FuncGetInt, sbtestsc.ParamIntParamName, "ppp")
+	collect(res, err, "")
+
+	res, err = chain.CallView(SandboxSCName, sbtestsc.FuncGetFibonacci, sbtestsc.ParamIntParamValue, n)
+	collect(res, err, "")
+
+	req = solo.NewCallParams(SandboxSCName, sbtestsc.FuncCallOnChain,
+		sbtestsc.ParamIntParamValue, 31,
+		sbtestsc.ParamHnameContract, cID.Hname(),
+		sbtestsc.ParamHnameEP, coretypes.Hn(sbtestsc.FuncRunRecursion),
+	)
+	res, err = chain.PostRequestSync(req, nil)
+	collect(res, err, "")
+
+	res, err = chain.CallView(SandboxSCName, sbtestsc.FuncGetCounter)
+	collect(res, err, "")
+
+	req = solo.NewCallParams(SandboxSCName, sbtestsc.FuncPanicFullEP)
+	res, err = chain.PostRequestSync(req, nil)
+	collect(res, err, sbtestsc.MsgFullPanic)
+
+	res, err = chain.CallView(SandboxSCName, sbtestsc.FuncPanicViewEP)
+	collect(res, err, sbtestsc.MsgViewPanic)
+	return ret
+}
@@ -0,0 +1,101 @@
+package sbtests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/testcore/sbtests/sbtestsc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasExhausted(t *testing.T) { run2(t, testGasExhausted) }
+func testGasExhausted(t *testing.T, w bool) {
+	_, chain := setupChain(t, nil)
+	cID, _ := setupTestSandboxSC(t, chain, nil, w)
+
+	req := solo.NewCallParams(SandboxSCName, sbtestsc.FuncCallOnChain,
+		sbtestsc.ParamIntParamValue, 31,
+		sbtestsc.ParamHnameContract, cID.Hname(),
+		sbtestsc.ParamHnameEP, coretypes.Hn(sbtestsc.FuncRunRecursion),
+	).WithGasBudget(1000)
+	_, err := chain.PostRequestSync(req, nil)
+	require.Error(t, err)
+	require.EqualValues(t, vm.ErrGasExhausted, err)
+
+	// state changes were rolled back
+	ret, err := chain.CallView(sbtestsc.Interface.Name, sbtestsc.FuncGetCounter)
+	require.NoError(t, err)
+	r, _, err := codec.DecodeInt64(ret.MustGet(sbtestsc.VarCounter))
+	require.NoError(t, err)
+	require.EqualValues(t, 0, r)
+
+	str, err := chain.GetEventLogRecordsString(SandboxSCName)
+	require.NoError(t, err)
+	require.Contains(t, str, vm.ErrGasExhausted.Error()+" (gas used: 1000)")
+}
+
+func TestGasUsedInEventLog(t *testing.T) { run2(t, testGasUsedInEventLog) }
+func testGasUsedInEventLog(t *testing.T, w bool) {
+	_, chain := setupChain(t, nil)
+	setupTestSandboxSC(t, chain, nil, w)
+
+	req := solo.NewCallParams(SandboxSCName, sbtestsc.FuncSetInt,
+		sbtestsc.ParamIntParamName, "ppp",
+		sbtestsc.ParamIntParamValue, 314,
+	)
+	_, err := chain.PostRequestSync(req, nil)
+	require.NoError(t, err)
+
+	str, err := chain.GetEventLogRecordsString(SandboxSCName)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(str, "Ok (gas used: "))
+	require.False(t, strings.Contains(str, "Ok (gas used: 0)"))
+}
+
+func TestGasFeeRefund(t *testing.T) { run2(t, testGasFeeRefund) }
+func testGasFeeRefund(t *testing.T, w bool) {
+	_, chain := setupChain(t, nil)
+	setupTestSandboxSC(t, chain, nil, w)
+	user := setupDeployer(t, chain)
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+
+	req := solo.NewCallParams(root.Interface.Name, root.FuncSetDefaultFee, root.ParamGasPrice, 10)
+	_, err := chain.PostRequestSync(req, nil)
+	require.NoError(t, err)
+
+	ret, err := chain.CallView(root.Interface.Name, root.FuncGetFeeInfo, root.ParamHname, coretypes.Hn(SandboxSCName))
+	require.NoError(t, err)
+	gasPrice, _, err := codec.DecodeInt64(ret.MustGet(root.ParamGasPrice))
+	require.NoError(t, err)
+	require.EqualValues(t, 10, gasPrice)
+
+	ownerBefore := chain.GetAccountBalance(chain.OriginatorAgentID).Balance(balance.ColorIOTA)
+
+	// budget 50000 gas at price 10 per 1000 gas units reserves 500 iotas
+	req = solo.NewCallParams(SandboxSCName, sbtestsc.FuncDoNothing).
+		WithTransfer(balance.ColorIOTA, 500).
+		WithGasBudget(50000)
+	_, err = chain.PostRequestSync(req, user)
+	require.NoError(t, err)
+
+	t.Logf("dump accounts:\n%s", chain.DumpAccounts())
+	used := chain.GetAccountBalance(chain.OriginatorAgentID).Balance(balance.ColorIOTA) - ownerBefore
+	refund := chain.GetAccountBalance(userAgentID).Balance(balance.ColorIOTA) - 1
+	require.True(t, used > 0)
+	require.True(t, refund > 0)
+	require.EqualValues(t, 500, used+refund)
+
+	// not enough tokens to cover the gas budget
+	req = solo.NewCallParams(SandboxSCName, sbtestsc.FuncDoNothing).
+		WithTransfer(balance.ColorIOTA, 499).
+		WithGasBudget(50000)
+	_, err = chain.PostRequestSync(req, user)
+	require.NoError(t, err)
+	chain.AssertAccountBalance(userAgentID, balance.ColorIOTA, 1+refund+1+499)
+}
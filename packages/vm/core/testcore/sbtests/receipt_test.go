@@ -0,0 +1,77 @@
+package sbtests
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/testcore/sbtests/sbtestsc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiptResult(t *testing.T) { run2(t, testReceiptResult) }
+func testReceiptResult(t *testing.T, w bool) {
+	_, chain := setupChain(t, nil)
+	setupTestSandboxSC(t, chain, nil, w)
+
+	n := int64(7)
+	req := solo.NewCallParams(SandboxSCName, sbtestsc.FuncCallOnChain,
+		sbtestsc.ParamIntParamValue, n,
+		sbtestsc.ParamHnameEP, coretypes.Hn(sbtestsc.FuncGetFibonacci),
+	)
+	tx, _, err := chain.PostRequestSyncTx(req, nil)
+	require.NoError(t, err)
+
+	rec, err := chain.GetRequestReceipt(coretypes.NewRequestID(tx.ID(), 0))
+	require.NoError(t, err)
+	require.True(t, rec.IsOk())
+	require.EqualValues(t, coretypes.NewRequestID(tx.ID(), 0), rec.RequestID)
+	require.EqualValues(t, chain.State.BlockIndex(), rec.BlockIndex)
+	require.EqualValues(t, 0, rec.RequestIndex)
+	require.True(t, rec.GasUsed > 0)
+	r, exists, err := codec.DecodeInt64(rec.Result.MustGet(sbtestsc.ParamIntParamValue))
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.EqualValues(t, fibo(n), r)
+}
+
+func TestReceiptEvents(t *testing.T) { run2(t, testReceiptEvents) }
+func testReceiptEvents(t *testing.T, w bool) {
+	_, chain := setupChain(t, nil)
+	setupTestSandboxSC(t, chain, nil, w)
+
+	req := solo.NewCallParams(SandboxSCName, sbtestsc.FuncEventLogEventData)
+	tx, _, err := chain.PostRequestSyncTx(req, nil)
+	require.NoError(t, err)
+
+	rec, err := chain.GetRequestReceipt(coretypes.NewRequestID(tx.ID(), 0))
+	require.NoError(t, err)
+	require.True(t, rec.IsOk())
+	require.EqualValues(t, 1, len(rec.Events))
+	require.EqualValues(t, coretypes.Hn(SandboxSCName), rec.Events[0].Contract)
+	require.EqualValues(t, "[Event] - Testing Event...", rec.Events[0].Message)
+}
+
+func TestReceiptError(t *testing.T) { run2(t, testReceiptError) }
+func testReceiptError(t *testing.T, w bool) {
+	_, chain := setupChain(t, nil)
+	setupTestSandboxSC(t, chain, nil, w)
+
+	req := solo.NewCallParams(SandboxSCName, sbtestsc.FuncPanicFullEP)
+	tx, _, err := chain.PostRequestSyncTx(req, nil)
+	require.Error(t, err)
+
+	rec, err := chain.GetRequestReceipt(coretypes.NewRequestID(tx.ID(), 0))
+	require.NoError(t, err)
+	require.False(t, rec.IsOk())
+	require.Contains(t, rec.Error, sbtestsc.MsgFullPanic)
+	require.EqualValues(t, 0, len(rec.Result))
+}
+
+func TestReceiptNotFound(t *testing.T) {
+	_, chain := setupChain(t, nil)
+
+	_, err := chain.GetRequestReceipt(coretypes.RequestID{})
+	require.Error(t, err)
+}
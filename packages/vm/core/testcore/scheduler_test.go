@@ -0,0 +1,257 @@
+package testcore
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/contracts/native"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/assert"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/stretchr/testify/require"
+)
+
+// schedtest is the contract scheduling calls to itself
+var schedtest = &coreutil.ContractInterface{
+	Name:        "schedtest",
+	Description: "Scheduler test contract",
+	ProgramHash: hashing.HashStrings("schedtest"),
+}
+
+const (
+	stFuncSchedule = "schedule"
+	stFuncTick     = "tick"
+	stFuncFail     = "fail"
+	stFuncCounter  = "counter"
+
+	stParamInterval = "i"
+	stParamBudget   = "b"
+	stVarCounter    = "c"
+)
+
+func init() {
+	schedtest.WithFunctions(func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		return nil, nil
+	}, []coreutil.ContractFunctionInterface{
+		coreutil.Func(stFuncSchedule, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			params := kvdecoder.New(ctx.Params(), ctx.Log())
+			id, err := scheduler.ScheduleCall(ctx, scheduler.CallParams{
+				EntryPoint: coretypes.Hn(stFuncTick),
+				Interval:   params.MustGetInt64(stParamInterval),
+			}, params.MustGetInt64(stParamBudget))
+			if err != nil {
+				return nil, err
+			}
+			return codec.MakeDict(map[string]interface{}{scheduler.ParamCallID: id}), nil
+		}),
+		coreutil.Func(stFuncTick, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			schedulerAgentID := coretypes.NewAgentIDFromContractID(scheduler.Interface.ContractID(ctx.ContractID().ChainID()))
+			assert.NewAssert(ctx.Log()).Require(ctx.Caller() == schedulerAgentID, "tick must be called by the scheduler")
+			counter, _, _ := codec.DecodeInt64(ctx.State().MustGet(stVarCounter))
+			ctx.State().Set(stVarCounter, codec.EncodeInt64(counter+1))
+			return nil, nil
+		}),
+		coreutil.Func(stFuncFail, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			return nil, fmt.Errorf("scheduled call failed")
+		}),
+		coreutil.ViewFunc(stFuncCounter, func(ctx coretypes.SandboxView) (dict.Dict, error) {
+			counter, _, _ := codec.DecodeInt64(ctx.State().MustGet(stVarCounter))
+			return codec.MakeDict(map[string]interface{}{stVarCounter: counter}), nil
+		}),
+	})
+	native.AddProcessor(schedtest)
+}
+
+func checkSchedCounter(t *testing.T, chain *solo.Chain, expected int64) {
+	res, err := chain.CallView(schedtest.Name, stFuncCounter)
+	require.NoError(t, err)
+	counter, _, err := codec.DecodeInt64(res.MustGet(stVarCounter))
+	require.NoError(t, err)
+	require.EqualValues(t, expected, counter)
+}
+
+func TestSchedulerRecurringCall(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	require.NoError(t, chain.DeployContract(nil, schedtest.Name, schedtest.ProgramHash))
+	schedtestAgentID := coretypes.NewAgentIDFromContractID(schedtest.ContractID(chain.ChainID))
+
+	// triggers are not charged with fees, the calls pay them with the fee from the budget
+	for _, hname := range []coretypes.Hname{scheduler.Interface.Hname(), schedtest.Hname()} {
+		req := solo.NewCallParams(root.Interface.Name, root.FuncSetContractFee,
+			root.ParamHname, hname,
+			root.ParamOwnerFee, 1,
+		)
+		_, err := chain.PostRequestSync(req, nil)
+		require.NoError(t, err)
+	}
+
+	user := env.NewSignatureSchemeWithFunds()
+	req := solo.NewCallParams(schedtest.Name, stFuncSchedule,
+		stParamInterval, 60,
+		stParamBudget, 10,
+	).WithTransfer(balance.ColorIOTA, 11)
+	res, err := chain.PostRequestSync(req, user)
+	require.NoError(t, err)
+	id, _, err := codec.DecodeInt64(res.MustGet(scheduler.ParamCallID))
+	require.NoError(t, err)
+	require.EqualValues(t, 0, id)
+
+	c, err := chain.GetScheduledCall(id)
+	require.NoError(t, err)
+	require.Equal(t, schedtestAgentID, c.Owner)
+	require.Equal(t, schedtest.Hname(), c.Target)
+	require.EqualValues(t, 60, c.Interval)
+	require.EqualValues(t, scheduler.DefaultFee, c.Fee)
+	// 1 iota for the request token of the first trigger
+	require.EqualValues(t, 9, c.Budget)
+
+	chain.WaitForEmptyBacklog()
+	checkSchedCounter(t, chain, 0)
+
+	// each call costs the fee and the request token of the next trigger: the budget is enough for 5 calls
+	for i := int64(1); i <= 5; i++ {
+		env.AdvanceClockBy(61 * time.Second)
+		chain.WaitForEmptyBacklog()
+		checkSchedCounter(t, chain, i)
+	}
+	_, err = chain.GetScheduledCall(id)
+	require.Error(t, err)
+	calls, err := chain.GetScheduledCalls()
+	require.NoError(t, err)
+	require.Len(t, calls, 0)
+
+	env.AdvanceClockBy(61 * time.Second)
+	chain.WaitForEmptyBacklog()
+	checkSchedCounter(t, chain, 5)
+
+	// the fees of the calls are taken by the chain owner
+	chain.AssertAccountBalance(schedtestAgentID, balance.ColorIOTA, 0)
+	chain.CheckChain()
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	require.NoError(t, chain.DeployContract(nil, schedtest.Name, schedtest.ProgramHash))
+
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+
+	// the target must be specified when scheduled by an address
+	req := solo.NewCallParams(scheduler.Interface.Name, scheduler.FuncScheduleCall,
+		scheduler.ParamEntryPoint, coretypes.Hn(stFuncTick),
+	)
+	_, err := chain.PostRequestSync(req, user)
+	require.Error(t, err)
+
+	due := env.LogicalTime().Add(time.Hour).Unix()
+	req = solo.NewCallParams(scheduler.Interface.Name, scheduler.FuncScheduleCall,
+		scheduler.ParamTargetHname, schedtest.Hname(),
+		scheduler.ParamEntryPoint, coretypes.Hn(stFuncTick),
+		scheduler.ParamTime, due,
+	).WithTransfer(balance.ColorIOTA, 10)
+	res, err := chain.PostRequestSync(req, user)
+	require.NoError(t, err)
+	id, _, err := codec.DecodeInt64(res.MustGet(scheduler.ParamCallID))
+	require.NoError(t, err)
+
+	c, err := chain.GetScheduledCall(id)
+	require.NoError(t, err)
+	require.Equal(t, userAgentID, c.Owner)
+	require.EqualValues(t, due, c.Due)
+	require.EqualValues(t, 0, c.Interval)
+	calls, err := chain.GetScheduledCalls(userAgentID)
+	require.NoError(t, err)
+	require.Len(t, calls, 1)
+
+	// only the owner can cancel
+	req = solo.NewCallParams(scheduler.Interface.Name, scheduler.FuncCancelCall,
+		scheduler.ParamCallID, id,
+	)
+	_, err = chain.PostRequestSync(req, nil)
+	require.Error(t, err)
+
+	_, err = chain.PostRequestSync(req, user)
+	require.NoError(t, err)
+	_, err = chain.GetScheduledCall(id)
+	require.Error(t, err)
+
+	// 3 request tokens + the budget without the request token of the trigger
+	chain.AssertAccountBalance(userAgentID, balance.ColorIOTA, 3+9)
+
+	// the trigger of the cancelled call does nothing
+	env.AdvanceClockBy(2 * time.Hour)
+	chain.WaitForEmptyBacklog()
+	checkSchedCounter(t, chain, 0)
+	chain.CheckChain()
+}
+
+func TestSchedulerOneTimeCall(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	require.NoError(t, chain.DeployContract(nil, schedtest.Name, schedtest.ProgramHash))
+
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+
+	req := solo.NewCallParams(scheduler.Interface.Name, scheduler.FuncScheduleCall,
+		scheduler.ParamTargetHname, schedtest.Hname(),
+		scheduler.ParamEntryPoint, coretypes.Hn(stFuncTick),
+		scheduler.ParamTime, env.LogicalTime().Add(time.Minute).Unix(),
+	).WithTransfer(balance.ColorIOTA, 5)
+	_, err := chain.PostRequestSync(req, user)
+	require.NoError(t, err)
+
+	chain.WaitForEmptyBacklog()
+	checkSchedCounter(t, chain, 0)
+
+	env.AdvanceClockBy(2 * time.Minute)
+	chain.WaitForEmptyBacklog()
+	checkSchedCounter(t, chain, 1)
+
+	// 5 - 1 for the request token of the trigger - 1 for the fee: the rest is returned
+	chain.AssertAccountBalance(userAgentID, balance.ColorIOTA, 1+3)
+	chain.CheckChain()
+}
+
+func TestSchedulerFailedCall(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	require.NoError(t, chain.DeployContract(nil, schedtest.Name, schedtest.ProgramHash))
+	schedulerAgentID := coretypes.NewAgentIDFromContractID(scheduler.Interface.ContractID(chain.ChainID))
+
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+	req := solo.NewCallParams(scheduler.Interface.Name, scheduler.FuncScheduleCall,
+		scheduler.ParamTargetHname, schedtest.Hname(),
+		scheduler.ParamEntryPoint, coretypes.Hn(stFuncFail),
+		scheduler.ParamTime, env.LogicalTime().Add(time.Minute).Unix(),
+		scheduler.ParamFee, 3,
+	).WithTransfer(balance.ColorIOTA, 10)
+	_, err := chain.PostRequestSync(req, user)
+	require.NoError(t, err)
+	chain.WaitForEmptyBacklog()
+	chain.AssertAccountBalance(schedulerAgentID, balance.ColorIOTA, 9)
+
+	env.AdvanceClockBy(2 * time.Minute)
+	chain.WaitForEmptyBacklog()
+
+	// 10 - 1 for the request token of the trigger - 3 for the fee: the rest is returned to the account.
+	// The fee of the failed call is returned to the address of the owner. Only the request token
+	// of the call is accrued to the scheduler, the same as when the call succeeds
+	chain.AssertAccountBalance(userAgentID, balance.ColorIOTA, 1+6)
+	chain.AssertAccountBalance(schedulerAgentID, balance.ColorIOTA, 1)
+	env.AssertAddressBalance(user.Address(), balance.ColorIOTA, testutil.RequestFundsAmount-11+3)
+	chain.CheckChain()
+}
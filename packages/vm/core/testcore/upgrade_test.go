@@ -0,0 +1,170 @@
+package testcore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/wasp/contracts/native"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	upFuncInc     = "inc"
+	upFuncCounter = "counter"
+
+	upParamFactor = "f"
+	upVarCounter  = "c"
+)
+
+// upgradeV1, upgradeV2 and upgradeV3 are versions of the program of the same counter contract.
+// V1 increments the counter by 1, V2 by 10 and migrates the counter of V1 by multiplying it.
+// The migration of V3 always fails
+var (
+	upgradeV1 = newUpgradeTestContract("upgradetest1", 1, nil)
+	upgradeV2 = newUpgradeTestContract("upgradetest2", 10, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		params := kvdecoder.New(ctx.Params(), ctx.Log())
+		factor := params.MustGetInt64(upParamFactor, 1)
+		counter, _, _ := codec.DecodeInt64(ctx.State().MustGet(upVarCounter))
+		ctx.State().Set(upVarCounter, codec.EncodeInt64(counter*factor))
+		return nil, nil
+	})
+	upgradeV3 = newUpgradeTestContract("upgradetest3", 100, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		ctx.State().Set(upVarCounter, codec.EncodeInt64(-1))
+		return nil, fmt.Errorf("can't migrate")
+	})
+)
+
+func newUpgradeTestContract(name string, step int64, migrate coreutil.Handler) *coreutil.ContractInterface {
+	ret := &coreutil.ContractInterface{
+		Name:        name,
+		Description: "Upgrade test contract",
+		ProgramHash: hashing.HashStrings(name),
+	}
+	funcs := []coreutil.ContractFunctionInterface{
+		coreutil.Func(upFuncInc, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			counter, _, _ := codec.DecodeInt64(ctx.State().MustGet(upVarCounter))
+			ctx.State().Set(upVarCounter, codec.EncodeInt64(counter+step))
+			return nil, nil
+		}),
+		coreutil.ViewFunc(upFuncCounter, func(ctx coretypes.SandboxView) (dict.Dict, error) {
+			counter, _, _ := codec.DecodeInt64(ctx.State().MustGet(upVarCounter))
+			return codec.MakeDict(map[string]interface{}{upVarCounter: counter}), nil
+		}),
+	}
+	if migrate != nil {
+		funcs = append(funcs, coreutil.Func(coretypes.FuncMigrate, migrate))
+	}
+	ret.WithFunctions(func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		return nil, nil
+	}, funcs)
+	native.AddProcessor(ret)
+	return ret
+}
+
+func checkUpgradeCounter(t *testing.T, chain *solo.Chain, name string, expected int64) {
+	res, err := chain.CallView(name, upFuncCounter)
+	require.NoError(t, err)
+	counter, _, err := codec.DecodeInt64(res.MustGet(upVarCounter))
+	require.NoError(t, err)
+	require.EqualValues(t, expected, counter)
+}
+
+func TestUpgradeContract(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	name := "counter"
+	require.NoError(t, chain.DeployContract(nil, name, upgradeV1.ProgramHash))
+	inc := solo.NewCallParams(name, upFuncInc)
+	_, err := chain.PostRequestSync(inc, nil)
+	require.NoError(t, err)
+	checkUpgradeCounter(t, chain, name, 1)
+
+	// V2 migrates the state of V1 and then runs its own code on it
+	err = chain.UpgradeContract(nil, name, upgradeV2.ProgramHash, upParamFactor, 5)
+	require.NoError(t, err)
+	rec, err := chain.FindContract(name)
+	require.NoError(t, err)
+	require.EqualValues(t, upgradeV2.ProgramHash, rec.ProgramHash)
+	require.EqualValues(t, name, rec.Name)
+	checkUpgradeCounter(t, chain, name, 5)
+	_, err = chain.PostRequestSync(inc, nil)
+	require.NoError(t, err)
+	checkUpgradeCounter(t, chain, name, 15)
+
+	// failed migration reverts the upgrade
+	err = chain.UpgradeContract(nil, name, upgradeV3.ProgramHash)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't migrate")
+	rec, err = chain.FindContract(name)
+	require.NoError(t, err)
+	require.EqualValues(t, upgradeV2.ProgramHash, rec.ProgramHash)
+	checkUpgradeCounter(t, chain, name, 15)
+
+	// 'migrate' is optional
+	err = chain.UpgradeContract(nil, name, upgradeV1.ProgramHash)
+	require.NoError(t, err)
+	checkUpgradeCounter(t, chain, name, 15)
+	_, err = chain.PostRequestSync(inc, nil)
+	require.NoError(t, err)
+	checkUpgradeCounter(t, chain, name, 16)
+
+	recs, err := chain.GetEventLogRecordsString(root.Interface.Name)
+	require.NoError(t, err)
+	require.Contains(t, recs, fmt.Sprintf("[upgrade] name: %s hname: %s, progHash: %s -> %s",
+		name, coretypes.Hn(name), upgradeV1.ProgramHash.String(), upgradeV2.ProgramHash.String()))
+}
+
+func TestUpgradeContractFail(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	name := "counter"
+	require.NoError(t, chain.DeployContract(nil, name, upgradeV2.ProgramHash))
+
+	// only the creator or the chain owner can upgrade
+	user := env.NewSignatureSchemeWithFunds()
+	err := chain.UpgradeContract(user, name, upgradeV1.ProgramHash)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not permitted")
+
+	err = chain.UpgradeContract(nil, name, upgradeV2.ProgramHash)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already has program")
+
+	err = chain.UpgradeContract(nil, name, hashing.HashStrings("no such program"))
+	require.Error(t, err)
+
+	err = chain.UpgradeContract(nil, "nonexistent", upgradeV1.ProgramHash)
+	require.Error(t, err)
+
+	err = chain.UpgradeContract(nil, accounts.Interface.Name, upgradeV1.ProgramHash)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't be managed")
+
+	// the deactivated contract must be activated before the upgrade
+	require.NoError(t, chain.DeactivateContract(nil, name))
+	err = chain.UpgradeContract(nil, name, upgradeV1.ProgramHash)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is deactivated")
+	require.NoError(t, chain.ActivateContract(nil, name))
+
+	// 'migrate' can only be called by the root contract
+	_, err = chain.PostRequestSync(solo.NewCallParams(name, coretypes.FuncMigrate, upParamFactor, 2), nil)
+	require.Error(t, err)
+
+	rec, err := chain.FindContract(name)
+	require.NoError(t, err)
+	require.EqualValues(t, upgradeV2.ProgramHash, rec.ProgramHash)
+}
@@ -0,0 +1,182 @@
+package testcore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/contracts/native"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/assert"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/xchain"
+	"github.com/stretchr/testify/require"
+)
+
+// xchaintest is the contract sending messages to its instance on another chain
+var xchaintest = &coreutil.ContractInterface{
+	Name:        "xchaintest",
+	Description: "Cross-chain messaging test contract",
+	ProgramHash: hashing.HashStrings("xchaintest"),
+}
+
+const (
+	xtFuncSend     = "send"
+	xtFuncEcho     = "echo"
+	xtFuncFail     = "fail"
+	xtFuncCallback = "callback"
+	xtFuncLast     = "last"
+
+	xtParamChainID    = "c"
+	xtParamEntryPoint = "e"
+	xtParamCallback   = "cb"
+	xtParamAmount     = "n"
+	xtParamValue      = "v"
+)
+
+func init() {
+	xchaintest.WithFunctions(func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		return nil, nil
+	}, []coreutil.ContractFunctionInterface{
+		coreutil.Func(xtFuncSend, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			params := kvdecoder.New(ctx.Params(), ctx.Log())
+			target := params.MustGetChainID(xtParamChainID)
+			callback := coretypes.Hname(0)
+			if params.MustGetInt64(xtParamCallback, 0) != 0 {
+				callback = coretypes.Hn(xtFuncCallback)
+			}
+			id, err := xchain.SendMessage(ctx, coretypes.PostRequestParams{
+				TargetContractID: xchaintest.ContractID(target),
+				EntryPoint:       coretypes.Hn(params.MustGetString(xtParamEntryPoint)),
+				Params: codec.MakeDict(map[string]interface{}{
+					xtParamValue: params.MustGetInt64(xtParamValue, 0),
+				}),
+				Transfer: cbalances.NewIotasOnly(params.MustGetInt64(xtParamAmount, 0)),
+			}, callback)
+			if err != nil {
+				return nil, err
+			}
+			return codec.MakeDict(map[string]interface{}{xchain.ParamMessageID: id}), nil
+		}),
+		coreutil.Func(xtFuncEcho, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			params := kvdecoder.New(ctx.Params(), ctx.Log())
+			return codec.MakeDict(map[string]interface{}{
+				xtParamValue: params.MustGetInt64(xtParamValue),
+			}), nil
+		}),
+		coreutil.Func(xtFuncFail, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			return nil, fmt.Errorf("failed on purpose")
+		}),
+		coreutil.Func(xtFuncCallback, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			xchainAgentID := coretypes.NewAgentIDFromContractID(xchain.Interface.ContractID(ctx.ContractID().ChainID()))
+			assert.NewAssert(ctx.Log()).Require(ctx.Caller() == xchainAgentID, "callback must be called by xchain")
+			ctx.State().Set(xchain.ParamMessage, ctx.Params().MustGet(xchain.ParamMessage))
+			return nil, nil
+		}),
+		coreutil.ViewFunc(xtFuncLast, func(ctx coretypes.SandboxView) (dict.Dict, error) {
+			ret := dict.New()
+			ret.Set(xchain.ParamMessage, ctx.State().MustGet(xchain.ParamMessage))
+			return ret, nil
+		}),
+	})
+	native.AddProcessor(xchaintest)
+}
+
+func TestXChainMessages(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain1 := env.NewChain(nil, "ch1")
+	chain2 := env.NewChain(nil, "ch2")
+
+	require.NoError(t, chain1.DeployContract(nil, xchaintest.Name, xchaintest.ProgramHash))
+	require.NoError(t, chain2.DeployContract(nil, xchaintest.Name, xchaintest.ProgramHash))
+	sender := coretypes.NewAgentIDFromContractID(xchaintest.ContractID(chain1.ChainID))
+	receiver := coretypes.NewAgentIDFromContractID(xchaintest.ContractID(chain2.ChainID))
+
+	// message delivered successfully, with callback
+	req := solo.NewCallParams(xchaintest.Name, xtFuncSend,
+		xtParamChainID, chain2.ChainID,
+		xtParamEntryPoint, xtFuncEcho,
+		xtParamValue, 42,
+		xtParamAmount, 5,
+		xtParamCallback, 1,
+	).WithTransfer(balance.ColorIOTA, 10)
+	res, err := chain1.PostRequestSync(req, nil)
+	require.NoError(t, err)
+	id, _, err := codec.DecodeInt64(res.MustGet(xchain.ParamMessageID))
+	require.NoError(t, err)
+	require.EqualValues(t, 0, id)
+
+	msg, err := chain1.GetOutboxMessage(id)
+	require.NoError(t, err)
+	require.Equal(t, xchain.StatusPending, msg.Status)
+	require.Equal(t, sender, msg.Sender)
+
+	env.WaitForEmptyBacklogs()
+
+	msg, err = chain1.GetOutboxMessage(id)
+	require.NoError(t, err)
+	require.Equal(t, xchain.StatusDelivered, msg.Status)
+	require.Empty(t, msg.Error)
+	v, _, _ := codec.DecodeInt64(msg.Result.MustGet(xtParamValue))
+	require.EqualValues(t, 42, v)
+	require.NotZero(t, msg.Acknowledged)
+
+	last, err := chain1.CallView(xchaintest.Name, xtFuncLast)
+	require.NoError(t, err)
+	lastMsg, err := xchain.DecodeMessage(last.MustGet(xchain.ParamMessage))
+	require.NoError(t, err)
+	require.EqualValues(t, id, lastMsg.ID)
+	require.Equal(t, xchain.StatusDelivered, lastMsg.Status)
+
+	// 10 - 5 transferred - 2 for request tokens
+	chain1.AssertAccountBalance(sender, balance.ColorIOTA, 3)
+	chain2.AssertAccountBalance(receiver, balance.ColorIOTA, 5)
+
+	// failed message: the transfer is returned to the sender
+	req = solo.NewCallParams(xchaintest.Name, xtFuncSend,
+		xtParamChainID, chain2.ChainID,
+		xtParamEntryPoint, xtFuncFail,
+		xtParamAmount, 5,
+	).WithTransfer(balance.ColorIOTA, 10)
+	res, err = chain1.PostRequestSync(req, nil)
+	require.NoError(t, err)
+	id, _, err = codec.DecodeInt64(res.MustGet(xchain.ParamMessageID))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, id)
+
+	env.WaitForEmptyBacklogs()
+
+	msg, err = chain1.GetOutboxMessage(id)
+	require.NoError(t, err)
+	require.Equal(t, xchain.StatusFailed, msg.Status)
+	require.Contains(t, msg.Error, "failed on purpose")
+
+	// 3 + 10 - 5 transferred - 1 for request token + 5 returned
+	chain1.AssertAccountBalance(sender, balance.ColorIOTA, 12)
+	chain2.AssertAccountBalance(receiver, balance.ColorIOTA, 5)
+
+	msgs, err := chain1.GetOutbox(sender)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	require.EqualValues(t, 0, msgs[0].ID)
+	require.EqualValues(t, 1, msgs[1].ID)
+	msgs, err = chain2.GetOutbox()
+	require.NoError(t, err)
+	require.Len(t, msgs, 0)
+
+	// only the xchain contract of the target chain can acknowledge
+	req = solo.NewCallParams(xchain.Interface.Name, xchain.FuncAcknowledge,
+		xchain.ParamMessageID, 0,
+	)
+	_, err = chain1.PostRequestSync(req, nil)
+	require.Error(t, err)
+
+	chain1.CheckChain()
+	chain2.CheckChain()
+}
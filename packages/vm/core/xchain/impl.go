@@ -0,0 +1,194 @@
+// 'xchain' is a core contract on the chain. It keeps track of cross-chain messages:
+// requests sent by the smart contracts of the chain to other chains through the contract.
+// Each message is recorded in the outbox of the sender chain. The VM of the target chain
+// sends the outcome of the request back in the acknowledgement, which updates the status
+// of the message and, if requested, is forwarded to the sender with the callback request
+package xchain
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/assert"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+)
+
+// initialize is mandatory
+func initialize(ctx coretypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("xchain.initialize.success hname = %s", Interface.Hname().String())
+	return nil, nil
+}
+
+// sendMessage records the message in the outbox and posts the request to the target contract.
+// The incoming transfer must contain 1 iota for the request token and 1 more iota for the
+// callback request, if the callback is set. The rest of the transfer is sent with the request.
+// Parameters:
+//   - ParamTargetContractID the target contract
+//   - ParamEntryPoint the entry point of the target contract
+//   - ParamArgs encoded dict.Dict with the parameters of the request (optional)
+//   - ParamCallback the entry point of the sender called with the outcome of the message (optional)
+//   - ParamTimeLock, ParamGasBudget the time lock and the gas budget of the request (optional)
+//
+// Returns:
+//   - ParamMessageID the ID of the message in the outbox
+func sendMessage(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	target := params.MustGetContractID(ParamTargetContractID)
+	entryPoint := params.MustGetHname(ParamEntryPoint)
+	callback := params.MustGetHname(ParamCallback, 0)
+	timeLock := params.MustGetInt64(ParamTimeLock, 0)
+	gasBudget := params.MustGetInt64(ParamGasBudget, 0)
+	args := dict.New()
+	if data := params.MustGetBytes(ParamArgs, nil); data != nil {
+		err := args.Read(bytes.NewReader(data))
+		a.Require(err == nil, "xchain.sendMessage: wrong args: %v", err)
+	}
+	caller := ctx.Caller()
+	a.Require(callback == 0 || !caller.IsAddress(), "xchain.sendMessage: callback is only possible to a smart contract")
+
+	// the fee for the request token(s) stays in the account of the contract
+	fee := int64(1)
+	if callback != 0 {
+		fee++
+	}
+	transfer := make(map[balance.Color]int64)
+	ctx.IncomingTransfer().AddToMap(transfer)
+	a.Require(transfer[balance.ColorIOTA] >= fee, "xchain.sendMessage: %d iota(s) needed for request tokens", fee)
+	transfer[balance.ColorIOTA] -= fee
+
+	msg := &Message{
+		ID:         nextMessageID(ctx.State()),
+		Sender:     caller,
+		Target:     target,
+		EntryPoint: entryPoint,
+		Callback:   callback,
+		Status:     StatusPending,
+		Posted:     ctx.GetTimestamp(),
+	}
+	storeMessage(ctx.State(), msg)
+
+	args.Set(ParamMessageID, codec.EncodeInt64(msg.ID))
+	succ := ctx.PostRequest(coretypes.PostRequestParams{
+		TargetContractID: target,
+		EntryPoint:       entryPoint,
+		TimeLock:         timeLock,
+		GasBudget:        uint64(gasBudget),
+		Params:           args,
+		Transfer:         cbalances.NewFromMap(transfer),
+	})
+	a.Require(succ, "xchain.sendMessage: failed to post request")
+
+	ctx.Log().Debugf("xchain.sendMessage.success: #%d %s -> %s::%s", msg.ID, caller.String(), target.String(), entryPoint.String())
+	ret := dict.New()
+	ret.Set(ParamMessageID, codec.EncodeInt64(msg.ID))
+	return ret, nil
+}
+
+// acknowledge is sent by the VM of the target chain after the request of the message is processed.
+// It updates the status of the message, accrues returned tokens to the sender and
+// posts the callback request to the sender, if requested. The callback request is sent with
+// the parameter ParamMessage: the encoded Message
+// Parameters:
+//   - ParamMessageID the ID of the message in the outbox
+//   - ParamError the error returned by the target chain, if failed
+//   - ParamResult encoded dict.Dict with the result, if delivered
+func acknowledge(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	id := params.MustGetInt64(ParamMessageID)
+	msg, err := GetMessage(ctx.State(), id)
+	a.RequireNoError(err)
+	a.Require(msg != nil, "xchain.acknowledge: message #%d not found", id)
+	a.Require(ctx.Caller() == coretypes.NewAgentIDFromContractID(Interface.ContractID(msg.Target.ChainID())),
+		"xchain.acknowledge: must be sent by the '%s' contract of the target chain", Name)
+	a.Require(msg.Status == StatusPending, "xchain.acknowledge: message #%d is already acknowledged", id)
+
+	msg.Error = params.MustGetString(ParamError, "")
+	msg.Result = dict.New()
+	if data := params.MustGetBytes(ParamResult, nil); data != nil {
+		err := msg.Result.Read(bytes.NewReader(data))
+		a.Require(err == nil, "xchain.acknowledge: wrong result: %v", err)
+	}
+	msg.Status = StatusDelivered
+	if msg.Error != "" {
+		msg.Status = StatusFailed
+	}
+	msg.Acknowledged = ctx.GetTimestamp()
+	storeMessage(ctx.State(), msg)
+
+	// tokens returned by the target chain, e.g. the transfer of the failed message
+	err = accounts.Accrue(ctx, msg.Sender, ctx.IncomingTransfer())
+	a.Require(err == nil, "xchain.acknowledge: failed to accrue returned tokens: %v", err)
+
+	if msg.Callback != 0 {
+		succ := ctx.PostRequest(coretypes.PostRequestParams{
+			TargetContractID: msg.Sender.MustContractID(),
+			EntryPoint:       msg.Callback,
+			Params: codec.MakeDict(map[string]interface{}{
+				ParamMessage: EncodeMessage(msg),
+			}),
+		})
+		a.Require(succ, "xchain.acknowledge: failed to post callback request")
+	}
+	ctx.Log().Debugf("xchain.acknowledge.success: #%d %s", msg.ID, msg.Status.String())
+	return nil, nil
+}
+
+// getMessage returns the record of the outgoing message
+// Parameters:
+//   - ParamMessageID the ID of the message
+//
+// Returns:
+//   - ParamMessage encoded Message
+func getMessage(ctx coretypes.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	id := params.MustGetInt64(ParamMessageID)
+	data := getMessageBytes(ctx.State(), id)
+	if data == nil {
+		return nil, fmt.Errorf("message #%d not found", id)
+	}
+	ret := dict.New()
+	ret.Set(ParamMessage, data)
+	return ret, nil
+}
+
+// getOutbox returns records of all outgoing messages of the chain
+// Parameters:
+//   - ParamAgentID return only messages sent by the agent (optional)
+//
+// Returns: map of encoded message ID -> encoded Message
+func getOutbox(ctx coretypes.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	var sender *coretypes.AgentID
+	if ok, _ := ctx.Params().Has(ParamAgentID); ok {
+		agentID := params.MustGetAgentID(ParamAgentID)
+		sender = &agentID
+	}
+	ret := dict.New()
+	var err error
+	collections.NewMapReadOnly(ctx.State(), varOutbox).MustIterate(func(elemKey []byte, value []byte) bool {
+		var msg *Message
+		if msg, err = DecodeMessage(value); err != nil {
+			return false
+		}
+		if sender == nil || msg.Sender == *sender {
+			ret.Set(kv.Key(elemKey), value)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
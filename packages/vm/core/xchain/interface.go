@@ -0,0 +1,197 @@
+package xchain
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+const (
+	Name        = "xchain"
+	description = "Cross-chain messaging Contract"
+)
+
+var (
+	Interface = &coreutil.ContractInterface{
+		Name:        Name,
+		Description: description,
+		ProgramHash: hashing.HashStrings(Name),
+	}
+)
+
+func init() {
+	Interface.WithFunctions(initialize, []coreutil.ContractFunctionInterface{
+		coreutil.Func(FuncSendMessage, sendMessage).
+			WithParams(
+				schema.Field(ParamTargetContractID, schema.TypeContractID),
+				schema.Field(ParamEntryPoint, schema.TypeHname),
+				schema.OptionalField(ParamCallback, schema.TypeHname),
+				schema.OptionalField(ParamTimeLock, schema.TypeInt),
+				schema.OptionalField(ParamGasBudget, schema.TypeInt),
+				schema.OptionalField(ParamArgs, schema.TypeBytes),
+			).
+			WithResults(schema.Field(ParamMessageID, schema.TypeInt)),
+		coreutil.Func(FuncAcknowledge, acknowledge).
+			WithParams(
+				schema.Field(ParamMessageID, schema.TypeInt),
+				schema.OptionalField(ParamError, schema.TypeString),
+				schema.OptionalField(ParamResult, schema.TypeBytes),
+			),
+		coreutil.ViewFunc(FuncGetMessage, getMessage).
+			WithParams(schema.Field(ParamMessageID, schema.TypeInt)).
+			WithResults(schema.Field(ParamMessage, schema.TypeBytes)),
+		coreutil.ViewFunc(FuncGetOutbox, getOutbox).
+			WithParams(schema.OptionalField(ParamAgentID, schema.TypeAgentID)),
+	})
+}
+
+const (
+	// request parameters
+	ParamTargetContractID = "t"
+	ParamEntryPoint       = "e"
+	ParamCallback         = "c"
+	ParamTimeLock         = "l"
+	ParamGasBudget        = "g"
+	ParamArgs             = "p"
+	ParamAgentID          = "a"
+	ParamError            = "err"
+	ParamResult           = "r"
+	ParamMessage          = "m"
+
+	// ParamMessageID is the ID of the message in the outbox of the sender chain.
+	// It is added to the parameters of the request delivering the message and
+	// passed back with the acknowledgement
+	ParamMessageID = "xchain.messageID"
+
+	// function names
+	FuncSendMessage = "sendMessage"
+	FuncAcknowledge = "acknowledge"
+	FuncGetMessage  = "getMessage"
+	FuncGetOutbox   = "getOutbox"
+)
+
+// MessageStatus is the delivery status of the outgoing message
+type MessageStatus byte
+
+const (
+	// StatusPending the message is sent, the acknowledgement has not been received yet
+	StatusPending = MessageStatus(iota)
+	// StatusDelivered the target chain processed the request successfully
+	StatusDelivered
+	// StatusFailed the target chain processed the request with an error
+	StatusFailed
+)
+
+func (s MessageStatus) String() string {
+	switch s {
+	case StatusPending:
+		return "pending"
+	case StatusDelivered:
+		return "delivered"
+	case StatusFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// Message is the record of the outgoing cross-chain message in the outbox of the sender chain
+type Message struct {
+	ID           int64
+	Sender       coretypes.AgentID
+	Target       coretypes.ContractID
+	EntryPoint   coretypes.Hname
+	Callback     coretypes.Hname // 0 if the sender is not notified about the outcome
+	Status       MessageStatus
+	Error        string    // error returned by the target chain, if failed
+	Result       dict.Dict // result returned by the target chain, if delivered
+	Posted       int64     // timestamp of the request which sent the message
+	Acknowledged int64     // timestamp of the request with the acknowledgement, 0 if pending
+}
+
+// serde
+func (msg *Message) Write(w io.Writer) error {
+	if err := util.WriteInt64(w, msg.ID); err != nil {
+		return err
+	}
+	if _, err := w.Write(msg.Sender[:]); err != nil {
+		return err
+	}
+	if err := msg.Target.Write(w); err != nil {
+		return err
+	}
+	if err := msg.EntryPoint.Write(w); err != nil {
+		return err
+	}
+	if err := msg.Callback.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteByte(w, byte(msg.Status)); err != nil {
+		return err
+	}
+	if err := util.WriteBytes32(w, []byte(msg.Error)); err != nil {
+		return err
+	}
+	result := msg.Result
+	if result == nil {
+		result = dict.New()
+	}
+	if err := result.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, msg.Posted); err != nil {
+		return err
+	}
+	return util.WriteInt64(w, msg.Acknowledged)
+}
+
+func (msg *Message) Read(r io.Reader) error {
+	if err := util.ReadInt64(r, &msg.ID); err != nil {
+		return err
+	}
+	if err := coretypes.ReadAgentID(r, &msg.Sender); err != nil {
+		return err
+	}
+	if err := msg.Target.Read(r); err != nil {
+		return err
+	}
+	if err := msg.EntryPoint.Read(r); err != nil {
+		return err
+	}
+	if err := msg.Callback.Read(r); err != nil {
+		return err
+	}
+	status, err := util.ReadByte(r)
+	if err != nil {
+		return err
+	}
+	msg.Status = MessageStatus(status)
+	e, err := util.ReadBytes32(r)
+	if err != nil {
+		return err
+	}
+	msg.Error = string(e)
+	msg.Result = dict.New()
+	if err := msg.Result.Read(r); err != nil {
+		return err
+	}
+	if err := util.ReadInt64(r, &msg.Posted); err != nil {
+		return err
+	}
+	return util.ReadInt64(r, &msg.Acknowledged)
+}
+
+func EncodeMessage(msg *Message) []byte {
+	return util.MustBytes(msg)
+}
+
+func DecodeMessage(data []byte) (*Message, error) {
+	ret := new(Message)
+	err := ret.Read(bytes.NewReader(data))
+	return ret, err
+}
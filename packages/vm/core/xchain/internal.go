@@ -0,0 +1,37 @@
+package xchain
+
+import (
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+)
+
+const (
+	// outbox: message ID -> encoded Message
+	varOutbox = "o"
+	// number of messages sent by the chain, i.e. the ID of the next message
+	varNumMessages = "n"
+)
+
+func nextMessageID(state kv.KVStore) int64 {
+	id, _, _ := codec.DecodeInt64(state.MustGet(varNumMessages))
+	state.Set(varNumMessages, codec.EncodeInt64(id+1))
+	return id
+}
+
+func storeMessage(state kv.KVStore, msg *Message) {
+	collections.NewMap(state, varOutbox).MustSetAt(codec.EncodeInt64(msg.ID), EncodeMessage(msg))
+}
+
+// GetMessage returns the record of the outgoing message or nil if it does not exist
+func GetMessage(state kv.KVStoreReader, id int64) (*Message, error) {
+	data := getMessageBytes(state, id)
+	if data == nil {
+		return nil, nil
+	}
+	return DecodeMessage(data)
+}
+
+func getMessageBytes(state kv.KVStoreReader, id int64) []byte {
+	return collections.NewMapReadOnly(state, varOutbox).MustGetAt(codec.EncodeInt64(id))
+}
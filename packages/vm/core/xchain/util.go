@@ -0,0 +1,46 @@
+package xchain
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// SendMessage calls "sendMessage" entry point of the xchain contract. It posts the request the same way
+// as Sandbox.PostRequest does, but the message is tracked in the outbox of the chain. If the callback is not 0,
+// the entry point of the caller is called with the outcome of the message, when it is acknowledged.
+// The iotas needed for request tokens are taken from the caller's account in addition to the transfer.
+// Can only be called from full sandbox context
+func SendMessage(ctx coretypes.Sandbox, par coretypes.PostRequestParams, callback coretypes.Hname) (int64, error) {
+	p := codec.MakeDict(map[string]interface{}{
+		ParamTargetContractID: par.TargetContractID,
+		ParamEntryPoint:       par.EntryPoint,
+		ParamTimeLock:         par.TimeLock,
+		ParamGasBudget:        par.GasBudget,
+	})
+	if par.Params != nil {
+		p.Set(ParamArgs, util.MustBytes(par.Params))
+	}
+	fee := int64(1)
+	if callback != 0 {
+		p.Set(ParamCallback, codec.EncodeHname(callback))
+		fee++
+	}
+	transfer := map[balance.Color]int64{balance.ColorIOTA: fee}
+	if par.Transfer != nil {
+		par.Transfer.AddToMap(transfer)
+	}
+	ret, err := ctx.Call(Interface.Hname(), coretypes.Hn(FuncSendMessage), p, cbalances.NewFromMap(transfer))
+	if err != nil {
+		return 0, err
+	}
+	id, ok, err := codec.DecodeInt64(ret.MustGet(ParamMessageID))
+	if err != nil || !ok {
+		return 0, fmt.Errorf("xchain.SendMessage: wrong message ID returned")
+	}
+	return id, nil
+}
@@ -0,0 +1,56 @@
+package vm
+
+import "errors"
+
+// gas budget limits of one request
+const (
+	// GasBudgetDefault is used when the request does not specify the budget (gas budget 0)
+	GasBudgetDefault = uint64(1_000_000)
+	// GasBudgetMax is the upper limit of the gas budget of the request. Bigger budgets are capped
+	GasBudgetMax = uint64(100_000_000)
+	// GasPriceUnit is the number of gas units the chain gas price is specified for.
+	// For example, gas price 5 means 5 fee tokens for each 1000 gas units
+	GasPriceUnit = uint64(1000)
+	// GasBudgetView limits the Wasm code which is run outside of requests: the views and
+	// 'on_load' of the Wasm modules. Nobody pays for this gas
+	GasBudgetView = uint64(10_000_000)
+)
+
+// gas cost of operations performed by the VM on behalf of the smart contract
+const (
+	GasCall           = uint64(100)
+	GasStateRead      = uint64(10)
+	GasStateWrite     = uint64(50)
+	GasPerByte        = uint64(1)
+	GasIterateItem    = uint64(5)
+	GasPostRequest    = uint64(500)
+	GasTransfer       = uint64(200)
+	GasDeployContract = uint64(5000)
+	GasEvent          = uint64(50)
+	GasWasmHostCall   = uint64(2)
+	// GasWasmInstruction is the cost of one executed Wasm instruction
+	GasWasmInstruction = uint64(1)
+)
+
+// ErrGasExhausted is the error of the request which exceeded its gas budget
+var ErrGasExhausted = errors.New("gas exhausted")
+
+// EffectiveGasBudget returns budget of the request adjusted to defaults and limits
+func EffectiveGasBudget(budget uint64) uint64 {
+	if budget == 0 {
+		return GasBudgetDefault
+	}
+	if budget > GasBudgetMax {
+		return GasBudgetMax
+	}
+	return budget
+}
+
+// GasFee returns number of fee tokens to be paid for the specified amount of gas
+// with the gas price. The fee is rounded up to the whole token
+func GasFee(gas uint64, gasPrice int64) int64 {
+	if gasPrice <= 0 || gas == 0 {
+		return 0
+	}
+	return int64((gas*uint64(gasPrice) + GasPriceUnit - 1) / GasPriceUnit)
+}
@@ -2,13 +2,13 @@ package runvm
 
 import (
 	"fmt"
-	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/statetxbuilder"
 	"github.com/iotaledger/wasp/packages/vm/vmcontext"
 	"time"
 
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/vm"
 )
@@ -20,14 +20,15 @@ func RunComputationsAsync(ctx *vm.VMTask) error {
 		return fmt.Errorf("RunComputationsAsync: must be at least 1 request")
 	}
 
-	txb, err := statetxbuilder.New(address.Address(ctx.ChainID), ctx.Color, ctx.Balances)
+	txb, err := statetxbuilder.New(ctx.ChainID, ctx.ChainAddress, ctx.Color, ctx.Balances)
 	if err != nil {
 		ctx.Log.Debugf("statetxbuilder.New: %v", err)
 		return err
 	}
 
 	// TODO 1 graceful shutdown of the running VM task (with daemon)
-	// TODO 2 timeout for VM. Gas limit
+	// the run time of the VM task is bounded by the gas budgets of the requests: the Wasm code
+	// is metered per instruction, see wasminterp.InjectFuelMetering
 
 	go runTask(ctx, txb)
 	return nil
@@ -35,6 +36,7 @@ func RunComputationsAsync(ctx *vm.VMTask) error {
 
 // runTask runs batch of requests on VM
 func runTask(task *vm.VMTask, txb *statetxbuilder.Builder) {
+	start := time.Now()
 	task.Log.Debugw("runTask IN",
 		"chainID", task.ChainID.String(),
 		"timestamp", task.Timestamp,
@@ -56,7 +58,7 @@ func runTask(task *vm.VMTask, txb *statetxbuilder.Builder) {
 	// the result accumulates in the VMContext and in the list of stateUpdates
 	timestamp := task.Timestamp
 	for _, reqRef := range task.Requests {
-		if reqRef.RequestSection().SolidArgs() == nil {
+		if reqRef.RequestSection().SolidArgs() == nil && !reqRef.ArgsUndecryptable {
 			task.Log.Panicf("inconsistency: request args have not been solidified")
 		}
 		vmctx.RunTheRequest(reqRef, timestamp)
@@ -88,6 +90,7 @@ func runTask(task *vm.VMTask, txb *statetxbuilder.Builder) {
 	task.ResultTransaction, err = vmctx.FinalizeTransactionEssence(
 		task.VirtualState.BlockIndex()+1,
 		stateHash,
+		vsClone.MerkleRoot(),
 		vsClone.Timestamp(),
 	)
 	if err != nil {
@@ -102,5 +105,6 @@ func runTask(task *vm.VMTask, txb *statetxbuilder.Builder) {
 		"tx essence hash", hashing.HashData(task.ResultTransaction.EssenceBytes()).String(),
 		"tx finalTimestamp", time.Unix(0, task.ResultTransaction.MustState().Timestamp()),
 	)
+	metrics.VMBatch(&task.ChainID, len(task.Requests), time.Since(start))
 	task.OnFinish(lastResult, lastErr, nil)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm"
 	"github.com/iotaledger/wasp/packages/vm/sandbox/sandbox_utils"
 	"github.com/iotaledger/wasp/packages/vm/vmcontext"
 )
@@ -66,6 +67,14 @@ func (s *sandbox) DeployContract(programHash hashing.HashValue, name string, des
 	return s.vmctx.DeployContract(programHash, name, description, initParams)
 }
 
+func (s *sandbox) RemoveProcessor(programHash hashing.HashValue) {
+	s.vmctx.RemoveProcessor(programHash)
+}
+
+func (s *sandbox) SweepContract(contract coretypes.Hname, target coretypes.AgentID, eraseState bool) coretypes.ColoredBalances {
+	return s.vmctx.SweepContract(contract, target, eraseState)
+}
+
 // Call calls an entry point of contract, passes parameters and funds
 func (s *sandbox) Call(contractHname coretypes.Hname, entryPoint coretypes.Hname, params dict.Dict, transfer coretypes.ColoredBalances) (dict.Dict, error) {
 	return s.vmctx.Call(contractHname, entryPoint, params, transfer)
@@ -92,13 +101,22 @@ func (s *sandbox) PostRequest(par coretypes.PostRequestParams) bool {
 	return s.vmctx.PostRequest(par)
 }
 
+func (s *sandbox) GasBurn(gas uint64) {
+	s.vmctx.GasBurn(gas)
+}
+
+func (s *sandbox) GasRemaining() uint64 {
+	return s.vmctx.GasRemaining()
+}
+
 func (s *sandbox) Log() coretypes.LogInterface {
 	return s.vmctx
 }
 
 func (s *sandbox) Event(msg string) {
+	s.vmctx.GasBurn(vm.GasEvent + uint64(len(msg))*vm.GasPerByte)
 	s.Log().Infof("eventlog::%s -> '%s'", s.vmctx.CurrentContractHname(), msg)
-	s.vmctx.StoreToEventLog(s.vmctx.CurrentContractHname(), []byte(msg))
+	s.vmctx.StoreEvent(msg)
 	s.vmctx.EventPublisher().Publish(msg)
 }
 
@@ -14,12 +14,17 @@ import (
 
 type Builder struct {
 	vtxb            *vtxBuilder
+	chainID         coretypes.ChainID
 	chainAddress    address.Address
+	moveTo          *address.Address
 	stateSection    *sctransaction.StateSection
 	requestSections []*sctransaction.RequestSection
 }
 
-func New(chainAddress address.Address, chainColor balance.Color, addressBalances map[valuetransaction.ID][]*balance.Balance) (*Builder, error) {
+// New creates builder of the state transaction which consumes outputs of the chain address.
+// The chain address is the address of the current committee. It is equal to the chain ID unless
+// the chain was moved to another committee
+func New(chainID coretypes.ChainID, chainAddress address.Address, chainColor balance.Color, addressBalances map[valuetransaction.ID][]*balance.Balance) (*Builder, error) {
 	if chainColor == balance.ColorNew || chainColor == balance.ColorIOTA {
 		return nil, errors.New("statetxbuilder.New: wrong chain color")
 	}
@@ -29,8 +34,9 @@ func New(chainAddress address.Address, chainColor balance.Color, addressBalances
 	}
 	ret := &Builder{
 		vtxb:            vtxb,
+		chainID:         chainID,
 		chainAddress:    chainAddress,
-		stateSection:    sctransaction.NewStateSection(sctransaction.NewStateSectionParams{Color: chainColor}),
+		stateSection:    sctransaction.NewStateSection(sctransaction.NewStateSectionParams{Color: chainColor, ChainID: chainID}),
 		requestSections: make([]*sctransaction.RequestSection, 0),
 	}
 	err = vtxb.MoveTokens(ret.chainAddress, chainColor, 1)
@@ -40,7 +46,9 @@ func New(chainAddress address.Address, chainColor balance.Color, addressBalances
 func (txb *Builder) Clone() *Builder {
 	ret := &Builder{
 		vtxb:            txb.vtxb.clone(),
+		chainID:         txb.chainID,
 		chainAddress:    txb.chainAddress,
+		moveTo:          txb.moveTo,
 		stateSection:    txb.stateSection.Clone(),
 		requestSections: make([]*sctransaction.RequestSection, len(txb.requestSections)),
 	}
@@ -55,10 +63,18 @@ func (txb *Builder) SetStateParams(stateIndex uint32, stateHash hashing.HashValu
 	return nil
 }
 
+func (txb *Builder) SetMerkleRoot(merkleRoot hashing.HashValue) {
+	txb.stateSection.WithMerkleRoot(merkleRoot)
+}
+
 // AddRequestSectionWithTransfer adds request block with the request
 // token and adds respective outputs for the colored transfers
 func (txb *Builder) AddRequestSection(req *sctransaction.RequestSection) error {
-	targetAddr := address.Address(req.Target().ChainID())
+	if req.Target().ChainID() == txb.chainID && txb.chainAddress != address.Address(txb.chainID) {
+		// request to itself after the chain was moved to another committee
+		req.WithChainAddress(txb.chainAddress)
+	}
+	targetAddr := req.TargetAddress()
 	var err error
 	if err = txb.vtxb.MintColor(targetAddr, balance.ColorIOTA, 1); err != nil {
 		return err
@@ -90,7 +106,29 @@ func (txb *Builder) Erase1TokenToChain(col balance.Color) bool {
 	return txb.vtxb.EraseColor(txb.chainAddress, col, 1) == nil
 }
 
+// MoveChain makes the state transaction move the chain token and all tokens owned by the chain
+// to the address of the new committee
+func (txb *Builder) MoveChain(newAddress address.Address) {
+	txb.moveTo = &newAddress
+}
+
+// ChainAddress returns the address the chain will be controlled by after the state transaction
+func (txb *Builder) ChainAddress() address.Address {
+	if txb.moveTo != nil {
+		return *txb.moveTo
+	}
+	return txb.chainAddress
+}
+
 func (txb *Builder) Build() (*sctransaction.Transaction, error) {
+	if txb.moveTo != nil && *txb.moveTo != txb.chainAddress {
+		txb.vtxb.moveOutputs(txb.chainAddress, *txb.moveTo)
+		for _, req := range txb.requestSections {
+			if req.Target().ChainID() == txb.chainID {
+				req.WithChainAddress(*txb.moveTo)
+			}
+		}
+	}
 	txb.MustValidate()
 	return sctransaction.NewTransaction(
 		txb.vtxb.build(),
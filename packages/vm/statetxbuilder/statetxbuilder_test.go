@@ -5,6 +5,7 @@ import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
 	_ "github.com/iotaledger/wasp/packages/sctransaction/properties"
 	"github.com/stretchr/testify/require"
@@ -30,7 +31,7 @@ func TestBasic(t *testing.T) {
 			balance.New(balance.ColorIOTA, 5),
 		},
 	}
-	b, err := New(chAddr, col1, inps)
+	b, err := New(coretypes.ChainID(chAddr), chAddr, col1, inps)
 	require.NoError(t, err)
 
 	b.MustValidate()
@@ -62,7 +63,7 @@ func TestClone(t *testing.T) {
 			balance.New(balance.ColorIOTA, 5),
 		},
 	}
-	b, err := New(chAddr, col1, inps)
+	b, err := New(coretypes.ChainID(chAddr), chAddr, col1, inps)
 	require.NoError(t, err)
 
 	b.MustValidate()
@@ -79,3 +80,40 @@ func TestClone(t *testing.T) {
 
 	require.EqualValues(t, tx.ID(), tx1.ID())
 }
+
+func TestMoveChain(t *testing.T) {
+	chSig := signaturescheme.ED25519(ed25519.GenerateKeyPair())
+	chAddr := chSig.Address()
+	chainID := coretypes.ChainID(chAddr)
+	newAddr := signaturescheme.ED25519(ed25519.GenerateKeyPair()).Address()
+	col1, _, err := balance.ColorFromBytes(hashing.RandomHash(nil).Bytes())
+	require.NoError(t, err)
+	txid1, _, err := transaction.IDFromBytes(hashing.RandomHash(nil).Bytes())
+	require.NoError(t, err)
+
+	inps := map[transaction.ID][]*balance.Balance{
+		txid1: {
+			balance.New(col1, 1),
+			balance.New(balance.ColorIOTA, 3),
+		},
+	}
+	b, err := New(chainID, chAddr, col1, inps)
+	require.NoError(t, err)
+
+	b.MoveChain(newAddr)
+	require.EqualValues(t, newAddr, b.ChainAddress())
+
+	tx, err := b.Build()
+	require.NoError(t, err)
+	tx.Sign(chSig)
+
+	prop, err := tx.Properties()
+	require.NoError(t, err)
+	require.EqualValues(t, newAddr, *prop.MustChainAddress())
+	require.EqualValues(t, chainID, *prop.MustChainID())
+	_, ok := tx.OutputBalancesByAddress(chAddr)
+	require.False(t, ok)
+	bals, ok := tx.OutputBalancesByAddress(newAddr)
+	require.True(t, ok)
+	require.EqualValues(t, 2, len(bals))
+}
@@ -248,6 +248,19 @@ func (vtxb *vtxBuilder) MintColor(targetAddr address.Address, sourceColor balanc
 	return nil
 }
 
+// moveOutputs redirects all outputs to the address, including the remainder, to another address
+func (vtxb *vtxBuilder) moveOutputs(from, to address.Address) {
+	if cmap, ok := vtxb.outputBalances[from]; ok {
+		delete(vtxb.outputBalances, from)
+		for col, amount := range cmap {
+			vtxb.addToOutputs(to, col, amount)
+		}
+	}
+	if vtxb.reminderAddr == from {
+		vtxb.reminderAddr = to
+	}
+}
+
 // Build build the final value transaction: not signed and without data payload
 func (vtxb *vtxBuilder) build() *valuetransaction.Transaction {
 	// send all remaining balances to the main address
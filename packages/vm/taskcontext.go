@@ -2,6 +2,7 @@ package vm
 
 import (
 	"bytes"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/hive.go/logger"
@@ -17,6 +18,9 @@ import (
 type RequestRefWithFreeTokens struct {
 	sctransaction.RequestRef
 	FreeTokens coretypes.ColoredBalances
+	// ArgsUndecryptable is agreed by the committee: the leader couldn't decrypt the arguments of the request.
+	// The request is settled with error no matter if the node itself can decrypt them
+	ArgsUndecryptable bool
 }
 
 // task context (for batch of requests)
@@ -25,6 +29,8 @@ type VMTask struct {
 	// inputs (immutable)
 	ChainID coretypes.ChainID
 	Color   balance.Color
+	// address of the current committee, which controls the balances
+	ChainAddress address.Address
 	// deterministic source of entropy
 	Entropy            hashing.HashValue
 	Balances           map[valuetransaction.ID][]*balance.Balance
@@ -40,11 +46,12 @@ type VMTask struct {
 	ResultBlock       state.Block
 }
 
-// BatchHash is used to uniquely identify the VM task
-func BatchHash(reqids []coretypes.RequestID, ts int64, leaderIndex uint16) hashing.HashValue {
+// BatchHash is used to uniquely identify the VM task, including the requests with undecryptable arguments
+func BatchHash(reqids []coretypes.RequestID, argsUndecryptable []bool, ts int64, leaderIndex uint16) hashing.HashValue {
 	var buf bytes.Buffer
 	for i := range reqids {
 		buf.Write(reqids[i][:])
+		_ = util.WriteBoolByte(&buf, argsUndecryptable[i])
 	}
 	_ = util.WriteInt64(&buf, ts)
 	_ = util.WriteUint16(&buf, leaderIndex)
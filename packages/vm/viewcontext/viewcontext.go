@@ -77,6 +77,9 @@ func (v *viewcontext) mustCallView(contractHname coretypes.Hname, epCode coretyp
 	if err != nil {
 		return nil, fmt.Errorf("failed to find contract %s: %v", contractHname, err)
 	}
+	if contractRecord.Deactivated {
+		return nil, fmt.Errorf("contract %s is deactivated", contractHname)
+	}
 	proc, err := v.processors.GetOrCreateProcessor(contractRecord, func(programHash hashing.HashValue) (string, []byte, error) {
 		if vmtype, ok := processors.GetBuiltinProcessorType(programHash); ok {
 			return vmtype, nil, nil
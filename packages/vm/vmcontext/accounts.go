@@ -5,6 +5,7 @@ import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/vm"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 )
 
@@ -23,6 +24,7 @@ func (vmctx *VMContext) GetMyBalances() coretypes.ColoredBalances {
 // TransferToAddress includes output of colored tokens into the transaction
 // i.e. it is a transfer of tokens from chain to layer 1 ledger
 func (vmctx *VMContext) TransferToAddress(targetAddr address.Address, transfer coretypes.ColoredBalances) bool {
+	vmctx.GasBurn(vm.GasTransfer)
 	privileged := vmctx.CurrentContractHname() == accounts.Interface.Hname()
 	fmt.Printf("TransferToAddress: %s privileged = %v\n", targetAddr.String(), privileged)
 	if !privileged {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/vm"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
@@ -12,7 +13,8 @@ import (
 
 var (
 	ErrContractNotFound   = errors.New("contract not found")
-	ErrEntryPointNotFound = errors.New("entry point not found")
+	ErrContractInactive   = errors.New("contract is deactivated")
+	ErrEntryPointNotFound = coretypes.ErrEntryPointNotFound
 	ErrProcessorNotFound  = errors.New("VM not found. Internal error")
 	ErrNotEnoughFees      = errors.New("not enough fees")
 	ErrWrongRequestToken  = errors.New("wrong request token")
@@ -21,10 +23,14 @@ var (
 // Call
 func (vmctx *VMContext) Call(targetContract coretypes.Hname, epCode coretypes.Hname, params dict.Dict, transfer coretypes.ColoredBalances) (dict.Dict, error) {
 	vmctx.log.Debugw("Call", "targetContract", targetContract, "epCode", epCode.String())
+	vmctx.GasBurn(vm.GasCall)
 	rec, ok := vmctx.findContractByHname(targetContract)
 	if !ok {
 		return nil, ErrContractNotFound
 	}
+	if rec.Deactivated {
+		return nil, ErrContractInactive
+	}
 	return vmctx.callByProgramHash(targetContract, epCode, params, transfer, rec.ProgramHash)
 }
 
@@ -42,6 +48,9 @@ func (vmctx *VMContext) callByProgramHash(targetContract coretypes.Hname, epCode
 		if epCode == coretypes.EntryPointInit {
 			return nil, fmt.Errorf("'init' entry point can't be a view")
 		}
+		if epCode == coretypes.EntryPointMigrate {
+			return nil, fmt.Errorf("'migrate' entry point can't be a view")
+		}
 		// passing nil as transfer: calling the view should not have effect on chain ledger
 		if err := vmctx.pushCallContextWithTransfer(targetContract, params, nil); err != nil {
 			return nil, err
@@ -61,6 +70,10 @@ func (vmctx *VMContext) callByProgramHash(targetContract coretypes.Hname, epCode
 			return nil, fmt.Errorf("attempt to callByProgramHash init not from the root contract")
 		}
 	}
+	// 'migrate' is called by the root contract only, when the contract is upgraded
+	if epCode == coretypes.EntryPointMigrate && !vmctx.callerIsRoot() {
+		return nil, fmt.Errorf("attempt to call migrate not from the root contract")
+	}
 	return ep.Call(NewSandbox(vmctx))
 }
 
@@ -89,6 +102,10 @@ func (vmctx *VMContext) callNonViewByProgramHash(targetContract coretypes.Hname,
 			return nil, fmt.Errorf("attempt to callByProgramHash init not from the root contract")
 		}
 	}
+	// 'migrate' is called by the root contract only, when the contract is upgraded
+	if epCode == coretypes.EntryPointMigrate && !vmctx.callerIsRoot() {
+		return nil, fmt.Errorf("attempt to call migrate not from the root contract")
+	}
 	return ep.Call(NewSandbox(vmctx))
 }
 
@@ -2,9 +2,12 @@ package vmcontext
 
 import (
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 )
 
@@ -12,6 +15,7 @@ import (
 // - if called from 'root' contract only loads VM from binary
 // - otherwise calls 'root' contract 'DeployContract' entry point to do the job.
 func (vmctx *VMContext) DeployContract(programHash hashing.HashValue, name string, description string, initParams dict.Dict) error {
+	vmctx.GasBurn(vm.GasDeployContract)
 	vmtype, programBinary, err := vmctx.getBinary(programHash)
 	if err != nil {
 		return err
@@ -32,3 +36,42 @@ func (vmctx *VMContext) DeployContract(programHash hashing.HashValue, name strin
 	_, err = vmctx.Call(root.Interface.Hname(), coretypes.Hn(root.FuncDeployContract), par, nil)
 	return err
 }
+
+// RemoveProcessor removes the processor of the program from the cache, e.g. after the contract
+// was upgraded to another program. Only 'root' contract can do it
+func (vmctx *VMContext) RemoveProcessor(programHash hashing.HashValue) {
+	if vmctx.CurrentContractHname() != root.Interface.Hname() {
+		vmctx.log.Panicf("RemoveProcessor: only 'root' contract can remove processors")
+	}
+	vmctx.log.Debugf("vmcontext.RemoveProcessor: %s", programHash.String())
+	vmctx.processors.RemoveProcessor(programHash)
+}
+
+// SweepContract moves all tokens of the account of the contract to the target account and,
+// if 'eraseState' is true, deletes all keys of the state partition of the contract.
+// It is used by the 'root' contract when it removes the contract. Only 'root' contract can do it
+func (vmctx *VMContext) SweepContract(contract coretypes.Hname, target coretypes.AgentID, eraseState bool) coretypes.ColoredBalances {
+	if vmctx.CurrentContractHname() != root.Interface.Hname() {
+		vmctx.log.Panicf("SweepContract: only 'root' contract can sweep contracts")
+	}
+	agentID := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(vmctx.chainID, contract))
+
+	vmctx.pushCallContext(accounts.Interface.Hname(), nil, nil)
+	balances, _ := accounts.GetAccountBalances(vmctx.State(), agentID)
+	swept := cbalances.NewFromMap(balances)
+	if !accounts.MoveBetweenAccounts(vmctx.State(), agentID, target, swept) {
+		vmctx.log.Panicf("SweepContract: can't move tokens from %s to %s", agentID.String(), target.String())
+	}
+	vmctx.popCallContext()
+	vmctx.log.Debugf("vmcontext.SweepContract: %s, moved to %s: %s", contract.String(), target.String(), swept.String())
+
+	if eraseState {
+		vmctx.pushCallContext(contract, nil, nil)
+		defer vmctx.popCallContext()
+
+		// one mutation deletes the whole state partition, the cost doesn't depend on the number of keys
+		vmctx.State().DelPrefix("")
+		vmctx.log.Debugf("vmcontext.SweepContract: %s, state erased", contract.String())
+	}
+	return swept
+}
@@ -0,0 +1,70 @@
+package vmcontext
+
+import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+)
+
+// GasBurn charges gas from the budget of the current request.
+// Gas is only metered while the request is being processed by the target contract.
+// When the budget is exhausted it panics with vm.ErrGasExhausted, the panic is caught
+// in RunTheRequest and the request is rolled back deterministically
+func (vmctx *VMContext) GasBurn(gas uint64) {
+	if !vmctx.gasMetering {
+		return
+	}
+	if vmctx.gasBudget-vmctx.gasBurned < gas {
+		vmctx.gasBurned = vmctx.gasBudget
+		panic(vm.ErrGasExhausted)
+	}
+	vmctx.gasBurned += gas
+}
+
+// GasRemaining returns gas left in the budget of the current request
+func (vmctx *VMContext) GasRemaining() uint64 {
+	return vmctx.gasBudget - vmctx.gasBurned
+}
+
+// GasBurned returns gas burned by the current request so far
+func (vmctx *VMContext) GasBurned() uint64 {
+	return vmctx.gasBurned
+}
+
+// gasBurnBytes charges the gas for the operation plus size of the data
+func (vmctx *VMContext) gasBurnBytes(gas uint64, size int) {
+	vmctx.GasBurn(gas + uint64(size)*vm.GasPerByte)
+}
+
+func (vmctx *VMContext) getGasPrice() int64 {
+	vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	return root.GetGasPrice(vmctx.State())
+}
+
+// mustSettleGasFee the fee reserved for the whole gas budget is split: the fee for the gas burned is
+// accrued to the validator, the rest (the unused gas) is refunded to the sender on-chain
+func (vmctx *VMContext) mustSettleGasFee() {
+	if vmctx.gasFeeReserved == 0 {
+		return
+	}
+	used := vm.GasFee(vmctx.gasBurned, vmctx.gasPrice)
+	if used > vmctx.gasFeeReserved {
+		used = vmctx.gasFeeReserved
+	}
+	vmctx.feeCharged += used
+	if used > 0 {
+		vmctx.creditToAccount(vmctx.validatorFeeTarget, cbalances.NewFromMap(map[balance.Color]int64{
+			vmctx.feeColor: used,
+		}))
+	}
+	if refund := vmctx.gasFeeReserved - used; refund > 0 {
+		vmctx.creditToAccount(vmctx.reqRef.SenderAgentID(), cbalances.NewFromMap(map[balance.Color]int64{
+			vmctx.feeColor: refund,
+		}))
+	}
+	vmctx.log.Debugf("mustSettleGasFee: gas burned %d, fee %d, refund %d", vmctx.gasBurned, used, vmctx.gasFeeReserved-used)
+	vmctx.gasFeeReserved = 0
+}
@@ -64,6 +64,7 @@ func (vmctx *VMContext) PostRequest(par coretypes.PostRequestParams) bool {
 		"ep", par.EntryPoint.String(),
 		"transfer", cbalances.Str(par.Transfer),
 	)
+	vmctx.GasBurn(vm.GasPostRequest)
 	myAgentID := vmctx.MyAgentID()
 	if !vmctx.debitFromAccount(myAgentID, cbalances.NewFromMap(map[balance.Color]int64{
 		balance.ColorIOTA: 1,
@@ -79,6 +80,7 @@ func (vmctx *VMContext) PostRequest(par coretypes.PostRequestParams) bool {
 	reqParams.AddEncodeSimpleMany(par.Params)
 	reqSection := sctransaction.NewRequestSection(vmctx.CurrentContractHname(), par.TargetContractID, par.EntryPoint).
 		WithTimelock(par.TimeLock).
+		WithGasBudget(par.GasBudget).
 		WithTransfer(par.Transfer).
 		WithArgs(reqParams)
 	return vmctx.txBuilder.AddRequestSection(reqSection) == nil
@@ -93,7 +95,7 @@ func (vmctx *VMContext) PostRequestToSelf(reqCode coretypes.Hname, params dict.D
 }
 
 func (vmctx *VMContext) PostRequestToSelfWithDelay(entryPoint coretypes.Hname, args dict.Dict, delaySec uint32) bool {
-	timelock := util.NanoSecToUnixSec(vmctx.timestamp) + delaySec
+	timelock := util.NanoSecToUnixSec(vmctx.timestamp) + int64(delaySec)
 
 	return vmctx.PostRequest(coretypes.PostRequestParams{
 		TargetContractID: vmctx.CurrentContractID(),
@@ -5,10 +5,15 @@ import (
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/receipts"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/iotaledger/wasp/packages/vm/core/xchain"
 	"github.com/iotaledger/wasp/packages/vm/processors"
 )
 
@@ -117,3 +122,66 @@ func (vmctx *VMContext) StoreToEventLog(contract coretypes.Hname, data []byte) {
 	vmctx.log.Debugf("StoreToEventLog/%s: data: '%s'", contract.String(), string(data))
 	eventlog.AppendToLog(vmctx.State(), vmctx.timestamp, contract, data)
 }
+
+// StoreEvent stores the event emitted by the current contract to the event log and
+// collects it for the receipt of the request
+func (vmctx *VMContext) StoreEvent(msg string) {
+	contract := vmctx.CurrentContractHname()
+	vmctx.requestEvents = append(vmctx.requestEvents, receipts.Event{Contract: contract, Message: msg})
+	vmctx.StoreToEventLog(contract, []byte(msg))
+}
+
+func (vmctx *VMContext) storeReceipt(rec *receipts.RequestReceipt) {
+	vmctx.pushCallContext(receipts.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	receipts.StoreReceipt(vmctx.State(), rec)
+}
+
+// requestArgs returns the solid arguments of the request. The arguments the committee agreed
+// can't be decrypted are empty, even if the node itself decrypted them
+func (vmctx *VMContext) requestArgs() dict.Dict {
+	if vmctx.reqRef.ArgsUndecryptable {
+		return dict.New()
+	}
+	return vmctx.reqRef.RequestSection().SolidArgs()
+}
+
+// crossChainMessageID returns the ID of the message if the request is a cross-chain message
+// sent through the 'xchain' contract of the sender chain
+func (vmctx *VMContext) crossChainMessageID() (int64, bool) {
+	sender := vmctx.reqRef.SenderAgentID()
+	if sender.IsAddress() || sender.MustContractID().Hname() != xchain.Interface.Hname() {
+		return 0, false
+	}
+	if vmctx.reqHname == xchain.Interface.Hname() {
+		// acknowledgements are not acknowledged
+		return 0, false
+	}
+	id, ok, err := codec.DecodeInt64(vmctx.requestArgs().MustGet(xchain.ParamMessageID))
+	if err != nil || !ok {
+		return 0, false
+	}
+	return id, true
+}
+
+// scheduledCallOwner returns the owner of the scheduled call if the request is the call posted by
+// the 'scheduler' contract of the chain
+func (vmctx *VMContext) scheduledCallOwner() (coretypes.AgentID, bool) {
+	schedulerAgentID := coretypes.NewAgentIDFromContractID(scheduler.Interface.ContractID(vmctx.chainID))
+	if vmctx.reqRef.SenderAgentID() != schedulerAgentID || vmctx.isSchedulerTrigger() {
+		return coretypes.AgentID{}, false
+	}
+	owner, ok, err := codec.DecodeAgentID(vmctx.requestArgs().MustGet(scheduler.ParamCallOwner))
+	if err != nil || !ok {
+		return coretypes.AgentID{}, false
+	}
+	return owner, true
+}
+
+// isSchedulerTrigger returns true if the request is posted by the 'scheduler' contract of the chain to itself.
+// The request token of the trigger is paid from the budget of the scheduled call, no fees are charged
+func (vmctx *VMContext) isSchedulerTrigger() bool {
+	schedulerAgentID := coretypes.NewAgentIDFromContractID(scheduler.Interface.ContractID(vmctx.chainID))
+	return vmctx.reqHname == scheduler.Interface.Hname() && vmctx.reqRef.SenderAgentID() == schedulerAgentID
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/receipts"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/packages/vm/processors"
 	"github.com/iotaledger/wasp/packages/vm/statetxbuilder"
@@ -32,16 +33,26 @@ type VMContext struct {
 	feeColor           balance.Color
 	ownerFee           int64
 	validatorFee       int64
+	feeCharged         int64 // fees taken from the current request, including the fee for the gas burned
+	// gas related
+	gasBudget      uint64
+	gasBurned      uint64
+	gasMetering    bool // true only while the request is being processed by the target contract
+	gasPrice       int64
+	gasFeeReserved int64
 	// request context
 	remainingAfterFees coretypes.ColoredBalances
 	entropy            hashing.HashValue // mutates with each request
 	reqRef             vm.RequestRefWithFreeTokens
 	reqHname           coretypes.Hname
+	requestIndex       uint16 // index of the current request in the block
+	numRequests        uint16 // number of requests run so far
 	contractRecord     *root.ContractRecord
 	timestamp          int64
 	stateUpdate        state.StateUpdate
 	lastError          error     // mutated
-	lastResult         dict.Dict // mutated. Used only by 'solo'
+	lastResult         dict.Dict // mutated. Stored in the receipt of the request
+	requestEvents      []receipts.Event
 	callStack          []*callContext
 }
 
@@ -64,6 +75,8 @@ func NewVMContext(task *vm.VMTask, txb *statetxbuilder.Builder) (*VMContext, err
 		log:          task.Log,
 		entropy:      task.Entropy,
 		callStack:    make([]*callContext, 0),
+
+		validatorFeeTarget: task.ValidatorFeeTarget,
 	}
 	return ret, nil
 }
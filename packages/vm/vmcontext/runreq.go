@@ -5,12 +5,18 @@ import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/receipts"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/xchain"
 )
 
 // runTheRequest:
@@ -33,6 +39,20 @@ func (vmctx *VMContext) RunTheRequest(reqRef vm.RequestRefWithFreeTokens, timest
 		vmctx.lastError = fmt.Errorf("smart contract '%s' does not exist", vmctx.reqHname)
 		return
 	}
+	if vmctx.contractRecord.Deactivated {
+		// the contract refuses calls, tokens are returned to the sender
+		vmctx.lastResult = nil
+		vmctx.lastError = fmt.Errorf("smart contract '%s' is deactivated", vmctx.contractRecord.Name)
+		vmctx.mustHandleFallback()
+		return
+	}
+	if vmctx.reqRef.ArgsUndecryptable {
+		// the committee agreed the arguments can't be decrypted, tokens are returned to the sender
+		vmctx.lastResult = nil
+		vmctx.lastError = sctransaction.ErrUndecryptableArgs
+		vmctx.mustHandleFallback()
+		return
+	}
 	// snapshot state baseline for rollback in case of panic
 	snapshotTxBuilder := vmctx.txBuilder.Clone()
 	snapshotStateUpdate := vmctx.stateUpdate.Clone()
@@ -44,6 +64,9 @@ func (vmctx *VMContext) RunTheRequest(reqRef vm.RequestRefWithFreeTokens, timest
 			if r := recover(); r != nil {
 				vmctx.lastResult = nil
 				vmctx.lastError = fmt.Errorf("recovered from panic in VM: %v", r)
+				if r == vm.ErrGasExhausted {
+					vmctx.lastError = vm.ErrGasExhausted
+				}
 				if dberr, ok := r.(buffered.DBError); ok {
 					// There was an error accessing the DB
 					// The world stops
@@ -58,8 +81,22 @@ func (vmctx *VMContext) RunTheRequest(reqRef vm.RequestRefWithFreeTokens, timest
 		// treating panic and error returned from request the same way
 		vmctx.txBuilder = snapshotTxBuilder
 		vmctx.stateUpdate = snapshotStateUpdate
+		vmctx.requestEvents = nil
 
 		vmctx.mustHandleFallback()
+		return
+	}
+	vmctx.mustMoveChainIfRotated()
+}
+
+// mustMoveChainIfRotated makes the state transaction move the chain to the address of the new
+// committee if the request changed it in the 'root' contract. It is checked after each request:
+// the 'root' contract may be called by the target contract of the request, not only by the request itself
+func (vmctx *VMContext) mustMoveChainIfRotated() {
+	chainAddress := vmctx.mustGetChainInfo().ChainAddress
+	if chainAddress != vmctx.txBuilder.ChainAddress() {
+		vmctx.log.Infof("chain is moved to the address of the new committee: %s", chainAddress.String())
+		vmctx.txBuilder.MoveChain(chainAddress)
 	}
 }
 
@@ -85,11 +122,13 @@ func (vmctx *VMContext) mustHandleRequestToken() {
 // mustHandleFees:
 // - handles request token
 // - handles node fee, including fallback if not enough
+// - reserves fee for the whole gas budget. The unused part of it is refunded in mustSettleGasFee
 func (vmctx *VMContext) mustHandleFees() {
 	transfer := vmctx.reqRef.RequestSection().Transfer()
-	totalFee := vmctx.ownerFee + vmctx.validatorFee
-	if totalFee == 0 || vmctx.requesterIsChainOwner() {
-		// no fees enabled or the caller is the chain owner
+	gasFee := vm.GasFee(vmctx.gasBudget, vmctx.gasPrice)
+	totalFee := vmctx.ownerFee + vmctx.validatorFee + gasFee
+	if totalFee == 0 || vmctx.requesterIsChainOwner() || vmctx.isSchedulerTrigger() {
+		// no fees enabled, the caller is the chain owner or the request is a trigger of the scheduler
 		vmctx.log.Debugf("mustHandleFees: no fees charged\n")
 		vmctx.remainingAfterFees = transfer
 		return
@@ -116,6 +155,8 @@ func (vmctx *VMContext) mustHandleFees() {
 			vmctx.feeColor: vmctx.validatorFee,
 		}))
 	}
+	vmctx.gasFeeReserved = gasFee
+	vmctx.feeCharged = vmctx.ownerFee + vmctx.validatorFee
 	// subtract fees from the transfer
 	remaining := map[balance.Color]int64{
 		vmctx.feeColor: -totalFee,
@@ -135,8 +176,13 @@ func (vmctx *VMContext) mustHandleFreeTokens() {
 // mustHandleFallback all remaining tokens are:
 // -- if sender is address, sent to that address
 // -- otherwise accrue to the sender on-chain
+// The sender of the call posted by the 'scheduler' is the owner of the scheduled call
 func (vmctx *VMContext) mustHandleFallback() {
 	sender := vmctx.reqRef.SenderAgentID()
+	if owner, ok := vmctx.scheduledCallOwner(); ok {
+		// the fee of the failed scheduled call is returned to the owner of the call
+		sender = owner
+	}
 	if sender.IsAddress() {
 		err := vmctx.txBuilder.TransferToAddress(sender.MustAddress(), vmctx.remainingAfterFees)
 		if err != nil {
@@ -152,12 +198,19 @@ func (vmctx *VMContext) mustCallFromRequest() {
 	req := vmctx.reqRef.RequestSection()
 	vmctx.log.Debugf("mustCallFromRequest: %s -- %s\n", vmctx.reqRef.RequestID().String(), req.String())
 
+	vmctx.gasMetering = true
+	defer func() {
+		vmctx.gasMetering = false
+	}()
 	// calling only non vew entry points. Calling the view will trigger error and fallback
 	vmctx.lastResult, vmctx.lastError = vmctx.callNonViewByProgramHash(
 		vmctx.reqHname, req.EntryPointCode(), req.SolidArgs(), vmctx.remainingAfterFees, vmctx.contractRecord.ProgramHash)
 }
 
 func (vmctx *VMContext) finalizeRequestCall() {
+	vmctx.mustSettleGasFee()
+	vmctx.mustAcknowledgeMessage()
+	vmctx.mustStoreReceipt()
 	vmctx.mustRequestToEventLog(vmctx.lastError)
 	vmctx.virtualState.ApplyStateUpdate(vmctx.stateUpdate)
 
@@ -175,11 +228,68 @@ func (vmctx *VMContext) mustRequestToEventLog(err error) {
 	if err != nil {
 		e = err.Error()
 	}
-	msg := fmt.Sprintf("[req] %s: %s", vmctx.reqRef.RequestID().String(), e)
+	msg := fmt.Sprintf("[req] %s: %s (gas used: %d)", vmctx.reqRef.RequestID().String(), e, vmctx.gasBurned)
 	vmctx.log.Infof("eventlog -> '%s'", msg)
 	vmctx.StoreToEventLog(vmctx.reqHname, []byte(msg))
 }
 
+// mustAcknowledgeMessage posts the outcome of the request back to the 'xchain' contract of the sender chain,
+// if the request is a cross-chain message sent through it. The request token of the acknowledgement
+// is paid with the iota accrued to the sender from the request token. If the request failed,
+// the transfer accrued to the sender by the fallback is returned with the acknowledgement
+func (vmctx *VMContext) mustAcknowledgeMessage() {
+	msgID, ok := vmctx.crossChainMessageID()
+	if !ok {
+		return
+	}
+	sender := vmctx.reqRef.SenderAgentID()
+	if !vmctx.debitFromAccount(sender, cbalances.NewIotasOnly(1)) {
+		vmctx.log.Panicf("mustAcknowledgeMessage: can't debit request token from the account of %s", sender.String())
+	}
+	refund := cbalances.NewFromMap(nil)
+	if vmctx.lastError != nil && vmctx.debitFromAccount(sender, vmctx.remainingAfterFees) {
+		refund = vmctx.remainingAfterFees
+	}
+
+	par := dict.New()
+	par.Set(xchain.ParamMessageID, codec.EncodeInt64(msgID))
+	if vmctx.lastError != nil {
+		par.Set(xchain.ParamError, codec.EncodeString(vmctx.lastError.Error()))
+	}
+	if vmctx.lastResult != nil {
+		par.Set(xchain.ParamResult, util.MustBytes(vmctx.lastResult))
+	}
+	args := requestargs.New(nil)
+	args.AddEncodeSimpleMany(par)
+	reqSection := sctransaction.NewRequestSection(
+		xchain.Interface.Hname(),
+		xchain.Interface.ContractID(sender.MustContractID().ChainID()),
+		coretypes.Hn(xchain.FuncAcknowledge),
+	).WithTransfer(refund).WithArgs(args)
+	if err := vmctx.txBuilder.AddRequestSection(reqSection); err != nil {
+		vmctx.log.Panicf("mustAcknowledgeMessage: can't add request section: %v", err)
+	}
+	vmctx.log.Debugf("mustAcknowledgeMessage: message #%d acknowledged to %s", msgID, sender.String())
+}
+
+// mustStoreReceipt stores the outcome of the request in the 'receipts' contract
+func (vmctx *VMContext) mustStoreReceipt() {
+	rec := &receipts.RequestReceipt{
+		RequestID:    *vmctx.reqRef.RequestID(),
+		BlockIndex:   vmctx.virtualState.BlockIndex() + 1,
+		RequestIndex: vmctx.requestIndex,
+		Result:       vmctx.lastResult,
+		FeeColor:     vmctx.feeColor,
+		Fee:          vmctx.feeCharged,
+		GasUsed:      vmctx.gasBurned,
+		Events:       vmctx.requestEvents,
+	}
+	if vmctx.lastError != nil {
+		rec.Error = vmctx.lastError.Error()
+	}
+	vmctx.storeReceipt(rec)
+}
+
 // mustGetBaseValues only makes sense if chain is already deployed
 func (vmctx *VMContext) mustGetBaseValues() {
 	info := vmctx.mustGetChainInfo()
@@ -188,6 +298,7 @@ func (vmctx *VMContext) mustGetBaseValues() {
 	}
 	vmctx.chainOwnerID = info.ChainOwnerID
 	vmctx.feeColor, vmctx.ownerFee, vmctx.validatorFee = vmctx.getFeeInfo()
+	vmctx.gasPrice = vmctx.getGasPrice()
 }
 
 // initRequestContext initializes VMContext for request and returns  if contract exists
@@ -201,6 +312,15 @@ func (vmctx *VMContext) initRequestContext(reqRef vm.RequestRefWithFreeTokens, t
 	vmctx.callStack = vmctx.callStack[:0]
 	vmctx.entropy = hashing.HashData(vmctx.entropy[:])
 	vmctx.remainingAfterFees = cbalances.NewFromMap(nil)
+	vmctx.gasBudget = vm.EffectiveGasBudget(reqRef.RequestSection().GasBudget())
+	vmctx.gasBurned = 0
+	vmctx.gasMetering = false
+	vmctx.gasPrice = 0
+	vmctx.gasFeeReserved = 0
+	vmctx.feeCharged = 0
+	vmctx.requestEvents = nil
+	vmctx.requestIndex = vmctx.numRequests
+	vmctx.numRequests++
 
 	vmctx.contractRecord, _ = vmctx.findContractByHname(vmctx.reqHname)
 }
@@ -210,12 +330,13 @@ func (vmctx *VMContext) isInitChainRequest() bool {
 	return s.Target().Hname() == root.Interface.Hname() && s.EntryPointCode() == coretypes.EntryPointInit
 }
 
-func (vmctx *VMContext) FinalizeTransactionEssence(blockIndex uint32, stateHash hashing.HashValue, timestamp int64) (*sctransaction.Transaction, error) {
+func (vmctx *VMContext) FinalizeTransactionEssence(blockIndex uint32, stateHash, merkleRoot hashing.HashValue, timestamp int64) (*sctransaction.Transaction, error) {
 	// add state block
 	err := vmctx.txBuilder.SetStateParams(blockIndex, stateHash, timestamp)
 	if err != nil {
 		return nil, err
 	}
+	vmctx.txBuilder.SetMerkleRoot(merkleRoot)
 	tx, err := vmctx.txBuilder.Build()
 	if err != nil {
 		return nil, err
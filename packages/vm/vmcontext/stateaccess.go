@@ -5,6 +5,7 @@ import (
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/buffered"
 	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm"
 )
 
 type stateWrapper struct {
@@ -12,6 +13,7 @@ type stateWrapper struct {
 	contractSubPartitionPrefix kv.Key
 	virtualState               state.VirtualState
 	stateUpdate                state.StateUpdate
+	vmctx                      *VMContext // for gas metering. May be nil
 }
 
 func newStateWrapper(contractHname coretypes.Hname, virtualState state.VirtualState, stateUpdate state.StateUpdate) stateWrapper {
@@ -28,14 +30,23 @@ func (s *stateWrapper) addContractSubPartition(key kv.Key) kv.Key {
 }
 
 func (vmctx *VMContext) stateWrapper() stateWrapper {
-	return newStateWrapper(
+	ret := newStateWrapper(
 		vmctx.CurrentContractHname(),
 		vmctx.virtualState,
 		vmctx.stateUpdate,
 	)
+	ret.vmctx = vmctx
+	return ret
+}
+
+func (s *stateWrapper) gasBurn(gas uint64, size int) {
+	if s.vmctx != nil {
+		s.vmctx.gasBurnBytes(gas, size)
+	}
 }
 
 func (s stateWrapper) Has(name kv.Key) (bool, error) {
+	s.gasBurn(vm.GasStateRead, len(name))
 	name = s.addContractSubPartition(name)
 	mut := s.stateUpdate.Mutations().Latest(name)
 	if mut != nil {
@@ -46,34 +57,34 @@ func (s stateWrapper) Has(name kv.Key) (bool, error) {
 
 func (s stateWrapper) Iterate(prefix kv.Key, f func(kv.Key, []byte) bool) error {
 	prefix = s.addContractSubPartition(prefix)
-	seen, done := s.stateUpdate.Mutations().IterateValues(prefix, func(key kv.Key, value []byte) bool {
+	if s.stateUpdate.Mutations().IterateValues(prefix, func(key kv.Key, value []byte) bool {
+		s.gasBurn(vm.GasIterateItem, len(key)+len(value))
 		return f(key[len(s.contractSubPartitionPrefix):], value)
-	})
-	if done {
+	}) {
 		return nil
 	}
 	return s.virtualState.Variables().Iterate(prefix, func(key kv.Key, value []byte) bool {
-		_, ok := seen[key]
-		if ok {
+		if s.stateUpdate.Mutations().Latest(key) != nil {
 			return true
 		}
+		s.gasBurn(vm.GasIterateItem, len(key)+len(value))
 		return f(key[len(s.contractSubPartitionPrefix):], value)
 	})
 }
 
 func (s stateWrapper) IterateKeys(prefix kv.Key, f func(key kv.Key) bool) error {
 	prefix = s.addContractSubPartition(prefix)
-	seen, done := s.stateUpdate.Mutations().IterateValues(prefix, func(key kv.Key, value []byte) bool {
+	if s.stateUpdate.Mutations().IterateValues(prefix, func(key kv.Key, value []byte) bool {
+		s.gasBurn(vm.GasIterateItem, len(key))
 		return f(key[len(s.contractSubPartitionPrefix):])
-	})
-	if done {
+	}) {
 		return nil
 	}
 	return s.virtualState.Variables().IterateKeys(prefix, func(key kv.Key) bool {
-		_, ok := seen[key]
-		if ok {
+		if s.stateUpdate.Mutations().Latest(key) != nil {
 			return true
 		}
+		s.gasBurn(vm.GasIterateItem, len(key))
 		return f(key[len(s.contractSubPartitionPrefix):])
 	})
 }
@@ -82,17 +93,28 @@ func (s stateWrapper) Get(name kv.Key) ([]byte, error) {
 	name = s.addContractSubPartition(name)
 	mut := s.stateUpdate.Mutations().Latest(name)
 	if mut != nil {
+		s.gasBurn(vm.GasStateRead, len(name)+len(mut.Value()))
 		return mut.Value(), nil
 	}
-	return s.virtualState.Variables().Get(name)
+	ret, err := s.virtualState.Variables().Get(name)
+	s.gasBurn(vm.GasStateRead, len(name)+len(ret))
+	return ret, err
 }
 
 func (s stateWrapper) Del(name kv.Key) {
+	s.gasBurn(vm.GasStateWrite, len(name))
 	name = s.addContractSubPartition(name)
 	s.stateUpdate.Mutations().Add(buffered.NewMutationDel(name))
 }
 
+func (s stateWrapper) DelPrefix(prefix kv.Key) {
+	s.gasBurn(vm.GasStateWrite, len(prefix))
+	prefix = s.addContractSubPartition(prefix)
+	s.stateUpdate.Mutations().Add(buffered.NewMutationDelPrefix(prefix))
+}
+
 func (s stateWrapper) Set(name kv.Key, value []byte) {
+	s.gasBurn(vm.GasStateWrite, len(name)+len(value))
 	name = s.addContractSubPartition(name)
 	s.stateUpdate.Mutations().Add(buffered.NewMutationSet(name, value))
 }
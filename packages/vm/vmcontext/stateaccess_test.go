@@ -77,3 +77,34 @@ func TestIterate(t *testing.T) {
 		return true
 	})
 }
+
+func TestDelPrefix(t *testing.T) {
+	db := mapdb.NewMapDB()
+
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+
+	virtualState := state.NewVirtualState(db, &chainID)
+	stateUpdate := state.NewStateUpdate(nil)
+	hname := coretypes.Hn("test")
+
+	// x1 is already in the virtual state, x2 and y are set by the contract
+	virtualState.Variables().Set(kv.Key(hname.Bytes())+"x1", []byte{1})
+
+	s := newStateWrapper(hname, virtualState, stateUpdate)
+	s.Set("x2", []byte{2})
+	s.Set("y", []byte{3})
+
+	s.DelPrefix("x")
+
+	assert.False(t, s.MustHas("x1"))
+	assert.False(t, s.MustHas("x2"))
+	assert.Equal(t, []byte{3}, s.MustGet("y"))
+
+	n := 0
+	s.MustIterateKeys(kv.EmptyPrefix, func(k kv.Key) bool {
+		assert.EqualValues(t, "y", string(k))
+		n++
+		return true
+	})
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmhost
+
+import (
+	"fmt"
+	"sort"
+)
+
+// names of the Wasm engines which can run the smart contracts
+const (
+	EngineWasmTime    = "wasmtime"
+	EngineInterpreter = "interpreter"
+)
+
+// engines contains the constructors of the engines available in this build.
+// Wasmtime needs cgo, so it is registered only when the node is built with cgo enabled
+var engines = map[string]func() WasmVM{
+	EngineInterpreter: func() WasmVM { return NewWasmInterpVM() },
+}
+
+// NewWasmVM creates new VM which runs the Wasm code with the engine
+func NewWasmVM(engine string) (WasmVM, error) {
+	if engine == "" {
+		engine = DefaultEngine()
+	}
+	constructor, ok := engines[engine]
+	if !ok {
+		return nil, fmt.Errorf("unknown Wasm engine '%s', available: %v", engine, Engines())
+	}
+	return constructor(), nil
+}
+
+// DefaultEngine returns Wasmtime if it is available in this build, otherwise the interpreter
+func DefaultEngine() string {
+	if HasEngine(EngineWasmTime) {
+		return EngineWasmTime
+	}
+	return EngineInterpreter
+}
+
+// HasEngine checks if the engine is available in this build
+func HasEngine(engine string) bool {
+	_, ok := engines[engine]
+	return ok
+}
+
+// Engines returns the names of the engines available in this build
+func Engines() []string {
+	ret := make([]string, 0, len(engines))
+	for name := range engines {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
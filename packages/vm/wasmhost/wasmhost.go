@@ -7,6 +7,9 @@ import (
 	"errors"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/wasminterp"
+	"github.com/iotaledger/wasp/packages/vm/wasmvalidate"
 )
 
 type WasmHost struct {
@@ -15,6 +18,36 @@ type WasmHost struct {
 	codeToFunc  map[uint32]string
 	funcToCode  map[string]uint32
 	funcToIndex map[string]int32
+	gasMeter    GasMeter
+	fuel        int64
+}
+
+// GasMeter is charged for the gas burned by the Wasm code. The sandbox implements it
+type GasMeter interface {
+	// GasBurn charges the gas. It panics with vm.ErrGasExhausted when the budget is exhausted
+	GasBurn(gas uint64)
+	GasRemaining() uint64
+}
+
+// budgetGasMeter is the gas meter with the fixed budget which is not charged to anybody
+type budgetGasMeter struct {
+	remaining uint64
+}
+
+func NewBudgetGasMeter(budget uint64) GasMeter {
+	return &budgetGasMeter{remaining: budget}
+}
+
+func (m *budgetGasMeter) GasBurn(gas uint64) {
+	if m.remaining < gas {
+		m.remaining = 0
+		panic(vm.ErrGasExhausted)
+	}
+	m.remaining -= gas
+}
+
+func (m *budgetGasMeter) GasRemaining() uint64 {
+	return m.remaining
 }
 
 func (host *WasmHost) InitVM(vm WasmVM, useBase58Keys bool) error {
@@ -27,6 +60,51 @@ func (host *WasmHost) Init(null HostObject, root HostObject, log *logger.Logger)
 	host.codeToFunc = make(map[uint32]string)
 	host.funcToCode = make(map[string]uint32)
 	host.funcToIndex = make(map[string]int32)
+	host.SetGasMeter(nil)
+}
+
+// GasMeter returns the meter which is charged for the gas burned by the Wasm code
+func (host *WasmHost) GasMeter() GasMeter {
+	return host.gasMeter
+}
+
+// SetGasMeter sets the meter which is charged for the executed Wasm instructions and for the calls
+// from the Wasm code to the host. nil means the fixed budget vm.GasBudgetView
+func (host *WasmHost) SetGasMeter(meter GasMeter) {
+	if meter == nil {
+		meter = NewBudgetGasMeter(vm.GasBudgetView)
+	}
+	host.gasMeter = meter
+}
+
+// refuel sets the fuel of the Wasm code to the gas remaining in the meter
+func (host *WasmHost) refuel() {
+	host.fuel = int64(host.gasMeter.GasRemaining() / vm.GasWasmInstruction)
+	host.vm.SetFuel(host.fuel)
+}
+
+// burnFuel charges the meter for the Wasm instructions executed since the last refuel
+func (host *WasmHost) burnFuel() {
+	fuel := host.vm.Fuel()
+	if fuel < 0 {
+		// the Wasm code trapped because it ran out of fuel
+		host.gasMeter.GasBurn(host.gasMeter.GasRemaining() + 1)
+		panic(vm.ErrGasExhausted)
+	}
+	host.gasMeter.GasBurn(uint64(host.fuel-fuel) * vm.GasWasmInstruction)
+	host.fuel = fuel
+}
+
+// enterHost is called when the Wasm code calls the host
+func (host *WasmHost) enterHost() {
+	host.burnFuel()
+	host.gasMeter.GasBurn(vm.GasWasmHostCall)
+}
+
+// leaveHost is called when the host returns to the Wasm code. The host may have burned gas
+// or run other Wasm code of the same instance in the meantime
+func (host *WasmHost) leaveHost() {
+	host.refuel()
 }
 
 func (host *WasmHost) FunctionFromCode(code uint32) string {
@@ -37,11 +115,30 @@ func (host *WasmHost) IsView(function string) bool {
 	return (host.funcToIndex[function] & 0x8000) != 0
 }
 
-func (host *WasmHost) LoadWasm(wasmData []byte) error {
-	err := host.vm.LoadWasm(wasmData)
+// LoadWasm loads the Wasm code with the memory limited by wasminterp.LimitMemory and metered by
+// wasminterp.InjectFuelMetering and runs its 'on_load'
+func (host *WasmHost) LoadWasm(wasmData []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if r != vm.ErrGasExhausted {
+				panic(r)
+			}
+			err = errors.New("on_load: " + vm.ErrGasExhausted.Error())
+		}
+	}()
+	wasmData, err = wasminterp.LimitMemory(wasmData, wasmvalidate.MaxMemoryPages)
+	if err != nil {
+		return err
+	}
+	wasmData, err = wasminterp.InjectFuelMetering(wasmData)
+	if err != nil {
+		return err
+	}
+	err = host.vm.LoadWasm(wasmData)
 	if err != nil {
 		return err
 	}
+	host.SetGasMeter(nil)
 	err = host.RunFunction("on_load")
 	if err != nil {
 		return err
@@ -51,7 +148,10 @@ func (host *WasmHost) LoadWasm(wasmData []byte) error {
 }
 
 func (host *WasmHost) RunFunction(functionName string) (err error) {
-	return host.vm.RunFunction(functionName)
+	host.refuel()
+	err = host.vm.RunFunction(functionName)
+	host.burnFuel()
+	return err
 }
 
 func (host *WasmHost) RunScFunction(functionName string) (err error) {
@@ -59,7 +159,10 @@ func (host *WasmHost) RunScFunction(functionName string) (err error) {
 	if !ok {
 		return errors.New("unknown SC function name: " + functionName)
 	}
-	return host.vm.RunScFunction(index)
+	host.refuel()
+	err = host.vm.RunScFunction(index)
+	host.burnFuel()
+	return err
 }
 
 func (host *WasmHost) SetExport(index int32, functionName string) {
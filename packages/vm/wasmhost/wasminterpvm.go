@@ -0,0 +1,126 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmhost
+
+import (
+	"errors"
+
+	"github.com/iotaledger/wasp/packages/vm/wasminterp"
+)
+
+// WasmInterpVM runs the Wasm code with the pure Go interpreter. It doesn't need cgo
+type WasmInterpVM struct {
+	WasmVmBase
+	instance *wasminterp.Instance
+	linker   *wasminterp.Linker
+	fuel     uint32
+}
+
+func NewWasmInterpVM() *WasmInterpVM {
+	vm := &WasmInterpVM{}
+	vm.linker = wasminterp.NewLinker()
+	return vm
+}
+
+func i32Types(n int) []wasminterp.ValueType {
+	ret := make([]wasminterp.ValueType, n)
+	for i := range ret {
+		ret[i] = wasminterp.I32
+	}
+	return ret
+}
+
+func i32Func(params int, results int) wasminterp.FuncType {
+	return wasminterp.FuncType{Params: i32Types(params), Results: i32Types(results)}
+}
+
+func i32Result(ret int32) []uint64 {
+	return []uint64{uint64(uint32(ret))}
+}
+
+func (vm *WasmInterpVM) LinkHost(impl WasmVM, host *WasmHost) error {
+	vm.WasmVmBase.LinkHost(impl, host)
+	err := vm.linker.DefineFunc("wasplib", "hostGetBytes", i32Func(5, 1),
+		func(args []uint64) []uint64 {
+			return i32Result(vm.HostGetBytes(int32(args[0]), int32(args[1]), int32(args[2]), int32(args[3]), int32(args[4])))
+		})
+	if err != nil {
+		return err
+	}
+	err = vm.linker.DefineFunc("wasplib", "hostGetKeyId", i32Func(2, 1),
+		func(args []uint64) []uint64 {
+			return i32Result(vm.HostGetKeyId(int32(args[0]), int32(args[1])))
+		})
+	if err != nil {
+		return err
+	}
+	err = vm.linker.DefineFunc("wasplib", "hostGetObjectId", i32Func(3, 1),
+		func(args []uint64) []uint64 {
+			return i32Result(vm.HostGetObjectId(int32(args[0]), int32(args[1]), int32(args[2])))
+		})
+	if err != nil {
+		return err
+	}
+	err = vm.linker.DefineFunc("wasplib", "hostSetBytes", i32Func(5, 0),
+		func(args []uint64) []uint64 {
+			vm.HostSetBytes(int32(args[0]), int32(args[1]), int32(args[2]), int32(args[3]), int32(args[4]))
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+	// go implementation uses this one to write panic message
+	err = vm.linker.DefineFunc("wasi_unstable", "fd_write", i32Func(4, 1),
+		func(args []uint64) []uint64 {
+			return i32Result(vm.HostFdWrite(int32(args[0]), int32(args[1]), int32(args[2]), int32(args[3])))
+		})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (vm *WasmInterpVM) LoadWasm(wasmData []byte) error {
+	module, err := wasminterp.Parse(wasmData)
+	if err != nil {
+		return err
+	}
+	export := module.Export("memory")
+	if export == nil {
+		return errors.New("no memory export")
+	}
+	if export.Kind != wasminterp.ExternalMemory {
+		return errors.New("not a memory type")
+	}
+	vm.instance, err = vm.linker.Instantiate(module)
+	if err != nil {
+		return err
+	}
+	vm.fuel, err = vm.instance.GlobalIndex(wasminterp.FuelGlobal)
+	return err
+}
+
+func (vm *WasmInterpVM) RunFunction(functionName string) error {
+	_, err := vm.instance.Call(functionName)
+	return err
+}
+
+func (vm *WasmInterpVM) RunScFunction(index int32) error {
+	frame := vm.PreCall()
+	_, err := vm.instance.Call("on_call_entrypoint", uint64(uint32(index)))
+	vm.PostCall(frame)
+	return err
+}
+
+func (vm *WasmInterpVM) Fuel() int64 {
+	return int64(vm.instance.Global(vm.fuel))
+}
+
+func (vm *WasmInterpVM) SetFuel(fuel int64) {
+	vm.instance.SetGlobal(vm.fuel, uint64(fuel))
+}
+
+func (vm *WasmInterpVM) UnsafeMemory() []byte {
+	return vm.instance.Memory()
+}
@@ -1,15 +1,19 @@
 // Copyright 2020 IOTA Stiftung
 // SPDX-License-Identifier: Apache-2.0
 
+// +build cgo
+
 package wasmhost
 
 import (
 	"errors"
 	"github.com/bytecodealliance/wasmtime-go"
+	"github.com/iotaledger/wasp/packages/vm/wasminterp"
 )
 
 type WasmTimeVM struct {
 	WasmVmBase
+	fuel     *wasmtime.Global
 	instance *wasmtime.Instance
 	linker   *wasmtime.Linker
 	memory   *wasmtime.Memory
@@ -17,6 +21,10 @@ type WasmTimeVM struct {
 	store    *wasmtime.Store
 }
 
+func init() {
+	engines[EngineWasmTime] = func() WasmVM { return NewWasmTimeVM() }
+}
+
 func NewWasmTimeVM() *WasmTimeVM {
 	vm := &WasmTimeVM{}
 	vm.store = wasmtime.NewStore(wasmtime.NewEngine())
@@ -83,6 +91,11 @@ func (vm *WasmTimeVM) LoadWasm(wasmData []byte) error {
 	if vm.memory == nil {
 		return errors.New("not a memory type")
 	}
+	fuel := vm.instance.GetExport(wasminterp.FuelGlobal)
+	if fuel == nil || fuel.Global() == nil {
+		return errors.New("no fuel global export")
+	}
+	vm.fuel = fuel.Global()
 	return nil
 }
 
@@ -106,6 +119,16 @@ func (vm *WasmTimeVM) RunScFunction(index int32) error {
 	return err
 }
 
+func (vm *WasmTimeVM) Fuel() int64 {
+	return vm.fuel.Get().I64()
+}
+
+func (vm *WasmTimeVM) SetFuel(fuel int64) {
+	if err := vm.fuel.Set(wasmtime.ValI64(fuel)); err != nil {
+		panic(err)
+	}
+}
+
 func (vm *WasmTimeVM) UnsafeMemory() []byte {
 	return vm.memory.UnsafeData()
 }
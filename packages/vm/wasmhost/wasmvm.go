@@ -13,6 +13,9 @@ type WasmVM interface {
 	LoadWasm(wasmData []byte) error
 	RunFunction(functionName string) error
 	RunScFunction(index int32) error
+	// Fuel returns the value of the fuel global injected by wasminterp.InjectFuelMetering
+	Fuel() int64
+	SetFuel(fuel int64)
 	UnsafeMemory() []byte
 	SaveMemory()
 }
@@ -33,6 +36,8 @@ func (vm *WasmVmBase) LinkHost(impl WasmVM, host *WasmHost) error {
 }
 
 func (vm *WasmVmBase) HostFdWrite(fd int32, iovs int32, size int32, written int32) int32 {
+	vm.host.enterHost()
+	defer vm.host.leaveHost()
 	vm.host.TraceAll("HostFdWrite(...)")
 	// very basic implementation that expects fd to be stdout and iovs to be only one element
 	ptr := vm.impl.UnsafeMemory()
@@ -44,6 +49,8 @@ func (vm *WasmVmBase) HostFdWrite(fd int32, iovs int32, size int32, written int3
 }
 
 func (vm *WasmVmBase) HostGetBytes(objId int32, keyId int32, typeId int32, stringRef int32, size int32) int32 {
+	vm.host.enterHost()
+	defer vm.host.leaveHost()
 	host := vm.host
 	host.TraceAll("HostGetBytes(o%d,k%d,t%d,r%d,s%d)", objId, keyId, typeId, stringRef, size)
 
@@ -64,6 +71,8 @@ func (vm *WasmVmBase) HostGetBytes(objId int32, keyId int32, typeId int32, strin
 }
 
 func (vm *WasmVmBase) HostGetKeyId(keyRef int32, size int32) int32 {
+	vm.host.enterHost()
+	defer vm.host.leaveHost()
 	host := vm.host
 	host.TraceAll("HostGetKeyId(r%d,s%d)", keyRef, size)
 	// non-negative size means original key was a string
@@ -78,12 +87,16 @@ func (vm *WasmVmBase) HostGetKeyId(keyRef int32, size int32) int32 {
 }
 
 func (vm *WasmVmBase) HostGetObjectId(objId int32, keyId int32, typeId int32) int32 {
+	vm.host.enterHost()
+	defer vm.host.leaveHost()
 	host := vm.host
 	host.TraceAll("HostGetObjectId(o%d,k%d,t%d)", objId, keyId, typeId)
 	return host.GetObjectId(objId, keyId, typeId)
 }
 
 func (vm *WasmVmBase) HostSetBytes(objId int32, keyId int32, typeId int32, stringRef int32, size int32) {
+	vm.host.enterHost()
+	defer vm.host.leaveHost()
 	host := vm.host
 	host.TraceAll("HostSetBytes(o%d,k%d,t%d,r%d,s%d)", objId, keyId, typeId, stringRef, size)
 	bytes := vm.vmGetBytes(stringRef, size)
@@ -0,0 +1,623 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasminterp
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	maxLocals = 50000
+	// maximal height of the operand stack of a function
+	maxFunctionHeight = 1 << 16
+)
+
+// instr is the instruction of the compiled code. Blocks are compiled to jumps to the resolved addresses:
+// br, br_if: a = target pc, b = stack height at the target, c = number of the kept values
+// br_table: a = index of the table of targets, c = number of the kept values
+// opJump, opJumpIfNot: a = target pc
+// memory instructions: a = offset
+// numeric instructions: c = number of the operands
+// other instructions: a = immediate value or index
+type instr struct {
+	op Opcode
+	a  uint64
+	b  uint32
+	c  uint32
+}
+
+type branchTarget struct {
+	pc     uint32
+	height uint32
+}
+
+type compiledCode struct {
+	instrs    []instr
+	brTables  [][]branchTarget
+	numParams int
+	locals    []ValueType
+	// maximal height of the operand stack
+	maxHeight int
+}
+
+type ctrlFrame struct {
+	op          Opcode
+	params      []ValueType
+	results     []ValueType
+	height      int
+	unreachable bool
+	// pc of the first instruction of the loop
+	startPC uint32
+	// branches to the end of the block
+	fixups []fixup
+	// the jump to else of the 'if' block
+	ifInstr int
+}
+
+// fixup is the branch instruction or the br_table entry, resolved at the end of the block
+type fixup struct {
+	instr int
+	table int
+	entry int
+}
+
+func (f *ctrlFrame) labelTypes() []ValueType {
+	if f.op == opLoop {
+		return f.params
+	}
+	return f.results
+}
+
+// unknownType is the type of the values of the polymorphic stack in the unreachable code
+const unknownType ValueType = 0
+
+// compiler validates the code of the function according to the validation algorithm of the specification
+// and compiles it
+type compiler struct {
+	m      *Module
+	r      *reader
+	locals []ValueType
+	vals   []ValueType
+	ctrls  []ctrlFrame
+	code   *compiledCode
+}
+
+func compile(m *Module, f *Function) (*compiledCode, error) {
+	ft := &m.Types[f.TypeIndex]
+	c := &compiler{
+		m: m,
+		r: &reader{data: f.Body},
+		code: &compiledCode{
+			numParams: len(ft.Params),
+			locals:    f.Locals,
+		},
+	}
+	c.locals = append(append(c.locals, ft.Params...), f.Locals...)
+	if len(c.locals) > maxLocals {
+		return nil, errors.New("too many locals")
+	}
+	c.pushCtrl(opBlock, nil, ft.Results)
+	for len(c.ctrls) > 0 {
+		if c.r.eof() {
+			return nil, errors.New("unexpected end of the code")
+		}
+		if err := c.compileInstr(); err != nil {
+			return nil, fmt.Errorf("offset %d: %v", c.r.pos, err)
+		}
+	}
+	if !c.r.eof() {
+		return nil, errors.New("unexpected code after the end of the function")
+	}
+	return c.code, nil
+}
+
+func (c *compiler) pc() uint32 {
+	return uint32(len(c.code.instrs))
+}
+
+func (c *compiler) emit(op Opcode, a uint64, b, cc uint32) int {
+	c.code.instrs = append(c.code.instrs, instr{op: op, a: a, b: b, c: cc})
+	return len(c.code.instrs) - 1
+}
+
+func (c *compiler) pushVal(t ValueType) error {
+	c.vals = append(c.vals, t)
+	if len(c.vals) > c.code.maxHeight {
+		c.code.maxHeight = len(c.vals)
+		if c.code.maxHeight > maxFunctionHeight {
+			return errors.New("operand stack too high")
+		}
+	}
+	return nil
+}
+
+func (c *compiler) pushVals(types []ValueType) error {
+	for _, t := range types {
+		if err := c.pushVal(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *compiler) popVal() (ValueType, error) {
+	frame := &c.ctrls[len(c.ctrls)-1]
+	if len(c.vals) == frame.height {
+		if frame.unreachable {
+			return unknownType, nil
+		}
+		return 0, errors.New("operand stack underflow")
+	}
+	ret := c.vals[len(c.vals)-1]
+	c.vals = c.vals[:len(c.vals)-1]
+	return ret, nil
+}
+
+func (c *compiler) popExpect(expected ValueType) (ValueType, error) {
+	actual, err := c.popVal()
+	if err != nil {
+		return 0, err
+	}
+	if actual == unknownType {
+		return expected, nil
+	}
+	if expected != unknownType && actual != expected {
+		return 0, fmt.Errorf("type mismatch: expected %s, got %s", expected, actual)
+	}
+	return actual, nil
+}
+
+func (c *compiler) popVals(types []ValueType) error {
+	for i := len(types) - 1; i >= 0; i-- {
+		if _, err := c.popExpect(types[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *compiler) pushCtrl(op Opcode, params, results []ValueType) {
+	c.ctrls = append(c.ctrls, ctrlFrame{
+		op:      op,
+		params:  params,
+		results: results,
+		height:  len(c.vals),
+		startPC: c.pc(),
+		ifInstr: -1,
+	})
+	// the parameters have been validated by the caller
+	_ = c.pushVals(params)
+}
+
+func (c *compiler) popCtrl() (*ctrlFrame, error) {
+	frame := c.ctrls[len(c.ctrls)-1]
+	if err := c.popVals(frame.results); err != nil {
+		return nil, err
+	}
+	if len(c.vals) != frame.height {
+		return nil, errors.New("values remaining on the stack at the end of the block")
+	}
+	c.ctrls = c.ctrls[:len(c.ctrls)-1]
+	return &frame, nil
+}
+
+func (c *compiler) setUnreachable() {
+	frame := &c.ctrls[len(c.ctrls)-1]
+	c.vals = c.vals[:frame.height]
+	frame.unreachable = true
+}
+
+func (c *compiler) readBlockType() ([]ValueType, []ValueType, error) {
+	b, err := c.r.byte()
+	if err != nil {
+		return nil, nil, err
+	}
+	switch t := ValueType(b); t {
+	case 0x40:
+		return nil, nil, nil
+	case I32, I64, F32, F64:
+		return nil, []ValueType{t}, nil
+	}
+	c.r.pos--
+	index, err := c.r.s64()
+	if err != nil {
+		return nil, nil, err
+	}
+	if index < 0 || index >= int64(len(c.m.Types)) {
+		return nil, nil, fmt.Errorf("invalid block type %d", index)
+	}
+	ft := &c.m.Types[index]
+	return ft.Params, ft.Results, nil
+}
+
+// branch emits the branch to the label of the depth. It returns the types of the label
+func (c *compiler) branch(op Opcode, depth uint32) ([]ValueType, error) {
+	if depth >= uint32(len(c.ctrls)) {
+		return nil, fmt.Errorf("invalid branch depth %d", depth)
+	}
+	frame := &c.ctrls[len(c.ctrls)-1-int(depth)]
+	types := frame.labelTypes()
+	i := c.emit(op, uint64(frame.startPC), uint32(frame.height), uint32(len(types)))
+	if frame.op != opLoop {
+		frame.fixups = append(frame.fixups, fixup{instr: i, table: -1})
+	}
+	return types, nil
+}
+
+func (c *compiler) resolve(fixups []fixup) {
+	for _, f := range fixups {
+		if f.table < 0 {
+			c.code.instrs[f.instr].a = uint64(c.pc())
+		} else {
+			c.code.brTables[f.table][f.entry].pc = c.pc()
+		}
+	}
+}
+
+func (c *compiler) readMemArg(size uint32) (uint64, error) {
+	if c.m.Memory == nil {
+		return 0, errors.New("no memory")
+	}
+	align, err := c.r.u32()
+	if err != nil {
+		return 0, err
+	}
+	if align >= 32 || 1<<align > size {
+		return 0, errors.New("alignment must not be larger than natural")
+	}
+	offset, err := c.r.u32()
+	return uint64(offset), err
+}
+
+func (c *compiler) readZeroByte() error {
+	b, err := c.r.byte()
+	if err != nil {
+		return err
+	}
+	if b != 0 {
+		return errors.New("zero byte expected")
+	}
+	return nil
+}
+
+func (c *compiler) compileInstr() error {
+	b, err := c.r.byte()
+	if err != nil {
+		return err
+	}
+	op := Opcode(b)
+	if op == opPrefixFC {
+		sub, err := c.r.u32()
+		if err != nil {
+			return err
+		}
+		if sub > 0xff {
+			return fmt.Errorf("unknown instruction 0xfc %d", sub)
+		}
+		op = 0xfc00 | Opcode(sub)
+	}
+
+	if sig, ok := numericSignatures[op]; ok {
+		if err := c.popVals(sig.params); err != nil {
+			return fmt.Errorf("%s: %v", op, err)
+		}
+		c.emit(op, 0, 0, uint32(len(sig.params)))
+		return c.pushVal(sig.result)
+	}
+	if op >= opI32Load && op <= opI64Store32 {
+		t, size, store := memorySignature(op)
+		offset, err := c.readMemArg(size)
+		if err != nil {
+			return fmt.Errorf("%s: %v", op, err)
+		}
+		if store {
+			if _, err := c.popExpect(t); err != nil {
+				return fmt.Errorf("%s: %v", op, err)
+			}
+		}
+		if _, err := c.popExpect(I32); err != nil {
+			return fmt.Errorf("%s: %v", op, err)
+		}
+		c.emit(op, offset, 0, 0)
+		if !store {
+			return c.pushVal(t)
+		}
+		return nil
+	}
+
+	switch op {
+	case opUnreachable:
+		c.emit(op, 0, 0, 0)
+		c.setUnreachable()
+	case opNop:
+	case opBlock, opLoop, opIf:
+		params, results, err := c.readBlockType()
+		if err != nil {
+			return err
+		}
+		if op == opIf {
+			if _, err := c.popExpect(I32); err != nil {
+				return fmt.Errorf("if: %v", err)
+			}
+		}
+		if err := c.popVals(params); err != nil {
+			return fmt.Errorf("%s: %v", op, err)
+		}
+		ifInstr := -1
+		if op == opIf {
+			ifInstr = c.emit(opJumpIfNot, 0, 0, 0)
+		}
+		c.pushCtrl(op, params, results)
+		c.ctrls[len(c.ctrls)-1].ifInstr = ifInstr
+	case opElse:
+		frame := &c.ctrls[len(c.ctrls)-1]
+		if frame.op != opIf {
+			return errors.New("else without if")
+		}
+		fr, err := c.popCtrl()
+		if err != nil {
+			return fmt.Errorf("else: %v", err)
+		}
+		fixups := append(fr.fixups, fixup{instr: c.emit(opJump, 0, 0, 0), table: -1})
+		c.code.instrs[fr.ifInstr].a = uint64(c.pc())
+		c.pushCtrl(opElse, fr.params, fr.results)
+		c.ctrls[len(c.ctrls)-1].fixups = fixups
+	case opEnd:
+		fr, err := c.popCtrl()
+		if err != nil {
+			return fmt.Errorf("end: %v", err)
+		}
+		if fr.op == opIf {
+			if !equalTypes(fr.params, fr.results) {
+				return errors.New("if without else must have the same parameters and results")
+			}
+			c.code.instrs[fr.ifInstr].a = uint64(c.pc())
+		}
+		c.resolve(fr.fixups)
+		if len(c.ctrls) == 0 {
+			// end of the function
+			c.emit(opReturn, 0, 0, uint32(len(fr.results)))
+			return nil
+		}
+		return c.pushVals(fr.results)
+	case opBr:
+		depth, err := c.r.u32()
+		if err != nil {
+			return err
+		}
+		types, err := c.branch(opBr, depth)
+		if err != nil {
+			return err
+		}
+		if err := c.popVals(types); err != nil {
+			return fmt.Errorf("br: %v", err)
+		}
+		c.setUnreachable()
+	case opBrIf:
+		depth, err := c.r.u32()
+		if err != nil {
+			return err
+		}
+		if _, err := c.popExpect(I32); err != nil {
+			return fmt.Errorf("br_if: %v", err)
+		}
+		types, err := c.branch(opBrIf, depth)
+		if err != nil {
+			return err
+		}
+		if err := c.popVals(types); err != nil {
+			return fmt.Errorf("br_if: %v", err)
+		}
+		return c.pushVals(types)
+	case opBrTable:
+		return c.compileBrTable()
+	case opReturn:
+		results := c.ctrls[0].results
+		if err := c.popVals(results); err != nil {
+			return fmt.Errorf("return: %v", err)
+		}
+		c.emit(opReturn, 0, 0, uint32(len(results)))
+		c.setUnreachable()
+	case opCall:
+		index, err := c.r.u32()
+		if err != nil {
+			return err
+		}
+		if index >= c.m.NumFunctions() {
+			return fmt.Errorf("call: invalid function index %d", index)
+		}
+		ft := c.m.FunctionType(index)
+		if err := c.popVals(ft.Params); err != nil {
+			return fmt.Errorf("call: %v", err)
+		}
+		c.emit(op, uint64(index), 0, 0)
+		return c.pushVals(ft.Results)
+	case opCallIndirect:
+		typeIndex, err := c.r.u32()
+		if err != nil {
+			return err
+		}
+		if err := c.readZeroByte(); err != nil {
+			return err
+		}
+		if c.m.Table == nil {
+			return errors.New("call_indirect: no table")
+		}
+		if typeIndex >= uint32(len(c.m.Types)) {
+			return fmt.Errorf("call_indirect: invalid type index %d", typeIndex)
+		}
+		if _, err := c.popExpect(I32); err != nil {
+			return fmt.Errorf("call_indirect: %v", err)
+		}
+		ft := &c.m.Types[typeIndex]
+		if err := c.popVals(ft.Params); err != nil {
+			return fmt.Errorf("call_indirect: %v", err)
+		}
+		c.emit(op, uint64(typeIndex), 0, 0)
+		return c.pushVals(ft.Results)
+	case opDrop:
+		if _, err := c.popVal(); err != nil {
+			return fmt.Errorf("drop: %v", err)
+		}
+		c.emit(op, 0, 0, 0)
+	case opSelect:
+		if _, err := c.popExpect(I32); err != nil {
+			return fmt.Errorf("select: %v", err)
+		}
+		t1, err := c.popVal()
+		if err != nil {
+			return fmt.Errorf("select: %v", err)
+		}
+		t2, err := c.popExpect(t1)
+		if err != nil {
+			return fmt.Errorf("select: %v", err)
+		}
+		c.emit(op, 0, 0, 0)
+		return c.pushVal(t2)
+	case opLocalGet, opLocalSet, opLocalTee:
+		index, err := c.r.u32()
+		if err != nil {
+			return err
+		}
+		if index >= uint32(len(c.locals)) {
+			return fmt.Errorf("%s: invalid local index %d", op, index)
+		}
+		t := c.locals[index]
+		if op != opLocalGet {
+			if _, err := c.popExpect(t); err != nil {
+				return fmt.Errorf("%s: %v", op, err)
+			}
+		}
+		c.emit(op, uint64(index), 0, 0)
+		if op != opLocalSet {
+			return c.pushVal(t)
+		}
+	case opGlobalGet, opGlobalSet:
+		index, err := c.r.u32()
+		if err != nil {
+			return err
+		}
+		if index >= uint32(len(c.m.Globals)) {
+			return fmt.Errorf("%s: invalid global index %d", op, index)
+		}
+		g := &c.m.Globals[index]
+		c.emit(op, uint64(index), 0, 0)
+		if op == opGlobalGet {
+			return c.pushVal(g.Type)
+		}
+		if !g.Mutable {
+			return errors.New("global.set: the global is immutable")
+		}
+		if _, err := c.popExpect(g.Type); err != nil {
+			return fmt.Errorf("global.set: %v", err)
+		}
+	case opMemorySize, opMemoryGrow:
+		if err := c.readZeroByte(); err != nil {
+			return err
+		}
+		if c.m.Memory == nil {
+			return fmt.Errorf("%s: no memory", op)
+		}
+		if op == opMemoryGrow {
+			if _, err := c.popExpect(I32); err != nil {
+				return fmt.Errorf("memory.grow: %v", err)
+			}
+		}
+		c.emit(op, 0, 0, 0)
+		return c.pushVal(I32)
+	case opMemoryCopy, opMemoryFill:
+		if err := c.readZeroByte(); err != nil {
+			return err
+		}
+		if op == opMemoryCopy {
+			if err := c.readZeroByte(); err != nil {
+				return err
+			}
+		}
+		if c.m.Memory == nil {
+			return fmt.Errorf("%s: no memory", op)
+		}
+		if err := c.popVals([]ValueType{I32, I32, I32}); err != nil {
+			return fmt.Errorf("%s: %v", op, err)
+		}
+		c.emit(op, 0, 0, 0)
+	case opI32Const:
+		v, err := c.r.s32()
+		if err != nil {
+			return err
+		}
+		c.emit(op, uint64(uint32(v)), 0, 0)
+		return c.pushVal(I32)
+	case opI64Const:
+		v, err := c.r.s64()
+		if err != nil {
+			return err
+		}
+		c.emit(op, uint64(v), 0, 0)
+		return c.pushVal(I64)
+	case opF32Const:
+		v, err := c.r.f32()
+		if err != nil {
+			return err
+		}
+		c.emit(op, uint64(v), 0, 0)
+		return c.pushVal(F32)
+	case opF64Const:
+		v, err := c.r.f64()
+		if err != nil {
+			return err
+		}
+		c.emit(op, v, 0, 0)
+		return c.pushVal(F64)
+	default:
+		return fmt.Errorf("unsupported instruction %s", op)
+	}
+	return nil
+}
+
+func (c *compiler) compileBrTable() error {
+	n, err := readVectorLength(c.r)
+	if err != nil {
+		return err
+	}
+	depths := make([]uint32, n+1)
+	for i := range depths {
+		if depths[i], err = c.r.u32(); err != nil {
+			return err
+		}
+	}
+	if _, err := c.popExpect(I32); err != nil {
+		return fmt.Errorf("br_table: %v", err)
+	}
+	tableIndex := len(c.code.brTables)
+	targets := make([]branchTarget, len(depths))
+	var arity int
+	for i, depth := range depths {
+		if depth >= uint32(len(c.ctrls)) {
+			return fmt.Errorf("br_table: invalid branch depth %d", depth)
+		}
+		frame := &c.ctrls[len(c.ctrls)-1-int(depth)]
+		types := frame.labelTypes()
+		if i == 0 {
+			arity = len(types)
+		} else if len(types) != arity {
+			return errors.New("br_table: inconsistent label arities")
+		}
+		// the types of each label are checked against the stack
+		saved := append([]ValueType(nil), c.vals...)
+		if err := c.popVals(types); err != nil {
+			return fmt.Errorf("br_table: %v", err)
+		}
+		c.vals = saved
+		targets[i] = branchTarget{pc: frame.startPC, height: uint32(frame.height)}
+		if frame.op != opLoop {
+			frame.fixups = append(frame.fixups, fixup{table: tableIndex, entry: i})
+		}
+	}
+	c.code.brTables = append(c.code.brTables, targets)
+	c.emit(opBrTable, uint64(tableIndex), 0, uint32(arity))
+	c.setUnreachable()
+	return nil
+}
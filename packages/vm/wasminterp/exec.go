@@ -0,0 +1,731 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasminterp
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+func b2i(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func f32(v uint64) float32 {
+	return math.Float32frombits(uint32(v))
+}
+
+func f32bits(f float32) uint64 {
+	return uint64(math.Float32bits(f))
+}
+
+func f64(v uint64) float64 {
+	return math.Float64frombits(v)
+}
+
+func f64bits(f float64) uint64 {
+	return math.Float64bits(f)
+}
+
+// call executes the function. The arguments are on the top of the stack, they are replaced by the results
+func (inst *Instance) call(index uint32) {
+	m := inst.module
+	if index < uint32(len(m.Imports)) {
+		inst.callHost(index)
+		return
+	}
+	code := m.Functions[index-uint32(len(m.Imports))].code
+	if inst.depth >= maxCallDepth {
+		panic(trap("call stack exhausted"))
+	}
+	inst.depth++
+
+	locals := inst.sp - code.numParams
+	inst.ensureStack(inst.sp + len(code.locals) + code.maxHeight)
+	stack := inst.stack
+	sp := inst.sp
+	for range code.locals {
+		stack[sp] = 0
+		sp++
+	}
+	base := sp
+	mem := inst.memory
+	instrs := code.instrs
+	pc := 0
+	for {
+		in := &instrs[pc]
+		pc++
+		switch in.op {
+		case opUnreachable:
+			panic(trap("unreachable"))
+		case opJump:
+			pc = int(in.a)
+		case opJumpIfNot:
+			sp--
+			if uint32(stack[sp]) == 0 {
+				pc = int(in.a)
+			}
+		case opBr:
+			sp = branch(stack, sp, base+int(in.b), int(in.c))
+			pc = int(in.a)
+		case opBrIf:
+			sp--
+			if uint32(stack[sp]) != 0 {
+				sp = branch(stack, sp, base+int(in.b), int(in.c))
+				pc = int(in.a)
+			}
+		case opBrTable:
+			sp--
+			i := uint32(stack[sp])
+			targets := code.brTables[in.a]
+			if i >= uint32(len(targets)-1) {
+				i = uint32(len(targets) - 1)
+			}
+			t := &targets[i]
+			sp = branch(stack, sp, base+int(t.height), int(in.c))
+			pc = int(t.pc)
+		case opReturn:
+			n := int(in.c)
+			copy(stack[locals:], stack[sp-n:sp])
+			inst.sp = locals + n
+			inst.depth--
+			return
+		case opCall:
+			inst.sp = sp
+			inst.call(uint32(in.a))
+			stack, sp, mem = inst.stack, inst.sp, inst.memory
+		case opCallIndirect:
+			sp--
+			i := uint32(stack[sp])
+			if i >= uint32(len(inst.table)) {
+				panic(trap("undefined element"))
+			}
+			fi := inst.table[i]
+			if fi == nullElement {
+				panic(trap("uninitialized element"))
+			}
+			if !m.FunctionType(fi).Equal(&m.Types[in.a]) {
+				panic(trap("indirect call type mismatch"))
+			}
+			inst.sp = sp
+			inst.call(fi)
+			stack, sp, mem = inst.stack, inst.sp, inst.memory
+		case opDrop:
+			sp--
+		case opSelect:
+			sp -= 2
+			if uint32(stack[sp+1]) == 0 {
+				stack[sp-1] = stack[sp]
+			}
+		case opLocalGet:
+			stack[sp] = stack[locals+int(in.a)]
+			sp++
+		case opLocalSet:
+			sp--
+			stack[locals+int(in.a)] = stack[sp]
+		case opLocalTee:
+			stack[locals+int(in.a)] = stack[sp-1]
+		case opGlobalGet:
+			stack[sp] = inst.globals[in.a]
+			sp++
+		case opGlobalSet:
+			sp--
+			inst.globals[in.a] = stack[sp]
+
+		// memory
+		case 0x28, 0x2a: // i32.load, f32.load
+			ea := uint64(uint32(stack[sp-1])) + in.a
+			if ea+4 > uint64(len(mem)) {
+				panic(trapOutOfBounds)
+			}
+			stack[sp-1] = uint64(binary.LittleEndian.Uint32(mem[ea:]))
+		case 0x29, 0x2b: // i64.load, f64.load
+			ea := uint64(uint32(stack[sp-1])) + in.a
+			if ea+8 > uint64(len(mem)) {
+				panic(trapOutOfBounds)
+			}
+			stack[sp-1] = binary.LittleEndian.Uint64(mem[ea:])
+		case 0x2c, 0x2d, 0x30, 0x31: // load8
+			ea := uint64(uint32(stack[sp-1])) + in.a
+			if ea+1 > uint64(len(mem)) {
+				panic(trapOutOfBounds)
+			}
+			v := mem[ea]
+			switch in.op {
+			case 0x2c:
+				stack[sp-1] = uint64(uint32(int32(int8(v))))
+			case 0x30:
+				stack[sp-1] = uint64(int64(int8(v)))
+			default:
+				stack[sp-1] = uint64(v)
+			}
+		case 0x2e, 0x2f, 0x32, 0x33: // load16
+			ea := uint64(uint32(stack[sp-1])) + in.a
+			if ea+2 > uint64(len(mem)) {
+				panic(trapOutOfBounds)
+			}
+			v := binary.LittleEndian.Uint16(mem[ea:])
+			switch in.op {
+			case 0x2e:
+				stack[sp-1] = uint64(uint32(int32(int16(v))))
+			case 0x32:
+				stack[sp-1] = uint64(int64(int16(v)))
+			default:
+				stack[sp-1] = uint64(v)
+			}
+		case 0x34, 0x35: // i64.load32_s, i64.load32_u
+			ea := uint64(uint32(stack[sp-1])) + in.a
+			if ea+4 > uint64(len(mem)) {
+				panic(trapOutOfBounds)
+			}
+			v := binary.LittleEndian.Uint32(mem[ea:])
+			if in.op == 0x34 {
+				stack[sp-1] = uint64(int64(int32(v)))
+			} else {
+				stack[sp-1] = uint64(v)
+			}
+		case 0x36, 0x38, 0x3e: // i32.store, f32.store, i64.store32
+			sp -= 2
+			ea := uint64(uint32(stack[sp])) + in.a
+			if ea+4 > uint64(len(mem)) {
+				panic(trapOutOfBounds)
+			}
+			binary.LittleEndian.PutUint32(mem[ea:], uint32(stack[sp+1]))
+		case 0x37, 0x39: // i64.store, f64.store
+			sp -= 2
+			ea := uint64(uint32(stack[sp])) + in.a
+			if ea+8 > uint64(len(mem)) {
+				panic(trapOutOfBounds)
+			}
+			binary.LittleEndian.PutUint64(mem[ea:], stack[sp+1])
+		case 0x3a, 0x3c: // store8
+			sp -= 2
+			ea := uint64(uint32(stack[sp])) + in.a
+			if ea+1 > uint64(len(mem)) {
+				panic(trapOutOfBounds)
+			}
+			mem[ea] = byte(stack[sp+1])
+		case 0x3b, 0x3d: // store16
+			sp -= 2
+			ea := uint64(uint32(stack[sp])) + in.a
+			if ea+2 > uint64(len(mem)) {
+				panic(trapOutOfBounds)
+			}
+			binary.LittleEndian.PutUint16(mem[ea:], uint16(stack[sp+1]))
+		case opMemorySize:
+			stack[sp] = uint64(len(mem) / PageSize)
+			sp++
+		case opMemoryGrow:
+			stack[sp-1] = uint64(inst.growMemory(uint32(stack[sp-1])))
+			mem = inst.memory
+		case opMemoryCopy:
+			sp -= 3
+			dst, src, n := uint64(uint32(stack[sp])), uint64(uint32(stack[sp+1])), uint64(uint32(stack[sp+2]))
+			if src+n > uint64(len(mem)) || dst+n > uint64(len(mem)) {
+				panic(trapOutOfBounds)
+			}
+			copy(mem[dst:dst+n], mem[src:src+n])
+		case opMemoryFill:
+			sp -= 3
+			dst, v, n := uint64(uint32(stack[sp])), byte(stack[sp+1]), uint64(uint32(stack[sp+2]))
+			if dst+n > uint64(len(mem)) {
+				panic(trapOutOfBounds)
+			}
+			for i := dst; i < dst+n; i++ {
+				mem[i] = v
+			}
+
+		// constants
+		case opI32Const, opI64Const, opF32Const, opF64Const:
+			stack[sp] = in.a
+			sp++
+
+		default:
+			if in.op >= firstNumericOpcode && in.op <= lastNumericOpcode {
+				sp = execNumeric(in.op, int(in.c), stack, sp)
+			} else {
+				sp = execTruncSat(in.op, stack, sp)
+			}
+		}
+	}
+}
+
+// branch keeps the top n values of the stack at the target height and returns the new stack pointer
+func branch(stack []uint64, sp, height, n int) int {
+	if n > 0 && sp-n != height {
+		copy(stack[height:], stack[sp-n:sp])
+	}
+	return height + n
+}
+
+// execNumeric executes the numeric instruction with the number of the operands and returns the new stack pointer
+func execNumeric(op Opcode, numOperands int, stack []uint64, sp int) int {
+	if numOperands == 1 {
+		stack[sp-1] = unaryOp(op, stack[sp-1])
+		return sp
+	}
+	sp--
+	a, b := stack[sp-1], stack[sp]
+	var r uint64
+	switch op {
+	// i32 comparisons
+	case 0x46:
+		r = b2i(uint32(a) == uint32(b))
+	case 0x47:
+		r = b2i(uint32(a) != uint32(b))
+	case 0x48:
+		r = b2i(int32(a) < int32(b))
+	case 0x49:
+		r = b2i(uint32(a) < uint32(b))
+	case 0x4a:
+		r = b2i(int32(a) > int32(b))
+	case 0x4b:
+		r = b2i(uint32(a) > uint32(b))
+	case 0x4c:
+		r = b2i(int32(a) <= int32(b))
+	case 0x4d:
+		r = b2i(uint32(a) <= uint32(b))
+	case 0x4e:
+		r = b2i(int32(a) >= int32(b))
+	case 0x4f:
+		r = b2i(uint32(a) >= uint32(b))
+
+	// i64 comparisons
+	case 0x51:
+		r = b2i(a == b)
+	case 0x52:
+		r = b2i(a != b)
+	case 0x53:
+		r = b2i(int64(a) < int64(b))
+	case 0x54:
+		r = b2i(a < b)
+	case 0x55:
+		r = b2i(int64(a) > int64(b))
+	case 0x56:
+		r = b2i(a > b)
+	case 0x57:
+		r = b2i(int64(a) <= int64(b))
+	case 0x58:
+		r = b2i(a <= b)
+	case 0x59:
+		r = b2i(int64(a) >= int64(b))
+	case 0x5a:
+		r = b2i(a >= b)
+
+	// f32 comparisons
+	case 0x5b:
+		r = b2i(f32(a) == f32(b))
+	case 0x5c:
+		r = b2i(f32(a) != f32(b))
+	case 0x5d:
+		r = b2i(f32(a) < f32(b))
+	case 0x5e:
+		r = b2i(f32(a) > f32(b))
+	case 0x5f:
+		r = b2i(f32(a) <= f32(b))
+	case 0x60:
+		r = b2i(f32(a) >= f32(b))
+
+	// f64 comparisons
+	case 0x61:
+		r = b2i(f64(a) == f64(b))
+	case 0x62:
+		r = b2i(f64(a) != f64(b))
+	case 0x63:
+		r = b2i(f64(a) < f64(b))
+	case 0x64:
+		r = b2i(f64(a) > f64(b))
+	case 0x65:
+		r = b2i(f64(a) <= f64(b))
+	case 0x66:
+		r = b2i(f64(a) >= f64(b))
+
+	// i32 arithmetic
+	case 0x6a:
+		r = uint64(uint32(a) + uint32(b))
+	case 0x6b:
+		r = uint64(uint32(a) - uint32(b))
+	case 0x6c:
+		r = uint64(uint32(a) * uint32(b))
+	case 0x6d:
+		x, y := int32(a), int32(b)
+		if y == 0 {
+			panic(trapDivByZero)
+		}
+		if x == math.MinInt32 && y == -1 {
+			panic(trapOverflow)
+		}
+		r = uint64(uint32(x / y))
+	case 0x6e:
+		if uint32(b) == 0 {
+			panic(trapDivByZero)
+		}
+		r = uint64(uint32(a) / uint32(b))
+	case 0x6f:
+		x, y := int32(a), int32(b)
+		if y == 0 {
+			panic(trapDivByZero)
+		}
+		if y == -1 {
+			r = 0
+		} else {
+			r = uint64(uint32(x % y))
+		}
+	case 0x70:
+		if uint32(b) == 0 {
+			panic(trapDivByZero)
+		}
+		r = uint64(uint32(a) % uint32(b))
+	case 0x71:
+		r = uint64(uint32(a) & uint32(b))
+	case 0x72:
+		r = uint64(uint32(a) | uint32(b))
+	case 0x73:
+		r = uint64(uint32(a) ^ uint32(b))
+	case 0x74:
+		r = uint64(uint32(a) << (b & 31))
+	case 0x75:
+		r = uint64(uint32(int32(a) >> (b & 31)))
+	case 0x76:
+		r = uint64(uint32(a) >> (b & 31))
+	case 0x77:
+		r = uint64(bits.RotateLeft32(uint32(a), int(b&31)))
+	case 0x78:
+		r = uint64(bits.RotateLeft32(uint32(a), -int(b&31)))
+
+	// i64 arithmetic
+	case 0x7c:
+		r = a + b
+	case 0x7d:
+		r = a - b
+	case 0x7e:
+		r = a * b
+	case 0x7f:
+		x, y := int64(a), int64(b)
+		if y == 0 {
+			panic(trapDivByZero)
+		}
+		if x == math.MinInt64 && y == -1 {
+			panic(trapOverflow)
+		}
+		r = uint64(x / y)
+	case 0x80:
+		if b == 0 {
+			panic(trapDivByZero)
+		}
+		r = a / b
+	case 0x81:
+		x, y := int64(a), int64(b)
+		if y == 0 {
+			panic(trapDivByZero)
+		}
+		if y == -1 {
+			r = 0
+		} else {
+			r = uint64(x % y)
+		}
+	case 0x82:
+		if b == 0 {
+			panic(trapDivByZero)
+		}
+		r = a % b
+	case 0x83:
+		r = a & b
+	case 0x84:
+		r = a | b
+	case 0x85:
+		r = a ^ b
+	case 0x86:
+		r = a << (b & 63)
+	case 0x87:
+		r = uint64(int64(a) >> (b & 63))
+	case 0x88:
+		r = a >> (b & 63)
+	case 0x89:
+		r = bits.RotateLeft64(a, int(b&63))
+	case 0x8a:
+		r = bits.RotateLeft64(a, -int(b&63))
+
+	// f32 arithmetic
+	case 0x92:
+		r = f32bits(f32(a) + f32(b))
+	case 0x93:
+		r = f32bits(f32(a) - f32(b))
+	case 0x94:
+		r = f32bits(f32(a) * f32(b))
+	case 0x95:
+		r = f32bits(f32(a) / f32(b))
+	case 0x96:
+		if math.IsNaN(float64(f32(a))) || math.IsNaN(float64(f32(b))) {
+			r = canonicalNaN32
+		} else {
+			r = f32bits(float32(math.Min(float64(f32(a)), float64(f32(b)))))
+		}
+	case 0x97:
+		if math.IsNaN(float64(f32(a))) || math.IsNaN(float64(f32(b))) {
+			r = canonicalNaN32
+		} else {
+			r = f32bits(float32(math.Max(float64(f32(a)), float64(f32(b)))))
+		}
+	case 0x98:
+		r = a&^signBit32 | b&signBit32
+
+	// f64 arithmetic
+	case 0xa0:
+		r = f64bits(f64(a) + f64(b))
+	case 0xa1:
+		r = f64bits(f64(a) - f64(b))
+	case 0xa2:
+		r = f64bits(f64(a) * f64(b))
+	case 0xa3:
+		r = f64bits(f64(a) / f64(b))
+	case 0xa4:
+		if math.IsNaN(f64(a)) || math.IsNaN(f64(b)) {
+			r = canonicalNaN64
+		} else {
+			r = f64bits(math.Min(f64(a), f64(b)))
+		}
+	case 0xa5:
+		if math.IsNaN(f64(a)) || math.IsNaN(f64(b)) {
+			r = canonicalNaN64
+		} else {
+			r = f64bits(math.Max(f64(a), f64(b)))
+		}
+	case 0xa6:
+		r = a&^signBit64 | b&signBit64
+	default:
+		panic(trap("unsupported instruction " + op.String()))
+	}
+	stack[sp-1] = r
+	return sp
+}
+
+const (
+	signBit32      = uint64(1) << 31
+	signBit64      = uint64(1) << 63
+	canonicalNaN32 = uint64(0x7fc00000)
+	canonicalNaN64 = uint64(0x7ff8000000000000)
+)
+
+func unaryOp(op Opcode, a uint64) uint64 {
+	switch op {
+	case 0x45:
+		return b2i(uint32(a) == 0)
+	case 0x50:
+		return b2i(a == 0)
+	case 0x67:
+		return uint64(bits.LeadingZeros32(uint32(a)))
+	case 0x68:
+		return uint64(bits.TrailingZeros32(uint32(a)))
+	case 0x69:
+		return uint64(bits.OnesCount32(uint32(a)))
+	case 0x79:
+		return uint64(bits.LeadingZeros64(a))
+	case 0x7a:
+		return uint64(bits.TrailingZeros64(a))
+	case 0x7b:
+		return uint64(bits.OnesCount64(a))
+
+	// f32
+	case 0x8b:
+		return a &^ signBit32
+	case 0x8c:
+		return a ^ signBit32
+	case 0x8d:
+		return f32bits(float32(math.Ceil(float64(f32(a)))))
+	case 0x8e:
+		return f32bits(float32(math.Floor(float64(f32(a)))))
+	case 0x8f:
+		return f32bits(float32(math.Trunc(float64(f32(a)))))
+	case 0x90:
+		return f32bits(float32(math.RoundToEven(float64(f32(a)))))
+	case 0x91:
+		return f32bits(float32(math.Sqrt(float64(f32(a)))))
+
+	// f64
+	case 0x99:
+		return a &^ signBit64
+	case 0x9a:
+		return a ^ signBit64
+	case 0x9b:
+		return f64bits(math.Ceil(f64(a)))
+	case 0x9c:
+		return f64bits(math.Floor(f64(a)))
+	case 0x9d:
+		return f64bits(math.Trunc(f64(a)))
+	case 0x9e:
+		return f64bits(math.RoundToEven(f64(a)))
+	case 0x9f:
+		return f64bits(math.Sqrt(f64(a)))
+
+	// conversions
+	case 0xa7:
+		return uint64(uint32(a))
+	case 0xa8:
+		return uint64(uint32(int32(truncChecked(float64(f32(a)), -1<<31, 1<<31))))
+	case 0xa9:
+		return uint64(uint32(truncChecked(float64(f32(a)), -1, 1<<32)))
+	case 0xaa:
+		return uint64(uint32(int32(truncChecked(f64(a), -1<<31, 1<<31))))
+	case 0xab:
+		return uint64(uint32(truncChecked(f64(a), -1, 1<<32)))
+	case 0xac:
+		return uint64(int64(int32(a)))
+	case 0xad:
+		return uint64(uint32(a))
+	case 0xae:
+		return uint64(int64(truncChecked(float64(f32(a)), -1<<63, 1<<63)))
+	case 0xaf:
+		return truncU64(truncChecked(float64(f32(a)), -1, 1<<64))
+	case 0xb0:
+		return uint64(int64(truncChecked(f64(a), -1<<63, 1<<63)))
+	case 0xb1:
+		return truncU64(truncChecked(f64(a), -1, 1<<64))
+	case 0xb2:
+		return f32bits(float32(int32(a)))
+	case 0xb3:
+		return f32bits(float32(uint32(a)))
+	case 0xb4:
+		return f32bits(float32(int64(a)))
+	case 0xb5:
+		return f32bits(float32(a))
+	case 0xb6:
+		return f32bits(float32(f64(a)))
+	case 0xb7:
+		return f64bits(float64(int32(a)))
+	case 0xb8:
+		return f64bits(float64(uint32(a)))
+	case 0xb9:
+		return f64bits(float64(int64(a)))
+	case 0xba:
+		return f64bits(float64(a))
+	case 0xbb:
+		return f64bits(float64(f32(a)))
+	case 0xbc, 0xbe:
+		return uint64(uint32(a))
+	case 0xbd, 0xbf:
+		return a
+
+	// sign extension
+	case 0xc0:
+		return uint64(uint32(int32(int8(a))))
+	case 0xc1:
+		return uint64(uint32(int32(int16(a))))
+	case 0xc2:
+		return uint64(int64(int8(a)))
+	case 0xc3:
+		return uint64(int64(int16(a)))
+	case 0xc4:
+		return uint64(int64(int32(a)))
+	}
+	panic(trap("unsupported instruction " + op.String()))
+}
+
+// truncChecked truncates the float to an integer which must be within (lower, upper), otherwise it traps
+func truncChecked(x, lower, upper float64) float64 {
+	if math.IsNaN(x) {
+		panic(trapConversion)
+	}
+	t := math.Trunc(x)
+	if lower == -1 {
+		if t <= -1 || t >= upper {
+			panic(trapOverflow)
+		}
+	} else if t < lower || t >= upper {
+		panic(trapOverflow)
+	}
+	return t
+}
+
+// truncU64 converts the truncated non-negative float to uint64
+func truncU64(t float64) uint64 {
+	if t >= 1<<63 {
+		return uint64(t-(1<<63)) | 1<<63
+	}
+	return uint64(t)
+}
+
+// truncSat truncates the float to the range [lower, upper), saturating at the bounds
+func truncSat(x, lower, upper float64) (float64, int) {
+	switch {
+	case math.IsNaN(x):
+		return 0, 0
+	case x < lower:
+		return 0, -1
+	case x >= upper:
+		return 0, 1
+	}
+	return math.Trunc(x), 0
+}
+
+func execTruncSat(op Opcode, stack []uint64, sp int) int {
+	a := stack[sp-1]
+	var r uint64
+	switch op {
+	case 0xfc00, 0xfc02:
+		x := f64(a)
+		if op == 0xfc00 {
+			x = float64(f32(a))
+		}
+		t, sat := truncSat(x, -1<<31, 1<<31)
+		switch sat {
+		case -1:
+			r = 1 << 31
+		case 1:
+			r = math.MaxInt32
+		default:
+			r = uint64(uint32(int32(t)))
+		}
+	case 0xfc01, 0xfc03:
+		x := f64(a)
+		if op == 0xfc01 {
+			x = float64(f32(a))
+		}
+		t, sat := truncSat(x, 0, 1<<32)
+		switch sat {
+		case -1:
+			r = 0
+		case 1:
+			r = math.MaxUint32
+		default:
+			r = uint64(uint32(t))
+		}
+	case 0xfc04, 0xfc06:
+		x := f64(a)
+		if op == 0xfc04 {
+			x = float64(f32(a))
+		}
+		t, sat := truncSat(x, -1<<63, 1<<63)
+		switch sat {
+		case -1:
+			r = 1 << 63
+		case 1:
+			r = math.MaxInt64
+		default:
+			r = uint64(int64(t))
+		}
+	case 0xfc05, 0xfc07:
+		x := f64(a)
+		if op == 0xfc05 {
+			x = float64(f32(a))
+		}
+		t, sat := truncSat(x, 0, 1<<64)
+		switch sat {
+		case -1:
+			r = 0
+		case 1:
+			r = math.MaxUint64
+		default:
+			r = truncU64(t)
+		}
+	default:
+		panic(trap("unsupported instruction " + op.String()))
+	}
+	stack[sp-1] = r
+	return sp
+}
@@ -0,0 +1,248 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasminterp
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// maximal depth of the nested calls of the functions
+	maxCallDepth = 10000
+	// maximal number of the values on the stack of the instance: locals and operands of all calls
+	maxStackSize     = 1 << 22
+	initialStackSize = 1024
+	// the value of the uninitialized table element
+	nullElement = ^uint32(0)
+)
+
+// Trap is the error of the execution of the Wasm code
+type Trap struct {
+	msg string
+}
+
+func (t *Trap) Error() string {
+	return "wasm trap: " + t.msg
+}
+
+func trap(msg string) *Trap {
+	return &Trap{msg: msg}
+}
+
+var (
+	trapOutOfBounds = trap("out of bounds memory access")
+	trapDivByZero   = trap("integer divide by zero")
+	trapOverflow    = trap("integer overflow")
+	trapConversion  = trap("invalid conversion to integer")
+)
+
+// HostFunc is the function provided by the host. The values of the arguments and the results are
+// represented as uint64: i32 and i64 as unsigned integers, f32 and f64 as IEEE 754 bits
+type HostFunc func(args []uint64) []uint64
+
+type hostFunction struct {
+	funcType FuncType
+	fn       HostFunc
+}
+
+// Linker resolves the imports of the modules to the host functions
+type Linker struct {
+	funcs map[string]*hostFunction
+}
+
+func NewLinker() *Linker {
+	return &Linker{funcs: make(map[string]*hostFunction)}
+}
+
+// DefineFunc defines the host function which can be imported by the modules as module.name
+func (l *Linker) DefineFunc(module, name string, funcType FuncType, fn HostFunc) error {
+	key := module + "." + name
+	if _, ok := l.funcs[key]; ok {
+		return fmt.Errorf("duplicate host function %s", key)
+	}
+	l.funcs[key] = &hostFunction{funcType: funcType, fn: fn}
+	return nil
+}
+
+// Instance is the instance of the module: its memory, globals and table
+type Instance struct {
+	module    *Module
+	hostFuncs []*hostFunction
+	memory    []byte
+	maxPages  uint32
+	globals   []uint64
+	table     []uint32
+	stack     []uint64
+	sp        int
+	depth     int
+}
+
+// Instantiate creates the instance of the module, initializes it and runs its start function
+func (l *Linker) Instantiate(m *Module) (*Instance, error) {
+	inst := &Instance{
+		module:    m,
+		hostFuncs: make([]*hostFunction, len(m.Imports)),
+		stack:     make([]uint64, initialStackSize),
+	}
+	for i, imp := range m.Imports {
+		hf, ok := l.funcs[imp.Module+"."+imp.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown import: %s.%s", imp.Module, imp.Name)
+		}
+		if ft := &m.Types[imp.TypeIndex]; !ft.Equal(&hf.funcType) {
+			return nil, fmt.Errorf("import %s.%s: type mismatch: expected %s, got %s", imp.Module, imp.Name, ft, &hf.funcType)
+		}
+		inst.hostFuncs[i] = hf
+	}
+	if m.Memory != nil {
+		inst.memory = make([]byte, uint64(m.Memory.Min)*PageSize)
+		inst.maxPages = MaxPages
+		if m.Memory.HasMax {
+			inst.maxPages = m.Memory.Max
+		}
+	}
+	inst.globals = make([]uint64, len(m.Globals))
+	for i := range m.Globals {
+		inst.globals[i] = m.Globals[i].Init
+	}
+	if m.Table != nil {
+		inst.table = make([]uint32, m.Table.Min)
+		for i := range inst.table {
+			inst.table[i] = nullElement
+		}
+	}
+	for _, e := range m.Elements {
+		if uint64(e.Offset)+uint64(len(e.Functions)) > uint64(len(inst.table)) {
+			return nil, errors.New("element segment does not fit")
+		}
+		copy(inst.table[e.Offset:], e.Functions)
+	}
+	for _, d := range m.Data {
+		if uint64(d.Offset)+uint64(len(d.Data)) > uint64(len(inst.memory)) {
+			return nil, errors.New("data segment does not fit")
+		}
+		copy(inst.memory[d.Offset:], d.Data)
+	}
+	if m.Start != nil {
+		if _, err := inst.callFunction(*m.Start, nil); err != nil {
+			return nil, err
+		}
+	}
+	return inst, nil
+}
+
+func (inst *Instance) Module() *Module {
+	return inst.module
+}
+
+// Memory returns the linear memory of the instance. The slice is valid until the memory grows
+func (inst *Instance) Memory() []byte {
+	return inst.memory
+}
+
+// GlobalIndex returns the index of the exported global
+func (inst *Instance) GlobalIndex(name string) (uint32, error) {
+	exp := inst.module.Export(name)
+	if exp == nil || exp.Kind != ExternalGlobal {
+		return 0, fmt.Errorf("unknown export global: '%s'", name)
+	}
+	return exp.Index, nil
+}
+
+// Global returns the value of the global
+func (inst *Instance) Global(index uint32) uint64 {
+	return inst.globals[index]
+}
+
+// SetGlobal sets the value of the global
+func (inst *Instance) SetGlobal(index uint32, value uint64) {
+	inst.globals[index] = value
+}
+
+// Call calls the exported function with the arguments and returns its results
+func (inst *Instance) Call(name string, args ...uint64) ([]uint64, error) {
+	exp := inst.module.Export(name)
+	if exp == nil || exp.Kind != ExternalFunction {
+		return nil, fmt.Errorf("unknown export function: '%s'", name)
+	}
+	if ft := inst.module.FunctionType(exp.Index); len(ft.Params) != len(args) {
+		return nil, fmt.Errorf("function '%s': expected %d arguments, got %d", name, len(ft.Params), len(args))
+	}
+	return inst.callFunction(exp.Index, args)
+}
+
+// callFunction calls the function. It may be called by the host functions re-entrantly.
+// Traps are returned as errors, other panics are propagated to the caller
+func (inst *Instance) callFunction(index uint32, args []uint64) (results []uint64, err error) {
+	savedSP, savedDepth := inst.sp, inst.depth
+	defer func() {
+		if r := recover(); r != nil {
+			inst.sp, inst.depth = savedSP, savedDepth
+			t, ok := r.(*Trap)
+			if !ok {
+				panic(r)
+			}
+			results, err = nil, t
+		}
+	}()
+	inst.ensureStack(inst.sp + len(args))
+	copy(inst.stack[inst.sp:], args)
+	inst.sp += len(args)
+	inst.call(index)
+	n := len(inst.module.FunctionType(index).Results)
+	results = make([]uint64, n)
+	copy(results, inst.stack[inst.sp-n:inst.sp])
+	inst.sp -= n
+	return results, nil
+}
+
+func (inst *Instance) ensureStack(size int) {
+	if size <= len(inst.stack) {
+		return
+	}
+	if size > maxStackSize {
+		panic(trap("call stack exhausted"))
+	}
+	newSize := 2 * len(inst.stack)
+	if newSize < size {
+		newSize = size
+	}
+	stack := make([]uint64, newSize)
+	copy(stack, inst.stack[:inst.sp])
+	inst.stack = stack
+}
+
+func (inst *Instance) callHost(index uint32) {
+	hf := inst.hostFuncs[index]
+	n := len(hf.funcType.Params)
+	args := make([]uint64, n)
+	copy(args, inst.stack[inst.sp-n:inst.sp])
+	inst.sp -= n
+	results := hf.fn(args)
+	if len(results) != len(hf.funcType.Results) {
+		panic(trap(fmt.Sprintf("host function returned %d results, expected %d", len(results), len(hf.funcType.Results))))
+	}
+	inst.ensureStack(inst.sp + len(results))
+	for i, v := range results {
+		if t := hf.funcType.Results[i]; t == I32 || t == F32 {
+			v = uint64(uint32(v))
+		}
+		inst.stack[inst.sp] = v
+		inst.sp++
+	}
+}
+
+func (inst *Instance) growMemory(delta uint32) uint32 {
+	pages := uint32(len(inst.memory) / PageSize)
+	if uint64(pages)+uint64(delta) > uint64(inst.maxPages) {
+		return ^uint32(0)
+	}
+	if delta > 0 {
+		memory := make([]byte, uint64(pages+delta)*PageSize)
+		copy(memory, inst.memory)
+		inst.memory = memory
+	}
+	return pages
+}
@@ -0,0 +1,430 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasminterp
+
+import (
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// helpers to assemble the binary modules
+
+func uleb(v uint64) []byte {
+	var ret []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(ret, b)
+		}
+		ret = append(ret, b|0x80)
+	}
+}
+
+func concat(parts ...[]byte) []byte {
+	var ret []byte
+	for _, p := range parts {
+		ret = append(ret, p...)
+	}
+	return ret
+}
+
+func vec(items ...[]byte) []byte {
+	return concat(uleb(uint64(len(items))), concat(items...))
+}
+
+func name(s string) []byte {
+	return concat(uleb(uint64(len(s))), []byte(s))
+}
+
+func section(id byte, items ...[]byte) []byte {
+	content := vec(items...)
+	return concat([]byte{id}, uleb(uint64(len(content))), content)
+}
+
+func funcType(params []byte, results []byte) []byte {
+	return concat([]byte{0x60}, uleb(uint64(len(params))), params, uleb(uint64(len(results))), results)
+}
+
+func exportFunc(exportName string, index byte) []byte {
+	return concat(name(exportName), []byte{byte(ExternalFunction), index})
+}
+
+func body(locals []byte, code ...byte) []byte {
+	content := concat(locals, code)
+	return concat(uleb(uint64(len(content))), content)
+}
+
+var noLocals = []byte{0}
+
+func module(sections ...[]byte) []byte {
+	return concat([]byte("\x00asm\x01\x00\x00\x00"), concat(sections...))
+}
+
+func instantiate(t *testing.T, linker *Linker, binary []byte) *Instance {
+	m, err := Parse(binary)
+	require.NoError(t, err)
+	if linker == nil {
+		linker = NewLinker()
+	}
+	inst, err := linker.Instantiate(m)
+	require.NoError(t, err)
+	return inst
+}
+
+func call1(t *testing.T, inst *Instance, fun string, args ...uint64) uint64 {
+	ret, err := inst.Call(fun, args...)
+	require.NoError(t, err)
+	require.Len(t, ret, 1)
+	return ret[0]
+}
+
+func i32(v int32) uint64 {
+	return uint64(uint32(v))
+}
+
+func TestFactorial(t *testing.T) {
+	inst := instantiate(t, nil, module(
+		section(1, funcType([]byte{0x7e}, []byte{0x7e})),
+		section(3, []byte{0}),
+		section(7, exportFunc("fac", 0)),
+		section(10, body(noLocals,
+			0x20, 0, 0x50, 0x04, 0x7e, // local.get 0; i64.eqz; if (result i64)
+			0x42, 1, 0x05, // i64.const 1; else
+			0x20, 0, 0x20, 0, 0x42, 1, 0x7d, // local.get 0; local.get 0; i64.const 1; i64.sub
+			0x10, 0, 0x7e, // call 0; i64.mul
+			0x0b, 0x0b)),
+	))
+	require.EqualValues(t, 1, call1(t, inst, "fac", 0))
+	require.EqualValues(t, 120, call1(t, inst, "fac", 5))
+	require.EqualValues(t, uint64(2432902008176640000), call1(t, inst, "fac", 20))
+
+	_, err := inst.Call("fac")
+	require.Error(t, err)
+	_, err = inst.Call("unknown")
+	require.Error(t, err)
+}
+
+func TestLoop(t *testing.T) {
+	inst := instantiate(t, nil, module(
+		section(1, funcType([]byte{0x7f}, []byte{0x7f})),
+		section(3, []byte{0}),
+		section(7, exportFunc("sum", 0)),
+		section(10, body([]byte{1, 1, 0x7f},
+			0x02, 0x40, 0x03, 0x40, // block; loop
+			0x20, 0, 0x45, 0x0d, 1, // local.get 0; i32.eqz; br_if 1
+			0x20, 1, 0x20, 0, 0x6a, 0x21, 1, // local.get 1; local.get 0; i32.add; local.set 1
+			0x20, 0, 0x41, 1, 0x6b, 0x21, 0, // local.get 0; i32.const 1; i32.sub; local.set 0
+			0x0c, 0, 0x0b, 0x0b, // br 0; end; end
+			0x20, 1, 0x0b)),
+	))
+	require.EqualValues(t, 0, call1(t, inst, "sum", 0))
+	require.EqualValues(t, 5050, call1(t, inst, "sum", 100))
+	require.EqualValues(t, 50005000, call1(t, inst, "sum", 10000))
+}
+
+func TestBrTable(t *testing.T) {
+	inst := instantiate(t, nil, module(
+		section(1, funcType([]byte{0x7f}, []byte{0x7f})),
+		section(3, []byte{0}),
+		section(7, exportFunc("select", 0)),
+		section(10, body(noLocals,
+			0x02, 0x40, 0x02, 0x40, 0x02, 0x40, // block; block; block
+			0x20, 0, 0x0e, 2, 0, 1, 2, 0x0b, // local.get 0; br_table 0 1 2; end
+			0x41, 10, 0x0f, 0x0b, // i32.const 10; return; end
+			0x41, 11, 0x0f, 0x0b, // i32.const 11; return; end
+			0x41, 12, 0x0b)),
+	))
+	require.EqualValues(t, 10, call1(t, inst, "select", 0))
+	require.EqualValues(t, 11, call1(t, inst, "select", 1))
+	require.EqualValues(t, 12, call1(t, inst, "select", 2))
+	require.EqualValues(t, 12, call1(t, inst, "select", 7))
+}
+
+func TestTraps(t *testing.T) {
+	inst := instantiate(t, nil, module(
+		section(1, funcType([]byte{0x7f, 0x7f}, []byte{0x7f}), funcType(nil, nil)),
+		section(3, []byte{0}, []byte{1}, []byte{1}),
+		section(7, exportFunc("div", 0), exportFunc("unreachable", 1), exportFunc("recurse", 2)),
+		section(10,
+			body(noLocals, 0x20, 0, 0x20, 1, 0x6d, 0x0b),
+			body(noLocals, 0x00, 0x0b),
+			body(noLocals, 0x10, 2, 0x0b),
+		),
+	))
+	require.EqualValues(t, 3, call1(t, inst, "div", 7, 2))
+	require.EqualValues(t, i32(-3), call1(t, inst, "div", i32(-7), 2))
+
+	_, err := inst.Call("div", 1, 0)
+	require.Equal(t, trapDivByZero, err)
+	_, err = inst.Call("div", i32(math.MinInt32), i32(-1))
+	require.Equal(t, trapOverflow, err)
+	_, err = inst.Call("unreachable")
+	require.IsType(t, &Trap{}, err)
+	_, err = inst.Call("recurse")
+	require.IsType(t, &Trap{}, err)
+
+	// the instance can be used after the trap
+	require.EqualValues(t, 5, call1(t, inst, "div", 10, 2))
+}
+
+func TestMemory(t *testing.T) {
+	inst := instantiate(t, nil, module(
+		section(1, funcType([]byte{0x7f}, []byte{0x7f}), funcType([]byte{0x7f, 0x7f}, nil)),
+		section(3, []byte{0}, []byte{0}, []byte{1}),
+		section(5, []byte{1, 1, 2}),
+		section(7, exportFunc("load", 0), exportFunc("grow", 1), exportFunc("store", 2),
+			concat(name("memory"), []byte{byte(ExternalMemory), 0})),
+		section(10,
+			body(noLocals, 0x20, 0, 0x2d, 0, 0, 0x0b),          // i32.load8_u
+			body(noLocals, 0x20, 0, 0x40, 0, 0x0b),             // memory.grow
+			body(noLocals, 0x20, 0, 0x20, 1, 0x36, 2, 0, 0x0b), // i32.store
+		),
+		section(11, concat([]byte{0, 0x41, 16, 0x0b}, name("hello"))),
+	))
+	require.Len(t, inst.Memory(), PageSize)
+	require.EqualValues(t, 'h', call1(t, inst, "load", 16))
+	require.EqualValues(t, 'o', call1(t, inst, "load", 20))
+
+	_, err := inst.Call("load", PageSize)
+	require.Equal(t, trapOutOfBounds, err)
+	_, err = inst.Call("store", PageSize-2, 1)
+	require.Equal(t, trapOutOfBounds, err)
+
+	require.NoError(t, ignoreResults(inst.Call("store", 100, 0x01020304)))
+	require.EqualValues(t, 4, inst.Memory()[100])
+	require.EqualValues(t, 1, inst.Memory()[103])
+
+	require.EqualValues(t, 1, call1(t, inst, "grow", 1))
+	require.Len(t, inst.Memory(), 2*PageSize)
+	require.EqualValues(t, 0, call1(t, inst, "load", PageSize))
+	require.EqualValues(t, 'h', call1(t, inst, "load", 16))
+	// maximum is 2 pages
+	require.EqualValues(t, i32(-1), call1(t, inst, "grow", 1))
+}
+
+func ignoreResults(_ []uint64, err error) error {
+	return err
+}
+
+func TestHostAndCallIndirect(t *testing.T) {
+	binary := module(
+		section(1, funcType([]byte{0x7f, 0x7f}, []byte{0x7f}), funcType([]byte{0x7f}, []byte{0x7f})),
+		section(2, concat(name("env"), name("add"), []byte{0, 0})),
+		section(3, []byte{1}, []byte{1}, []byte{0}),
+		section(4, []byte{0x70, 0, 3}),
+		section(7, exportFunc("dispatch", 3)),
+		section(9, concat([]byte{0, 0x41, 0, 0x0b}, vec([]byte{1}, []byte{2}))),
+		section(10,
+			body(noLocals, 0x20, 0, 0x20, 0, 0x10, 0, 0x0b),    // double: call host add
+			body(noLocals, 0x20, 0, 0x20, 0, 0x6c, 0x0b),       // square
+			body(noLocals, 0x20, 1, 0x20, 0, 0x11, 1, 0, 0x0b), // call_indirect (type 1)
+		),
+	)
+	m, err := Parse(binary)
+	require.NoError(t, err)
+
+	linker := NewLinker()
+	_, err = linker.Instantiate(m)
+	require.Error(t, err)
+
+	wrongType := NewLinker()
+	require.NoError(t, wrongType.DefineFunc("env", "add", FuncType{Params: []ValueType{I32}, Results: []ValueType{I32}},
+		func(args []uint64) []uint64 { return args }))
+	_, err = wrongType.Instantiate(m)
+	require.Error(t, err)
+
+	calls := 0
+	require.NoError(t, linker.DefineFunc("env", "add", FuncType{Params: []ValueType{I32, I32}, Results: []ValueType{I32}},
+		func(args []uint64) []uint64 {
+			calls++
+			return []uint64{args[0] + args[1]}
+		}))
+	require.Error(t, linker.DefineFunc("env", "add", FuncType{}, nil))
+	inst := instantiate(t, linker, binary)
+
+	require.EqualValues(t, 42, call1(t, inst, "dispatch", 0, 21))
+	require.EqualValues(t, 1, calls)
+	require.EqualValues(t, 25, call1(t, inst, "dispatch", 1, 5))
+	// results of the host are truncated to 32 bits
+	require.EqualValues(t, i32(-2), call1(t, inst, "dispatch", 0, i32(-1)))
+
+	_, err = inst.Call("dispatch", 2, 1)
+	require.IsType(t, &Trap{}, err)
+	_, err = inst.Call("dispatch", 3, 1)
+	require.IsType(t, &Trap{}, err)
+}
+
+func TestFloatConversions(t *testing.T) {
+	inst := instantiate(t, nil, module(
+		section(1, funcType([]byte{0x7c}, []byte{0x7f})),
+		section(3, []byte{0}, []byte{0}),
+		section(7, exportFunc("trunc", 0), exportFunc("trunc_sat", 1)),
+		section(10,
+			body(noLocals, 0x20, 0, 0xaa, 0x0b),       // i32.trunc_f64_s
+			body(noLocals, 0x20, 0, 0xfc, 0x02, 0x0b), // i32.trunc_sat_f64_s
+		),
+	))
+	f := func(v float64) uint64 { return math.Float64bits(v) }
+
+	require.EqualValues(t, i32(-3), call1(t, inst, "trunc", f(-3.9)))
+	_, err := inst.Call("trunc", f(math.NaN()))
+	require.Equal(t, trapConversion, err)
+	_, err = inst.Call("trunc", f(1e20))
+	require.Equal(t, trapOverflow, err)
+
+	require.EqualValues(t, math.MaxInt32, call1(t, inst, "trunc_sat", f(1e20)))
+	require.EqualValues(t, i32(math.MinInt32), call1(t, inst, "trunc_sat", f(-1e20)))
+	require.EqualValues(t, 0, call1(t, inst, "trunc_sat", f(math.NaN())))
+}
+
+func TestInvalidModules(t *testing.T) {
+	_, err := Parse([]byte("\x00asm\x02\x00\x00\x00"))
+	require.Error(t, err)
+
+	// i32.add of i32 and i64
+	_, err = Parse(module(
+		section(1, funcType(nil, []byte{0x7f})),
+		section(3, []byte{0}),
+		section(10, body(noLocals, 0x41, 1, 0x42, 1, 0x6a, 0x0b)),
+	))
+	require.Error(t, err)
+
+	// missing result
+	_, err = Parse(module(
+		section(1, funcType(nil, []byte{0x7f})),
+		section(3, []byte{0}),
+		section(10, body(noLocals, 0x0b)),
+	))
+	require.Error(t, err)
+
+	// call of unknown function
+	_, err = Parse(module(
+		section(1, funcType(nil, nil)),
+		section(3, []byte{0}),
+		section(10, body(noLocals, 0x10, 5, 0x0b)),
+	))
+	require.Error(t, err)
+
+	// truncated body
+	_, err = Parse(module(
+		section(1, funcType(nil, nil)),
+		section(3, []byte{0}),
+		section(10, body(noLocals, 0x02, 0x40)),
+	))
+	require.Error(t, err)
+}
+
+func TestContractBinaries(t *testing.T) {
+	files, err := filepath.Glob("../../../contracts/rust/*/test/*_bg.wasm")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		m, err := Parse(data)
+		require.NoError(t, err, file)
+		require.NotNil(t, m.Export("on_load"), file)
+		require.NotNil(t, m.Export("on_call_entrypoint"), file)
+		require.NotNil(t, m.Memory, file)
+	}
+}
+
+func TestCheckNoFloats(t *testing.T) {
+	m, err := Parse(module(
+		section(1, funcType([]byte{0x7f}, []byte{0x7f})),
+		section(3, []byte{0}),
+		section(10, body(noLocals, 0x20, 0, 0xb2, 0xa8, 0x0b)), // f32.convert_i32_s; i32.trunc_f32_s
+	))
+	require.NoError(t, err)
+	require.Error(t, m.CheckNoFloats())
+
+	m, err = Parse(module(
+		section(1, funcType([]byte{0x7f}, []byte{0x7f})),
+		section(3, []byte{0}),
+		section(10, body([]byte{1, 1, 0x7d}, 0x20, 0, 0x0b)), // unused f32 local
+	))
+	require.NoError(t, err)
+	require.Error(t, m.CheckNoFloats())
+
+	m, err = Parse(module(
+		section(1, funcType([]byte{0x7f}, []byte{0x7f})),
+		section(3, []byte{0}),
+		section(5, []byte{0, 1}),
+		section(10, body(noLocals, 0x20, 0, 0x28, 2, 0, 0x0b)), // i32.load
+	))
+	require.NoError(t, err)
+	require.NoError(t, m.CheckNoFloats())
+}
+
+func instantiateMetered(t *testing.T, binary []byte) (*Instance, uint32) {
+	metered, err := InjectFuelMetering(binary)
+	require.NoError(t, err)
+	inst := instantiate(t, nil, metered)
+	fuel, err := inst.GlobalIndex(FuelGlobal)
+	require.NoError(t, err)
+	return inst, fuel
+}
+
+func TestFuelMetering(t *testing.T) {
+	inst, fuel := instantiateMetered(t, module(
+		section(1, funcType([]byte{0x7f}, []byte{0x7f})),
+		section(3, []byte{0}),
+		section(7, exportFunc("sum", 0)),
+		section(10, body([]byte{1, 1, 0x7f},
+			0x02, 0x40, 0x03, 0x40, // block; loop
+			0x20, 0, 0x45, 0x0d, 1, // local.get 0; i32.eqz; br_if 1
+			0x20, 1, 0x20, 0, 0x6a, 0x21, 1, // local.get 1; local.get 0; i32.add; local.set 1
+			0x20, 0, 0x41, 1, 0x6b, 0x21, 0, // local.get 0; i32.const 1; i32.sub; local.set 0
+			0x0c, 0, 0x0b, 0x0b, // br 0; end; end
+			0x20, 1, 0x0b)),
+	))
+	// block, loop: 2; 101 times the exit check: 3; 100 times the loop body: 9; local.get, end: 2
+	const cost = 2 + 101*3 + 100*9 + 2
+	inst.SetGlobal(fuel, cost+10)
+	require.EqualValues(t, 5050, call1(t, inst, "sum", 100))
+	require.EqualValues(t, 10, inst.Global(fuel))
+
+	inst.SetGlobal(fuel, cost-1)
+	_, err := inst.Call("sum", 100)
+	require.Error(t, err)
+	require.EqualValues(t, FuelExhausted, int64(inst.Global(fuel)))
+}
+
+func TestFuelMeteringEndlessLoop(t *testing.T) {
+	inst, fuel := instantiateMetered(t, module(
+		section(1, funcType(nil, nil)),
+		section(3, []byte{0}),
+		section(7, exportFunc("loop", 0)),
+		section(10, body(noLocals, 0x03, 0x40, 0x0c, 0, 0x0b, 0x0b)), // loop; br 0; end
+	))
+	inst.SetGlobal(fuel, 1000000)
+	_, err := inst.Call("loop")
+	require.Error(t, err)
+	require.EqualValues(t, FuelExhausted, int64(inst.Global(fuel)))
+
+	_, err = InjectFuelMetering(module(
+		section(6, []byte{0x7e, 1, 0x42, 0, 0x0b}),
+		section(7, concat(name(FuelGlobal), []byte{byte(ExternalGlobal), 0})),
+	))
+	require.Error(t, err)
+}
+
+func TestFuelMeteringContractBinaries(t *testing.T) {
+	files, err := filepath.Glob("../../../contracts/rust/*/test/*_bg.wasm")
+	require.NoError(t, err)
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		metered, err := InjectFuelMetering(data)
+		require.NoError(t, err, file)
+		m, err := Parse(metered)
+		require.NoError(t, err, file)
+		require.NotNil(t, m.Export(FuelGlobal), file)
+	}
+}
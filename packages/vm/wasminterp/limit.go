@@ -0,0 +1,50 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasminterp
+
+import "fmt"
+
+// LimitMemory rewrites the memory section of the module so that its memory never exceeds maxPages.
+// The maximum declared by the module is lowered to maxPages, or set if the module declares none.
+// Every engine enforces the declared maximum: 'memory.grow' beyond it fails and returns -1.
+// The module must not require more than maxPages initially
+func LimitMemory(binary []byte, maxPages uint32) ([]byte, error) {
+	m, err := Parse(binary)
+	if err != nil {
+		return nil, err
+	}
+	if m.Memory == nil {
+		return binary, nil
+	}
+	if m.Memory.Min > maxPages {
+		return nil, fmt.Errorf("initial memory of %d pages exceeds the limit of %d pages", m.Memory.Min, maxPages)
+	}
+	if m.Memory.HasMax && m.Memory.Max <= maxPages {
+		return binary, nil
+	}
+	// one memory with both limits: (memory min maxPages)
+	limits := appendU32(appendU32([]byte{1, 1}, m.Memory.Min), maxPages)
+
+	ret := append([]byte(nil), magic...)
+	r := &reader{data: binary, pos: len(magic)}
+	for !r.eof() {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		content, err := r.bytes(size)
+		if err != nil {
+			return nil, err
+		}
+		if id == sectionMemory {
+			content = limits
+		}
+		ret = appendSection(ret, id, content)
+	}
+	return ret, nil
+}
@@ -0,0 +1,305 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasminterp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FuelGlobal is the name of the exported mutable i64 global injected by InjectFuelMetering.
+// It holds the number of instructions the module is still allowed to execute
+const FuelGlobal = "__fuel"
+
+// FuelExhausted is the value of the fuel global after the module trapped because it ran out of fuel
+const FuelExhausted = int64(-1)
+
+// InjectFuelMetering rewrites the binary of the module so that it counts the executed instructions.
+// The code of each function is split into straight line segments at the control instructions.
+// Before each segment, the number of its instructions is subtracted from the fuel global. When not
+// enough fuel is left, the global is set to FuelExhausted and the module traps with 'unreachable'.
+// The host sets the fuel before it calls the module and reads it back when the module calls the host
+// or returns. The rewriting is deterministic, so every engine executes the same amount of code
+func InjectFuelMetering(binary []byte) ([]byte, error) {
+	m, err := Parse(binary)
+	if err != nil {
+		return nil, err
+	}
+	if m.Export(FuelGlobal) != nil {
+		return nil, fmt.Errorf("export '%s' is reserved", FuelGlobal)
+	}
+	fuel := uint32(len(m.Globals))
+	// (global (mut i64) (i64.const 0))
+	fuelGlobal := []byte{byte(I64), 1, byte(opI64Const), 0, byte(opEnd)}
+	fuelExport := append(encodeName(FuelGlobal), byte(ExternalGlobal))
+	fuelExport = appendU32(fuelExport, fuel)
+
+	ret := append([]byte(nil), magic...)
+	r := &reader{data: binary, pos: len(magic)}
+	globalsDone, exportsDone := false, false
+	for !r.eof() {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		content, err := r.bytes(size)
+		if err != nil {
+			return nil, err
+		}
+		// the sections are ordered by their ids, except the custom and data count sections.
+		// The data count section follows the element section, after the global and export sections
+		if id != sectionCustom && id > sectionGlobal && !globalsDone {
+			ret = appendSection(ret, sectionGlobal, appendVectorItem(nil, fuelGlobal))
+			globalsDone = true
+		}
+		if id != sectionCustom && id > sectionExport && !exportsDone {
+			ret = appendSection(ret, sectionExport, appendVectorItem(nil, fuelExport))
+			exportsDone = true
+		}
+		switch id {
+		case sectionGlobal:
+			content = appendVectorItem(content, fuelGlobal)
+			globalsDone = true
+		case sectionExport:
+			content = appendVectorItem(content, fuelExport)
+			exportsDone = true
+		case sectionCode:
+			if content, err = meterCode(content, fuel); err != nil {
+				return nil, fmt.Errorf("section %d: %v", id, err)
+			}
+		}
+		ret = appendSection(ret, id, content)
+	}
+	if !globalsDone {
+		ret = appendSection(ret, sectionGlobal, appendVectorItem(nil, fuelGlobal))
+	}
+	if !exportsDone {
+		ret = appendSection(ret, sectionExport, appendVectorItem(nil, fuelExport))
+	}
+	// the result must be valid too
+	if _, err := Parse(ret); err != nil {
+		return nil, fmt.Errorf("metered module: %v", err)
+	}
+	return ret, nil
+}
+
+func meterCode(content []byte, fuel uint32) ([]byte, error) {
+	r := &reader{data: content}
+	n, err := readVectorLength(r)
+	if err != nil {
+		return nil, err
+	}
+	ret := appendU32(nil, n)
+	for i := uint32(0); i < n; i++ {
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		body, err := r.bytes(size)
+		if err != nil {
+			return nil, err
+		}
+		metered, err := meterFunction(body, fuel)
+		if err != nil {
+			return nil, fmt.Errorf("function %d: %v", i, err)
+		}
+		ret = appendU32(ret, uint32(len(metered)))
+		ret = append(ret, metered...)
+	}
+	return ret, nil
+}
+
+// meterFunction inserts the metering code before each segment of the function body
+func meterFunction(body []byte, fuel uint32) ([]byte, error) {
+	r := &reader{data: body}
+	numGroups, err := readVectorLength(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < numGroups; i++ {
+		if _, err := r.u32(); err != nil {
+			return nil, err
+		}
+		if _, err := r.byte(); err != nil {
+			return nil, err
+		}
+	}
+	ret := append([]byte(nil), body[:r.pos]...)
+	segmentStart := r.pos
+	count := 0
+	for !r.eof() {
+		op, err := skipInstr(r)
+		if err != nil {
+			return nil, err
+		}
+		count++
+		if endsSegment(op) || r.eof() {
+			ret = appendFuelCharge(ret, fuel, count)
+			ret = append(ret, body[segmentStart:r.pos]...)
+			segmentStart = r.pos
+			count = 0
+		}
+	}
+	return ret, nil
+}
+
+// endsSegment returns true for the instructions after which the execution may continue elsewhere
+// than with the next instruction, or may come from elsewhere
+func endsSegment(op Opcode) bool {
+	switch op {
+	case opBlock, opLoop, opIf, opElse, opEnd, opBr, opBrIf, opBrTable, opReturn, opUnreachable:
+		return true
+	}
+	return false
+}
+
+// appendFuelCharge appends the code which subtracts the cost from the fuel global or traps
+func appendFuelCharge(code []byte, fuel uint32, cost int) []byte {
+	getFuel := appendU32([]byte{byte(opGlobalGet)}, fuel)
+	setFuel := appendU32([]byte{byte(opGlobalSet)}, fuel)
+	constCost := appendS64([]byte{byte(opI64Const)}, int64(cost))
+	code = append(code, getFuel...)
+	code = append(code, constCost...)
+	code = append(code, 0x53, byte(opIf), 0x40) // i64.lt_s; if
+	code = append(code, appendS64([]byte{byte(opI64Const)}, FuelExhausted)...)
+	code = append(code, setFuel...)
+	code = append(code, byte(opUnreachable), byte(opEnd))
+	code = append(code, getFuel...)
+	code = append(code, constCost...)
+	code = append(code, 0x7d) // i64.sub
+	return append(code, setFuel...)
+}
+
+// skipInstr reads the instruction with its immediates. The code is validated by Parse before
+func skipInstr(r *reader) (Opcode, error) {
+	b, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	op := Opcode(b)
+	if op == opPrefixFC {
+		sub, err := r.u32()
+		if err != nil {
+			return 0, err
+		}
+		op = 0xfc00 | Opcode(sub)
+	}
+	if op >= opI32Load && op <= opI64Store32 {
+		if _, err := r.u32(); err != nil {
+			return 0, err
+		}
+		_, err := r.u32()
+		return op, err
+	}
+	switch op {
+	case opBlock, opLoop, opIf:
+		t, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		switch ValueType(t) {
+		case 0x40, I32, I64, F32, F64:
+		default:
+			r.pos--
+			_, err = r.s64()
+		}
+		return op, err
+	case opBr, opBrIf, opCall, opLocalGet, opLocalSet, opLocalTee, opGlobalGet, opGlobalSet:
+		_, err := r.u32()
+		return op, err
+	case opBrTable:
+		n, err := readVectorLength(r)
+		if err != nil {
+			return 0, err
+		}
+		for i := uint32(0); i <= n; i++ {
+			if _, err := r.u32(); err != nil {
+				return 0, err
+			}
+		}
+		return op, nil
+	case opCallIndirect:
+		if _, err := r.u32(); err != nil {
+			return 0, err
+		}
+		_, err := r.byte()
+		return op, err
+	case opMemorySize, opMemoryGrow, opMemoryFill:
+		_, err := r.byte()
+		return op, err
+	case opMemoryCopy:
+		_, err := r.bytes(2)
+		return op, err
+	case opI32Const:
+		_, err := r.s32()
+		return op, err
+	case opI64Const:
+		_, err := r.s64()
+		return op, err
+	case opF32Const:
+		_, err := r.bytes(4)
+		return op, err
+	case opF64Const:
+		_, err := r.bytes(8)
+		return op, err
+	}
+	if _, ok := numericSignatures[op]; ok {
+		return op, nil
+	}
+	switch op {
+	case opUnreachable, opNop, opElse, opEnd, opReturn, opDrop, opSelect:
+		return op, nil
+	}
+	return 0, errors.New("unsupported instruction " + op.String())
+}
+
+func appendSection(ret []byte, id byte, content []byte) []byte {
+	ret = append(ret, id)
+	ret = appendU32(ret, uint32(len(content)))
+	return append(ret, content...)
+}
+
+// appendVectorItem appends the encoded item to the encoded vector
+func appendVectorItem(vector []byte, item []byte) []byte {
+	n := uint32(0)
+	pos := 0
+	if len(vector) > 0 {
+		r := &reader{data: vector}
+		n, _ = r.u32()
+		pos = r.pos
+	}
+	ret := appendU32(nil, n+1)
+	ret = append(ret, vector[pos:]...)
+	return append(ret, item...)
+}
+
+func encodeName(s string) []byte {
+	return append(appendU32(nil, uint32(len(s))), s...)
+}
+
+func appendU32(b []byte, v uint32) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func appendS64(b []byte, v int64) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
@@ -0,0 +1,750 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package wasminterp is a pure Go interpreter of WebAssembly modules. It implements the MVP of the
+// WebAssembly specification with the sign extension, saturating conversion and memory.copy/memory.fill
+// extensions. It doesn't need cgo, and it is deterministic: it doesn't use any resources of the host
+// except the memory. The imports of the module are limited to functions provided by the host
+package wasminterp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type ValueType byte
+
+const (
+	I32 ValueType = 0x7f
+	I64 ValueType = 0x7e
+	F32 ValueType = 0x7d
+	F64 ValueType = 0x7c
+)
+
+func (t ValueType) String() string {
+	switch t {
+	case I32:
+		return "i32"
+	case I64:
+		return "i64"
+	case F32:
+		return "f32"
+	case F64:
+		return "f64"
+	}
+	return fmt.Sprintf("type(0x%02x)", byte(t))
+}
+
+func (t ValueType) isFloat() bool {
+	return t == F32 || t == F64
+}
+
+type FuncType struct {
+	Params  []ValueType
+	Results []ValueType
+}
+
+func (ft *FuncType) Equal(other *FuncType) bool {
+	return equalTypes(ft.Params, other.Params) && equalTypes(ft.Results, other.Results)
+}
+
+func (ft *FuncType) String() string {
+	str := func(types []ValueType) string {
+		s := make([]string, len(types))
+		for i, t := range types {
+			s[i] = t.String()
+		}
+		return "(" + strings.Join(s, ",") + ")"
+	}
+	return str(ft.Params) + "->" + str(ft.Results)
+}
+
+func equalTypes(a, b []ValueType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type ExternalKind byte
+
+const (
+	ExternalFunction ExternalKind = 0
+	ExternalTable    ExternalKind = 1
+	ExternalMemory   ExternalKind = 2
+	ExternalGlobal   ExternalKind = 3
+)
+
+func (k ExternalKind) String() string {
+	switch k {
+	case ExternalFunction:
+		return "function"
+	case ExternalTable:
+		return "table"
+	case ExternalMemory:
+		return "memory"
+	case ExternalGlobal:
+		return "global"
+	}
+	return fmt.Sprintf("kind(%d)", byte(k))
+}
+
+// Import is an imported function
+type Import struct {
+	Module    string
+	Name      string
+	TypeIndex uint32
+}
+
+type Export struct {
+	Name  string
+	Kind  ExternalKind
+	Index uint32
+}
+
+type Limits struct {
+	Min    uint32
+	Max    uint32
+	HasMax bool
+}
+
+type Global struct {
+	Type    ValueType
+	Mutable bool
+	Init    uint64
+}
+
+// Function is a function defined in the module
+type Function struct {
+	TypeIndex uint32
+	Locals    []ValueType
+	Body      []byte
+	code      *compiledCode
+}
+
+type ElementSegment struct {
+	Offset    uint32
+	Functions []uint32
+}
+
+type DataSegment struct {
+	Offset uint32
+	Data   []byte
+}
+
+// Module is the decoded and validated Wasm module
+type Module struct {
+	Types     []FuncType
+	Imports   []Import
+	Functions []Function
+	Table     *Limits
+	Memory    *Limits
+	Globals   []Global
+	Exports   []Export
+	Start     *uint32
+	Elements  []ElementSegment
+	Data      []DataSegment
+}
+
+const (
+	PageSize = 65536
+	MaxPages = 65536
+	// limits the number of the elements of the vectors of the module
+	maxVectorLength = 1 << 20
+)
+
+const (
+	sectionCustom    = 0
+	sectionType      = 1
+	sectionImport    = 2
+	sectionFunction  = 3
+	sectionTable     = 4
+	sectionMemory    = 5
+	sectionGlobal    = 6
+	sectionExport    = 7
+	sectionStart     = 8
+	sectionElement   = 9
+	sectionCode      = 10
+	sectionData      = 11
+	sectionDataCount = 12
+)
+
+var magic = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+// Parse decodes and validates the binary of the module
+func Parse(data []byte) (*Module, error) {
+	if !bytes.HasPrefix(data, magic) {
+		return nil, errors.New("not a Wasm module or unsupported version")
+	}
+	m := &Module{}
+	r := &reader{data: data, pos: len(magic)}
+	var funcTypeIndices []uint32
+	lastID := byte(0)
+	for !r.eof() {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		content, err := r.bytes(size)
+		if err != nil {
+			return nil, err
+		}
+		if id == sectionCustom {
+			continue
+		}
+		// the data count section is placed before the code section
+		order := func(id byte) byte {
+			if id == sectionDataCount {
+				return sectionElement*2 + 1
+			}
+			return id * 2
+		}
+		if order(id) <= order(lastID) {
+			return nil, fmt.Errorf("unexpected section %d", id)
+		}
+		lastID = id
+		sr := &reader{data: content}
+		switch id {
+		case sectionType:
+			err = m.readTypes(sr)
+		case sectionImport:
+			err = m.readImports(sr)
+		case sectionFunction:
+			funcTypeIndices, err = readIndices(sr)
+		case sectionTable:
+			m.Table, err = readSingleLimits(sr, true)
+		case sectionMemory:
+			m.Memory, err = readSingleLimits(sr, false)
+		case sectionGlobal:
+			err = m.readGlobals(sr)
+		case sectionExport:
+			err = m.readExports(sr)
+		case sectionStart:
+			var start uint32
+			start, err = sr.u32()
+			m.Start = &start
+		case sectionElement:
+			err = m.readElements(sr)
+		case sectionCode:
+			err = m.readCode(sr, funcTypeIndices)
+		case sectionData:
+			err = m.readData(sr)
+		case sectionDataCount:
+			_, err = sr.u32()
+		default:
+			err = fmt.Errorf("unknown section %d", id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("section %d: %v", id, err)
+		}
+		if !sr.eof() {
+			return nil, fmt.Errorf("section %d: unexpected data at the end of the section", id)
+		}
+	}
+	if len(funcTypeIndices) != len(m.Functions) {
+		return nil, errors.New("function and code sections have inconsistent lengths")
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func readVectorLength(r *reader) (uint32, error) {
+	n, err := r.u32()
+	if err != nil {
+		return 0, err
+	}
+	if n > maxVectorLength || int(n) > len(r.data)-r.pos {
+		return 0, fmt.Errorf("too many elements: %d", n)
+	}
+	return n, nil
+}
+
+func readIndices(r *reader) ([]uint32, error) {
+	n, err := readVectorLength(r)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]uint32, n)
+	for i := range ret {
+		if ret[i], err = r.u32(); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func readValueType(r *reader) (ValueType, error) {
+	b, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	switch t := ValueType(b); t {
+	case I32, I64, F32, F64:
+		return t, nil
+	}
+	return 0, fmt.Errorf("unsupported value type 0x%02x", b)
+}
+
+func readValueTypes(r *reader) ([]ValueType, error) {
+	n, err := readVectorLength(r)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]ValueType, n)
+	for i := range ret {
+		if ret[i], err = readValueType(r); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func readLimits(r *reader) (*Limits, error) {
+	flags, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	ret := &Limits{}
+	if ret.Min, err = r.u32(); err != nil {
+		return nil, err
+	}
+	switch flags {
+	case 0:
+	case 1:
+		ret.HasMax = true
+		if ret.Max, err = r.u32(); err != nil {
+			return nil, err
+		}
+		if ret.Max < ret.Min {
+			return nil, errors.New("maximum is less than minimum")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported limits flags 0x%02x", flags)
+	}
+	return ret, nil
+}
+
+// readSingleLimits reads the table or memory section. Only one table and one memory are supported
+func readSingleLimits(r *reader, table bool) (*Limits, error) {
+	n, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	if n > 1 {
+		return nil, errors.New("multiple tables or memories are not supported")
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	if table {
+		elemType, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if elemType != 0x70 {
+			return nil, fmt.Errorf("unsupported table element type 0x%02x", elemType)
+		}
+	}
+	ret, err := readLimits(r)
+	if err != nil {
+		return nil, err
+	}
+	if !table && (ret.Min > MaxPages || (ret.HasMax && ret.Max > MaxPages)) {
+		return nil, errors.New("memory size must be at most 65536 pages (4GiB)")
+	}
+	return ret, nil
+}
+
+func (m *Module) readTypes(r *reader) error {
+	n, err := readVectorLength(r)
+	if err != nil {
+		return err
+	}
+	m.Types = make([]FuncType, n)
+	for i := range m.Types {
+		form, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if form != 0x60 {
+			return fmt.Errorf("unsupported type form 0x%02x", form)
+		}
+		if m.Types[i].Params, err = readValueTypes(r); err != nil {
+			return err
+		}
+		if m.Types[i].Results, err = readValueTypes(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Module) readImports(r *reader) error {
+	n, err := readVectorLength(r)
+	if err != nil {
+		return err
+	}
+	m.Imports = make([]Import, n)
+	for i := range m.Imports {
+		imp := &m.Imports[i]
+		if imp.Module, err = r.name(); err != nil {
+			return err
+		}
+		if imp.Name, err = r.name(); err != nil {
+			return err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if ExternalKind(kind) != ExternalFunction {
+			return fmt.Errorf("import %s.%s: imports of kind '%s' are not supported", imp.Module, imp.Name, ExternalKind(kind))
+		}
+		if imp.TypeIndex, err = r.u32(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readConstExpr reads the initializer expression. Only constants are supported, because
+// the module can't import globals
+func readConstExpr(r *reader, t ValueType) (uint64, error) {
+	b, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	op := Opcode(b)
+	var ret uint64
+	switch {
+	case op == opI32Const && t == I32:
+		v, err := r.s32()
+		if err != nil {
+			return 0, err
+		}
+		ret = uint64(uint32(v))
+	case op == opI64Const && t == I64:
+		v, err := r.s64()
+		if err != nil {
+			return 0, err
+		}
+		ret = uint64(v)
+	case op == opF32Const && t == F32:
+		v, err := r.f32()
+		if err != nil {
+			return 0, err
+		}
+		ret = uint64(v)
+	case op == opF64Const && t == F64:
+		if ret, err = r.f64(); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("unsupported constant expression 0x%02x of type %s", op, t)
+	}
+	end, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	if Opcode(end) != opEnd {
+		return 0, errors.New("constant expression must be a single constant")
+	}
+	return ret, nil
+}
+
+func (m *Module) readGlobals(r *reader) error {
+	n, err := readVectorLength(r)
+	if err != nil {
+		return err
+	}
+	m.Globals = make([]Global, n)
+	for i := range m.Globals {
+		g := &m.Globals[i]
+		if g.Type, err = readValueType(r); err != nil {
+			return err
+		}
+		mut, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if mut > 1 {
+			return fmt.Errorf("invalid mutability 0x%02x", mut)
+		}
+		g.Mutable = mut == 1
+		if g.Init, err = readConstExpr(r, g.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Module) readExports(r *reader) error {
+	n, err := readVectorLength(r)
+	if err != nil {
+		return err
+	}
+	m.Exports = make([]Export, n)
+	names := make(map[string]bool)
+	for i := range m.Exports {
+		exp := &m.Exports[i]
+		if exp.Name, err = r.name(); err != nil {
+			return err
+		}
+		if names[exp.Name] {
+			return fmt.Errorf("duplicate export '%s'", exp.Name)
+		}
+		names[exp.Name] = true
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if kind > byte(ExternalGlobal) {
+			return fmt.Errorf("invalid export kind 0x%02x", kind)
+		}
+		exp.Kind = ExternalKind(kind)
+		if exp.Index, err = r.u32(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Module) readElements(r *reader) error {
+	n, err := readVectorLength(r)
+	if err != nil {
+		return err
+	}
+	m.Elements = make([]ElementSegment, n)
+	for i := range m.Elements {
+		flags, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if flags != 0 {
+			return fmt.Errorf("unsupported element segment flags %d", flags)
+		}
+		offset, err := readConstExpr(r, I32)
+		if err != nil {
+			return err
+		}
+		m.Elements[i].Offset = uint32(offset)
+		if m.Elements[i].Functions, err = readIndices(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Module) readCode(r *reader, typeIndices []uint32) error {
+	n, err := readVectorLength(r)
+	if err != nil {
+		return err
+	}
+	if int(n) != len(typeIndices) {
+		return errors.New("function and code sections have inconsistent lengths")
+	}
+	m.Functions = make([]Function, n)
+	for i := range m.Functions {
+		f := &m.Functions[i]
+		f.TypeIndex = typeIndices[i]
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		body, err := r.bytes(size)
+		if err != nil {
+			return err
+		}
+		br := &reader{data: body}
+		numGroups, err := readVectorLength(br)
+		if err != nil {
+			return err
+		}
+		total := uint64(0)
+		for j := uint32(0); j < numGroups; j++ {
+			count, err := br.u32()
+			if err != nil {
+				return err
+			}
+			t, err := readValueType(br)
+			if err != nil {
+				return err
+			}
+			total += uint64(count)
+			if total > maxLocals {
+				return fmt.Errorf("function %d: too many locals", len(m.Imports)+i)
+			}
+			for k := uint32(0); k < count; k++ {
+				f.Locals = append(f.Locals, t)
+			}
+		}
+		f.Body = body[br.pos:]
+	}
+	return nil
+}
+
+func (m *Module) readData(r *reader) error {
+	n, err := readVectorLength(r)
+	if err != nil {
+		return err
+	}
+	m.Data = make([]DataSegment, n)
+	for i := range m.Data {
+		flags, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if flags != 0 {
+			return fmt.Errorf("unsupported data segment flags %d", flags)
+		}
+		offset, err := readConstExpr(r, I32)
+		if err != nil {
+			return err
+		}
+		m.Data[i].Offset = uint32(offset)
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if m.Data[i].Data, err = r.bytes(size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NumFunctions is the number of the functions in the index space of the module: imported and defined
+func (m *Module) NumFunctions() uint32 {
+	return uint32(len(m.Imports) + len(m.Functions))
+}
+
+// FunctionType returns the type of the function in the index space of the module
+func (m *Module) FunctionType(index uint32) *FuncType {
+	if index < uint32(len(m.Imports)) {
+		return &m.Types[m.Imports[index].TypeIndex]
+	}
+	return &m.Types[m.Functions[index-uint32(len(m.Imports))].TypeIndex]
+}
+
+// Export returns the export with the name or nil
+func (m *Module) Export(name string) *Export {
+	for i := range m.Exports {
+		if m.Exports[i].Name == name {
+			return &m.Exports[i]
+		}
+	}
+	return nil
+}
+
+// CheckNoFloats returns an error describing the first use of the floating point types or instructions
+// in the module, or nil if the module uses integers only
+func (m *Module) CheckNoFloats() error {
+	for i := range m.Types {
+		if hasFloat(m.Types[i].Params) || hasFloat(m.Types[i].Results) {
+			return fmt.Errorf("type %d: floating point type in %s", i, &m.Types[i])
+		}
+	}
+	for i := range m.Globals {
+		if m.Globals[i].Type.isFloat() {
+			return fmt.Errorf("global %d: floating point type %s", i, m.Globals[i].Type)
+		}
+	}
+	for i := range m.Functions {
+		f := &m.Functions[i]
+		index := len(m.Imports) + i
+		if hasFloat(f.Locals) {
+			return fmt.Errorf("function %d: floating point local", index)
+		}
+		for _, in := range f.code.instrs {
+			if isFloatInstruction(in.op) {
+				return fmt.Errorf("function %d: floating point instruction %s", index, in.op)
+			}
+		}
+	}
+	return nil
+}
+
+func hasFloat(types []ValueType) bool {
+	for _, t := range types {
+		if t.isFloat() {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the indices of the module and the code of the functions
+func (m *Module) validate() error {
+	for _, imp := range m.Imports {
+		if imp.TypeIndex >= uint32(len(m.Types)) {
+			return fmt.Errorf("import %s.%s: invalid type index %d", imp.Module, imp.Name, imp.TypeIndex)
+		}
+	}
+	for i := range m.Functions {
+		if m.Functions[i].TypeIndex >= uint32(len(m.Types)) {
+			return fmt.Errorf("function %d: invalid type index %d", len(m.Imports)+i, m.Functions[i].TypeIndex)
+		}
+	}
+	for _, exp := range m.Exports {
+		var count uint32
+		switch exp.Kind {
+		case ExternalFunction:
+			count = m.NumFunctions()
+		case ExternalTable:
+			if m.Table != nil {
+				count = 1
+			}
+		case ExternalMemory:
+			if m.Memory != nil {
+				count = 1
+			}
+		case ExternalGlobal:
+			count = uint32(len(m.Globals))
+		}
+		if exp.Index >= count {
+			return fmt.Errorf("export '%s': invalid %s index %d", exp.Name, exp.Kind, exp.Index)
+		}
+	}
+	if m.Start != nil {
+		if *m.Start >= m.NumFunctions() {
+			return fmt.Errorf("invalid start function %d", *m.Start)
+		}
+		if ft := m.FunctionType(*m.Start); len(ft.Params) != 0 || len(ft.Results) != 0 {
+			return errors.New("start function must have no parameters and no results")
+		}
+	}
+	for _, e := range m.Elements {
+		if m.Table == nil {
+			return errors.New("element segment without table")
+		}
+		for _, fi := range e.Functions {
+			if fi >= m.NumFunctions() {
+				return fmt.Errorf("element segment: invalid function index %d", fi)
+			}
+		}
+	}
+	if len(m.Data) > 0 && m.Memory == nil {
+		return errors.New("data segment without memory")
+	}
+	for i := range m.Functions {
+		code, err := compile(m, &m.Functions[i])
+		if err != nil {
+			return fmt.Errorf("function %d: %v", len(m.Imports)+i, err)
+		}
+		m.Functions[i].code = code
+	}
+	return nil
+}
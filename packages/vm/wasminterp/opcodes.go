@@ -0,0 +1,199 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasminterp
+
+import "fmt"
+
+// Opcode is the opcode of the instruction. The opcodes with the 0xfc prefix are 0xfc00 | <sub-opcode>
+type Opcode uint16
+
+const (
+	opUnreachable  Opcode = 0x00
+	opNop          Opcode = 0x01
+	opBlock        Opcode = 0x02
+	opLoop         Opcode = 0x03
+	opIf           Opcode = 0x04
+	opElse         Opcode = 0x05
+	opEnd          Opcode = 0x0b
+	opBr           Opcode = 0x0c
+	opBrIf         Opcode = 0x0d
+	opBrTable      Opcode = 0x0e
+	opReturn       Opcode = 0x0f
+	opCall         Opcode = 0x10
+	opCallIndirect Opcode = 0x11
+	opDrop         Opcode = 0x1a
+	opSelect       Opcode = 0x1b
+	opLocalGet     Opcode = 0x20
+	opLocalSet     Opcode = 0x21
+	opLocalTee     Opcode = 0x22
+	opGlobalGet    Opcode = 0x23
+	opGlobalSet    Opcode = 0x24
+	opI32Load      Opcode = 0x28
+	opI64Store32   Opcode = 0x3e
+	opMemorySize   Opcode = 0x3f
+	opMemoryGrow   Opcode = 0x40
+	opI32Const     Opcode = 0x41
+	opI64Const     Opcode = 0x42
+	opF32Const     Opcode = 0x43
+	opF64Const     Opcode = 0x44
+
+	opPrefixFC         Opcode = 0xfc
+	opMemoryCopy       Opcode = 0xfc0a
+	opMemoryFill       Opcode = 0xfc0b
+	firstNumericOpcode Opcode = 0x45
+	lastNumericOpcode  Opcode = 0xc4
+)
+
+// internal opcodes of the compiled code
+const (
+	opJump      Opcode = 0xff00 + iota // jump to a
+	opJumpIfNot                        // jump to a if the condition is zero
+)
+
+var opcodeNames = map[Opcode]string{
+	0x00: "unreachable", 0x01: "nop", 0x02: "block", 0x03: "loop", 0x04: "if", 0x05: "else", 0x0b: "end",
+	0x0c: "br", 0x0d: "br_if", 0x0e: "br_table", 0x0f: "return", 0x10: "call", 0x11: "call_indirect",
+	0x1a: "drop", 0x1b: "select",
+	0x20: "local.get", 0x21: "local.set", 0x22: "local.tee", 0x23: "global.get", 0x24: "global.set",
+	0x28: "i32.load", 0x29: "i64.load", 0x2a: "f32.load", 0x2b: "f64.load",
+	0x2c: "i32.load8_s", 0x2d: "i32.load8_u", 0x2e: "i32.load16_s", 0x2f: "i32.load16_u",
+	0x30: "i64.load8_s", 0x31: "i64.load8_u", 0x32: "i64.load16_s", 0x33: "i64.load16_u",
+	0x34: "i64.load32_s", 0x35: "i64.load32_u",
+	0x36: "i32.store", 0x37: "i64.store", 0x38: "f32.store", 0x39: "f64.store",
+	0x3a: "i32.store8", 0x3b: "i32.store16", 0x3c: "i64.store8", 0x3d: "i64.store16", 0x3e: "i64.store32",
+	0x3f: "memory.size", 0x40: "memory.grow",
+	0x41: "i32.const", 0x42: "i64.const", 0x43: "f32.const", 0x44: "f64.const",
+	0x45: "i32.eqz", 0x46: "i32.eq", 0x47: "i32.ne", 0x48: "i32.lt_s", 0x49: "i32.lt_u", 0x4a: "i32.gt_s",
+	0x4b: "i32.gt_u", 0x4c: "i32.le_s", 0x4d: "i32.le_u", 0x4e: "i32.ge_s", 0x4f: "i32.ge_u",
+	0x50: "i64.eqz", 0x51: "i64.eq", 0x52: "i64.ne", 0x53: "i64.lt_s", 0x54: "i64.lt_u", 0x55: "i64.gt_s",
+	0x56: "i64.gt_u", 0x57: "i64.le_s", 0x58: "i64.le_u", 0x59: "i64.ge_s", 0x5a: "i64.ge_u",
+	0x5b: "f32.eq", 0x5c: "f32.ne", 0x5d: "f32.lt", 0x5e: "f32.gt", 0x5f: "f32.le", 0x60: "f32.ge",
+	0x61: "f64.eq", 0x62: "f64.ne", 0x63: "f64.lt", 0x64: "f64.gt", 0x65: "f64.le", 0x66: "f64.ge",
+	0x67: "i32.clz", 0x68: "i32.ctz", 0x69: "i32.popcnt", 0x6a: "i32.add", 0x6b: "i32.sub", 0x6c: "i32.mul",
+	0x6d: "i32.div_s", 0x6e: "i32.div_u", 0x6f: "i32.rem_s", 0x70: "i32.rem_u", 0x71: "i32.and", 0x72: "i32.or",
+	0x73: "i32.xor", 0x74: "i32.shl", 0x75: "i32.shr_s", 0x76: "i32.shr_u", 0x77: "i32.rotl", 0x78: "i32.rotr",
+	0x79: "i64.clz", 0x7a: "i64.ctz", 0x7b: "i64.popcnt", 0x7c: "i64.add", 0x7d: "i64.sub", 0x7e: "i64.mul",
+	0x7f: "i64.div_s", 0x80: "i64.div_u", 0x81: "i64.rem_s", 0x82: "i64.rem_u", 0x83: "i64.and", 0x84: "i64.or",
+	0x85: "i64.xor", 0x86: "i64.shl", 0x87: "i64.shr_s", 0x88: "i64.shr_u", 0x89: "i64.rotl", 0x8a: "i64.rotr",
+	0x8b: "f32.abs", 0x8c: "f32.neg", 0x8d: "f32.ceil", 0x8e: "f32.floor", 0x8f: "f32.trunc", 0x90: "f32.nearest",
+	0x91: "f32.sqrt", 0x92: "f32.add", 0x93: "f32.sub", 0x94: "f32.mul", 0x95: "f32.div", 0x96: "f32.min",
+	0x97: "f32.max", 0x98: "f32.copysign",
+	0x99: "f64.abs", 0x9a: "f64.neg", 0x9b: "f64.ceil", 0x9c: "f64.floor", 0x9d: "f64.trunc", 0x9e: "f64.nearest",
+	0x9f: "f64.sqrt", 0xa0: "f64.add", 0xa1: "f64.sub", 0xa2: "f64.mul", 0xa3: "f64.div", 0xa4: "f64.min",
+	0xa5: "f64.max", 0xa6: "f64.copysign",
+	0xa7: "i32.wrap_i64", 0xa8: "i32.trunc_f32_s", 0xa9: "i32.trunc_f32_u", 0xaa: "i32.trunc_f64_s",
+	0xab: "i32.trunc_f64_u", 0xac: "i64.extend_i32_s", 0xad: "i64.extend_i32_u", 0xae: "i64.trunc_f32_s",
+	0xaf: "i64.trunc_f32_u", 0xb0: "i64.trunc_f64_s", 0xb1: "i64.trunc_f64_u", 0xb2: "f32.convert_i32_s",
+	0xb3: "f32.convert_i32_u", 0xb4: "f32.convert_i64_s", 0xb5: "f32.convert_i64_u", 0xb6: "f32.demote_f64",
+	0xb7: "f64.convert_i32_s", 0xb8: "f64.convert_i32_u", 0xb9: "f64.convert_i64_s", 0xba: "f64.convert_i64_u",
+	0xbb: "f64.promote_f32", 0xbc: "i32.reinterpret_f32", 0xbd: "i64.reinterpret_f64",
+	0xbe: "f32.reinterpret_i32", 0xbf: "f64.reinterpret_i64",
+	0xc0: "i32.extend8_s", 0xc1: "i32.extend16_s", 0xc2: "i64.extend8_s", 0xc3: "i64.extend16_s",
+	0xc4:   "i64.extend32_s",
+	0xfc00: "i32.trunc_sat_f32_s", 0xfc01: "i32.trunc_sat_f32_u", 0xfc02: "i32.trunc_sat_f64_s",
+	0xfc03: "i32.trunc_sat_f64_u", 0xfc04: "i64.trunc_sat_f32_s", 0xfc05: "i64.trunc_sat_f32_u",
+	0xfc06: "i64.trunc_sat_f64_s", 0xfc07: "i64.trunc_sat_f64_u", 0xfc0a: "memory.copy", 0xfc0b: "memory.fill",
+}
+
+func (op Opcode) String() string {
+	if name, ok := opcodeNames[op]; ok {
+		return name
+	}
+	return fmt.Sprintf("opcode(0x%x)", uint16(op))
+}
+
+// opSignature is the signature of the numeric instruction
+type opSignature struct {
+	params []ValueType
+	result ValueType
+}
+
+var numericSignatures = func() map[Opcode]opSignature {
+	ret := make(map[Opcode]opSignature)
+	set := func(first, last Opcode, params []ValueType, result ValueType) {
+		for op := first; op <= last; op++ {
+			ret[op] = opSignature{params: params, result: result}
+		}
+	}
+	i32, i64, f32, f64 := []ValueType{I32}, []ValueType{I64}, []ValueType{F32}, []ValueType{F64}
+	i32i32, i64i64 := []ValueType{I32, I32}, []ValueType{I64, I64}
+	f32f32, f64f64 := []ValueType{F32, F32}, []ValueType{F64, F64}
+
+	set(0x45, 0x45, i32, I32)
+	set(0x46, 0x4f, i32i32, I32)
+	set(0x50, 0x50, i64, I32)
+	set(0x51, 0x5a, i64i64, I32)
+	set(0x5b, 0x60, f32f32, I32)
+	set(0x61, 0x66, f64f64, I32)
+	set(0x67, 0x69, i32, I32)
+	set(0x6a, 0x78, i32i32, I32)
+	set(0x79, 0x7b, i64, I64)
+	set(0x7c, 0x8a, i64i64, I64)
+	set(0x8b, 0x91, f32, F32)
+	set(0x92, 0x98, f32f32, F32)
+	set(0x99, 0x9f, f64, F64)
+	set(0xa0, 0xa6, f64f64, F64)
+	set(0xa7, 0xa7, i64, I32)
+	set(0xa8, 0xa9, f32, I32)
+	set(0xaa, 0xab, f64, I32)
+	set(0xac, 0xad, i32, I64)
+	set(0xae, 0xaf, f32, I64)
+	set(0xb0, 0xb1, f64, I64)
+	set(0xb2, 0xb3, i32, F32)
+	set(0xb4, 0xb5, i64, F32)
+	set(0xb6, 0xb6, f64, F32)
+	set(0xb7, 0xb8, i32, F64)
+	set(0xb9, 0xba, i64, F64)
+	set(0xbb, 0xbb, f32, F64)
+	set(0xbc, 0xbc, f32, I32)
+	set(0xbd, 0xbd, f64, I64)
+	set(0xbe, 0xbe, i32, F32)
+	set(0xbf, 0xbf, i64, F64)
+	set(0xc0, 0xc1, i32, I32)
+	set(0xc2, 0xc4, i64, I64)
+	set(0xfc00, 0xfc01, f32, I32)
+	set(0xfc02, 0xfc03, f64, I32)
+	set(0xfc04, 0xfc05, f32, I64)
+	set(0xfc06, 0xfc07, f64, I64)
+	return ret
+}()
+
+// memorySignature returns the value type and the access size of the load or store instruction
+func memorySignature(op Opcode) (ValueType, uint32, bool) {
+	switch op {
+	case 0x28, 0x36:
+		return I32, 4, op == 0x36
+	case 0x29, 0x37:
+		return I64, 8, op == 0x37
+	case 0x2a, 0x38:
+		return F32, 4, op == 0x38
+	case 0x2b, 0x39:
+		return F64, 8, op == 0x39
+	case 0x2c, 0x2d, 0x3a:
+		return I32, 1, op == 0x3a
+	case 0x2e, 0x2f, 0x3b:
+		return I32, 2, op == 0x3b
+	case 0x30, 0x31, 0x3c:
+		return I64, 1, op == 0x3c
+	case 0x32, 0x33, 0x3d:
+		return I64, 2, op == 0x3d
+	case 0x34, 0x35, 0x3e:
+		return I64, 4, op == 0x3e
+	}
+	panic("not a memory instruction")
+}
+
+// isFloatInstruction checks if the instruction takes or produces floating point values
+func isFloatInstruction(op Opcode) bool {
+	switch op {
+	case opF32Const, opF64Const:
+		return true
+	}
+	if op >= opI32Load && op <= opI64Store32 {
+		t, _, _ := memorySignature(op)
+		return t.isFloat()
+	}
+	sig, ok := numericSignatures[op]
+	return ok && (sig.result.isFloat() || hasFloat(sig.params))
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasminterp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var errUnexpectedEnd = errors.New("unexpected end of the binary")
+
+// reader decodes the primitive values of the Wasm binary format
+type reader struct {
+	data []byte
+	pos  int
+}
+
+func (r *reader) eof() bool {
+	return r.pos >= len(r.data)
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, errUnexpectedEnd
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) bytes(n uint32) ([]byte, error) {
+	if uint64(r.pos)+uint64(n) > uint64(len(r.data)) {
+		return nil, errUnexpectedEnd
+	}
+	ret := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return ret, nil
+}
+
+func (r *reader) u32() (uint32, error) {
+	v, err := r.leb128(32, false)
+	return uint32(v), err
+}
+
+func (r *reader) s32() (int32, error) {
+	v, err := r.leb128(32, true)
+	return int32(v), err
+}
+
+func (r *reader) s64() (int64, error) {
+	v, err := r.leb128(64, true)
+	return int64(v), err
+}
+
+func (r *reader) f32() (uint32, error) {
+	b, err := r.bytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24, nil
+}
+
+func (r *reader) f64() (uint64, error) {
+	b, err := r.bytes(8)
+	if err != nil {
+		return 0, err
+	}
+	lo := uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24
+	hi := uint64(b[4]) | uint64(b[5])<<8 | uint64(b[6])<<16 | uint64(b[7])<<24
+	return lo | hi<<32, nil
+}
+
+func (r *reader) name() (string, error) {
+	n, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(n)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// leb128 decodes the LEB128 encoded integer of the given bit size
+func (r *reader) leb128(size uint, signed bool) (uint64, error) {
+	var ret uint64
+	var shift uint
+	maxBytes := int((size + 6) / 7)
+	for i := 0; i < maxBytes; i++ {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		ret |= uint64(b&0x7f) << shift
+		shift += 7
+		if b&0x80 != 0 {
+			continue
+		}
+		if i == maxBytes-1 {
+			// the unused bits of the last byte must be the sign extension or zero
+			unused := shift - size
+			mask := byte(0x7f) &^ (byte(0x7f) >> unused)
+			top := b & mask
+			if signed {
+				negative := b&(0x40>>unused) != 0
+				if (negative && top != mask) || (!negative && top != 0) {
+					return 0, fmt.Errorf("integer too large")
+				}
+			} else if top != 0 {
+				return 0, fmt.Errorf("integer too large")
+			}
+		}
+		if signed && shift < 64 && b&0x40 != 0 {
+			ret |= math.MaxUint64 << shift
+		}
+		if size < 64 {
+			if signed {
+				ret = uint64(int64(ret<<(64-size)) >> (64 - size))
+			} else {
+				ret &= 1<<size - 1
+			}
+		}
+		return ret, nil
+	}
+	return 0, fmt.Errorf("integer representation too long")
+}
@@ -216,12 +216,7 @@ func (o *ScDict) SetBytes(keyId int32, typeId int32, bytes []byte) {
 
 	if keyId == wasmhost.KeyLength {
 		if o.kvStore != nil {
-			//TODO this goes wrong for state, should clear map tree instead
-			o.kvStore = dict.New()
-			//if (o.typeId & wasmhost.OBJTYPE_ARRAY) != 0 {
-			//	key := o.NestedKey()[1:]
-			//	o.kvStore.Del(kv.Key(key))
-			//}
+			o.clear()
 		}
 		o.objects = make(map[int32]int32)
 		o.length = 0
@@ -230,6 +225,20 @@ func (o *ScDict) SetBytes(keyId int32, typeId int32, bytes []byte) {
 	o.kvStore.Set(o.key(keyId, typeId), bytes)
 }
 
+// clear removes all nested keys of the object from the underlying kvStore
+func (o *ScDict) clear() {
+	if o.isRoot {
+		o.kvStore = dict.New()
+		return
+	}
+	key := o.NestedKey()[1:]
+	o.kvStore.DelPrefix(kv.Key(key + "."))
+	if (o.typeId & wasmhost.OBJTYPE_ARRAY) != 0 {
+		// array length is stored under the key of the array itself
+		o.kvStore.Del(kv.Key(key))
+	}
+}
+
 func (o *ScDict) Suffix(keyId int32) string {
 	if (o.typeId & wasmhost.OBJTYPE_ARRAY) != 0 {
 		return fmt.Sprintf(".%d", keyId)
@@ -27,6 +27,10 @@ func (s ScViewState) Del(key kv.Key) {
 	s.ctxView.Log().Panicf("ScViewState.Del")
 }
 
+func (s ScViewState) DelPrefix(prefix kv.Key) {
+	s.ctxView.Log().Panicf("ScViewState.DelPrefix")
+}
+
 func (s ScViewState) Get(key kv.Key) ([]byte, error) {
 	return s.viewState.Get(key)
 }
@@ -61,10 +61,16 @@ func (host *wasmProcessor) call(ctx coretypes.Sandbox, ctxView coretypes.Sandbox
 
 	saveCtx := host.ctx
 	saveCtxView := host.ctxView
+	saveGasMeter := host.GasMeter()
 
 	host.ctx = ctx
 	host.ctxView = ctxView
 	host.nesting++
+	if ctx != nil {
+		host.SetGasMeter(ctx)
+	} else {
+		host.SetGasMeter(nil)
+	}
 
 	defer func() {
 		host.nesting--
@@ -75,6 +81,7 @@ func (host *wasmProcessor) call(ctx coretypes.Sandbox, ctxView coretypes.Sandbox
 		}
 		host.ctx = saveCtx
 		host.ctxView = saveCtxView
+		host.SetGasMeter(saveGasMeter)
 	}()
 
 	testMode, _ := host.params().Has("testMode")
@@ -118,8 +125,18 @@ func (host *wasmProcessor) GetEntryPoint(code coretypes.Hname) (coretypes.EntryP
 	return host, true
 }
 
+// GetProcessor creates the processor which runs the binary code with the default Wasm engine
 func GetProcessor(binaryCode []byte, logger *logger.Logger) (coretypes.Processor, error) {
-	vm, err := NewWasmProcessor(wasmhost.NewWasmTimeVM(), logger)
+	return GetProcessorForEngine(wasmhost.DefaultEngine(), binaryCode, logger)
+}
+
+// GetProcessorForEngine creates the processor which runs the binary code with the Wasm engine
+func GetProcessorForEngine(engine string, binaryCode []byte, logger *logger.Logger) (coretypes.Processor, error) {
+	wasmVM, err := wasmhost.NewWasmVM(engine)
+	if err != nil {
+		return nil, err
+	}
+	vm, err := NewWasmProcessor(wasmVM, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,117 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmvalidate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/vm/wasminterp"
+)
+
+// the object and key ids of the host, see wasmhost.KvStoreHost and wasmhost.KeyExports
+const (
+	rootObjectId    = 1
+	exportsObjectId = 2
+	keyExports      = -18
+	keyZzzzzzz      = -41
+	// the bit of the index of the view entry points
+	viewIndexFlag = 0x8000
+	// the number of instructions 'on_load' may execute, the same as vm.GasBudgetView
+	onLoadFuel = 10_000_000
+)
+
+// onLoadError is raised by the host functions to abort 'on_load'
+type onLoadError struct {
+	err error
+}
+
+// discoverEntryPoints runs 'on_load' of the module with the host which only accepts the definitions
+// of the entry points and returns the entry points defined by the module.
+// The module must be metered by wasminterp.InjectFuelMetering
+func discoverEntryPoints(m *wasminterp.Module) (ret []EntryPoint, err error) {
+	var inst *wasminterp.Instance
+	names := make(map[string]bool)
+	hnames := make(map[coretypes.Hname]bool)
+	abort := func(format string, args ...interface{}) {
+		panic(&onLoadError{err: fmt.Errorf("on_load: "+format, args...)})
+	}
+
+	handlers := make(map[string]wasminterp.HostFunc)
+	handlers["hostGetObjectId"] = func(args []uint64) []uint64 {
+		objId, keyId := int32(args[0]), int32(args[1])
+		if objId != rootObjectId || keyId != keyExports {
+			abort("access to object %d key %d is not allowed", objId, keyId)
+		}
+		return []uint64{exportsObjectId}
+	}
+	handlers["hostSetBytes"] = func(args []uint64) []uint64 {
+		objId, keyId, ref, size := int32(args[0]), int32(args[1]), uint32(args[3]), uint32(args[4])
+		if objId != exportsObjectId {
+			abort("update of object %d is not allowed", objId)
+		}
+		if keyId < 0 {
+			// the version marker of the host interface
+			if keyId != keyZzzzzzz {
+				abort("predefined key value mismatch")
+			}
+			return nil
+		}
+		mem := inst.Memory()
+		if uint64(ref)+uint64(size) > uint64(len(mem)) {
+			abort("out of bounds memory access")
+		}
+		name := string(mem[ref : ref+size])
+		hname := coretypes.Hn(name)
+		if names[name] || hnames[hname] {
+			abort("duplicate entry point '%s'", name)
+		}
+		names[name] = true
+		hnames[hname] = true
+		ret = append(ret, EntryPoint{Name: name, View: keyId&viewIndexFlag != 0})
+		return nil
+	}
+
+	linker := wasminterp.NewLinker()
+	for i := range HostFunctions {
+		hf := &HostFunctions[i]
+		handler, ok := handlers[hf.Name]
+		if !ok {
+			handler = func(args []uint64) []uint64 {
+				abort("call of %s.%s is not allowed", hf.Module, hf.Name)
+				return nil
+			}
+		}
+		if err := linker.DefineFunc(hf.Module, hf.Name, hf.Type, handler); err != nil {
+			return nil, err
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*onLoadError)
+			if !ok {
+				panic(r)
+			}
+			ret, err = nil, e.err
+		}
+	}()
+	inst, err = linker.Instantiate(m)
+	if err != nil {
+		return nil, err
+	}
+	fuel, err := inst.GlobalIndex(wasminterp.FuelGlobal)
+	if err != nil {
+		return nil, err
+	}
+	inst.SetGlobal(fuel, onLoadFuel)
+	if _, err = inst.Call("on_load"); err != nil {
+		return nil, fmt.Errorf("on_load: %v", err)
+	}
+	if len(ret) == 0 {
+		return nil, errors.New("on_load: no entry points defined")
+	}
+	return ret, nil
+}
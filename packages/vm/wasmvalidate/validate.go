@@ -0,0 +1,133 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package wasmvalidate checks the Wasm binaries of the smart contracts before they are deployed.
+// It checks that the module is valid, imports only the functions provided by the Wasm host,
+// exports the functions called by the host and the memory within the limits, and doesn't use
+// the floating point instructions, which are a source of non-determinism.
+// It also discovers the entry points of the smart contract by running its 'on_load' function
+package wasmvalidate
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/wasp/packages/vm/wasminterp"
+)
+
+// VMType is the VM type of the blobs with Wasm programs
+const VMType = "wasmtimevm"
+
+// MaxMemoryPages is the maximal memory of the module, in pages of 64 KiB.
+// The memory of the module is limited by wasminterp.LimitMemory before it is loaded
+const MaxMemoryPages = 256
+
+// HostFunction is the function which the Wasm host provides to the modules
+type HostFunction struct {
+	Module string
+	Name   string
+	Type   wasminterp.FuncType
+}
+
+// HostFunctions are the functions which the Wasm host provides to the modules
+var HostFunctions = []HostFunction{
+	{Module: "wasplib", Name: "hostGetBytes", Type: i32Func(5, 1)},
+	{Module: "wasplib", Name: "hostGetKeyId", Type: i32Func(2, 1)},
+	{Module: "wasplib", Name: "hostGetObjectId", Type: i32Func(3, 1)},
+	{Module: "wasplib", Name: "hostSetBytes", Type: i32Func(5, 0)},
+	{Module: "wasi_unstable", Name: "fd_write", Type: i32Func(4, 1)},
+}
+
+// RequiredExports are the functions which the Wasm host calls
+var RequiredExports = map[string]wasminterp.FuncType{
+	"on_load":            i32Func(0, 0),
+	"on_call_entrypoint": i32Func(1, 0),
+}
+
+// EntryPoint is the entry point of the smart contract
+type EntryPoint struct {
+	Name string
+	View bool
+}
+
+func i32Func(params int, results int) wasminterp.FuncType {
+	ret := wasminterp.FuncType{
+		Params:  make([]wasminterp.ValueType, params),
+		Results: make([]wasminterp.ValueType, results),
+	}
+	for i := range ret.Params {
+		ret.Params[i] = wasminterp.I32
+	}
+	for i := range ret.Results {
+		ret.Results[i] = wasminterp.I32
+	}
+	return ret
+}
+
+// Validate checks the Wasm binary and returns the entry points of the smart contract
+func Validate(binary []byte) ([]EntryPoint, error) {
+	m, err := wasminterp.Parse(binary)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Wasm module: %v", err)
+	}
+	if err = checkImports(m); err != nil {
+		return nil, err
+	}
+	if err = checkExports(m); err != nil {
+		return nil, err
+	}
+	if err = m.CheckNoFloats(); err != nil {
+		return nil, fmt.Errorf("non-deterministic code: %v", err)
+	}
+	limited, err := wasminterp.LimitMemory(binary, MaxMemoryPages)
+	if err != nil {
+		return nil, err
+	}
+	metered, err := wasminterp.InjectFuelMetering(limited)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Wasm module: %v", err)
+	}
+	if m, err = wasminterp.Parse(metered); err != nil {
+		return nil, fmt.Errorf("invalid Wasm module: %v", err)
+	}
+	return discoverEntryPoints(m)
+}
+
+func findHostFunction(module, name string) *HostFunction {
+	for i := range HostFunctions {
+		if HostFunctions[i].Module == module && HostFunctions[i].Name == name {
+			return &HostFunctions[i]
+		}
+	}
+	return nil
+}
+
+func checkImports(m *wasminterp.Module) error {
+	for _, imp := range m.Imports {
+		hf := findHostFunction(imp.Module, imp.Name)
+		if hf == nil {
+			return fmt.Errorf("import %s.%s is not provided by the host", imp.Module, imp.Name)
+		}
+		if ft := &m.Types[imp.TypeIndex]; !ft.Equal(&hf.Type) {
+			return fmt.Errorf("import %s.%s: wrong type %s, expected %s", imp.Module, imp.Name, ft, &hf.Type)
+		}
+	}
+	return nil
+}
+
+func checkExports(m *wasminterp.Module) error {
+	for _, name := range []string{"on_load", "on_call_entrypoint"} {
+		expected := RequiredExports[name]
+		exp := m.Export(name)
+		if exp == nil || exp.Kind != wasminterp.ExternalFunction {
+			return fmt.Errorf("missing export function '%s'", name)
+		}
+		if ft := m.FunctionType(exp.Index); !ft.Equal(&expected) {
+			return fmt.Errorf("export '%s': wrong type %s, expected %s", name, ft, &expected)
+		}
+	}
+	exp := m.Export("memory")
+	if exp == nil || exp.Kind != wasminterp.ExternalMemory {
+		return fmt.Errorf("missing export memory 'memory'")
+	}
+	return nil
+}
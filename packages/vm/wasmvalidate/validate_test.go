@@ -0,0 +1,133 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmvalidate
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const inccounterWasm = "../../../contracts/rust/inccounter/test/inccounter_bg.wasm"
+
+func TestInccounter(t *testing.T) {
+	binary, err := ioutil.ReadFile(inccounterWasm)
+	require.NoError(t, err)
+	entryPoints, err := Validate(binary)
+	require.NoError(t, err)
+	require.Len(t, entryPoints, 11)
+	require.Contains(t, entryPoints, EntryPoint{Name: "increment"})
+	require.Contains(t, entryPoints, EntryPoint{Name: "init"})
+	require.Contains(t, entryPoints, EntryPoint{Name: "getCounter", View: true})
+}
+
+func TestContractBinaries(t *testing.T) {
+	files, err := filepath.Glob("../../../contracts/rust/*/test/*_bg.wasm")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		binary, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		entryPoints, err := Validate(binary)
+		require.NoError(t, err, file)
+		require.NotEmpty(t, entryPoints, file)
+	}
+}
+
+// module assembles the binary of the module which exports the memory, 'on_load' with the body
+// and 'on_call_entrypoint', with the imports and memory of minPages pages
+func module(imports [][]byte, onLoad []byte, minPages uint32) []byte {
+	section := func(id byte, items ...[]byte) []byte {
+		content := []byte{byte(len(items))}
+		for _, item := range items {
+			content = append(content, item...)
+		}
+		return append([]byte{id, byte(len(content))}, content...)
+	}
+	name := func(s string) []byte {
+		return append([]byte{byte(len(s))}, s...)
+	}
+	numImports := byte(len(imports))
+	ret := []byte("\x00asm\x01\x00\x00\x00")
+	// types: ()->(), (i32)->()
+	ret = append(ret, section(1, []byte{0x60, 0, 0}, []byte{0x60, 1, 0x7f, 0})...)
+	if len(imports) > 0 {
+		ret = append(ret, section(2, imports...)...)
+	}
+	ret = append(ret, section(3, []byte{0}, []byte{1})...)
+	limits := []byte{0}
+	for ; minPages >= 0x80; minPages >>= 7 {
+		limits = append(limits, byte(minPages&0x7f)|0x80)
+	}
+	ret = append(ret, section(5, append(limits, byte(minPages)))...)
+	ret = append(ret, section(7,
+		append(name("memory"), 2, 0),
+		append(name("on_load"), 0, numImports),
+		append(name("on_call_entrypoint"), 0, numImports+1),
+	)...)
+	onLoad = append([]byte{0}, onLoad...)
+	ret = append(ret, section(10,
+		append([]byte{byte(len(onLoad))}, onLoad...),
+		[]byte{2, 0, 0x0b},
+	)...)
+	return ret
+}
+
+func TestInvalidModules(t *testing.T) {
+	// no entry points defined
+	_, err := Validate(module(nil, []byte{0x0b}, 1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no entry points")
+
+	_, err = Validate([]byte("not a wasm module"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid Wasm module")
+
+	// import not provided by the host
+	env := append([]byte{3}, "env"...)
+	env = append(env, 3, 'f', 'o', 'o', 0, 0)
+	_, err = Validate(module([][]byte{env}, []byte{0x0b}, 1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not provided by the host")
+
+	// host function with wrong type
+	wasplib := append([]byte{7}, "wasplib"...)
+	wasplib = append(wasplib, 12)
+	wasplib = append(wasplib, "hostSetBytes"...)
+	wasplib = append(wasplib, 0, 0)
+	_, err = Validate(module([][]byte{wasplib}, []byte{0x0b}, 1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "wrong type")
+
+	// memory exceeds the limit
+	_, err = Validate(module(nil, []byte{0x0b}, MaxMemoryPages+1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "exceeds the limit")
+
+	// memory can't grow over the limit: i32.const MaxMemoryPages; memory.grow; i32.const -1; i32.ne; if; unreachable; end
+	_, err = Validate(module(nil, []byte{0x41, 0x80, 0x02, 0x40, 0, 0x41, 0x7f, 0x47, 0x04, 0x40, 0x00, 0x0b, 0x0b}, 1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no entry points")
+	// up to the limit it grows: i32.const MaxMemoryPages-1; memory.grow; i32.const -1; i32.eq; if; unreachable; end
+	_, err = Validate(module(nil, []byte{0x41, 0xff, 0x01, 0x40, 0, 0x41, 0x7f, 0x46, 0x04, 0x40, 0x00, 0x0b, 0x0b}, 1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no entry points")
+
+	// f32.const 0; drop
+	_, err = Validate(module(nil, []byte{0x43, 0, 0, 0, 0, 0x1a, 0x0b}, 1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "floating point")
+
+	// trap in on_load
+	_, err = Validate(module(nil, []byte{0x00, 0x0b}, 1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unreachable")
+
+	// endless loop in on_load runs out of fuel: loop; br 0; end
+	_, err = Validate(module(nil, []byte{0x03, 0x40, 0x0c, 0, 0x0b, 0x0b}, 1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "on_load")
+}
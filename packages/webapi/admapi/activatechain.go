@@ -5,11 +5,21 @@ import (
 	"net/http"
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	peering_pkg "github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/iotaledger/wasp/plugins/chains"
+	"github.com/iotaledger/wasp/plugins/peering"
+	registry_plugin "github.com/iotaledger/wasp/plugins/registry"
 	"github.com/labstack/echo/v4"
 	"github.com/pangpanglabs/echoswagger/v2"
 )
@@ -22,6 +32,22 @@ func addChainEndpoints(adm echoswagger.ApiGroup) {
 	adm.POST(routes.DeactivateChain(":chainID"), handleDeactivateChain).
 		AddParamPath("", "chainID", "ChainID (base58)").
 		SetSummary("Deactivate a chain")
+
+	adm.POST(routes.UpdateChainCommittee(":chainID"), handleUpdateChainCommittee).
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamBody(model.ChainCommittee{
+			Color:          model.NewColor(&balance.Color{5, 6, 7, 8}),
+			Address:        model.NewAddress(&address.Address{9, 10, 11, 12}),
+			CommitteeNodes: []string{"wasp1:4000", "wasp2:4000"},
+		}, "ChainCommittee", "New committee of the chain", true).
+		SetSummary("Move the chain to another committee after the root contract approved the new address")
+
+	adm.POST(routes.SetChainAccessNodes(":chainID"), handleSetChainAccessNodes).
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamBody(model.ChainAccessNodes{
+			AccessNodes: []string{"wasp5:4000"},
+		}, "ChainAccessNodes", "Access nodes of the chain", true).
+		SetSummary("Set the access nodes of the chain. Active chain is restarted with the new set of peers")
 }
 
 func handleActivateChain(c echo.Context) error {
@@ -62,3 +88,132 @@ func handleDeactivateChain(c echo.Context) error {
 
 	return c.NoContent(http.StatusOK)
 }
+
+// handleUpdateChainCommittee switches the node to the new committee of the chain.
+// If the node has the state of the chain, the new address must be the one approved by the 'root' contract.
+// A node without the state, e.g. a new member of the committee, can't check it: it syncs the state
+// from the chain's anchor transactions on the ledger.
+// The node keeps the chain active only if it holds a key share of the new committee address
+// or if it is an access node of the chain
+func handleUpdateChainCommittee(c echo.Context) error {
+	scAddress, err := address.FromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain id: %s", c.Param("chainID")))
+	}
+	chainID := (coretypes.ChainID)(scAddress)
+
+	var req model.ChainCommittee
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	newAddress, err := address.FromBase58(string(req.Address))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid committee address: %s", req.Address))
+	}
+	color := req.Color.Color()
+
+	approvedAddress, err := approvedCommitteeAddress(&chainID)
+	if err != nil {
+		return err
+	}
+	if approvedAddress != nil && *approvedAddress != newAddress {
+		return httperrors.Conflict(fmt.Sprintf("Committee address %s is not approved by the chain, the chain address is %s",
+			newAddress.String(), approvedAddress.String()))
+	}
+
+	bd, err := registry.GetChainRecord(&chainID)
+	if err != nil {
+		return err
+	}
+	if bd != nil {
+		if bd.Color != color {
+			return httperrors.Conflict(fmt.Sprintf("Inconsistent chain color %s, chain record has %s", color, bd.Color))
+		}
+		if err := chains.DeactivateChain(bd); err != nil {
+			return err
+		}
+	} else {
+		bd = &registry.ChainRecord{
+			ChainID: chainID,
+			Color:   color,
+		}
+	}
+	bd.Address = newAddress
+	bd.CommitteeNodes = req.CommitteeNodes
+	if req.AccessNodes != nil {
+		bd.AccessNodes = req.AccessNodes
+	}
+	// access nodes keep following the chain
+	self := peering.DefaultNetworkProvider().Self()
+	bd.Active = bd.IsAccessNode(self.NetID(), peering_pkg.Identity(self.PubKey()))
+	if dkShare, err := registry_plugin.DefaultRegistry().LoadDKShare(&newAddress); err == nil && dkShare.Index != nil {
+		bd.Active = true
+	}
+	if err := registry.SaveChainRecord(bd); err != nil {
+		return err
+	}
+	log.Infof("chain %s moved to the committee %s. Active: %v", chainID.String(), newAddress.String(), bd.Active)
+
+	if bd.Active {
+		if err := chains.ActivateChain(bd); err != nil {
+			return err
+		}
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func handleSetChainAccessNodes(c echo.Context) error {
+	scAddress, err := address.FromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain id: %s", c.Param("chainID")))
+	}
+	chainID := (coretypes.ChainID)(scAddress)
+
+	var req model.ChainAccessNodes
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	bd, err := registry.GetChainRecord(&chainID)
+	if err != nil {
+		return err
+	}
+	if bd == nil {
+		return httperrors.NotFound(fmt.Sprintf("ChainRecord not found: %s", chainID))
+	}
+	if bd.Active {
+		if err := chains.DeactivateChain(bd); err != nil {
+			return err
+		}
+	}
+	bd.AccessNodes = req.AccessNodes
+	if err := registry.SaveChainRecord(bd); err != nil {
+		return err
+	}
+	log.Infof("access nodes of the chain %s set to %+v", chainID.String(), bd.AccessNodes)
+
+	if bd.Active {
+		if err := chains.ActivateChain(bd); err != nil {
+			return err
+		}
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// approvedCommitteeAddress reads the committee address approved by the 'root' contract
+// from the solid state of the chain. It returns nil if the node has no state of the chain
+func approvedCommitteeAddress(chainID *coretypes.ChainID) (*address.Address, error) {
+	virtualState, _, ok, err := state.LoadSolidState(chainID)
+	if err != nil || !ok {
+		return nil, err
+	}
+	rootState := subrealm.New(virtualState.Variables(), kv.Key(root.Interface.Hname().Bytes()))
+	data, err := rootState.Get(root.VarChainAddress)
+	if err != nil {
+		return nil, err
+	}
+	ret, ok, err := codec.DecodeAddress(data)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &ret, nil
+}
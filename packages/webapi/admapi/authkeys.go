@@ -0,0 +1,180 @@
+package admapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/hashing"
+	registry_pkg "github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/util/auth"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/plugins/registry"
+	"github.com/labstack/echo/v4"
+	"github.com/mr-tron/base58"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addAuthKeysEndpoints(adm echoswagger.ApiGroup, m ...echo.MiddlewareFunc) {
+	apiKeyExample := model.APIKeyInfo{
+		Key:     "6Hkq2Az9...",
+		Hash:    hashing.HashStrings("6Hkq2Az9...").String(),
+		Name:    "monitoring",
+		Role:    auth.RoleReadOnly.String(),
+		Created: time.Now(),
+	}
+	keyExample := model.AuthorizedKey{
+		PubKey: ed25519.PublicKey{1, 2, 3, 4}.String(),
+		Role:   auth.RoleChainAdmin.String(),
+	}
+
+	adm.POST(routes.PostAPIKey(), handlePostAPIKey, m...).
+		SetSummary("Create a new API key").
+		AddParamBody(model.APIKeyRequest{Name: "monitoring", Role: auth.RoleReadOnly.String()}, "APIKeyRequest", "Name and role of the key", true).
+		AddResponse(http.StatusOK, "API key", apiKeyExample, nil)
+
+	adm.GET(routes.ListAPIKeys(), handleListAPIKeys, m...).
+		SetSummary("Get the list of API keys (without the keys themselves)").
+		AddResponse(http.StatusOK, "API keys", []model.APIKeyInfo{apiKeyExample}, nil)
+
+	adm.DELETE(routes.RevokeAPIKey(":hash"), handleRevokeAPIKey, m...).
+		SetSummary("Revoke an API key").
+		AddParamPath("", "hash", "Hash of the key (base58)")
+
+	adm.PUT(routes.PutAuthorizedKey(), handlePutAuthorizedKey, m...).
+		SetSummary("Authorize an ed25519 public key to log in with a signature").
+		AddParamBody(keyExample, "AuthorizedKey", "Public key and role", true)
+
+	adm.GET(routes.ListAuthorizedKeys(), handleListAuthorizedKeys, m...).
+		SetSummary("Get the list of authorized public keys").
+		AddResponse(http.StatusOK, "Authorized keys", []model.AuthorizedKey{keyExample}, nil)
+
+	adm.DELETE(routes.RevokeAuthorizedKey(":pubKey"), handleRevokeAuthorizedKey, m...).
+		SetSummary("Revoke the authorization of a public key").
+		AddParamPath("", "pubKey", "Public key (base58)")
+}
+
+func parseRole(s string) (auth.Role, error) {
+	role, err := auth.ParseRole(s)
+	if err != nil || role == auth.RoleNone {
+		return auth.RoleNone, httperrors.BadRequest(fmt.Sprintf("Invalid role '%s'", s))
+	}
+	return role, nil
+}
+
+func parsePubKey(s string) (ed25519.PublicKey, error) {
+	b, err := base58.Decode(s)
+	if err != nil {
+		return ed25519.PublicKey{}, httperrors.BadRequest("Invalid public key")
+	}
+	pubKey, _, err := ed25519.PublicKeyFromBytes(b)
+	if err != nil {
+		return ed25519.PublicKey{}, httperrors.BadRequest("Invalid public key")
+	}
+	return pubKey, nil
+}
+
+func apiKeyInfo(rec *registry_pkg.APIKeyRecord) model.APIKeyInfo {
+	return model.APIKeyInfo{
+		Hash:    rec.Hash.String(),
+		Name:    rec.Name,
+		Role:    rec.Role.String(),
+		Created: rec.Created,
+	}
+}
+
+func handlePostAPIKey(c echo.Context) error {
+	var req model.APIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	role, err := parseRole(req.Role)
+	if err != nil {
+		return err
+	}
+	key, rec, err := registry.DefaultRegistry().NewAPIKey(req.Name, role)
+	if err != nil {
+		return err
+	}
+	ret := apiKeyInfo(rec)
+	ret.Key = key
+	return c.JSON(http.StatusOK, ret)
+}
+
+func handleListAPIKeys(c echo.Context) error {
+	recs, err := registry.DefaultRegistry().GetAPIKeys()
+	if err != nil {
+		return err
+	}
+	ret := make([]model.APIKeyInfo, len(recs))
+	for i, rec := range recs {
+		ret[i] = apiKeyInfo(rec)
+	}
+	return c.JSON(http.StatusOK, ret)
+}
+
+func handleRevokeAPIKey(c echo.Context) error {
+	h, err := hashing.HashValueFromBase58(c.Param("hash"))
+	if err != nil {
+		return httperrors.BadRequest("Invalid key hash")
+	}
+	ok, err := registry.DefaultRegistry().RevokeAPIKey(h)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return httperrors.NotFound(fmt.Sprintf("API key not found: %s", h))
+	}
+	log.Infof("API key %s revoked", h)
+	return c.NoContent(http.StatusOK)
+}
+
+func handlePutAuthorizedKey(c echo.Context) error {
+	var req model.AuthorizedKey
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	pubKey, err := parsePubKey(req.PubKey)
+	if err != nil {
+		return err
+	}
+	role, err := parseRole(req.Role)
+	if err != nil {
+		return err
+	}
+	if err := registry.DefaultRegistry().AuthorizeKey(pubKey, role); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func handleListAuthorizedKeys(c echo.Context) error {
+	keys, err := registry.DefaultRegistry().GetAuthorizedKeys()
+	if err != nil {
+		return err
+	}
+	ret := make([]model.AuthorizedKey, len(keys))
+	for i, k := range keys {
+		ret[i] = model.AuthorizedKey{PubKey: k.PubKey.String(), Role: k.Role.String()}
+	}
+	return c.JSON(http.StatusOK, ret)
+}
+
+func handleRevokeAuthorizedKey(c echo.Context) error {
+	pubKey, err := parsePubKey(c.Param("pubKey"))
+	if err != nil {
+		return err
+	}
+	ok, err := registry.DefaultRegistry().RevokeKey(pubKey)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return httperrors.NotFound(fmt.Sprintf("Key not authorized: %s", pubKey))
+	}
+	log.Infof("authorization of key %s revoked", pubKey)
+	return c.NoContent(http.StatusOK)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/registry"
@@ -20,6 +21,8 @@ func addChainRecordEndpoints(adm echoswagger.ApiGroup) {
 		Color:          model.NewColor(&balance.Color{5, 6, 7, 8}),
 		CommitteeNodes: []string{"wasp1:4000", "wasp2:4000"},
 		Active:         false,
+		Address:        model.NewAddress(&address.Address{9, 10, 11, 12}),
+		AccessNodes:    []string{"wasp5:4000"},
 	}
 
 	adm.POST(routes.PutChainRecord(), handlePutChainRecord).
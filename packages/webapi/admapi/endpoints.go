@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/util/auth"
 	"github.com/labstack/echo/v4"
 	"github.com/pangpanglabs/echoswagger/v2"
 )
@@ -15,15 +16,26 @@ func initLogger() {
 	log = logger.NewLogger("webapi/adm")
 }
 
+// AddEndpoints adds the admin endpoints. Chain and DKG endpoints require the chain-admin role,
+// node shutdown, key management and the node certificate require the node-admin role.
+// If adminWhitelist is not nil, only loopback and whitelisted addresses are allowed
 func AddEndpoints(adm echoswagger.ApiGroup, adminWhitelist []net.IP) {
 	initLogger()
 
-	adm.EchoGroup().Use(protected(adminWhitelist))
+	if adminWhitelist != nil {
+		adm.EchoGroup().Use(protected(adminWhitelist))
+	}
+	adm.EchoGroup().Use(auth.RequireRole(auth.RoleChainAdmin))
+	requireNodeAdmin := auth.RequireRole(auth.RoleNodeAdmin)
 
-	addShutdownEndpoint(adm)
+	addShutdownEndpoint(adm, requireNodeAdmin)
 	addChainRecordEndpoints(adm)
 	addChainEndpoints(adm)
+	addSnapshotEndpoints(adm)
+	addPruningEndpoints(adm)
 	addDKSharesEndpoints(adm)
+	addAuthKeysEndpoints(adm, requireNodeAdmin)
+	addIdentityEndpoints(adm, requireNodeAdmin)
 }
 
 // allow only if the remote address is private or in whitelist
@@ -0,0 +1,86 @@
+package admapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	peering_pkg "github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/plugins/dkg"
+	"github.com/iotaledger/wasp/plugins/peering"
+	"github.com/iotaledger/wasp/plugins/registry"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addIdentityEndpoints(adm echoswagger.ApiGroup, m ...echo.MiddlewareFunc) {
+	certExample := model.NodeCertificate{
+		Identity: "id:2Q7a...",
+		Owner:    ed25519.PublicKey{1, 2, 3, 4}.String(),
+		Issued:   time.Now(),
+		Data:     model.NewBytes([]byte("certificate")),
+	}
+
+	adm.GET(routes.GetNodeIdentity(), handleGetNodeIdentity).
+		SetSummary("Get the peering identity and the certificate of the node").
+		AddResponse(http.StatusOK, "Node identity", model.NodeIdentity{
+			NetID:       "wasp1:4000",
+			Identity:    "id:2Q7a...",
+			Certificate: &certExample,
+		}, nil)
+
+	adm.PUT(routes.PutNodeCertificate(), handlePutNodeCertificate, m...).
+		SetSummary("Set the certificate of the node peering key, issued by the node owner").
+		AddParamBody(certExample, "NodeCertificate", "Node certificate. Only the binary data is used", true)
+
+	adm.GET(routes.ListTrustedPeers(), handleListTrustedPeers).
+		SetSummary("Get the list of peers which presented a valid certificate").
+		AddResponse(http.StatusOK, "Trusted peers", []model.TrustedPeer{{NetID: "wasp2:4000", Certificate: certExample}}, nil)
+}
+
+func handleGetNodeIdentity(c echo.Context) error {
+	self := peering.DefaultNetworkProvider().Self()
+	ret := model.NodeIdentity{
+		NetID:    self.NetID(),
+		Identity: peering_pkg.Identity(self.PubKey()),
+	}
+	cert, err := registry.DefaultRegistry().GetNodeCertificate()
+	if err != nil {
+		return err
+	}
+	if cert != nil {
+		ret.Certificate = model.NewNodeCertificate(cert)
+	}
+	return c.JSON(http.StatusOK, ret)
+}
+
+func handlePutNodeCertificate(c echo.Context) error {
+	var req model.NodeCertificate
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	cert, err := peering_pkg.NodeCertificateFromBytes(req.Data.Bytes(), dkg.DefaultNode().GroupSuite())
+	if err != nil {
+		return httperrors.BadRequest("Invalid certificate")
+	}
+	if err := peering.SetNodeCertificate(cert); err != nil {
+		return httperrors.BadRequest(err.Error())
+	}
+	log.Infof("node certificate set: %s", cert.String())
+	return c.NoContent(http.StatusOK)
+}
+
+func handleListTrustedPeers(c echo.Context) error {
+	trusted, err := registry.DefaultRegistry().GetTrustedPeers()
+	if err != nil {
+		return err
+	}
+	ret := make([]*model.TrustedPeer, len(trusted))
+	for i, tp := range trusted {
+		ret[i] = model.NewTrustedPeer(tp)
+	}
+	return c.JSON(http.StatusOK, ret)
+}
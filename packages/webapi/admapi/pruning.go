@@ -0,0 +1,95 @@
+package admapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/plugins/pruning"
+	"github.com/iotaledger/wasp/plugins/registry"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addPruningEndpoints(adm echoswagger.ApiGroup) {
+	adm.GET(routes.PruningPolicy(":chainID"), handleGetPruningPolicy).
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddResponse(http.StatusOK, "Pruning policy", model.PruningPolicy{}, nil).
+		SetSummary("Get the pruning policy of the chain")
+
+	adm.POST(routes.PruningPolicy(":chainID"), handleSetPruningPolicy).
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamBody(model.PruningPolicy{}, "PruningPolicy", "Pruning policy", true).
+		AddResponse(http.StatusOK, "Pruning policy has been saved", nil, nil).
+		SetSummary("Set the pruning policy of the chain").
+		SetDescription("A block is retained if it is one of the last KeepBlocks blocks or if it is newer than KeepDuration. " +
+			"Blocks younger than 24 hours are never pruned")
+
+	adm.POST(routes.PruneChain(":chainID"), handlePruneChain).
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamBody(model.PruneParams{}, "PruneParams", "Parameters", false).
+		AddResponse(http.StatusOK, "Pruning report", model.PruningResult{}, nil).
+		SetSummary("Prune the chain according to its pruning policy")
+}
+
+func chainIDFromParam(c echo.Context) (*coretypes.ChainID, error) {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return nil, httperrors.BadRequest(fmt.Sprintf("Invalid chain ID: %+v", c.Param("chainID")))
+	}
+	return &chainID, nil
+}
+
+func handleGetPruningPolicy(c echo.Context) error {
+	chainID, err := chainIDFromParam(c)
+	if err != nil {
+		return err
+	}
+	policy, err := registry.DefaultRegistry().GetPruningPolicy(chainID)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return c.JSON(http.StatusOK, model.NewPruningPolicy(pruning.DefaultPolicy(), true))
+	}
+	return c.JSON(http.StatusOK, model.NewPruningPolicy(policy, false))
+}
+
+func handleSetPruningPolicy(c echo.Context) error {
+	chainID, err := chainIDFromParam(c)
+	if err != nil {
+		return err
+	}
+	var req model.PruningPolicy
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	if req.KeepDuration < 0 {
+		return httperrors.BadRequest("KeepDuration must not be negative")
+	}
+	if err := registry.DefaultRegistry().SavePruningPolicy(chainID, req.Policy()); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func handlePruneChain(c echo.Context) error {
+	chainID, err := chainIDFromParam(c)
+	if err != nil {
+		return err
+	}
+	var req model.PruneParams
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	res, err := pruning.PruneChain(chainID, req.DryRun)
+	if err != nil {
+		return err
+	}
+	log.Infof("pruned chain %s (dry run: %v): %d blocks, %d bytes reclaimed",
+		chainID.String(), res.DryRun, res.NumBlocks, res.ReclaimedBytes)
+	return c.JSON(http.StatusOK, model.NewPruningResult(res))
+}
@@ -9,8 +9,8 @@ import (
 	"github.com/pangpanglabs/echoswagger/v2"
 )
 
-func addShutdownEndpoint(adm echoswagger.ApiGroup) {
-	adm.GET(routes.Shutdown(), handleShutdown).
+func addShutdownEndpoint(adm echoswagger.ApiGroup, m ...echo.MiddlewareFunc) {
+	adm.GET(routes.Shutdown(), handleShutdown, m...).
 		SetSummary("Shut down the node")
 }
 
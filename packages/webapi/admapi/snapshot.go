@@ -0,0 +1,76 @@
+package admapi
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/snapshot"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/plugins/chains"
+	"github.com/iotaledger/wasp/plugins/database"
+	"github.com/iotaledger/wasp/plugins/nodeconn"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+// the node doesn't respond if the transaction is not confirmed
+const confirmedTxTimeout = 10 * time.Second
+
+func addSnapshotEndpoints(adm echoswagger.ApiGroup) {
+	adm.GET(routes.ExportSnapshot(":chainID"), handleExportSnapshot).
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddResponse(http.StatusOK, "Snapshot of the chain", model.SnapshotData{}, nil).
+		SetSummary("Export the snapshot of the solid state of the chain")
+
+	adm.POST(routes.ImportSnapshot(), handleImportSnapshot).
+		AddParamBody(model.SnapshotData{}, "SnapshotData", "Snapshot of the chain", true).
+		AddResponse(http.StatusOK, "Imported snapshot", model.SnapshotInfo{}, nil).
+		SetSummary("Import the snapshot of a chain").
+		SetDescription("The chain must be registered in the node, not running and must not have a state in the node. " +
+			"The anchor transaction of the snapshot must be confirmed on the ledger. " +
+			"After the import the chain starts from the block of the snapshot when it is activated")
+}
+
+func handleExportSnapshot(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID: %+v", c.Param("chainID")))
+	}
+	var buf bytes.Buffer
+	s, err := snapshot.Export(database.GetInstance(), &chainID, &buf)
+	if err != nil {
+		return err
+	}
+	log.Infof("exported snapshot of chain %s at block #%d, size: %d bytes", chainID.String(), s.BlockIndex(), buf.Len())
+	return c.JSON(http.StatusOK, model.NewSnapshotData(buf.Bytes()))
+}
+
+func handleImportSnapshot(c echo.Context) error {
+	var req model.SnapshotData
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	data := req.Data.Bytes()
+	s, err := snapshot.Read(bytes.NewReader(data))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid snapshot: %v", err))
+	}
+	if chains.GetChain(s.ChainID()) != nil {
+		return httperrors.Conflict(fmt.Sprintf("Chain %s is running", s.ChainID().String()))
+	}
+	getConfirmedTx := func(txid valuetransaction.ID) (*valuetransaction.Transaction, error) {
+		return nodeconn.GetConfirmedTransaction(txid, confirmedTxTimeout)
+	}
+	if s, err = snapshot.Import(database.GetInstance(), bytes.NewReader(data), getConfirmedTx); err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Failed to import the snapshot: %v", err))
+	}
+	log.Infof("imported snapshot of chain %s at block #%d", s.ChainID().String(), s.BlockIndex())
+	return c.JSON(http.StatusOK, model.NewSnapshotInfo(s))
+}
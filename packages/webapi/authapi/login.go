@@ -0,0 +1,113 @@
+package authapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/util/auth"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/mr-tron/base58"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+type loginService struct {
+	authenticator *auth.Authenticator
+}
+
+func AddEndpoints(server echoswagger.ApiRouter, authenticator *auth.Authenticator) {
+	s := &loginService{authenticator}
+
+	example := model.LoginResponse{Token: "eyJhbGciOi...", Role: auth.RoleChainAdmin.String()}
+
+	server.POST(routes.Login(), s.handleLogin).
+		SetSummary("Log in with username and password").
+		AddParamBody(model.LoginRequest{}, "Credentials", "Username and password", true).
+		AddResponse(http.StatusOK, "Token", example, nil)
+
+	server.GET(routes.LoginChallenge(), s.handleChallenge).
+		SetSummary("Get a one-time challenge for the key signature login").
+		AddResponse(http.StatusOK, "Challenge", model.LoginChallengeResponse{}, nil)
+
+	server.POST(routes.LoginWithKey(), s.handleLoginWithKey).
+		SetSummary("Log in with the signature of a challenge by an authorized ed25519 key").
+		AddParamBody(model.KeyLoginRequest{}, "Signature", "Public key and signature of the challenge", true).
+		AddResponse(http.StatusOK, "Token", example, nil)
+}
+
+func (s *loginService) handleLogin(c echo.Context) error {
+	var req model.LoginRequest
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	token, err := s.authenticator.LoginWithPassword(req.Username, req.Password)
+	if err != nil {
+		return loginError(err)
+	}
+	return c.JSON(http.StatusOK, loginResponse(token))
+}
+
+func (s *loginService) handleChallenge(c echo.Context) error {
+	challenge, err := s.authenticator.NewChallenge()
+	if err != nil {
+		return loginError(err)
+	}
+	return c.JSON(http.StatusOK, model.LoginChallengeResponse{Challenge: challenge})
+}
+
+func (s *loginService) handleLoginWithKey(c echo.Context) error {
+	var req model.KeyLoginRequest
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	pubKey, err := decodePubKey(req.PubKey)
+	if err != nil {
+		return httperrors.BadRequest(err.Error())
+	}
+	sigBytes, err := base58.Decode(req.Signature)
+	if err != nil {
+		return httperrors.BadRequest("Invalid signature")
+	}
+	sig, _, err := ed25519.SignatureFromBytes(sigBytes)
+	if err != nil {
+		return httperrors.BadRequest("Invalid signature")
+	}
+	token, err := s.authenticator.LoginWithSignature(pubKey, req.Challenge, sig)
+	if err != nil {
+		return loginError(err)
+	}
+	return c.JSON(http.StatusOK, loginResponse(token))
+}
+
+func decodePubKey(s string) (ed25519.PublicKey, error) {
+	b, err := base58.Decode(s)
+	if err != nil {
+		return ed25519.PublicKey{}, errors.New("Invalid public key")
+	}
+	pubKey, _, err := ed25519.PublicKeyFromBytes(b)
+	if err != nil {
+		return ed25519.PublicKey{}, errors.New("Invalid public key")
+	}
+	return pubKey, nil
+}
+
+func loginError(err error) error {
+	switch {
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		return httperrors.Unauthorized(err.Error())
+	case errors.Is(err, auth.ErrTokensNotEnabled):
+		return httperrors.NotFound(err.Error())
+	}
+	return err
+}
+
+func loginResponse(token *auth.Token) *model.LoginResponse {
+	return &model.LoginResponse{
+		Token:   token.Value,
+		Role:    token.Role.String(),
+		Expires: token.Expires,
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"net"
 
 	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/util/auth"
 	"github.com/iotaledger/wasp/packages/webapi/admapi"
+	"github.com/iotaledger/wasp/packages/webapi/authapi"
 	"github.com/iotaledger/wasp/packages/webapi/blob"
+	"github.com/iotaledger/wasp/packages/webapi/events"
 	"github.com/iotaledger/wasp/packages/webapi/info"
 	"github.com/iotaledger/wasp/packages/webapi/request"
 	"github.com/iotaledger/wasp/packages/webapi/state"
@@ -14,14 +17,21 @@ import (
 
 var log *logger.Logger
 
-func Init(server echoswagger.ApiRoot, adminWhitelist []net.IP) {
+// Init adds the endpoints. The public ones require the read-only role, admin roles are
+// enforced by admapi. The IP whitelist of the admin endpoints applies in addition to
+// the authentication
+func Init(server echoswagger.ApiRoot, adminWhitelist []net.IP, authenticator *auth.Authenticator) {
 	log = logger.NewLogger("WebAPI")
 
 	server.SetRequestContentType("application/json")
 	server.SetResponseContentType("application/json")
 
-	pub := server.Group("public", "").SetDescription("Public endpoints")
+	authGroup := server.Group("auth", "").SetDescription("Authentication endpoints")
+	authapi.AddEndpoints(authGroup, authenticator)
+
+	pub := server.Group("public", "", auth.RequireRole(auth.RoleReadOnly)).SetDescription("Public endpoints")
 	blob.AddEndpoints(pub)
+	events.AddEndpoints(pub)
 	info.AddEndpoints(pub)
 	request.AddEndpoints(pub)
 	state.AddEndpoints(pub)
@@ -0,0 +1,187 @@
+package events
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/publisher"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+	"golang.org/x/net/websocket"
+)
+
+// number of events buffered for each subscriber. Events are dropped for subscribers which can't keep up
+const subscriberBufferSize = 100
+
+var log *logger.Logger
+
+// subscribers of each chain: chain ID (base58) -> set of *subscriber
+var subscribers = struct {
+	sync.RWMutex
+	m map[string]map[*subscriber]struct{}
+}{m: make(map[string]map[*subscriber]struct{})}
+
+var startOnce sync.Once
+
+type subscriber struct {
+	filter eventFilter
+	events chan *model.ChainEvent
+}
+
+type eventFilter struct {
+	contract *coretypes.Hname
+	types    map[string]bool
+}
+
+func AddEndpoints(server echoswagger.ApiRouter) {
+	log = logger.NewLogger("webapi/events")
+	startOnce.Do(startForwarder)
+
+	server.GET(routes.ChainEvents(":chainID"), handleChainEvents).
+		SetSummary("Subscribe to the events of the chain").
+		SetDescription("Upgrades the connection to WebSocket and streams the events of the chain as JSON-encoded ChainEvent objects").
+		AddParamPath("", "chainID", "ChainID (base58-encoded)").
+		AddParamQuery("", "contract", "Stream only events emitted by the contract with the hname (hex-encoded)", false).
+		AddParamQuery("", "type", "Stream only events of the types (comma-separated), e.g. state,vmmsg", false).
+		AddResponse(http.StatusSwitchingProtocols, "Stream of chain events", model.ChainEvent{}, nil)
+}
+
+func parseFilter(c echo.Context) (eventFilter, error) {
+	ret := eventFilter{}
+	if s := c.QueryParam("contract"); s != "" {
+		hname, err := coretypes.HnameFromString(s)
+		if err != nil {
+			return ret, httperrors.BadRequest(fmt.Sprintf("Invalid contract hname: %+v", s))
+		}
+		ret.contract = &hname
+	}
+	for _, param := range c.QueryParams()["type"] {
+		for _, t := range strings.Split(param, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				if ret.types == nil {
+					ret.types = make(map[string]bool)
+				}
+				ret.types[t] = true
+			}
+		}
+	}
+	return ret, nil
+}
+
+func (f *eventFilter) accepts(ev *model.ChainEvent) bool {
+	if f.types != nil && !f.types[ev.Type] {
+		return false
+	}
+	if f.contract != nil && ev.Contract != f.contract.String() {
+		return false
+	}
+	return true
+}
+
+func handleChainEvents(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID: %+v", c.Param("chainID")))
+	}
+	filter, err := parseFilter(c)
+	if err != nil {
+		return err
+	}
+
+	websocket.Handler(func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		sub := &subscriber{
+			filter: filter,
+			events: make(chan *model.ChainEvent, subscriberBufferSize),
+		}
+		subscribe(chainID.String(), sub)
+		defer unsubscribe(chainID.String(), sub)
+
+		log.Debugf("websocket opened for %s, chain %s", c.Request().RemoteAddr, chainID.String())
+		defer log.Debugf("websocket closed for %s, chain %s", c.Request().RemoteAddr, chainID.String())
+
+		// the client is not expected to send anything: reading only detects the closed connection
+		closed := make(chan struct{})
+		go func() {
+			_, _ = io.Copy(ioutil.Discard, ws)
+			close(closed)
+		}()
+
+		for {
+			select {
+			case ev := <-sub.events:
+				if err := websocket.JSON.Send(ws, ev); err != nil {
+					return
+				}
+			case <-closed:
+				return
+			}
+		}
+	}).ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+func subscribe(chainID string, sub *subscriber) {
+	subscribers.Lock()
+	defer subscribers.Unlock()
+
+	subs, ok := subscribers.m[chainID]
+	if !ok {
+		subs = make(map[*subscriber]struct{})
+		subscribers.m[chainID] = subs
+	}
+	subs[sub] = struct{}{}
+}
+
+func unsubscribe(chainID string, sub *subscriber) {
+	subscribers.Lock()
+	defer subscribers.Unlock()
+
+	subs := subscribers.m[chainID]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(subscribers.m, chainID)
+	}
+}
+
+// startForwarder forwards the messages of the publisher to the subscribers of the chain
+func startForwarder() {
+	publisher.Event.Attach(events.NewClosure(func(msgType string, parts []string) {
+		if len(parts) == 0 {
+			return
+		}
+		subscribers.RLock()
+		defer subscribers.RUnlock()
+
+		subs, ok := subscribers.m[parts[0]]
+		if !ok {
+			return
+		}
+		ev, err := model.NewChainEvent(msgType, parts)
+		if err != nil {
+			log.Warnf("can't convert message to chain event: %v", err)
+			return
+		}
+		for sub := range subs {
+			if !sub.filter.accepts(ev) {
+				continue
+			}
+			select {
+			case sub.events <- ev:
+			default:
+				log.Warnf("dropped %s event of chain %s: subscriber is too slow", ev.Type, parts[0])
+			}
+		}
+	}))
+}
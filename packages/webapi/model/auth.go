@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// LoginRequest is the body of the password login request
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginChallengeResponse is the one-time challenge to be signed for the key login
+type LoginChallengeResponse struct {
+	Challenge string `json:"challenge" swagger:"desc(Random challenge. The string 'wasp-login:' + challenge must be signed within a minute.)"`
+}
+
+// KeyLoginRequest is the body of the key signature login request
+type KeyLoginRequest struct {
+	PubKey    string `json:"pubKey" swagger:"desc(ed25519 public key (base58))"`
+	Challenge string `json:"challenge" swagger:"desc(Challenge returned by the challenge endpoint)"`
+	Signature string `json:"signature" swagger:"desc(Signature of 'wasp-login:' + challenge (base58))"`
+}
+
+// LoginResponse holds the token issued by a successful login
+type LoginResponse struct {
+	Token   string    `json:"token" swagger:"desc(Token to be sent in the 'Authorization: Bearer' header)"`
+	Role    string    `json:"role" swagger:"desc(Role granted by the token: read-only, chain-admin or node-admin)"`
+	Expires time.Time `json:"expires"`
+}
+
+// APIKeyRequest is the body of the request creating an API key
+type APIKeyRequest struct {
+	Name string `json:"name" swagger:"desc(Description of the key owner)"`
+	Role string `json:"role" swagger:"desc(Role granted by the key: read-only, chain-admin or node-admin)"`
+}
+
+// APIKeyInfo describes an API key. Key is only returned when the key is created
+type APIKeyInfo struct {
+	Key     string    `json:"key,omitempty" swagger:"desc(The API key, to be sent in the X-API-Key header. Returned only once.)"`
+	Hash    string    `json:"hash" swagger:"desc(Hash of the key, identifies the key to revoke it)"`
+	Name    string    `json:"name"`
+	Role    string    `json:"role"`
+	Created time.Time `json:"created"`
+}
+
+// AuthorizedKey is a public key allowed to log in with a signature
+type AuthorizedKey struct {
+	PubKey string `json:"pubKey" swagger:"desc(ed25519 public key (base58))"`
+	Role   string `json:"role" swagger:"desc(Role granted to the key: read-only, chain-admin or node-admin)"`
+}
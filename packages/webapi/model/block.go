@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// BlockInfo is the summary of the block of the chain
+type BlockInfo struct {
+	Index      uint32      `swagger:"desc(Index of the block)"`
+	StateTxID  ValueTxID   `swagger:"desc(ID of the anchor transaction of the block (base58-encoded))"`
+	Timestamp  time.Time   `swagger:"desc(Timestamp of the block)"`
+	RequestIDs []RequestID `swagger:"desc(IDs of the requests processed in the block (base58-encoded))"`
+}
+
+type BlockListResponse struct {
+	LatestBlockIndex uint32      `swagger:"desc(Index of the latest solid block of the chain)"`
+	Blocks           []BlockInfo `swagger:"desc(Blocks, starting from the newest one)"`
+}
+
+// Mutation is a single change of the state variable
+type Mutation struct {
+	Kind  string `swagger:"desc(Kind of the mutation: set, del or delprefix)"`
+	Key   Bytes  `swagger:"desc(Key of the state variable, or the deleted prefix (base64-encoded))"`
+	Value Bytes  `swagger:"desc(Value of the state variable after the mutation (base64-encoded))"`
+}
+
+// StateUpdate is the list of changes to the state made by the request
+type StateUpdate struct {
+	RequestID RequestID  `swagger:"desc(ID of the request (base58-encoded))"`
+	Timestamp time.Time  `swagger:"desc(Timestamp of the state update)"`
+	Mutations []Mutation `swagger:"desc(Mutations in the order they were applied)"`
+}
+
+type BlockResponse struct {
+	Index        uint32        `swagger:"desc(Index of the block)"`
+	StateTxID    ValueTxID     `swagger:"desc(ID of the anchor transaction of the block (base58-encoded))"`
+	Timestamp    time.Time     `swagger:"desc(Timestamp of the block)"`
+	StateUpdates []StateUpdate `swagger:"desc(State updates of the block, one per request)"`
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/wasp/packages/coretypes"
+)
+
+// ChainEvent is an event of the chain, as streamed by the websocket events endpoint.
+// Fields not relevant for the type of the event are omitted
+type ChainEvent struct {
+	Type         string    `swagger:"desc(Type of the event: state, request_in, request_out, vmmsg, chainrec, active_committee or dismissed_committee)"`
+	ChainID      ChainID   `swagger:"desc(ID of the chain (base58-encoded))"`
+	Contract     string    `json:",omitempty" swagger:"desc(vmmsg: hname of the contract which emitted the event (hex-encoded))"`
+	Message      string    `json:",omitempty" swagger:"desc(vmmsg: the event message)"`
+	RequestID    RequestID `json:",omitempty" swagger:"desc(request_in, request_out: ID of the request (base58-encoded))"`
+	StateIndex   *uint32   `json:",omitempty" swagger:"desc(state, request_out: index of the block)"`
+	RequestIndex *uint16   `json:",omitempty" swagger:"desc(request_out: index of the request in the block)"`
+	BlockSize    uint16    `json:",omitempty" swagger:"desc(state, request_out: number of requests in the block)"`
+	StateTxID    ValueTxID `json:",omitempty" swagger:"desc(state: ID of the anchor transaction of the state (base58-encoded))"`
+	StateHash    HashValue `json:",omitempty" swagger:"desc(state: hash of the state (base58-encoded))"`
+	Timestamp    int64     `json:",omitempty" swagger:"desc(state: timestamp of the block (unix nanoseconds))"`
+	Color        Color     `json:",omitempty" swagger:"desc(chainrec: color of the chain (base58-encoded))"`
+}
+
+// NewChainEvent converts a message of the publisher into a ChainEvent.
+// See docs/docs/publisher.md for the format of the messages
+func NewChainEvent(msgType string, parts []string) (*ChainEvent, error) {
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("%s: missing chain ID", msgType)
+	}
+	ret := &ChainEvent{Type: msgType, ChainID: ChainID(parts[0])}
+	var err error
+	switch msgType {
+	case "active_committee", "dismissed_committee":
+	case "chainrec":
+		err = checkParts(msgType, parts, 2)
+		if err == nil {
+			ret.Color = Color(parts[1])
+		}
+	case "vmmsg":
+		err = checkParts(msgType, parts, 3)
+		if err == nil {
+			ret.Contract = parts[1]
+			ret.Message = parts[2]
+		}
+	case "request_in":
+		err = checkParts(msgType, parts, 3)
+		if err == nil {
+			ret.RequestID, err = parseRequestID(parts[1], parts[2])
+		}
+	case "request_out":
+		err = checkParts(msgType, parts, 6)
+		if err == nil {
+			err = ret.parseRequestOut(parts)
+		}
+	case "state":
+		err = checkParts(msgType, parts, 6)
+		if err == nil {
+			err = ret.parseState(parts)
+		}
+	default:
+		err = fmt.Errorf("unknown message type '%s'", msgType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func checkParts(msgType string, parts []string, n int) error {
+	if len(parts) < n {
+		return fmt.Errorf("%s: expected %d parts, got %d", msgType, n, len(parts))
+	}
+	return nil
+}
+
+func parseRequestID(txid string, index string) (RequestID, error) {
+	id, err := valuetransaction.IDFromBase58(txid)
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.ParseUint(index, 10, 16)
+	if err != nil {
+		return "", err
+	}
+	reqID := coretypes.NewRequestID(id, uint16(n))
+	return NewRequestID(&reqID), nil
+}
+
+// request_out <chain ID> <request tx ID> <request index> <state index> <seq number in the block> <block size>
+func (e *ChainEvent) parseRequestOut(parts []string) error {
+	var err error
+	if e.RequestID, err = parseRequestID(parts[1], parts[2]); err != nil {
+		return err
+	}
+	stateIndex, err := strconv.ParseUint(parts[3], 10, 32)
+	if err != nil {
+		return err
+	}
+	requestIndex, err := strconv.ParseUint(parts[4], 10, 16)
+	if err != nil {
+		return err
+	}
+	blockSize, err := strconv.ParseUint(parts[5], 10, 16)
+	if err != nil {
+		return err
+	}
+	si := uint32(stateIndex)
+	ri := uint16(requestIndex)
+	e.StateIndex = &si
+	e.RequestIndex = &ri
+	e.BlockSize = uint16(blockSize)
+	return nil
+}
+
+// state <chain ID> <state index> <block size> <state tx ID> <state hash> <timestamp>
+func (e *ChainEvent) parseState(parts []string) error {
+	stateIndex, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return err
+	}
+	blockSize, err := strconv.ParseUint(parts[2], 10, 16)
+	if err != nil {
+		return err
+	}
+	timestamp, err := strconv.ParseInt(parts[5], 10, 64)
+	if err != nil {
+		return err
+	}
+	si := uint32(stateIndex)
+	e.StateIndex = &si
+	e.BlockSize = uint16(blockSize)
+	e.StateTxID = ValueTxID(parts[3])
+	e.StateHash = HashValue(parts[4])
+	e.Timestamp = timestamp
+	return nil
+}
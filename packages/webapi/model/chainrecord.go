@@ -7,8 +7,10 @@ import (
 type ChainRecord struct {
 	ChainID        ChainID  `swagger:"desc(ChainID (base58-encoded))"`
 	Color          Color    `swagger:"desc(Chain color (base58-encoded))"`
-	CommitteeNodes []string `swagger:"desc(List of committee nodes (network IDs))"`
+	CommitteeNodes []string `swagger:"desc(List of committee nodes (network IDs or node identities))"`
 	Active         bool     `swagger:"desc(Whether or not the chain is active)"`
+	Address        Address  `swagger:"desc(Address of the committee controlling the chain (base58-encoded). Defaults to the chain ID)"`
+	AccessNodes    []string `swagger:"desc(List of access nodes (network IDs or node identities), following the chain without taking part in the consensus)"`
 }
 
 func NewChainRecord(bd *registry.ChainRecord) *ChainRecord {
@@ -17,14 +19,34 @@ func NewChainRecord(bd *registry.ChainRecord) *ChainRecord {
 		Color:          NewColor(&bd.Color),
 		CommitteeNodes: bd.CommitteeNodes[:],
 		Active:         bd.Active,
+		Address:        NewAddress(&bd.Address),
+		AccessNodes:    bd.AccessNodes[:],
 	}
 }
 
 func (bd *ChainRecord) ChainRecord() *registry.ChainRecord {
-	return &registry.ChainRecord{
+	ret := &registry.ChainRecord{
 		ChainID:        bd.ChainID.ChainID(),
 		Color:          bd.Color.Color(),
 		CommitteeNodes: bd.CommitteeNodes[:],
 		Active:         bd.Active,
+		AccessNodes:    bd.AccessNodes[:],
 	}
+	if bd.Address != "" {
+		ret.Address = bd.Address.Address()
+	}
+	return ret
+}
+
+// ChainCommittee is the body of the request to move the chain to another committee
+type ChainCommittee struct {
+	Color          Color    `swagger:"desc(Chain color (base58-encoded))"`
+	Address        Address  `swagger:"desc(Address of the new committee (base58-encoded))"`
+	CommitteeNodes []string `swagger:"desc(List of the new committee nodes (network IDs))"`
+	AccessNodes    []string `swagger:"desc(List of access nodes (network IDs). If omitted, access nodes in the chain record are kept)"`
+}
+
+// ChainAccessNodes is the body of the request to set the access nodes of the chain
+type ChainAccessNodes struct {
+	AccessNodes []string `swagger:"desc(List of access nodes (network IDs))"`
 }
@@ -0,0 +1,7 @@
+package model
+
+// EncryptionKeysResponse holds the public key shares of the committee of the chain, which
+// request arguments can be encrypted to
+type EncryptionKeysResponse struct {
+	Keys []string `json:"keys" swagger:"desc(Public key shares of the committee members, in DKShare index order (base64-encoded).)"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/iotaledger/wasp/packages/peering"
+)
+
+// NodeIdentity describes the peering identity of the node
+type NodeIdentity struct {
+	NetID       string           `json:"netID" swagger:"desc(Network address of the node)"`
+	Identity    string           `json:"identity" swagger:"desc(Identity of the node: 'id:' + base58 peering public key. Can be used instead of the NetID in chain records.)"`
+	Certificate *NodeCertificate `json:"certificate" swagger:"desc(Certificate issued by the node owner, if any)"`
+}
+
+// NodeCertificate is the certificate of the node peering public key issued by the node owner
+type NodeCertificate struct {
+	Identity string    `json:"identity" swagger:"desc(Identity of the certified node)"`
+	Owner    string    `json:"owner" swagger:"desc(ed25519 public key of the node owner (base58))"`
+	Issued   time.Time `json:"issued"`
+	Data     Bytes     `json:"data" swagger:"desc(Binary certificate (base64). The only field needed to upload a certificate.)"`
+}
+
+// TrustedPeer is a peer which presented a valid certificate
+type TrustedPeer struct {
+	NetID       string          `json:"netID" swagger:"desc(Last known network address of the peer)"`
+	Certificate NodeCertificate `json:"certificate"`
+}
+
+func NewNodeCertificate(cert *peering.NodeCertificate) *NodeCertificate {
+	return &NodeCertificate{
+		Identity: cert.Identity(),
+		Owner:    cert.OwnerPubKey.String(),
+		Issued:   cert.Issued,
+		Data:     NewBytes(cert.Bytes()),
+	}
+}
+
+func NewTrustedPeer(tp *peering.TrustedPeer) *TrustedPeer {
+	return &TrustedPeer{
+		NetID:       tp.NetID,
+		Certificate: *NewNodeCertificate(tp.Cert),
+	}
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/state"
+)
+
+type PruningPolicy struct {
+	KeepBlocks   uint32        `swagger:"desc(Number of the latest blocks retained (0 to disable the rule))"`
+	KeepDuration time.Duration `swagger:"desc(Blocks newer than this are retained, in nanoseconds (0 to disable the rule))"`
+	IsDefault    bool          `swagger:"desc(True if the chain has no own policy and the default policy of the node applies)"`
+}
+
+func NewPruningPolicy(p *registry.PruningPolicy, isDefault bool) *PruningPolicy {
+	return &PruningPolicy{
+		KeepBlocks:   p.KeepBlocks,
+		KeepDuration: p.KeepDuration,
+		IsDefault:    isDefault,
+	}
+}
+
+func (p *PruningPolicy) Policy() *registry.PruningPolicy {
+	return &registry.PruningPolicy{
+		KeepBlocks:   p.KeepBlocks,
+		KeepDuration: p.KeepDuration,
+	}
+}
+
+type PruneParams struct {
+	DryRun bool `swagger:"desc(If true, nothing is removed and only the report is returned)"`
+}
+
+type PruningResult struct {
+	FirstBlockIndex   uint32 `swagger:"desc(Index of the first block retained after pruning)"`
+	NumBlocks         int    `swagger:"desc(Number of the pruned blocks)"`
+	NumRequestMarkers int    `swagger:"desc(Number of the pruned markers of processed requests)"`
+	ReclaimedBytes    int64  `swagger:"desc(Total size of the removed keys and values)"`
+	DryRun            bool   `swagger:"desc(True if nothing was removed)"`
+}
+
+func NewPruningResult(r *state.PruningResult) *PruningResult {
+	return &PruningResult{
+		FirstBlockIndex:   r.FirstBlockIndex,
+		NumBlocks:         r.NumBlocks,
+		NumRequestMarkers: r.NumRequestMarkers,
+		ReclaimedBytes:    r.ReclaimedBytes,
+		DryRun:            r.DryRun,
+	}
+}
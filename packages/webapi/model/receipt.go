@@ -0,0 +1,70 @@
+package model
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/receipts"
+)
+
+type RequestReceipt struct {
+	RequestID    RequestID      `swagger:"desc(ID of the request (base58-encoded))"`
+	BlockIndex   uint32         `swagger:"desc(Index of the block the request was processed in)"`
+	RequestIndex uint16         `swagger:"desc(Index of the request in the block)"`
+	Error        string         `swagger:"desc(Error returned by the request. Empty if the request was successful)"`
+	Result       dict.Dict      `swagger:"desc(Result returned by the request)"`
+	FeeColor     Color          `swagger:"desc(Fee color (base58-encoded))"`
+	Fee          int64          `swagger:"desc(Fees charged from the request, including the fee for the gas burned)"`
+	GasUsed      uint64         `swagger:"desc(Gas burned by the request)"`
+	Events       []RequestEvent `swagger:"desc(Events emitted while processing the request)"`
+}
+
+type RequestEvent struct {
+	Contract string `swagger:"desc(Hname of the contract which emitted the event (hex-encoded))"`
+	Message  string `swagger:"desc(Event message)"`
+}
+
+func NewRequestReceipt(rec *receipts.RequestReceipt) *RequestReceipt {
+	ret := &RequestReceipt{
+		RequestID:    NewRequestID(&rec.RequestID),
+		BlockIndex:   rec.BlockIndex,
+		RequestIndex: rec.RequestIndex,
+		Error:        rec.Error,
+		Result:       rec.Result,
+		FeeColor:     NewColor(&rec.FeeColor),
+		Fee:          rec.Fee,
+		GasUsed:      rec.GasUsed,
+		Events:       make([]RequestEvent, len(rec.Events)),
+	}
+	for i, e := range rec.Events {
+		ret.Events[i] = RequestEvent{
+			Contract: e.Contract.String(),
+			Message:  e.Message,
+		}
+	}
+	return ret
+}
+
+func (r *RequestReceipt) RequestReceipt() (*receipts.RequestReceipt, error) {
+	ret := &receipts.RequestReceipt{
+		RequestID:    r.RequestID.RequestID(),
+		BlockIndex:   r.BlockIndex,
+		RequestIndex: r.RequestIndex,
+		Error:        r.Error,
+		Result:       r.Result,
+		FeeColor:     r.FeeColor.Color(),
+		Fee:          r.Fee,
+		GasUsed:      r.GasUsed,
+		Events:       make([]receipts.Event, len(r.Events)),
+	}
+	for i, e := range r.Events {
+		hname, err := coretypes.HnameFromString(e.Contract)
+		if err != nil {
+			return nil, err
+		}
+		ret.Events[i] = receipts.Event{
+			Contract: hname,
+			Message:  e.Message,
+		}
+	}
+	return ret, nil
+}
@@ -10,4 +10,12 @@ type RequestStatusResponse struct {
 	IsProcessed bool `swagger:"desc(True if the request has been processed)"`
 }
 
+type PostRequestTx struct {
+	Tx Bytes `swagger:"desc(Signed value transaction with the request(s) (base64-encoded))"`
+}
+
+type PostRequestResponse struct {
+	RequestIDs []RequestID `swagger:"desc(IDs of the requests to the chain contained in the transaction)"`
+}
+
 const WaitRequestProcessedDefaultTimeout = 30 * time.Second
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"encoding/json"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+)
+
+// RequestID is the base58 representation of the request ID
+type RequestID string
+
+func NewRequestID(reqID *coretypes.RequestID) RequestID {
+	return RequestID(reqID.Base58())
+}
+
+func (id RequestID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(id))
+}
+
+func (id *RequestID) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	_, err := coretypes.NewRequestIDFromBase58(s)
+	*id = RequestID(s)
+	return err
+}
+
+func (id RequestID) RequestID() coretypes.RequestID {
+	r, err := coretypes.NewRequestIDFromBase58(string(id))
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"github.com/iotaledger/wasp/packages/snapshot"
+)
+
+type SnapshotData struct {
+	Data Bytes `swagger:"desc(Snapshot file content (base64))"`
+}
+
+func NewSnapshotData(data []byte) *SnapshotData {
+	return &SnapshotData{Data: NewBytes(data)}
+}
+
+type SnapshotInfo struct {
+	ChainID    ChainID   `swagger:"desc(ChainID (base58-encoded))"`
+	BlockIndex uint32    `swagger:"desc(Index of the block of the snapshot)"`
+	StateHash  HashValue `swagger:"desc(Hash of the state)"`
+	StateTxID  ValueTxID `swagger:"desc(ID of the anchor transaction of the state)"`
+	NumBlobs   int       `swagger:"desc(Number of the blob cache entries)"`
+}
+
+func NewSnapshotInfo(s *snapshot.Snapshot) *SnapshotInfo {
+	chainID := s.ChainID()
+	txid := s.AnchorTransaction.ID()
+	return &SnapshotInfo{
+		ChainID:    NewChainID(&chainID),
+		BlockIndex: s.BlockIndex(),
+		StateHash:  NewHashValue(s.SolidState.Hash()),
+		StateTxID:  NewValueTxID(&txid),
+		NumBlobs:   len(s.Blobs),
+	}
+}
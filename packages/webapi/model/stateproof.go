@@ -0,0 +1,11 @@
+package model
+
+// StateProofResponse is the proof of inclusion of the state key/value pair
+// into the state of the chain at the specific block index
+type StateProofResponse struct {
+	BlockIndex uint32    `swagger:"desc(Index of the block the proof refers to)"`
+	StateTxID  ValueTxID `swagger:"desc(ID of the anchor transaction of the block (base58-encoded))"`
+	MerkleRoot HashValue `swagger:"desc(Merkle root of the state committed in the anchor transaction (base58-encoded))"`
+	Value      Bytes     `swagger:"desc(Value of the key (base64-encoded))"`
+	Proof      Bytes     `swagger:"desc(Binary encoded Merkle proof (base64-encoded))"`
+}
@@ -0,0 +1,49 @@
+package request
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	registry_pkg "github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/plugins/registry"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addEncryptionKeysEndpoint(server echoswagger.ApiRouter) {
+	server.GET(routes.EncryptionKeys(":chainID"), handleEncryptionKeys).
+		SetSummary("Get the public keys of the committee, used to encrypt request arguments").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddResponse(http.StatusOK, "Encryption keys", model.EncryptionKeysResponse{}, nil)
+}
+
+func handleEncryptionKeys(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID %+v: %s", c.Param("chainID"), err.Error()))
+	}
+	addr := address.Address(chainID)
+	if chr, err := registry_pkg.GetChainRecord(&chainID); err == nil && chr != nil {
+		// the chain could have been moved to another committee
+		addr = chr.Address
+	}
+	dkShare, err := registry.DefaultRegistry().LoadDKShare(&addr)
+	if err != nil {
+		return httperrors.NotFound(fmt.Sprintf("Committee keys not found for chain %s", chainID.String()))
+	}
+	ret := model.EncryptionKeysResponse{Keys: make([]string, len(dkShare.PublicShares))}
+	for i, pub := range dkShare.PublicShares {
+		b, err := pub.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		ret.Keys[i] = base64.StdEncoding.EncodeToString(b)
+	}
+	return c.JSON(http.StatusOK, ret)
+}
@@ -0,0 +1,71 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/plugins/chains"
+	"github.com/iotaledger/wasp/plugins/nodeconn"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addPostRequestEndpoint(server echoswagger.ApiRouter) {
+	server.POST(routes.PostRequest(":chainID"), handlePostRequest).
+		SetSummary("Post a signed request transaction to the chain. The node forwards it to the ledger").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamBody(model.PostRequestTx{}, "PostRequestTx", "Request transaction", true).
+		AddResponse(http.StatusAccepted, "Request IDs", model.PostRequestResponse{}, nil)
+}
+
+func handlePostRequest(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID %+v: %s", c.Param("chainID"), err.Error()))
+	}
+	ch := chains.GetChain(chainID)
+	if ch == nil {
+		return httperrors.NotFound(fmt.Sprintf("Chain not found: %s", chainID.String()))
+	}
+
+	var req model.PostRequestTx
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	vtx, _, err := valuetransaction.FromBytes(req.Tx.Bytes())
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid transaction: %v", err))
+	}
+	if !vtx.SignaturesValid() {
+		return httperrors.BadRequest("Invalid transaction signatures")
+	}
+	tx, err := sctransaction.ParseValueTransaction(vtx)
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Not a smart contract transaction: %v", err))
+	}
+	if _, err := tx.Properties(); err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid smart contract transaction: %v", err))
+	}
+	ret := model.PostRequestResponse{RequestIDs: make([]model.RequestID, 0)}
+	for i, reqSect := range tx.Requests() {
+		if reqSect.Target().ChainID() != chainID {
+			continue
+		}
+		reqID := coretypes.NewRequestID(tx.ID(), uint16(i))
+		ret.RequestIDs = append(ret.RequestIDs, model.NewRequestID(&reqID))
+	}
+	if len(ret.RequestIDs) == 0 {
+		return httperrors.BadRequest(fmt.Sprintf("The transaction contains no requests to the chain %s", chainID.String()))
+	}
+	addr := ch.Address()
+	if err := nodeconn.PostTransactionToNode(vtx, &addr, ch.OwnPeerIndex()); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusAccepted, ret)
+}
@@ -0,0 +1,47 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/receipts"
+	"github.com/iotaledger/wasp/packages/vm/viewcontext"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addReceiptEndpoint(server echoswagger.ApiRouter) {
+	server.GET(routes.RequestReceipt(":chainID", ":reqID"), handleRequestReceipt).
+		SetSummary("Get the receipt of the processed request: the error, the result, fees and events").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamPath("", "reqID", "Request ID (base58)").
+		AddResponse(http.StatusOK, "Request receipt", model.RequestReceipt{}, nil)
+}
+
+func handleRequestReceipt(c echo.Context) error {
+	ch, reqID, err := parseParams(c)
+	if err != nil {
+		return err
+	}
+	vctx, err := viewcontext.NewFromDB(*ch.ID(), ch.Processors())
+	if err != nil {
+		return fmt.Errorf("Failed to create context: %v", err)
+	}
+	params := dict.New()
+	params.Set(receipts.ParamRequestID, codec.EncodeRequestID(*reqID))
+	ret, err := vctx.CallView(receipts.Interface.Hname(), coretypes.Hn(receipts.FuncGetReceipt), params)
+	if err != nil {
+		return httperrors.NotFound(fmt.Sprintf("Receipt not found: %v", err))
+	}
+	rec, err := receipts.DecodeRequestReceipt(ret.MustGet(receipts.ParamReceipt))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, model.NewRequestReceipt(rec))
+}
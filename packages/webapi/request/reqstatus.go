@@ -28,6 +28,10 @@ func AddEndpoints(server echoswagger.ApiRouter) {
 		AddParamPath("", "chainID", "ChainID (base58)").
 		AddParamPath("", "reqID", "Request ID (base58)").
 		AddParamBody(model.WaitRequestProcessedParams{}, "Params", "Optional parameters", false)
+
+	addReceiptEndpoint(server)
+	addEncryptionKeysEndpoint(server)
+	addPostRequestEndpoint(server)
 }
 
 func handleRequestStatus(c echo.Context) error {
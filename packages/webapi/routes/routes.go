@@ -12,14 +12,38 @@ func RequestStatus(chainID string, reqID string) string {
 	return "/chain/" + chainID + "/request/" + reqID + "/status"
 }
 
+func PostRequest(chainID string) string {
+	return "/chain/" + chainID + "/request"
+}
+
 func WaitRequestProcessed(chainID string, reqID string) string {
 	return "/chain/" + chainID + "/request/" + reqID + "/wait"
 }
 
+func RequestReceipt(chainID string, reqID string) string {
+	return "/chain/" + chainID + "/request/" + reqID + "/receipt"
+}
+
 func StateQuery(chainID string) string {
 	return "/chain/" + chainID + "/state/query"
 }
 
+func StateProof(chainID string, key string) string {
+	return "/chain/" + chainID + "/state/proof/" + key
+}
+
+func ListBlocks(chainID string) string {
+	return "/chain/" + chainID + "/blocks"
+}
+
+func GetBlock(chainID string, blockIndex string) string {
+	return "/chain/" + chainID + "/block/" + blockIndex
+}
+
+func ChainEvents(chainID string) string {
+	return "/ws/chain/" + chainID + "/events"
+}
+
 func PutBlob() string {
 	return "/blob/put"
 }
@@ -40,6 +64,30 @@ func DeactivateChain(chainID string) string {
 	return "/adm/chain/" + chainID + "/deactivate"
 }
 
+func UpdateChainCommittee(chainID string) string {
+	return "/adm/chain/" + chainID + "/committee"
+}
+
+func SetChainAccessNodes(chainID string) string {
+	return "/adm/chain/" + chainID + "/accessnodes"
+}
+
+func ExportSnapshot(chainID string) string {
+	return "/adm/chain/" + chainID + "/snapshot"
+}
+
+func ImportSnapshot() string {
+	return "/adm/snapshot"
+}
+
+func PruningPolicy(chainID string) string {
+	return "/adm/chain/" + chainID + "/pruning"
+}
+
+func PruneChain(chainID string) string {
+	return "/adm/chain/" + chainID + "/prune"
+}
+
 func ListChainRecords() string {
 	return "/adm/chainrecords"
 }
@@ -67,3 +115,55 @@ func DumpState(contractID string) string {
 func Shutdown() string {
 	return "/adm/shutdown"
 }
+
+func Login() string {
+	return "/auth/login"
+}
+
+func LoginChallenge() string {
+	return "/auth/challenge"
+}
+
+func LoginWithKey() string {
+	return "/auth/login/key"
+}
+
+func ListAPIKeys() string {
+	return "/adm/apikeys"
+}
+
+func PostAPIKey() string {
+	return "/adm/apikey"
+}
+
+func RevokeAPIKey(hash string) string {
+	return "/adm/apikey/" + hash
+}
+
+func ListAuthorizedKeys() string {
+	return "/adm/authorizedkeys"
+}
+
+func PutAuthorizedKey() string {
+	return "/adm/authorizedkey"
+}
+
+func RevokeAuthorizedKey(pubKey string) string {
+	return "/adm/authorizedkey/" + pubKey
+}
+
+func EncryptionKeys(chainID string) string {
+	return "/chain/" + chainID + "/encryptionkeys"
+}
+
+func GetNodeIdentity() string {
+	return "/adm/node/identity"
+}
+
+func PutNodeCertificate() string {
+	return "/adm/node/certificate"
+}
+
+func ListTrustedPeers() string {
+	return "/adm/peering/trusted"
+}
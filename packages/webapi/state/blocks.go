@@ -0,0 +1,148 @@
+package state
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+const (
+	listBlocksDefaultCount = 20
+	listBlocksMaxCount     = 100
+)
+
+func addBlockEndpoints(server echoswagger.ApiRouter) {
+	server.GET(routes.ListBlocks(":chainID"), handleListBlocks).
+		SetSummary("List blocks of the chain, starting from the newest one").
+		AddParamPath("", "chainID", "ChainID (base58-encoded)").
+		AddParamQuery(uint32(0), "from", "Index of the newest block in the list. Defaults to the latest block", false).
+		AddParamQuery(uint32(0), "count", fmt.Sprintf("Maximum number of blocks in the list. Defaults to %d, at most %d",
+			listBlocksDefaultCount, listBlocksMaxCount), false).
+		AddResponse(http.StatusOK, "List of blocks", model.BlockListResponse{}, nil)
+
+	server.GET(routes.GetBlock(":chainID", ":blockIndex"), handleGetBlock).
+		SetSummary("Get the block with the state updates made by each request").
+		AddParamPath("", "chainID", "ChainID (base58-encoded)").
+		AddParamPath("", "blockIndex", "Block index").
+		AddResponse(http.StatusOK, "Block", model.BlockResponse{}, nil)
+}
+
+func handleListBlocks(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID: %+v", c.Param("chainID")))
+	}
+	vs, _, ok, err := state.LoadSolidState(&chainID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return httperrors.NotFound(fmt.Sprintf("State not found: %s", chainID.String()))
+	}
+
+	from := vs.BlockIndex()
+	if s := c.QueryParam("from"); s != "" {
+		n, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return httperrors.BadRequest(fmt.Sprintf("Invalid block index: %+v", s))
+		}
+		if uint32(n) < from {
+			from = uint32(n)
+		}
+	}
+	count := uint64(listBlocksDefaultCount)
+	if s := c.QueryParam("count"); s != "" {
+		if count, err = strconv.ParseUint(s, 10, 32); err != nil {
+			return httperrors.BadRequest(fmt.Sprintf("Invalid count: %+v", s))
+		}
+		if count > listBlocksMaxCount {
+			count = listBlocksMaxCount
+		}
+	}
+
+	ret := model.BlockListResponse{
+		LatestBlockIndex: vs.BlockIndex(),
+		Blocks:           make([]model.BlockInfo, 0, count),
+	}
+	for i := int64(from); i >= 0 && uint64(len(ret.Blocks)) < count; i-- {
+		block, err := state.LoadBlock(&chainID, uint32(i))
+		if err != nil {
+			return err
+		}
+		if block == nil {
+			// the rest of the blocks is not available in the db
+			break
+		}
+		ret.Blocks = append(ret.Blocks, newBlockInfo(block))
+	}
+	return c.JSON(http.StatusOK, ret)
+}
+
+func handleGetBlock(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID: %+v", c.Param("chainID")))
+	}
+	blockIndex, err := strconv.ParseUint(c.Param("blockIndex"), 10, 32)
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid block index: %+v", c.Param("blockIndex")))
+	}
+	block, err := state.LoadBlock(&chainID, uint32(blockIndex))
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		return httperrors.NotFound(fmt.Sprintf("Block #%d not found in chain %s", blockIndex, chainID.String()))
+	}
+
+	txid := block.StateTransactionID()
+	ret := model.BlockResponse{
+		Index:        block.StateIndex(),
+		StateTxID:    model.NewValueTxID(&txid),
+		Timestamp:    time.Unix(0, block.Timestamp()),
+		StateUpdates: make([]model.StateUpdate, 0, block.Size()),
+	}
+	block.ForEach(func(_ uint16, su state.StateUpdate) bool {
+		mutations := make([]model.Mutation, 0, su.Mutations().Len())
+		su.Mutations().Iterate(func(mut buffered.Mutation) bool {
+			mutations = append(mutations, model.Mutation{
+				Kind:  buffered.MutationKind(mut),
+				Key:   model.NewBytes([]byte(mut.Key())),
+				Value: model.NewBytes(mut.Value()),
+			})
+			return true
+		})
+		ret.StateUpdates = append(ret.StateUpdates, model.StateUpdate{
+			RequestID: model.NewRequestID(su.RequestID()),
+			Timestamp: time.Unix(0, su.Timestamp()),
+			Mutations: mutations,
+		})
+		return true
+	})
+	return c.JSON(http.StatusOK, ret)
+}
+
+func newBlockInfo(block state.Block) model.BlockInfo {
+	txid := block.StateTransactionID()
+	reqIDs := block.RequestIDs()
+	ret := model.BlockInfo{
+		Index:      block.StateIndex(),
+		StateTxID:  model.NewValueTxID(&txid),
+		Timestamp:  time.Unix(0, block.Timestamp()),
+		RequestIDs: make([]model.RequestID, len(reqIDs)),
+	}
+	for i, reqID := range reqIDs {
+		ret.RequestIDs[i] = model.NewRequestID(reqID)
+	}
+	return ret
+}
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/vm/viewcontext"
 	"github.com/iotaledger/wasp/packages/webapi/httperrors"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
@@ -26,7 +29,11 @@ func AddEndpoints(server echoswagger.ApiRouter) {
 		AddParamPath("", "contractID", "ContractID (base58-encoded)").
 		AddParamPath("getInfo", "fname", "Function name").
 		AddParamBody(dictExample, "params", "Parameters", false).
+		AddParamQuery(uint32(0), "atBlock", "Block index of the historical state. Defaults to the latest state", false).
 		AddResponse(http.StatusOK, "Result", dictExample, nil)
+
+	addStateProofEndpoint(server)
+	addBlockEndpoints(server)
 }
 
 func handleCallView(c echo.Context) error {
@@ -50,12 +57,33 @@ func handleCallView(c echo.Context) error {
 		return httperrors.NotFound(fmt.Sprintf("Chain not found: %s", contractID.ChainID()))
 	}
 
+	var ret dict.Dict
+	if atBlock := c.QueryParam("atBlock"); atBlock != "" {
+		blockIndex, err := strconv.ParseUint(atBlock, 10, 32)
+		if err != nil {
+			return httperrors.BadRequest(fmt.Sprintf("Invalid block index: %+v", atBlock))
+		}
+		// the view is called while the historical state is consistent
+		var callErr error
+		err = state.WithStateAtBlock(chain.ID(), uint32(blockIndex), func(variables buffered.BufferedKVStore, block state.Block) error {
+			vctx := viewcontext.New(*chain.ID(), variables, block.Timestamp(), chain.Processors(), nil)
+			ret, callErr = vctx.CallView(contractID.Hname(), coretypes.Hn(fname), params)
+			return nil
+		})
+		if err != nil {
+			return httperrors.NotFound(fmt.Sprintf("State at block #%d is not available: %v", blockIndex, err))
+		}
+		if callErr != nil {
+			return httperrors.BadRequest(fmt.Sprintf("View call failed: %v", callErr))
+		}
+		return c.JSON(http.StatusOK, ret)
+	}
+
 	vctx, err := viewcontext.NewFromDB(*chain.ID(), chain.Processors())
 	if err != nil {
 		return fmt.Errorf(fmt.Sprintf("Failed to create context: %v", err))
 	}
-
-	ret, err := vctx.CallView(contractID.Hname(), coretypes.Hn(fname), params)
+	ret, err = vctx.CallView(contractID.Hname(), coretypes.Hn(fname), params)
 	if err != nil {
 		return httperrors.BadRequest(fmt.Sprintf("View call failed: %v", err))
 	}
@@ -0,0 +1,79 @@
+package state
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/state/merkle"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addStateProofEndpoint(server echoswagger.ApiRouter) {
+	server.GET(routes.StateProof(":chainID", ":key"), handleStateProof).
+		SetSummary("Get the Merkle proof of inclusion of the state key").
+		AddParamPath("", "chainID", "ChainID (base58-encoded)").
+		AddParamPath("", "key", "State key (hex-encoded)").
+		AddParamQuery(uint32(0), "atBlock", "Block index. Defaults to the latest block", false).
+		AddResponse(http.StatusOK, "Proof of inclusion", model.StateProofResponse{}, nil)
+}
+
+func handleStateProof(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID: %+v", c.Param("chainID")))
+	}
+	key, err := hex.DecodeString(c.Param("key"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid key: %+v", c.Param("key")))
+	}
+
+	vs, block, ok, err := state.LoadSolidState(&chainID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return httperrors.NotFound(fmt.Sprintf("State not found: %s", chainID.String()))
+	}
+	blockIndex := vs.BlockIndex()
+	if atBlock := c.QueryParam("atBlock"); atBlock != "" {
+		b, err := strconv.ParseUint(atBlock, 10, 32)
+		if err != nil {
+			return httperrors.BadRequest(fmt.Sprintf("Invalid block index: %+v", atBlock))
+		}
+		blockIndex = uint32(b)
+	}
+
+	var proof *merkle.Proof
+	if blockIndex == vs.BlockIndex() {
+		proof, err = vs.MerkleProof(kv.Key(key))
+	} else {
+		proof, block, err = state.LoadStateProof(&chainID, kv.Key(key), blockIndex)
+	}
+	if err != nil {
+		return httperrors.NotFound(fmt.Sprintf("Proof at block #%d is not available: %v", blockIndex, err))
+	}
+	root, err := proof.Root()
+	if err != nil {
+		return err
+	}
+	if blockIndex == vs.BlockIndex() && root != vs.MerkleRoot() {
+		return fmt.Errorf("inconsistent state: Merkle root mismatch at block #%d", blockIndex)
+	}
+	txid := block.StateTransactionID()
+	return c.JSON(http.StatusOK, &model.StateProofResponse{
+		BlockIndex: blockIndex,
+		StateTxID:  model.NewValueTxID(&txid),
+		MerkleRoot: model.NewHashValue(root),
+		Value:      model.NewBytes(proof.Value),
+		Proof:      model.NewBytes(proof.Bytes()),
+	})
+}
@@ -87,15 +87,21 @@ func ActivateChain(chr *registry_pkg.ChainRecord) error {
 		return fmt.Errorf("cannot activate chain for deactivated chain record")
 	}
 
-	_, ok := chains[chr.ChainID]
-	if ok {
-		log.Debugf("chain is already active: %s", chr.ChainID.String())
-		return nil
+	if c, ok := chains[chr.ChainID]; ok {
+		if !c.IsDismissed() {
+			log.Debugf("chain is already active: %s", chr.ChainID.String())
+			return nil
+		}
+		// the chain was deactivated before, for example to move it to another committee
+		delete(chains, chr.ChainID)
+		if c.Address() != chr.Address {
+			nodeconn.Unsubscribe(c.Address())
+		}
 	}
 	// create new chain object
 	defaultRegistry := registry.DefaultRegistry()
 	c := chain.New(chr, log, peering.DefaultNetworkProvider(), defaultRegistry, defaultRegistry, func() {
-		nodeconn.Subscribe((address.Address)(chr.ChainID), chr.Color)
+		nodeconn.Subscribe(chr.Address, chr.Color)
 	})
 	if c != nil {
 		chains[chr.ChainID] = c
@@ -129,8 +135,22 @@ func GetChain(chainID coretypes.ChainID) chain.Chain {
 	ret, ok := chains[chainID]
 	if ok && ret.IsDismissed() {
 		delete(chains, chainID)
-		nodeconn.Unsubscribe((address.Address)(chainID))
+		nodeconn.Unsubscribe(ret.Address())
 		return nil
 	}
 	return ret
 }
+
+// GetChainByAddress returns active chain object controlled by the committee address or nil if it doesn't exist.
+// The address is equal to the chain ID unless the chain was moved to another committee
+func GetChainByAddress(addr address.Address) chain.Chain {
+	chainsMutex.RLock()
+	defer chainsMutex.RUnlock()
+
+	for _, c := range chains {
+		if c.Address() == addr && !c.IsDismissed() {
+			return c
+		}
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/iotaledger/wasp/packages/dashboard"
 	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/packages/util/auth"
+	"github.com/iotaledger/wasp/plugins/registry"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
@@ -40,7 +41,8 @@ func configure(*node.Plugin) {
 		Format: `${time_rfc3339_nano} ${remote_ip} ${method} ${uri} ${status} error="${error}"` + "\n",
 	}))
 	Server.Use(middleware.Recover())
-	auth.AddAuthentication(Server, parameters.GetStringToString(parameters.DashboardAuth))
+	auth.AddAuthentication(Server, parameters.GetStringToString(parameters.DashboardAuth), registry.KeyStore())
+	Server.Use(auth.RequireRole(auth.RoleReadOnly))
 
 	dashboard.Init(Server)
 }
@@ -8,7 +8,6 @@ import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/chain"
-	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/plugins/chains"
 )
@@ -35,7 +34,7 @@ func dispatchState(tx *sctransaction.Transaction) {
 
 func dispatchBalances(addr address.Address, bals map[valuetransaction.ID][]*balance.Balance) {
 	// pass to the committee by address
-	if cmt := chains.GetChain((coretypes.ChainID)(addr)); cmt != nil {
+	if cmt := chains.GetChainByAddress(addr); cmt != nil {
 		cmt.ReceiveMessage(chain.BalancesMsg{Balances: bals})
 	}
 }
@@ -43,7 +42,7 @@ func dispatchBalances(addr address.Address, bals map[valuetransaction.ID][]*bala
 func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.ID][]*balance.Balance, tx *sctransaction.Transaction) {
 	log.Debugw("dispatchAddressUpdate", "addr", addr.String())
 
-	cmt := chains.GetChain((coretypes.ChainID)(addr))
+	cmt := chains.GetChainByAddress(addr)
 	if cmt == nil {
 		log.Debugw("committee not found", "addr", addr.String())
 		// wrong addressee
@@ -57,7 +56,7 @@ func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.I
 	})
 
 	txProp := tx.MustProperties() // was parsed before
-	if txProp.IsState() && *txProp.MustChainID() == (coretypes.ChainID)(addr) {
+	if txProp.IsState() && *txProp.MustChainID() == *cmt.ID() {
 		// it is a state update to addr. Send it
 		cmt.ReceiveMessage(&chain.StateTransactionMsg{
 			Transaction: tx,
@@ -73,7 +72,7 @@ func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.I
 		freeTokens = nil
 	}
 	for i, reqBlk := range tx.Requests() {
-		if reqBlk.Target().ChainID() == (coretypes.ChainID)(addr) {
+		if reqBlk.Target().ChainID() == *cmt.ID() && reqBlk.TargetAddress() == addr {
 			cmt.ReceiveMessage(&chain.RequestMsg{
 				Transaction: tx,
 				Index:       (uint16)(i),
@@ -86,7 +85,7 @@ func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.I
 
 func dispatchTxInclusionLevel(level byte, txid *valuetransaction.ID, addrs []address.Address) {
 	for _, addr := range addrs {
-		cmt := chains.GetChain((coretypes.ChainID)(addr))
+		cmt := chains.GetChainByAddress(addr)
 		if cmt == nil {
 			continue
 		}
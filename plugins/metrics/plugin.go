@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/wasp/packages/metrics"
+	"github.com/iotaledger/wasp/packages/parameters"
+	peering_pkg "github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/plugins/peering"
+)
+
+// PluginName is the name of the metrics plugin.
+const PluginName = "Metrics"
+
+var (
+	server *http.Server
+	log    *logger.Logger
+)
+
+// Init returns the metrics plugin. It is disabled by default; enable it by adding
+// "metrics" to node.enablePlugins
+func Init() *node.Plugin {
+	return node.NewPlugin(PluginName, node.Disabled, configure, run)
+}
+
+func configure(_ *node.Plugin) {
+	log = logger.NewLogger(PluginName)
+
+	// the network provider is created when the peering plugin is configured,
+	// so it is resolved only when the metrics are collected
+	metrics.SetPeerStatusProvider(func() []peering_pkg.PeerStatusProvider {
+		return peering.DefaultNetworkProvider().PeerStatus()
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	server = &http.Server{
+		Addr:    parameters.GetString(parameters.MetricsBindAddress),
+		Handler: mux,
+	}
+}
+
+func run(_ *node.Plugin) {
+	log.Infof("Starting %s ...", PluginName)
+	if err := daemon.BackgroundWorker(PluginName, worker); err != nil {
+		log.Errorf("Error starting as daemon: %s", err)
+	}
+}
+
+func worker(shutdownSignal <-chan struct{}) {
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		log.Infof("%s started, bind address=%s", PluginName, server.Addr)
+		if err := server.ListenAndServe(); err != nil {
+			if !errors.Is(err, http.ErrServerClosed) {
+				log.Errorf("Error serving: %s", err)
+			}
+		}
+	}()
+
+	select {
+	case <-shutdownSignal:
+	case <-stopped:
+	}
+
+	log.Infof("Stopping %s ...", PluginName)
+	defer log.Infof("Stopping %s ... done", PluginName)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Errorf("Error stopping: %s", err)
+	}
+}
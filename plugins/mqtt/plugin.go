@@ -0,0 +1,81 @@
+package mqtt
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/wasp/packages/parameters"
+	"github.com/iotaledger/wasp/packages/publisher"
+	"github.com/iotaledger/wasp/packages/publisher/mqtt"
+)
+
+// PluginName is the name of the MQTT plugin.
+const PluginName = "MQTT"
+
+var (
+	log *logger.Logger
+)
+
+type message struct {
+	msgType string
+	parts   []string
+}
+
+// Init returns the MQTT plugin. It is disabled by default; enable it by adding
+// "mqtt" to node.enablePlugins
+func Init() *node.Plugin {
+	return node.NewPlugin(PluginName, node.Disabled, configure, run)
+}
+
+func configure(_ *node.Plugin) {
+	log = logger.NewLogger(PluginName)
+}
+
+func run(_ *node.Plugin) {
+	messages := make(chan *message, 100)
+
+	bindAddress := parameters.GetString(parameters.MqttBindAddress)
+	wsBindAddress := parameters.GetString(parameters.MqttWebsocketBindAddress)
+	broker, err := mqtt.NewBroker(bindAddress, wsBindAddress)
+	if err != nil {
+		log.Errorf("failed to initialize MQTT broker: %v", err)
+		return
+	}
+	log.Infof("MQTT broker is running on %s", bindAddress)
+	if wsBindAddress != "" {
+		log.Infof("MQTT over websocket is running on %s", wsBindAddress)
+	}
+
+	onPublish := events.NewClosure(func(msgType string, parts []string) {
+		select {
+		case messages <- &message{msgType: msgType, parts: parts}:
+		case <-time.After(1 * time.Second):
+			log.Warnf("Failed to publish MQTT message: %s %v", msgType, parts)
+		}
+	})
+
+	err = daemon.BackgroundWorker(PluginName, func(shutdownSignal <-chan struct{}) {
+		publisher.Event.Attach(onPublish)
+		defer publisher.Event.Detach(onPublish)
+
+		for {
+			select {
+			case msg := <-messages:
+				if err := broker.Publish(msg.msgType, msg.parts); err != nil {
+					log.Errorf("Failed to publish MQTT message: %v", err)
+				}
+			case <-shutdownSignal:
+				if err := broker.Close(); err != nil {
+					log.Warnf("Failed to close MQTT broker: %v", err)
+				}
+				return
+			}
+		}
+	})
+	if err != nil {
+		panic(err)
+	}
+}
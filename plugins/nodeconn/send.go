@@ -2,9 +2,12 @@ package nodeconn
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
+	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/plugins/peering"
 )
@@ -83,3 +86,29 @@ func PostTransactionToNode(tx *valuetransaction.Transaction, fromSc *address.Add
 	}
 	return nil
 }
+
+// GetConfirmedTransaction requests the confirmed transaction from the node and waits for it.
+// The node doesn't respond if the transaction is not confirmed, so the timeout means it is not
+func GetConfirmedTransaction(txid valuetransaction.ID, timeout time.Duration) (*valuetransaction.Transaction, error) {
+	received := make(chan *valuetransaction.Transaction, 1)
+	closure := events.NewClosure(func(msg interface{}) {
+		if msgt, ok := msg.(*waspconn.WaspFromNodeConfirmedTransactionMsg); ok && msgt.Tx.ID() == txid {
+			select {
+			case received <- msgt.Tx:
+			default:
+			}
+		}
+	})
+	EventMessageReceived.Attach(closure)
+	defer EventMessageReceived.Detach(closure)
+
+	if err := RequestConfirmedTransactionFromNode(&txid); err != nil {
+		return nil, err
+	}
+	select {
+	case tx := <-received:
+		return tx, nil
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("transaction %s is not confirmed", txid.String())
+	}
+}
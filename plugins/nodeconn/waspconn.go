@@ -14,6 +14,7 @@ import (
 	"github.com/iotaledger/hive.go/backoff"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/netutil/buffconn"
+	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/plugins/peering"
 )
@@ -51,6 +52,7 @@ func nodeConnect() {
 	bconnMutex.Lock()
 	bconn = buffconn.NewBufferedConnection(conn, tangle.MaxMessageSize)
 	bconnMutex.Unlock()
+	metrics.NodeConnConnected(true)
 
 	log.Debugf("established connection with node at %s", addr)
 
@@ -61,6 +63,7 @@ func nodeConnect() {
 	bconn.Events.ReceiveMessage.Attach(dataReceivedClosure)
 	bconn.Events.Close.Attach(events.NewClosure(func() {
 		log.Errorf("lost connection with %s", addr)
+		metrics.NodeConnConnected(false)
 		go func() {
 			bconnMutex.Lock()
 			bconnSave := bconn
@@ -97,6 +100,7 @@ func IsConnected() bool {
 func retryNodeConnect() {
 	log.Infof("will retry connecting to the node after %v", retryAfter)
 	time.Sleep(retryAfter)
+	metrics.NodeConnReconnect()
 	go nodeConnect()
 }
 
@@ -42,6 +42,20 @@ func Init(suite *pairing.SuiteBn256) *node.Plugin {
 		if err != nil {
 			panic(err)
 		}
+		if err = defaultNetworkProvider.UseTrustedPeerStore(registry.DefaultRegistry()); err != nil {
+			panic(err)
+		}
+		var nodeCert *peering_pkg.NodeCertificate
+		if nodeCert, err = registry.DefaultRegistry().GetNodeCertificate(); err != nil {
+			panic(err)
+		}
+		if nodeCert != nil {
+			if err = defaultNetworkProvider.SetNodeCertificate(nodeCert); err != nil {
+				log.Warnf("the node certificate is not used: %v", err)
+			} else {
+				log.Infof("node certificate loaded. Owner: %s", nodeCert.OwnerPubKey)
+			}
+		}
 		log.Infof(
 			"--------------------------------- NetID is %s -----------------------------------",
 			defaultNetworkProvider.Self().NetID(),
@@ -60,6 +74,15 @@ func Init(suite *pairing.SuiteBn256) *node.Plugin {
 	return node.NewPlugin(pluginName, node.Enabled, configure, run)
 }
 
+// SetNodeCertificate validates and saves the certificate of the node and starts
+// presenting it to the peers.
+func SetNodeCertificate(cert *peering_pkg.NodeCertificate) error {
+	if err := defaultNetworkProvider.SetNodeCertificate(cert); err != nil {
+		return err
+	}
+	return registry.DefaultRegistry().SaveNodeCertificate(cert)
+}
+
 // DefaultNetworkProvider returns the default network provider implementation.
 func DefaultNetworkProvider() peering_pkg.NetworkProvider {
 	return defaultNetworkProvider
@@ -0,0 +1,119 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pruning runs the background pruner, which removes the old blocks of the chains
+// according to the pruning policy of each chain, or the default policy of the node.
+package pruning
+
+import (
+	"sync"
+	"time"
+
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/parameters"
+	registry_pkg "github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/plugins/nodeconn"
+	"github.com/iotaledger/wasp/plugins/registry"
+)
+
+const PluginName = "Pruning"
+
+// confirmedTxTimeout is how long the pruner waits for the anchor transaction from the node.
+// The node doesn't respond if the transaction is not confirmed
+const confirmedTxTimeout = 10 * time.Second
+
+var (
+	log *logger.Logger
+	// pruning of chains is serialized between the background worker and the API calls
+	mutex sync.Mutex
+)
+
+func Init() *node.Plugin {
+	return node.NewPlugin(PluginName, node.Enabled, configure, run)
+}
+
+func configure(_ *node.Plugin) {
+	log = logger.NewLogger(PluginName)
+}
+
+func run(_ *node.Plugin) {
+	err := daemon.BackgroundWorker(PluginName, func(shutdownSignal <-chan struct{}) {
+		interval := parameters.GetDuration(parameters.PruningInterval)
+		log.Infof("%s started, interval: %v, default policy: %s", PluginName, interval, DefaultPolicy().String())
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-shutdownSignal:
+				log.Infof("Stopping %s ... done", PluginName)
+				return
+			case <-ticker.C:
+				pruneAll()
+			}
+		}
+	}, parameters.PriorityPruning)
+	if err != nil {
+		log.Errorf("failed to start as daemon: %s", err)
+	}
+}
+
+// DefaultPolicy is the pruning policy of the chains which have no own policy
+func DefaultPolicy() *registry_pkg.PruningPolicy {
+	return &registry_pkg.PruningPolicy{
+		KeepBlocks:   uint32(parameters.GetInt(parameters.PruningKeepBlocks)),
+		KeepDuration: parameters.GetDuration(parameters.PruningKeepDuration),
+	}
+}
+
+// GetPolicy returns the effective pruning policy of the chain
+func GetPolicy(chainID *coretypes.ChainID) (*registry_pkg.PruningPolicy, error) {
+	policy, err := registry.DefaultRegistry().GetPruningPolicy(chainID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		policy = DefaultPolicy()
+	}
+	return policy, nil
+}
+
+// PruneChain prunes the chain according to its policy. In the dry run mode nothing is removed
+func PruneChain(chainID *coretypes.ChainID, dryRun bool) (*state.PruningResult, error) {
+	policy, err := GetPolicy(chainID)
+	if err != nil {
+		return nil, err
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return state.Prune(chainID, policy.KeepBlocks, policy.KeepDuration, time.Now(), dryRun, getConfirmedTransaction)
+}
+
+func getConfirmedTransaction(txid valuetransaction.ID) (*valuetransaction.Transaction, error) {
+	return nodeconn.GetConfirmedTransaction(txid, confirmedTxTimeout)
+}
+
+func pruneAll() {
+	chainRecords, err := registry_pkg.GetChainRecords()
+	if err != nil {
+		log.Errorf("failed to load chain records: %v", err)
+		return
+	}
+	for _, chr := range chainRecords {
+		res, err := PruneChain(&chr.ChainID, false)
+		if err != nil {
+			log.Errorf("failed to prune chain %s: %v", chr.ChainID.String(), err)
+			continue
+		}
+		if res.NumBlocks == 0 {
+			continue
+		}
+		log.Infof("pruned chain %s: %d blocks, %d request markers, %d bytes reclaimed. First block: #%d",
+			chr.ChainID.String(), res.NumBlocks, res.NumRequestMarkers, res.ReclaimedBytes, res.FirstBlockIndex)
+	}
+}
@@ -4,10 +4,12 @@
 package registry
 
 import (
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/logger"
 	hive_node "github.com/iotaledger/hive.go/node"
 	registry_pkg "github.com/iotaledger/wasp/packages/registry"
 	tcrypto_pkg "github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/util/auth"
 )
 
 const pluginName = "Registry"
@@ -21,6 +23,22 @@ func DefaultRegistry() *registry_pkg.Impl {
 	return defaultRegistry
 }
 
+// KeyStore returns the auth.KeyStore backed by the default registry.
+// The registry is resolved on each call, so it can be used by plugins configured before this one.
+func KeyStore() auth.KeyStore {
+	return keyStore{}
+}
+
+type keyStore struct{}
+
+func (keyStore) APIKeyRole(key string) (auth.Role, bool, error) {
+	return defaultRegistry.APIKeyRole(key)
+}
+
+func (keyStore) AuthorizedKeyRole(pubKey ed25519.PublicKey) (auth.Role, bool, error) {
+	return defaultRegistry.AuthorizedKeyRole(pubKey)
+}
+
 // Init is an entry point for the plugin.
 func Init(suite tcrypto_pkg.Suite) *hive_node.Plugin {
 	configure := func(_ *hive_node.Plugin) {
@@ -2,14 +2,18 @@
 // Plugin name serves as a VM type during dynamic loading of the binary.
 // VM plugins can be enabled/disabled in the configuration of the node instance
 // wasmtimevm plugin statically links VM implemented with Wasmtime to Wasp
-// be registering wasmhost.GetProcessor as function
+// be registering wasmhost.GetProcessor as function.
+// The Wasm engine which runs the binaries (Wasmtime or the pure Go interpreter) is selected
+// by the 'wasm.engine' parameter, the VM type stays the same for both engines
 package wasmtimevm
 
 import (
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/packages/vm/processors"
+	"github.com/iotaledger/wasp/packages/vm/wasmhost"
 	"github.com/iotaledger/wasp/packages/vm/wasmproc"
 )
 
@@ -25,14 +29,22 @@ func Init() *node.Plugin {
 func configure(_ *node.Plugin) {
 	log = logger.NewLogger(VMType)
 
+	engine := parameters.GetString(parameters.WasmEngine)
+	if engine == "" {
+		engine = wasmhost.DefaultEngine()
+	}
+	if !wasmhost.HasEngine(engine) {
+		log.Panicf("%v: unknown Wasm engine '%s', available: %v", VMType, engine, wasmhost.Engines())
+	}
+
 	// register VM type(s)
 	err := processors.RegisterVMType(VMType, func(binary []byte) (coretypes.Processor, error) {
-		return wasmproc.GetProcessor(binary, log)
+		return wasmproc.GetProcessorForEngine(engine, binary, log)
 	})
 	if err != nil {
 		log.Panicf("%v: %v", VMType, err)
 	}
-	log.Infof("registered VM type: '%s'", VMType)
+	log.Infof("registered VM type: '%s', Wasm engine: '%s'", VMType, engine)
 }
 
 func run(_ *node.Plugin) {
@@ -11,10 +11,12 @@ import (
 	"github.com/iotaledger/hive.go/daemon"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/packages/util/auth"
 	"github.com/iotaledger/wasp/packages/webapi"
 	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/plugins/registry"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/pangpanglabs/echoswagger/v2"
@@ -57,9 +59,11 @@ func configure(*node.Plugin) {
 		Format: `${time_rfc3339_nano} ${remote_ip} ${method} ${uri} ${status} error="${error}"` + "\n",
 	}))
 
-	auth.AddAuthentication(Server.Echo(), parameters.GetStringToString(parameters.WebAPIAuth))
+	Server.Echo().Use(metricsMiddleware)
 
-	webapi.Init(Server, adminWhitelist())
+	authenticator := auth.AddAuthentication(Server.Echo(), parameters.GetStringToString(parameters.WebAPIAuth), registry.KeyStore())
+
+	webapi.Init(Server, adminWhitelist(), authenticator)
 }
 
 func customHTTPErrorHandler(err error, c echo.Context) {
@@ -76,6 +80,23 @@ func customHTTPErrorHandler(err error, c echo.Context) {
 	c.Echo().DefaultHTTPErrorHandler(err, c)
 }
 
+// metricsMiddleware records the latency of each request
+func metricsMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		err := next(c)
+		code := c.Response().Status
+		switch he := err.(type) {
+		case *httperrors.HTTPError:
+			code = he.Code
+		case *echo.HTTPError:
+			code = he.Code
+		}
+		metrics.WebAPIRequest(c.Request().Method, c.Path(), code, time.Since(start))
+		return err
+	}
+}
+
 func adminWhitelist() []net.IP {
 	r := make([]net.IP, 0)
 	for _, ip := range parameters.GetStringSlice(parameters.WebAPIAdminWhitelist) {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
+	"os"
 	"time"
 
 	"github.com/iotaledger/goshimmer/client/wallet/packages/seed"
@@ -13,6 +14,7 @@ import (
 	"github.com/iotaledger/wasp/client/chainclient"
 	"github.com/iotaledger/wasp/client/multiclient"
 	"github.com/iotaledger/wasp/client/scclient"
+	waspapi "github.com/iotaledger/wasp/packages/apilib"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv"
@@ -31,6 +33,7 @@ type Chain struct {
 	OriginatorSeed *seed.Seed
 
 	CommitteeNodes []int
+	AccessNodes    []int
 	Quorum         uint16
 	Address        address.Address
 
@@ -40,8 +43,9 @@ type Chain struct {
 	Cluster *Cluster
 }
 
+// ChainAddress is the address of the committee currently controlling the chain
 func (ch *Chain) ChainAddress() *address.Address {
-	r := address.Address(ch.ChainID)
+	r := ch.Address
 	return &r
 }
 
@@ -76,12 +80,55 @@ func (ch *Chain) OriginatorClient() *chainclient.Client {
 }
 
 func (ch *Chain) Client(sigScheme signaturescheme.SignatureScheme) *chainclient.Client {
-	return chainclient.New(
+	ret := chainclient.New(
 		ch.Cluster.Level1Client(),
 		ch.Cluster.WaspClient(ch.CommitteeNodes[0]),
 		ch.ChainID,
 		sigScheme,
 	)
+	if ch.Address != address.Address(ch.ChainID) {
+		ret.ChainAddress = ch.ChainAddress()
+	}
+	return ret
+}
+
+// AddAccessNodes makes the given nodes of the cluster follow the chain as access nodes
+func (ch *Chain) AddAccessNodes(nodes []int) error {
+	err := waspapi.AddAccessNodes(waspapi.AddAccessNodesParams{
+		ChainID:                ch.ChainID,
+		CommitteeApiHosts:      ch.ApiHosts(),
+		AccessNodeApiHosts:     ch.Cluster.Config.ApiHosts(nodes),
+		AccessNodePeeringHosts: ch.Cluster.Config.PeeringHosts(nodes),
+		Textout:                os.Stdout,
+		Prefix:                 "[cluster] ",
+	})
+	if err != nil {
+		return err
+	}
+	ch.AccessNodes = append(ch.AccessNodes, nodes...)
+	return nil
+}
+
+// RotateCommittee moves the chain to a new committee, formed by the given nodes of the cluster
+func (ch *Chain) RotateCommittee(committeeNodes []int, quorum uint16) error {
+	addr, err := waspapi.RotateChain(waspapi.RotateChainParams{
+		Node:                     ch.Cluster.Level1Client(),
+		ChainID:                  ch.ChainID,
+		CommitteeApiHosts:        ch.ApiHosts(),
+		NewCommitteeApiHosts:     ch.Cluster.Config.ApiHosts(committeeNodes),
+		NewCommitteePeeringHosts: ch.Cluster.Config.PeeringHosts(committeeNodes),
+		T:                        quorum,
+		OwnerSigScheme:           ch.OriginatorSigScheme(),
+		Textout:                  os.Stdout,
+		Prefix:                   "[cluster] ",
+	})
+	if err != nil {
+		return err
+	}
+	ch.Address = *addr
+	ch.CommitteeNodes = committeeNodes
+	ch.Quorum = quorum
+	return nil
 }
 
 func (ch *Chain) SCClient(contractHname coretypes.Hname, sigScheme signaturescheme.SignatureScheme) *scclient.SCClient {
@@ -95,11 +142,12 @@ func (ch *Chain) CommitteeMultiClient() *multiclient.MultiClient {
 func (ch *Chain) WithSCState(hname coretypes.Hname, f func(host string, blockIndex uint32, state dict.Dict) bool) bool {
 	pass := true
 	for i, host := range ch.ApiHosts() {
-		if !ch.Cluster.IsNodeUp(i) {
+		nodeIndex := ch.CommitteeNodes[i]
+		if !ch.Cluster.IsNodeUp(nodeIndex) {
 			continue
 		}
 		contractID := coretypes.NewContractID(ch.ChainID, hname)
-		actual, err := ch.Cluster.WaspClient(i).DumpSCState(&contractID)
+		actual, err := ch.Cluster.WaspClient(nodeIndex).DumpSCState(&contractID)
 		if model.IsHTTPNotFound(err) {
 			pass = false
 			fmt.Printf("   FAIL: state does not exist\n")
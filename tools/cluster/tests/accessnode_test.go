@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/contracts/native/inccounter"
+	"github.com/iotaledger/wasp/packages/apilib"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessNode(t *testing.T) {
+	setup(t, "test_cluster")
+
+	chain, err := clu.DeployChain("chain with access node", []int{0, 1, 2}, 2)
+	check(err, t)
+
+	name := "inncounter1"
+	hname := coretypes.Hn(name)
+	_, err = chain.DeployContract(name, inccounter.Interface.ProgramHash.String(), "inccounter", map[string]interface{}{
+		inccounter.VarCounter: 42,
+		root.ParamName:        name,
+	})
+	check(err, t)
+
+	accessNode := 3
+	err = chain.AddAccessNodes([]int{accessNode})
+	check(err, t)
+
+	rec, err := clu.WaspClient(0).GetChainRecord(chain.ChainID)
+	check(err, t)
+	require.EqualValues(t, clu.Config.PeeringHosts([]int{accessNode}), rec.AccessNodes)
+
+	// the request is posted through the access node
+	reqTx, err := apilib.CreateRequestTransaction(apilib.CreateRequestTransactionParams{
+		Level1Client:    clu.Level1Client(),
+		SenderSigScheme: chain.OriginatorSigScheme(),
+		RequestSectionParams: []apilib.RequestSectionParams{{
+			TargetContractID: coretypes.NewContractID(chain.ChainID, hname),
+			EntryPointCode:   coretypes.Hn(inccounter.FuncIncCounter),
+		}},
+	})
+	check(err, t)
+	reqIDs, err := clu.WaspClient(accessNode).PostRequest(&chain.ChainID, reqTx)
+	check(err, t)
+	require.EqualValues(t, 1, len(reqIDs))
+
+	err = chain.CommitteeMultiClient().WaitUntilAllRequestsProcessed(reqTx, 30*time.Second)
+	check(err, t)
+	// the access node syncs the state and serves it
+	err = clu.WaspClient(accessNode).WaitUntilAllRequestsProcessed(reqTx, 30*time.Second)
+	check(err, t)
+
+	res, err := clu.WaspClient(accessNode).CallView(coretypes.NewContractID(chain.ChainID, hname), inccounter.FuncGetCounter, nil)
+	check(err, t)
+	counter, _, err := codec.DecodeInt64(res.MustGet(inccounter.VarCounter))
+	check(err, t)
+	require.EqualValues(t, 43, counter)
+}
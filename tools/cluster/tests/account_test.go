@@ -79,7 +79,7 @@ func testBasicAccounts(t *testing.T, chain *cluster.Chain, counter *cluster.Mess
 		checkRoots(t, chain)
 
 		contractRegistry := collections.NewMapReadOnly(state, root.VarContractRegistry)
-		require.EqualValues(t, 5, contractRegistry.MustLen())
+		require.EqualValues(t, 8, contractRegistry.MustLen())
 
 		crBytes := contractRegistry.MustGetAt(hname.Bytes())
 		require.NotNil(t, crBytes)
@@ -191,7 +191,7 @@ func TestBasic2Accounts(t *testing.T) {
 		checkRoots(t, chain)
 
 		contractRegistry := collections.NewMapReadOnly(state, root.VarContractRegistry)
-		require.EqualValues(t, 5, contractRegistry.MustLen())
+		require.EqualValues(t, 8, contractRegistry.MustLen())
 
 		crBytes := contractRegistry.MustGetAt(hname.Bytes())
 		require.NotNil(t, crBytes)
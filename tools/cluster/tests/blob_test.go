@@ -39,7 +39,7 @@ func setupBlobTest(t *testing.T) *cluster.Chain {
 		require.EqualValues(t, 1, blockIndex)
 		checkRoots(t, chain)
 		contractRegistry := collections.NewMapReadOnly(state, root.VarContractRegistry)
-		require.EqualValues(t, 4, contractRegistry.MustLen())
+		require.EqualValues(t, 7, contractRegistry.MustLen())
 		return true
 	})
 
@@ -49,7 +49,7 @@ func TestIncDeployment(t *testing.T) {
 		require.EqualValues(t, chain.Description, desc)
 
 		contractRegistry := collections.NewMapReadOnly(state, root.VarContractRegistry)
-		require.EqualValues(t, 5, contractRegistry.MustLen())
+		require.EqualValues(t, 8, contractRegistry.MustLen())
 		//--
 		crBytes := contractRegistry.MustGetAt(root.Interface.Hname().Bytes())
 		require.NotNil(t, crBytes)
@@ -107,7 +107,7 @@ func testNothing(t *testing.T, numRequests int) {
 		require.EqualValues(t, chain.Description, desc)
 
 		contractRegistry := collections.NewMapReadOnly(state, root.VarContractRegistry)
-		require.EqualValues(t, 5, contractRegistry.MustLen())
+		require.EqualValues(t, 8, contractRegistry.MustLen())
 		//--
 		crBytes := contractRegistry.MustGetAt(root.Interface.Hname().Bytes())
 		require.NotNil(t, crBytes)
@@ -164,7 +164,7 @@ func testIncrement(t *testing.T, numRequests int) {
 		require.EqualValues(t, chain.Description, desc)
 
 		contractRegistry := collections.NewMapReadOnly(state, root.VarContractRegistry)
-		require.EqualValues(t, 5, contractRegistry.MustLen())
+		require.EqualValues(t, 8, contractRegistry.MustLen())
 		//--
 		crBytes := contractRegistry.MustGetAt(root.Interface.Hname().Bytes())
 		require.NotNil(t, crBytes)
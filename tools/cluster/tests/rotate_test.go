@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/contracts/native/inccounter"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateCommittee(t *testing.T) {
+	setup(t, "test_cluster")
+
+	chain, err := clu.DeployChain("chain to rotate", []int{0, 1, 2}, 2)
+	check(err, t)
+
+	name := "inncounter1"
+	hname := coretypes.Hn(name)
+	_, err = chain.DeployContract(name, inccounter.Interface.ProgramHash.String(), "inccounter", map[string]interface{}{
+		inccounter.VarCounter: 42,
+		root.ParamName:        name,
+	})
+	check(err, t)
+
+	oldAddress := chain.Address
+	err = chain.RotateCommittee([]int{1, 2, 3}, 2)
+	check(err, t)
+	require.NotEqual(t, oldAddress, chain.Address)
+	require.EqualValues(t, []int{1, 2, 3}, chain.CommitteeNodes)
+
+	if !clu.VerifyAddressBalances(&oldAddress, 0, map[balance.Color]int64{}, "old committee address") {
+		t.Fail()
+	}
+	if !clu.VerifyAddressBalances(&chain.Address, 4, map[balance.Color]int64{
+		balance.ColorIOTA: 3,
+		chain.Color:       1,
+	}, "new committee address") {
+		t.Fail()
+	}
+
+	// chain records of the nodes follow the new committee
+	for _, i := range []int{0, 1, 2, 3} {
+		rec, err := clu.WaspClient(i).GetChainRecord(chain.ChainID)
+		check(err, t)
+		require.EqualValues(t, chain.Address, rec.Address)
+		require.EqualValues(t, i != 0, rec.Active)
+	}
+
+	// the new committee processes requests
+	reqTx, err := chain.OriginatorClient().PostRequest(hname, coretypes.Hn(inccounter.FuncIncCounter))
+	check(err, t)
+	err = chain.CommitteeMultiClient().WaitUntilAllRequestsProcessed(reqTx, 60*time.Second)
+	check(err, t)
+
+	chain.WithSCState(root.Interface.Hname(), func(host string, blockIndex uint32, state dict.Dict) bool {
+		chainAddress, _, _ := codec.DecodeAddress(state.MustGet(root.VarChainAddress))
+		require.EqualValues(t, chain.Address, chainAddress)
+		return true
+	})
+	chain.WithSCState(hname, func(host string, blockIndex uint32, state dict.Dict) bool {
+		counterValue, _, _ := codec.DecodeInt64(state.MustGet(inccounter.VarCounter))
+		require.EqualValues(t, 43, counterValue)
+		return true
+	})
+}
+
+func TestRotateCommitteeNoOverlap(t *testing.T) {
+	setup(t, "test_cluster")
+
+	chain, err := clu.DeployChain("chain to rotate", []int{0, 1}, 2)
+	check(err, t)
+
+	oldAddress := chain.Address
+	err = chain.RotateCommittee([]int{2, 3}, 2)
+	require.Error(t, err)
+	require.EqualValues(t, oldAddress, chain.Address)
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -17,6 +18,7 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/tools/cluster"
 	"github.com/iotaledger/wasp/tools/cluster/testutil"
+	cliwallet "github.com/iotaledger/wasp/tools/wasp-cli/wallet"
 	"github.com/stretchr/testify/require"
 )
 
@@ -45,11 +47,12 @@ func NewWaspCliTest(t *testing.T) *WaspCliTest {
 	return w
 }
 
-func (w *WaspCliTest) runCmd(args []string, f func(*exec.Cmd)) []string {
+func (w *WaspCliTest) runCmd(tool string, args []string, f func(*exec.Cmd)) []string {
 	// -w: wait for requests
 	// -d: debug output
-	cmd := exec.Command("wasp-cli", append([]string{"-w", "-d"}, args...)...)
+	cmd := exec.Command(tool, append([]string{"-w", "-d"}, args...)...)
 	cmd.Dir = w.dir
+	cmd.Env = append(os.Environ(), cliwallet.PassphraseEnvVar+"=test")
 
 	stdout := &bytes.Buffer{}
 	cmd.Stdout = stdout
@@ -65,7 +68,8 @@ func (w *WaspCliTest) runCmd(args []string, f func(*exec.Cmd)) []string {
 	outStr, errStr := stdout.String(), stderr.String()
 	if err != nil {
 		require.NoError(w.t, fmt.Errorf(
-			"cmd `wasp-cli %s` failed\n%w\noutput:\n%s",
+			"cmd `%s %s` failed\n%w\noutput:\n%s",
+			tool,
 			strings.Join(args, " "),
 			err,
 			outStr+errStr,
@@ -77,11 +81,22 @@ func (w *WaspCliTest) runCmd(args []string, f func(*exec.Cmd)) []string {
 }
 
 func (w *WaspCliTest) Run(args ...string) []string {
-	return w.runCmd(args, nil)
+	return w.runCmd("wasp-cli", args, nil)
+}
+
+// RunAdmin runs a wasp-admin command, sharing the configuration with wasp-cli
+func (w *WaspCliTest) RunAdmin(args ...string) []string {
+	return w.runCmd("wasp-admin", args, nil)
+}
+
+// RunJSON runs a wasp-cli command with JSON output and decodes the result into v
+func (w *WaspCliTest) RunJSON(v interface{}, args ...string) {
+	out := w.Run(append([]string{"--output=json"}, args...)...)
+	require.NoError(w.t, json.Unmarshal([]byte(strings.Join(out, "\n")), v))
 }
 
 func (w *WaspCliTest) Pipe(in []string, args ...string) []string {
-	return w.runCmd(args, func(cmd *exec.Cmd) {
+	return w.runCmd("wasp-cli", args, func(cmd *exec.Cmd) {
 		cmd.Stdin = bytes.NewReader([]byte(strings.Join(in, "\n")))
 	})
 }
@@ -112,7 +127,7 @@ func TestWaspCliNoChains(t *testing.T) {
 	ownerAddr := regexp.MustCompile(`(?m)Address:[[:space:]]+([[:alnum:]]+)$`).FindStringSubmatch(out[1])[1]
 	require.NotEmpty(t, ownerAddr)
 
-	out = w.Run("chain", "list")
+	out = w.RunAdmin("chain", "list")
 	require.Contains(t, out[0], "Total 0 chain(s)")
 }
 
@@ -130,7 +145,7 @@ func TestWaspCli1Chain(t *testing.T) {
 	alias := "chain1"
 
 	// test chain deploy command
-	w.Run("chain", "deploy", "--chain="+alias, "--committee=0,1,2,3", "--quorum=3")
+	w.RunAdmin("chain", "deploy", "--chain="+alias, "--committee=0,1,2,3", "--quorum=3")
 
 	// test chain info command
 	out = w.Run("chain", "info")
@@ -138,8 +153,15 @@ func TestWaspCli1Chain(t *testing.T) {
 	require.NotEmpty(t, chainID)
 	t.Logf("Chain ID: %s", chainID)
 
+	// test the JSON output
+	var info struct {
+		ChainID string `json:"chainID"`
+	}
+	w.RunJSON(&info, "chain", "info")
+	require.Equal(t, chainID, info.ChainID)
+
 	// test chain list command
-	out = w.Run("chain", "list")
+	out = w.RunAdmin("chain", "list")
 	require.Contains(t, out[0], "Total 1 chain(s)")
 	require.Contains(t, out[4], chainID)
 
@@ -163,7 +185,7 @@ func TestWaspCli1Chain(t *testing.T) {
 	require.Regexp(t, "(?m)IOTA[[:space:]]+1$", out[3])
 
 	// same test, this time calling the view function manually
-	out = w.Run("chain", "call-view", "accounts", "balance", "string", "a", "agentid", agentID)
+	out = w.Run("chain", "call-view", "--raw", "accounts", "balance", "string", "a", "agentid", agentID)
 	out = w.Pipe(out, "decode", "color", "int")
 	require.Regexp(t, "(?m)IOTA:[[:space:]]+1$", out[0])
 
@@ -177,7 +199,7 @@ func TestWaspCliContract(t *testing.T) {
 	w := NewWaspCliTest(t)
 	w.Run("init")
 	w.Run("request-funds")
-	w.Run("chain", "deploy", "--chain=chain1", "--committee=0,1,2,3", "--quorum=3")
+	w.RunAdmin("chain", "deploy", "--chain=chain1", "--committee=0,1,2,3", "--quorum=3")
 
 	vmtype := "wasmtimevm"
 	name := "inccounter"
@@ -209,7 +231,7 @@ func TestWaspCliBlobContract(t *testing.T) {
 	w := NewWaspCliTest(t)
 	w.Run("init")
 	w.Run("request-funds")
-	w.Run("chain", "deploy", "--chain=chain1", "--committee=0,1,2,3", "--quorum=3")
+	w.RunAdmin("chain", "deploy", "--chain=chain1", "--committee=0,1,2,3", "--quorum=3")
 
 	// test chain list-blobs command
 	out := w.Run("chain", "list-blobs")
@@ -242,6 +264,36 @@ func TestWaspCliBlobContract(t *testing.T) {
 	require.Contains(t, out[0], description)
 }
 
+func TestWaspCliOfflineSigning(t *testing.T) {
+	w := NewWaspCliTest(t)
+	w.Run("init")
+	w.Run("request-funds")
+
+	var addr struct {
+		Address string `json:"address"`
+	}
+	w.RunJSON(&addr, "address")
+	w.Run("account", "import-address", "watch", addr.Address)
+
+	w.Run("account", "new", "target")
+	var target struct {
+		Address string `json:"address"`
+	}
+	w.RunJSON(&target, "--account=target", "address")
+
+	// the watch-only account builds the transaction, the default account signs it
+	file := "tx.json"
+	w.Run("--account=watch", "send-funds", target.Address, "IOTA", "10", "--unsigned="+file)
+	w.Run("sign", file)
+	w.Run("post-tx", file)
+
+	var balance struct {
+		Total int64 `json:"total"`
+	}
+	w.RunJSON(&balance, "--account=target", "balance")
+	require.EqualValues(t, 10, balance.Total)
+}
+
 func TestWaspCliBlobRegistry(t *testing.T) {
 	w := NewWaspCliTest(t)
 
@@ -261,4 +313,11 @@ func TestWaspCliBlobRegistry(t *testing.T) {
 	// test that `blob has` returns true
 	out = w.Run("blob", "has", blobHash)
 	require.Contains(t, out[0], "true")
+
+	// same test, with JSON output
+	var has struct {
+		Exists bool `json:"exists"`
+	}
+	w.RunJSON(&has, "blob", "has", blobHash)
+	require.True(t, has.Exists)
 }
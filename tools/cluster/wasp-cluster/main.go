@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/iotaledger/wasp/tools/cluster"
+	"github.com/iotaledger/wasp/tools/wasp-cli/wallet"
 	"github.com/spf13/pflag"
 )
 
@@ -132,10 +133,19 @@ type WaspCli struct {
 }
 
 func (w *WaspCli) Run(args ...string) {
+	w.run("wasp-cli", args)
+}
+
+func (w *WaspCli) RunAdmin(args ...string) {
+	w.run("wasp-admin", args)
+}
+
+func (w *WaspCli) run(tool string, args []string) {
 	// -w: wait for requests
 	// -d: debug output
-	cmd := exec.Command("wasp-cli", append([]string{"-w", "-d"}, args...)...)
+	cmd := exec.Command(tool, append([]string{"-w", "-d"}, args...)...)
 	cmd.Dir = w.dir
+	cmd.Env = append(os.Environ(), wallet.PassphraseEnvVar+"=wasp-cluster")
 
 	stdout := &bytes.Buffer{}
 	cmd.Stdout = stdout
@@ -147,7 +157,8 @@ func (w *WaspCli) Run(args ...string) {
 	outStr, errStr := stdout.String(), stderr.String()
 	if err != nil {
 		check(fmt.Errorf(
-			"cmd `wasp-cli %s` failed\n%w\noutput:\n%s",
+			"cmd `%s %s` failed\n%w\noutput:\n%s",
+			tool,
 			strings.Join(args, " "),
 			err,
 			outStr+errStr,
@@ -179,7 +190,7 @@ func inccounterScript(w *WaspCli) {
 
 	w.Run("init")
 	w.Run("request-funds")
-	w.Run("chain", "deploy", "--chain=chain1", "--committee=0,1,2,3", "--quorum=3")
+	w.RunAdmin("chain", "deploy", "--chain=chain1", "--committee=0,1,2,3", "--quorum=3")
 	w.Run("chain", "deploy-contract", vmtype, name, description, file)
 	w.Run("chain", "post-request", name, "increment")
 }
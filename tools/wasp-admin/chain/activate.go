@@ -0,0 +1,15 @@
+package chain
+
+import (
+	clichain "github.com/iotaledger/wasp/tools/wasp-cli/chain"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
+)
+
+func activateCmd(cmd *cobra.Command, args []string) {
+	log.Check(clichain.MultiClient().ActivateChain(clichain.GetCurrentChainID()))
+}
+
+func deactivateCmd(cmd *cobra.Command, args []string) {
+	log.Check(clichain.MultiClient().DeactivateChain(clichain.GetCurrentChainID()))
+}
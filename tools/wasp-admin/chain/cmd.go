@@ -0,0 +1,52 @@
+package chain
+
+import (
+	clichain "github.com/iotaledger/wasp/tools/wasp-cli/chain"
+	"github.com/spf13/cobra"
+)
+
+func InitCommands(root *cobra.Command) {
+	chainCmd := &cobra.Command{
+		Use:   "chain",
+		Short: "Manage the chains in the wasp nodes",
+	}
+	clichain.InitAliasFlags(chainCmd)
+	root.AddCommand(chainCmd)
+
+	chainCmd.AddCommand(
+		deployCommand(),
+		&cobra.Command{
+			Use:   "activate",
+			Short: "Activate the chain in its committee nodes",
+			Args:  cobra.NoArgs,
+			Run:   activateCmd,
+		},
+		&cobra.Command{
+			Use:   "deactivate",
+			Short: "Deactivate the chain in its committee nodes",
+			Args:  cobra.NoArgs,
+			Run:   deactivateCmd,
+		},
+		&cobra.Command{
+			Use:   "list",
+			Short: "List the chain records of the wasp node",
+			Args:  cobra.NoArgs,
+			Run:   listCmd,
+		},
+		&cobra.Command{
+			Use:   "record",
+			Short: "Show the chain record of the chain",
+			Args:  cobra.NoArgs,
+			Run:   recordCmd,
+		},
+		&cobra.Command{
+			Use:   "dump-state <name>",
+			Short: "Dump the state of a contract",
+			Args:  cobra.ExactArgs(1),
+			Run:   dumpStateCmd,
+		},
+		snapshotCommand(),
+		pruningCommand(),
+		pruneCommand(),
+	)
+}
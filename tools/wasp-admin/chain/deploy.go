@@ -0,0 +1,52 @@
+package chain
+
+import (
+	"github.com/iotaledger/wasp/packages/apilib"
+	clichain "github.com/iotaledger/wasp/tools/wasp-cli/chain"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/wallet"
+	"github.com/spf13/cobra"
+)
+
+var committee []int
+var quorum int
+var description string
+
+func deployCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploy a new chain and set it as the current chain",
+		Args:  cobra.NoArgs,
+		Run:   deployCmd,
+	}
+	cmd.Flags().IntSliceVarP(&committee, "committee", "", []int{0, 1, 2, 3}, "committee indices")
+	cmd.Flags().IntVarP(&quorum, "quorum", "", 3, "quorum")
+	cmd.Flags().StringVarP(&description, "description", "", "", "description")
+	return cmd
+}
+
+type deployResult struct {
+	Alias   string `json:"alias"`
+	ChainID string `json:"chainID"`
+}
+
+func deployCmd(cmd *cobra.Command, args []string) {
+	alias := clichain.GetChainAlias()
+
+	chainid, _, _, err := apilib.DeployChain(apilib.CreateChainParams{
+		Node:                  config.GoshimmerClient(),
+		CommitteeApiHosts:     config.CommitteeApi(committee),
+		CommitteePeeringHosts: config.CommitteePeering(committee),
+		N:                     uint16(len(committee)),
+		T:                     uint16(quorum),
+		OriginatorSigScheme:   wallet.Load().SignatureScheme(),
+		Description:           description,
+		Textout:               log.Out(),
+		Prefix:                "",
+	})
+	log.Check(err)
+
+	clichain.AddChainAlias(alias, chainid.String())
+	log.PrintResult(deployResult{Alias: alias, ChainID: chainid.String()}, func() {})
+}
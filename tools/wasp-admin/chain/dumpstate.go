@@ -0,0 +1,23 @@
+package chain
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes"
+	clichain "github.com/iotaledger/wasp/tools/wasp-cli/chain"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
+)
+
+func dumpStateCmd(cmd *cobra.Command, args []string) {
+	contractID := coretypes.NewContractID(clichain.GetCurrentChainID(), coretypes.Hn(args[0]))
+	dump, err := config.WaspClient().DumpSCState(&contractID)
+	log.Check(err)
+
+	if log.JSONOutput() {
+		log.PrintJSON(dump)
+		return
+	}
+	log.Printf("State of %s at block #%d:\n", args[0], dump.Index)
+	util.PrintDictAsJson(dump.Variables)
+}
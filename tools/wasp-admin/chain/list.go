@@ -4,11 +4,14 @@ import (
 	"fmt"
 
 	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	clichain "github.com/iotaledger/wasp/tools/wasp-cli/chain"
 	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
 )
 
-func listCmd(args []string) {
+func listCmd(cmd *cobra.Command, args []string) {
 	client := config.WaspClient()
 	chains, err := client.GetChainRecordList()
 	log.Check(err)
@@ -29,3 +32,17 @@ func showChainList(chains []*registry.ChainRecord) {
 	}
 	log.PrintTable(header, rows)
 }
+
+func recordCmd(cmd *cobra.Command, args []string) {
+	chain, err := config.WaspClient().GetChainRecord(clichain.GetCurrentChainID())
+	log.Check(err)
+
+	log.PrintResult(model.NewChainRecord(chain), func() {
+		log.Printf("Chain ID: %s\n", chain.ChainID)
+		log.Printf("Color: %s\n", chain.Color)
+		log.Printf("Address: %s\n", chain.Address)
+		log.Printf("Committee nodes: %+v\n", chain.CommitteeNodes)
+		log.Printf("Access nodes: %+v\n", chain.AccessNodes)
+		log.Printf("Active: %v\n", chain.Active)
+	})
+}
@@ -0,0 +1,79 @@
+package chain
+
+import (
+	"time"
+
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	clichain "github.com/iotaledger/wasp/tools/wasp-cli/chain"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
+)
+
+func pruningCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pruning",
+		Short: "Show the pruning policy of the chain in the wasp node",
+		Args:  cobra.NoArgs,
+		Run:   showPruningPolicyCmd,
+	}
+
+	var keepBlocks uint32
+	var keepDuration time.Duration
+	setCmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set the pruning policy of the chain in the wasp node",
+		Long: "Set the pruning policy of the chain in the wasp node. A block is retained if it is one of the " +
+			"last --keep-blocks blocks or if it is newer than --keep-duration. Blocks younger than 24 hours " +
+			"are never pruned. With both values zero, nothing is pruned.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			chainID := clichain.GetCurrentChainID()
+			log.Check(config.WaspClient().SetPruningPolicy(chainID, &model.PruningPolicy{
+				KeepBlocks:   keepBlocks,
+				KeepDuration: keepDuration,
+			}))
+			showPruningPolicyCmd(cmd, args)
+		},
+	}
+	setCmd.Flags().Uint32VarP(&keepBlocks, "keep-blocks", "", 0, "number of the latest blocks to retain")
+	setCmd.Flags().DurationVarP(&keepDuration, "keep-duration", "", 0, "retain the blocks newer than this (e.g. 720h)")
+	cmd.AddCommand(setCmd)
+	return cmd
+}
+
+func pruneCommand() *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Prune the old blocks of the chain in the wasp node according to its pruning policy",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			res, err := config.WaspClient().PruneChain(clichain.GetCurrentChainID(), dryRun)
+			log.Check(err)
+			log.PrintResult(res, func() {
+				if res.DryRun {
+					log.Printf("Dry run, nothing was removed\n")
+				}
+				log.Printf("Pruned blocks:           %d\n", res.NumBlocks)
+				log.Printf("Pruned request markers:  %d\n", res.NumRequestMarkers)
+				log.Printf("Reclaimed bytes:         %d\n", res.ReclaimedBytes)
+				log.Printf("First retained block:    #%d\n", res.FirstBlockIndex)
+			})
+		},
+	}
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "", false, "only report what would be removed")
+	return cmd
+}
+
+func showPruningPolicyCmd(cmd *cobra.Command, args []string) {
+	policy, err := config.WaspClient().GetPruningPolicy(clichain.GetCurrentChainID())
+	log.Check(err)
+	log.PrintResult(policy, func() {
+		log.Printf("%s", policy.Policy().String())
+		if policy.IsDefault {
+			log.Printf(" (default policy of the node)")
+		}
+		log.Printf("\n")
+	})
+}
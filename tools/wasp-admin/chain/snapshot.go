@@ -0,0 +1,70 @@
+package chain
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"github.com/iotaledger/wasp/packages/snapshot"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	clichain "github.com/iotaledger/wasp/tools/wasp-cli/chain"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
+)
+
+func snapshotCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Export and import snapshots of the state of the chain",
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "export <file>",
+			Short: "Export the snapshot of the solid state of the chain from the wasp node to the file",
+			Args:  cobra.ExactArgs(1),
+			Run:   exportSnapshotCmd,
+		},
+		&cobra.Command{
+			Use:   "import <file>",
+			Short: "Import the snapshot of a chain from the file to the wasp node",
+			Args:  cobra.ExactArgs(1),
+			Run:   importSnapshotCmd,
+		},
+	)
+	return cmd
+}
+
+func exportSnapshotCmd(cmd *cobra.Command, args []string) {
+	data, err := config.WaspClient().ExportSnapshot(clichain.GetCurrentChainID())
+	log.Check(err)
+	s := readSnapshot(data)
+	log.Check(ioutil.WriteFile(args[0], data, 0644))
+	printSnapshotInfo(model.NewSnapshotInfo(s))
+}
+
+func importSnapshotCmd(cmd *cobra.Command, args []string) {
+	data, err := ioutil.ReadFile(args[0])
+	log.Check(err)
+	readSnapshot(data)
+	info, err := config.WaspClient().ImportSnapshot(data)
+	log.Check(err)
+	printSnapshotInfo(info)
+}
+
+// readSnapshot reads and verifies the snapshot
+func readSnapshot(data []byte) *snapshot.Snapshot {
+	s, err := snapshot.Read(bytes.NewReader(data))
+	log.Check(err)
+	log.Check(s.Verify())
+	return s
+}
+
+func printSnapshotInfo(info *model.SnapshotInfo) {
+	log.PrintResult(info, func() {
+		log.Printf("Chain ID:     %s\n", info.ChainID)
+		log.Printf("Block index:  %d\n", info.BlockIndex)
+		log.Printf("State hash:   %s\n", info.StateHash)
+		log.Printf("Anchor tx:    %s\n", info.StateTxID)
+		log.Printf("Blobs:        %d\n", info.NumBlobs)
+	})
+}
@@ -0,0 +1,79 @@
+package dks
+
+import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/wasp/client"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
+)
+
+var committee []int
+var quorum int
+var timeoutMS uint16
+
+func InitCommands(root *cobra.Command) {
+	dksCmd := &cobra.Command{
+		Use:   "dks",
+		Short: "Manage the distributed key sets of the wasp nodes",
+	}
+	root.AddCommand(dksCmd)
+
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Run the DKG among the committee nodes and show the generated key set",
+		Args:  cobra.NoArgs,
+		Run:   generateCmd,
+	}
+	generateCmd.Flags().IntSliceVarP(&committee, "committee", "", []int{0, 1, 2, 3}, "committee indices")
+	generateCmd.Flags().IntVarP(&quorum, "quorum", "", 3, "quorum")
+	generateCmd.Flags().Uint16VarP(&timeoutMS, "timeout", "", 60000, "DKG timeout in milliseconds")
+
+	dksCmd.AddCommand(
+		generateCmd,
+		&cobra.Command{
+			Use:   "show <address>",
+			Short: "Show the key set of the shared address",
+			Args:  cobra.ExactArgs(1),
+			Run:   showCmd,
+		},
+	)
+}
+
+func generateCmd(cmd *cobra.Command, args []string) {
+	// the DKG is initiated by the first node of the committee
+	initiator := client.NewWaspClient(config.CommitteeApi(committee)[0]).
+		WithToken(config.WaspToken()).
+		WithAPIKey(config.WaspAPIKey())
+	dks, err := initiator.DKSharesPost(&model.DKSharesPostRequest{
+		PeerNetIDs: config.CommitteePeering(committee),
+		Threshold:  uint16(quorum),
+		TimeoutMS:  timeoutMS,
+	})
+	log.Check(err)
+	printDKShares(dks)
+}
+
+func showCmd(cmd *cobra.Command, args []string) {
+	addr, err := address.FromBase58(args[0])
+	log.Check(err)
+	dks, err := config.WaspClient().DKSharesGet(&addr)
+	log.Check(err)
+	printDKShares(dks)
+}
+
+func printDKShares(dks *model.DKSharesInfo) {
+	log.PrintResult(dks, func() {
+		log.Printf("Address:         %s\n", dks.Address)
+		log.Printf("Shared pub key:  %s\n", dks.SharedPubKey)
+		log.Printf("Threshold:       %d/%d\n", dks.Threshold, len(dks.PubKeyShares))
+		if dks.PeerIndex != nil {
+			log.Printf("Peer index:      %d\n", *dks.PeerIndex)
+		}
+		log.Verbose("Pub key shares:\n")
+		for i, share := range dks.PubKeyShares {
+			log.Verbose("  %d: %s\n", i, share)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/iotaledger/wasp/tools/wasp-admin/chain"
+	"github.com/iotaledger/wasp/tools/wasp-admin/dks"
+	"github.com/iotaledger/wasp/tools/wasp-admin/node"
+	"github.com/iotaledger/wasp/tools/wasp-cli/cli"
+	"github.com/iotaledger/wasp/tools/wasp-cli/login"
+	"github.com/iotaledger/wasp/tools/wasp-cli/wallet"
+)
+
+func main() {
+	root := cli.NewRootCmd("wasp-admin", "Administration tool for the wasp nodes")
+
+	wallet.InitFlags(root)
+	chain.InitCommands(root)
+	dks.InitCommands(root)
+	node.InitCommands(root)
+	login.InitCommands(root)
+
+	cli.Execute(root)
+}
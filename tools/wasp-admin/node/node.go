@@ -0,0 +1,92 @@
+package node
+
+import (
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/wallet"
+	"github.com/spf13/cobra"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func InitCommands(root *cobra.Command) {
+	nodeCmd := &cobra.Command{
+		Use:   "node",
+		Short: "Manage the wasp node",
+	}
+	root.AddCommand(nodeCmd)
+
+	nodeCmd.AddCommand(
+		&cobra.Command{
+			Use:   "identity",
+			Short: "Show the identity and the certificate of the node",
+			Args:  cobra.NoArgs,
+			Run:   identityCmd,
+		},
+		&cobra.Command{
+			Use:   "certify",
+			Short: "Certify the node with the wallet key pair of its owner",
+			Args:  cobra.NoArgs,
+			Run:   certifyCmd,
+		},
+		&cobra.Command{
+			Use:   "trusted-peers",
+			Short: "List the peers which presented a valid certificate",
+			Args:  cobra.NoArgs,
+			Run:   trustedPeersCmd,
+		},
+		&cobra.Command{
+			Use:   "shutdown",
+			Short: "Shut down the node",
+			Args:  cobra.NoArgs,
+			Run:   shutdownCmd,
+		},
+	)
+}
+
+func identityCmd(cmd *cobra.Command, args []string) {
+	id, err := config.WaspClient().GetNodeIdentity()
+	log.Check(err)
+	if log.JSONOutput() {
+		log.PrintJSON(id)
+		return
+	}
+	log.Printf("NetID:    %s\n", id.NetID)
+	log.Printf("Identity: %s\n", id.Identity)
+	if id.Certificate == nil {
+		log.Printf("Certificate: none\n")
+		return
+	}
+	log.Printf("Certificate: owner %s, issued %s\n", id.Certificate.Owner, id.Certificate.Issued)
+}
+
+// certifyCmd issues the certificate of the node with the wallet key pair of the node owner
+func certifyCmd(cmd *cobra.Command, args []string) {
+	client := config.WaspClient()
+	id, err := client.GetNodeIdentity()
+	log.Check(err)
+	nodePubKey, err := peering.PubKeyFromIdentity(id.Identity, pairing.NewSuiteBn256())
+	log.Check(err)
+	cert, err := peering.NewNodeCertificate(nodePubKey, *wallet.Load().KeyPair())
+	log.Check(err)
+	log.Check(client.PutNodeCertificate(cert))
+	log.PrintResult(model.NewNodeCertificate(cert), func() {
+		log.Printf("Node %s certified, owner %s\n", id.NetID, cert.OwnerPubKey)
+	})
+}
+
+func trustedPeersCmd(cmd *cobra.Command, args []string) {
+	peers, err := config.WaspClient().GetTrustedPeers()
+	log.Check(err)
+	rows := make([][]string, len(peers))
+	for i, tp := range peers {
+		rows[i] = []string{tp.NetID, tp.Certificate.Identity, tp.Certificate.Owner}
+	}
+	log.PrintTable([]string{"netID", "identity", "owner"}, rows)
+}
+
+func shutdownCmd(cmd *cobra.Command, args []string) {
+	log.Check(config.WaspClient().Shutdown())
+	log.Printf("Node %s is shutting down\n", config.WaspApi())
+}
@@ -3,59 +3,53 @@ package blob
 import (
 	"io/ioutil"
 	"os"
-	"strings"
 
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
-	"github.com/spf13/pflag"
+	"github.com/spf13/cobra"
 )
 
-func InitCommands(commands map[string]func([]string), flags *pflag.FlagSet) {
-	commands["blob"] = blobCmd
-}
-
-var subcmds = map[string]func([]string){
-	"put": putBlobCmd,
-	"get": getBlobCmd,
-	"has": hasBlobCmd,
-}
-
-func blobCmd(args []string) {
-	if len(args) < 1 {
-		usage()
-	}
-	subcmd, ok := subcmds[args[0]]
-	if !ok {
-		usage()
+func InitCommands(root *cobra.Command) {
+	blobCmd := &cobra.Command{
+		Use:   "blob",
+		Short: "Manage the blobs in the node registry",
 	}
-	subcmd(args[1:])
-}
+	root.AddCommand(blobCmd)
 
-func usage() {
-	cmdNames := make([]string, 0)
-	for k := range subcmds {
-		cmdNames = append(cmdNames, k)
-	}
-
-	log.Usage("%s blob [%s]\n", os.Args[0], strings.Join(cmdNames, "|"))
+	blobCmd.AddCommand(
+		&cobra.Command{
+			Use:   "put <filename>",
+			Short: "Upload a file as a blob",
+			Args:  cobra.ExactArgs(1),
+			Run:   putBlobCmd,
+		},
+		&cobra.Command{
+			Use:   "get <hash>",
+			Short: "Download a blob to stdout",
+			Args:  cobra.ExactArgs(1),
+			Run:   getBlobCmd,
+		},
+		&cobra.Command{
+			Use:   "has <hash>",
+			Short: "Check whether the node has a blob",
+			Args:  cobra.ExactArgs(1),
+			Run:   hasBlobCmd,
+		},
+	)
 }
 
-func putBlobCmd(args []string) {
-	if len(args) != 1 {
-		log.Usage("%s blob put <filename>\n", os.Args[0])
-	}
+func putBlobCmd(cmd *cobra.Command, args []string) {
 	data, err := ioutil.ReadFile(args[0])
 	log.Check(err)
 	hash, err := config.WaspClient().PutBlob(data)
 	log.Check(err)
-	log.Printf("Blob uploaded. Hash: %s\n", hash)
+	log.PrintResult(map[string]string{"hash": hash.String()}, func() {
+		log.Printf("Blob uploaded. Hash: %s\n", hash)
+	})
 }
 
-func getBlobCmd(args []string) {
-	if len(args) != 1 {
-		log.Usage("%s blob get <hash>\n", os.Args[0])
-	}
+func getBlobCmd(cmd *cobra.Command, args []string) {
 	hash, err := hashing.HashValueFromBase58(args[0])
 	log.Check(err)
 	data, err := config.WaspClient().GetBlob(hash)
@@ -64,13 +58,12 @@ func getBlobCmd(args []string) {
 	log.Check(err)
 }
 
-func hasBlobCmd(args []string) {
-	if len(args) != 1 {
-		log.Usage("%s blob has <hash>\n", os.Args[0])
-	}
+func hasBlobCmd(cmd *cobra.Command, args []string) {
 	hash, err := hashing.HashValueFromBase58(args[0])
 	log.Check(err)
 	ok, err := config.WaspClient().HasBlob(hash)
 	log.Check(err)
-	log.Printf("%v", ok)
+	log.PrintResult(map[string]bool{"exists": ok}, func() {
+		log.Printf("%v\n", ok)
+	})
 }
@@ -2,7 +2,6 @@ package chain
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
@@ -12,9 +11,10 @@ import (
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
 )
 
-func listAccountsCmd(args []string) {
+func listAccountsCmd(cmd *cobra.Command, args []string) {
 	ret, err := SCClient(accounts.Interface.Hname()).CallView(accounts.FuncAccounts, nil)
 	log.Check(err)
 
@@ -32,11 +32,7 @@ func listAccountsCmd(args []string) {
 	log.PrintTable(header, rows)
 }
 
-func balanceCmd(args []string) {
-	if len(args) != 1 {
-		log.Usage("%s chain balance <agentid>\n", os.Args[0])
-	}
-
+func balanceCmd(cmd *cobra.Command, args []string) {
 	agentID, err := coretypes.NewAgentIDFromString(args[0])
 	log.Check(err)
 
@@ -4,7 +4,7 @@ import (
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
-	"github.com/spf13/pflag"
+	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
@@ -15,7 +15,7 @@ func GetChainAlias() string {
 		chainAlias = viper.GetString("chain")
 	}
 	if chainAlias == "" {
-		log.Fatal("No current chain. Call `chain deploy --chain=<alias>` or `set chain <alias>`")
+		log.Fatal("No current chain. Call `wasp-admin chain deploy --chain=<alias>` or `set chain <alias>`")
 	}
 	return chainAlias
 }
@@ -24,8 +24,9 @@ func SetCurrentChain(chainAlias string) {
 	config.Set("chain", chainAlias)
 }
 
-func initAliasFlags(flags *pflag.FlagSet) {
-	flags.StringVarP(&chainAlias, "chain", "a", "", "chain alias")
+// InitAliasFlags adds the --chain flag to the command and its subcommands
+func InitAliasFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&chainAlias, "chain", "a", "", "chain alias")
 }
 
 func AddChainAlias(chainAlias string, id string) {
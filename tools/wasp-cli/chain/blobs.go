@@ -2,7 +2,6 @@ package chain
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv/codec"
@@ -12,30 +11,39 @@ import (
 	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
 )
 
-func storeBlobCmd(args []string) {
-	if len(args) == 0 {
-		log.Fatal("Usage: %s chain store-blob [type field type value ...]", os.Args[0])
+func storeBlobCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "store-blob <type> <field> <type> <value> [...]",
+		Short: "Store a blob in the chain",
+		Args:  cobra.MinimumNArgs(4),
+		Run: func(cmd *cobra.Command, args []string) {
+			hash := uploadBlob(util.EncodeParams(args), false)
+			log.PrintResult(blobResult{Hash: hash.String()}, func() {})
+		},
 	}
-	uploadBlob(util.EncodeParams(args), false)
+	initUploadFlags(cmd)
+	return cmd
+}
+
+type blobResult struct {
+	Hash string `json:"hash"`
 }
 
 func uploadBlob(fieldValues dict.Dict, forceWait bool) (hash hashing.HashValue) {
 	util.WithSCTransaction(func() (tx *sctransaction.Transaction, err error) {
-		hash, tx, err = Client().UploadBlob(fieldValues, config.CommitteeApi(chainCommittee()), uploadQuorum)
+		hash, tx, err = Client().UploadBlob(fieldValues, config.CommitteeApi(Committee()), uploadQuorum)
 		if err == nil {
-			log.Printf("uploaded blob to chain -- hash: %s", hash)
+			log.Printf("uploaded blob to chain -- hash: %s\n", hash)
 		}
 		return
 	}, forceWait)
 	return
 }
 
-func showBlobCmd(args []string) {
-	if len(args) != 1 {
-		log.Fatal("Usage: %s chain show-blob <hash>", os.Args[0])
-	}
+func showBlobCmd(cmd *cobra.Command, args []string) {
 	hash := util.ValueFromString("base58", args[0])
 	fields, err := SCClient(blob.Interface.Hname()).CallView(blob.FuncGetBlobInfo, codec.MakeDict(map[string]interface{}{
 		blob.ParamHash: hash,
@@ -54,7 +62,7 @@ func showBlobCmd(args []string) {
 	util.PrintDictAsJson(values)
 }
 
-func listBlobsCmd(args []string) {
+func listBlobsCmd(cmd *cobra.Command, args []string) {
 	ret, err := SCClient(blob.Interface.Hname()).CallView(blob.FuncListBlobs, nil)
 	log.Check(err)
 
@@ -0,0 +1,65 @@
+package chain
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
+)
+
+func listBlocksCmd(cmd *cobra.Command, args []string) {
+	var from *uint32
+	if len(args) == 1 {
+		n := parseBlockIndex(args[0])
+		from = &n
+	}
+	res, err := Client().ListBlocks(from, 0)
+	log.Check(err)
+	if log.JSONOutput() {
+		log.PrintJSON(res)
+		return
+	}
+
+	log.Printf("Latest block: #%d\n", res.LatestBlockIndex)
+	header := []string{"index", "timestamp", "anchor tx", "#requests"}
+	rows := make([][]string, len(res.Blocks))
+	for i, b := range res.Blocks {
+		rows[i] = []string{
+			fmt.Sprintf("%d", b.Index),
+			b.Timestamp.Format(time.RFC3339),
+			string(b.StateTxID),
+			fmt.Sprintf("%d", len(b.RequestIDs)),
+		}
+	}
+	log.PrintTable(header, rows)
+}
+
+func blockCmd(cmd *cobra.Command, args []string) {
+	b, err := Client().GetBlock(parseBlockIndex(args[0]))
+	log.Check(err)
+	if log.JSONOutput() {
+		log.PrintJSON(b)
+		return
+	}
+
+	log.Printf("Block index: %d\n", b.Index)
+	log.Printf("Timestamp: %s\n", b.Timestamp.Format(time.RFC3339))
+	log.Printf("Anchor transaction: %s\n", b.StateTxID)
+	for _, su := range b.StateUpdates {
+		log.Printf("\nRequest %s (%s)\n", su.RequestID, su.Timestamp.Format(time.RFC3339))
+		header := []string{"kind", "key", "value"}
+		rows := make([][]string, len(su.Mutations))
+		for i, mut := range su.Mutations {
+			rows[i] = []string{mut.Kind, fmt.Sprintf("%q", mut.Key.Bytes()), fmt.Sprintf("%x", mut.Value.Bytes())}
+		}
+		log.PrintTable(header, rows)
+	}
+}
+
+func parseBlockIndex(s string) uint32 {
+	n, err := strconv.ParseUint(s, 10, 32)
+	log.Check(err)
+	return uint32(n)
+}
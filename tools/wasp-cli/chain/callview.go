@@ -1,18 +1,59 @@
 package chain
 
 import (
-	"os"
-
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
 )
 
-func callViewCmd(args []string) {
-	if len(args) < 2 {
-		log.Fatal("Usage: %s chain call-view <name> <funcname> [params]", os.Args[0])
+// rawResults disables decoding of the results with the schema of the contract
+var rawResults bool
+
+// atBlock is the index of the block to call the view on. Negative means the latest block
+var atBlock int
+
+func callViewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "call-view <name> <funcname> [params]",
+		Short: "Call a view function of a contract",
+		Long: `Call a view function of a contract.
+
+The params are either <name>=<value> ..., encoded with the types declared
+in the schema of the contract, or <type> <key> <type> <value> ...
+If the contract has a schema, the results are decoded with it unless --raw is given.`,
+		Args: cobra.MinimumNArgs(2),
+		Run:  callViewCmd,
+	}
+	cmd.Flags().IntVarP(&atBlock, "block", "", -1, "call the view on the state at the block index (default: latest)")
+	cmd.Flags().BoolVarP(&rawResults, "raw", "", false, "print the results without decoding them with the schema")
+	return cmd
+}
+
+func callViewCmd(cmd *cobra.Command, args []string) {
+	client := SCClient(coretypes.Hn(args[0]))
+	var s *schema.Schema
+	if !rawResults || isNamedArgs(args[2:]) {
+		s = getSchema(coretypes.Hn(args[0]))
+	}
+	params := encodeArgs(s, args[0], args[1], args[2:])
+	var r dict.Dict
+	var err error
+	if atBlock >= 0 {
+		r, err = client.CallViewAtBlock(args[1], params, uint32(atBlock))
+	} else {
+		r, err = client.CallView(args[1], params)
 	}
-	r, err := SCClient(coretypes.Hn(args[0])).CallView(args[1], util.EncodeParams(args[2:]))
 	log.Check(err)
+	if s != nil && !rawResults {
+		if f, ok := s.Func(args[1]); ok {
+			results, err := f.DecodeResults(r)
+			log.Check(err)
+			log.PrintJSON(results)
+			return
+		}
+	}
 	util.PrintDictAsJson(r)
 }
@@ -1,57 +1,95 @@
 package chain
 
 import (
-	"os"
-	"strings"
-
-	"github.com/iotaledger/wasp/tools/wasp-cli/log"
-	"github.com/spf13/pflag"
+	"github.com/spf13/cobra"
 )
 
-func InitCommands(commands map[string]func([]string), flags *pflag.FlagSet) {
-	commands["chain"] = chainCmd
-
-	fs := pflag.NewFlagSet("chain", pflag.ExitOnError)
-	initDeployFlags(fs)
-	initUploadFlags(fs)
-	initAliasFlags(fs)
-	flags.AddFlagSet(fs)
-}
-
-var subcmds = map[string]func([]string){
-	"list":            listCmd,
-	"deploy":          deployCmd,
-	"info":            infoCmd,
-	"list-contracts":  listContractsCmd,
-	"deploy-contract": deployContractCmd,
-	"list-accounts":   listAccountsCmd,
-	"balance":         balanceCmd,
-	"list-blobs":      listBlobsCmd,
-	"store-blob":      storeBlobCmd,
-	"show-blob":       showBlobCmd,
-	"log":             logCmd,
-	"post-request":    postRequestCmd,
-	"call-view":       callViewCmd,
-	"activate":        activateCmd,
-	"deactivate":      deactivateCmd,
-}
-
-func chainCmd(args []string) {
-	if len(args) < 1 {
-		usage()
-	}
-	subcmd, ok := subcmds[args[0]]
-	if !ok {
-		usage()
-	}
-	subcmd(args[1:])
-}
-
-func usage() {
-	cmdNames := make([]string, 0)
-	for k := range subcmds {
-		cmdNames = append(cmdNames, k)
+func InitCommands(root *cobra.Command) {
+	chainCmd := &cobra.Command{
+		Use:   "chain",
+		Short: "Interact with a chain",
 	}
+	InitAliasFlags(chainCmd)
+	root.AddCommand(chainCmd)
 
-	log.Usage("%s chain [%s]\n", os.Args[0], strings.Join(cmdNames, "|"))
+	chainCmd.AddCommand(
+		&cobra.Command{
+			Use:   "info",
+			Short: "Show information about the chain",
+			Args:  cobra.NoArgs,
+			Run:   infoCmd,
+		},
+		&cobra.Command{
+			Use:   "list-contracts",
+			Short: "List the contracts deployed in the chain",
+			Args:  cobra.NoArgs,
+			Run:   listContractsCmd,
+		},
+		deployContractCommand(),
+		upgradeContractCommand(),
+		&cobra.Command{
+			Use:   "deactivate-contract <name>",
+			Short: "Refuse all calls to a contract until it is activated again",
+			Args:  cobra.ExactArgs(1),
+			Run:   deactivateContractCmd,
+		},
+		&cobra.Command{
+			Use:   "activate-contract <name>",
+			Short: "Make a deactivated contract callable again",
+			Args:  cobra.ExactArgs(1),
+			Run:   activateContractCmd,
+		},
+		removeContractCommand(),
+		&cobra.Command{
+			Use:   "list-accounts",
+			Short: "List the accounts in the chain",
+			Args:  cobra.NoArgs,
+			Run:   listAccountsCmd,
+		},
+		&cobra.Command{
+			Use:   "balance <agentid>",
+			Short: "Show the balance of an account in the chain",
+			Args:  cobra.ExactArgs(1),
+			Run:   balanceCmd,
+		},
+		&cobra.Command{
+			Use:   "list-blobs",
+			Short: "List the blobs stored in the chain",
+			Args:  cobra.NoArgs,
+			Run:   listBlobsCmd,
+		},
+		storeBlobCommand(),
+		&cobra.Command{
+			Use:   "show-blob <hash>",
+			Short: "Show the fields of a blob stored in the chain",
+			Args:  cobra.ExactArgs(1),
+			Run:   showBlobCmd,
+		},
+		&cobra.Command{
+			Use:   "log <name>",
+			Short: "Show the event log of a contract",
+			Args:  cobra.ExactArgs(1),
+			Run:   logCmd,
+		},
+		&cobra.Command{
+			Use:   "schema <name>",
+			Short: "Show the schema of a contract: its functions with parameters and results",
+			Args:  cobra.ExactArgs(1),
+			Run:   schemaCmd,
+		},
+		postRequestCommand(),
+		callViewCommand(),
+		&cobra.Command{
+			Use:   "list-blocks [<from index>]",
+			Short: "List the blocks of the chain",
+			Args:  cobra.MaximumNArgs(1),
+			Run:   listBlocksCmd,
+		},
+		&cobra.Command{
+			Use:   "block <index>",
+			Short: "Show a block of the chain",
+			Args:  cobra.ExactArgs(1),
+			Run:   blockCmd,
+		},
+	)
 }
@@ -5,7 +5,8 @@ import (
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 )
 
-func chainCommittee() []int {
+// Committee returns the indices of the committee nodes of the current chain
+func Committee() []int {
 	chain, err := config.WaspClient().GetChainRecord(GetCurrentChainID())
 	log.Check(err)
 
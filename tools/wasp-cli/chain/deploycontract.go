@@ -1,38 +1,47 @@
 package chain
 
 import (
-	"os"
+	"strings"
 
 	"github.com/iotaledger/wasp/client/chainclient"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/wasmvalidate"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
 )
 
-func deployContractCmd(args []string) {
-	if len(args) != 4 {
-		log.Fatal("Usage: %s chain deploy-contract <vmtype> <name> <description> <filename>", os.Args[0])
+// schemaFile is the JSON file with the schema stored in the blob of the program
+var schemaFile string
+
+func deployContractCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy-contract <vmtype> <name> <description> <filename>",
+		Short: "Deploy a contract in the chain",
+		Args:  cobra.ExactArgs(4),
+		Run:   deployContractCmd,
 	}
+	initUploadFlags(cmd)
+	cmd.Flags().StringVarP(&schemaFile, "schema", "", "", "JSON file with the schema of the contract, published with the program")
+	return cmd
+}
 
+func deployContractCmd(cmd *cobra.Command, args []string) {
 	vmtype := args[0]
 	name := args[1]
 	description := args[2]
 	filename := args[3]
 
-	blobFieldValues := codec.MakeDict(map[string]interface{}{
-		blob.VarFieldVMType:             vmtype,
-		blob.VarFieldProgramDescription: description,
-		blob.VarFieldProgramBinary:      util.ReadFile(filename),
-	})
-
-	progHash := uploadBlob(blobFieldValues, true)
+	progHash, exports := uploadProgram(vmtype, description, filename)
 
-	util.WithSCTransaction(func() (*sctransaction.Transaction, error) {
+	tx := util.WithSCTransaction(func() (*sctransaction.Transaction, error) {
 		return Client().PostRequest(
 			root.Interface.Hname(),
 			coretypes.Hn(root.FuncDeployContract),
@@ -45,4 +54,68 @@ func deployContractCmd(args []string) {
 			},
 		)
 	})
+	log.PrintResult(deployContractResult{
+		Hname:       coretypes.Hn(name).String(),
+		ProgramHash: progHash.String(),
+		TxID:        tx.ID().String(),
+		Exports:     exports,
+	}, func() {
+		if len(exports) > 0 {
+			log.Printf("entry points: %s\n", strings.Join(exports, ", "))
+		}
+	})
+}
+
+// uploadProgram stores the program in a blob and returns its hash and the entry points
+// exported by it, if it is a Wasm program
+func uploadProgram(vmtype string, description string, filename string) (hashing.HashValue, []string) {
+	binary := util.ReadFile(filename)
+
+	// the chain rejects invalid Wasm programs, so they are checked before uploading
+	var exports []string
+	var entryPoints []wasmvalidate.EntryPoint
+	if vmtype == wasmvalidate.VMType {
+		var err error
+		entryPoints, err = wasmvalidate.Validate(binary)
+		log.Check(err)
+		for _, ep := range entryPoints {
+			if ep.View {
+				exports = append(exports, ep.Name+" (view)")
+			} else {
+				exports = append(exports, ep.Name)
+			}
+		}
+	}
+
+	blobFieldValues := codec.MakeDict(map[string]interface{}{
+		blob.VarFieldVMType:             vmtype,
+		blob.VarFieldProgramDescription: description,
+		blob.VarFieldProgramBinary:      binary,
+	})
+	if schemaFile != "" {
+		blobFieldValues.Set(blob.VarFieldProgramSchema, readSchema(schemaFile, entryPoints))
+	}
+	return uploadBlob(blobFieldValues, true), exports
+}
+
+type deployContractResult struct {
+	Hname       string   `json:"hname"`
+	ProgramHash string   `json:"programHash"`
+	TxID        string   `json:"txID"`
+	Exports     []string `json:"exports,omitempty"`
+}
+
+// readSchema reads the schema and checks it against the entry points of the program,
+// the same way the chain checks it at deployment
+func readSchema(filename string, entryPoints []wasmvalidate.EntryPoint) []byte {
+	s, err := schema.Parse(util.ReadFile(filename))
+	log.Check(err)
+	if entryPoints != nil {
+		views := make(map[string]bool)
+		for _, ep := range entryPoints {
+			views[ep.Name] = ep.View
+		}
+		log.Check(s.CheckEntryPoints(views))
+	}
+	return s.Bytes()
 }
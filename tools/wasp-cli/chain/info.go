@@ -5,51 +5,72 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
-	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
 )
 
-func infoCmd(args []string) {
-	chain, err := config.WaspClient().GetChainRecord(GetCurrentChainID())
+type infoResult struct {
+	ChainID             string `json:"chainID"`
+	Color               string `json:"color"`
+	Address             string `json:"address"`
+	Description         string `json:"description"`
+	Contracts           int    `json:"contracts"`
+	Owner               string `json:"owner"`
+	DelegatedOwner      string `json:"delegatedOwner,omitempty"`
+	FeeColor            string `json:"feeColor"`
+	DefaultOwnerFee     int64  `json:"defaultOwnerFee"`
+	DefaultValidatorFee int64  `json:"defaultValidatorFee"`
+}
+
+func infoCmd(cmd *cobra.Command, args []string) {
+	chainID := GetCurrentChainID()
+	info, err := SCClient(root.Interface.Hname()).CallView(root.FuncGetChainInfo, nil)
 	log.Check(err)
 
-	log.Printf("Chain ID: %s\n", chain.ChainID)
-	log.Printf("Committee nodes: %+v\n", chain.CommitteeNodes)
-	log.Printf("Active: %v\n", chain.Active)
+	res := infoResult{ChainID: chainID.String()}
 
-	if chain.Active {
-		info, err := SCClient(root.Interface.Hname()).CallView(root.FuncGetChainInfo, nil)
-		log.Check(err)
+	color, _, err := codec.DecodeColor(info.MustGet(root.VarChainColor))
+	log.Check(err)
+	res.Color = color.String()
 
-		color, _, err := codec.DecodeColor(info.MustGet(root.VarChainColor))
-		log.Check(err)
-		log.Printf("Chain Color: %s\n", color)
+	addr, _, err := codec.DecodeAddress(info.MustGet(root.VarChainAddress))
+	log.Check(err)
+	res.Address = address.Address(addr).String()
 
-		addr, _, err := codec.DecodeAddress(info.MustGet(root.VarChainAddress))
-		log.Check(err)
-		log.Printf("Chain Address: %s\n", address.Address(addr))
+	res.Description, _, err = codec.DecodeString(info.MustGet(root.VarDescription))
+	log.Check(err)
 
-		description, _, err := codec.DecodeString(info.MustGet(root.VarDescription))
-		log.Check(err)
-		log.Printf("Description: %s\n", description)
+	contracts, err := root.DecodeContractRegistry(collections.NewMapReadOnly(info, root.VarContractRegistry))
+	log.Check(err)
+	res.Contracts = len(contracts)
 
-		contracts, err := root.DecodeContractRegistry(collections.NewMapReadOnly(info, root.VarContractRegistry))
-		log.Check(err)
-		log.Printf("#Contracts: %d\n", len(contracts))
+	ownerID, _, err := codec.DecodeAgentID(info.MustGet(root.VarChainOwnerID))
+	log.Check(err)
+	res.Owner = ownerID.String()
 
-		ownerID, _, err := codec.DecodeAgentID(info.MustGet(root.VarChainOwnerID))
-		log.Check(err)
-		log.Printf("Owner: %s\n", ownerID)
+	delegated, ok, err := codec.DecodeAgentID(info.MustGet(root.VarChainOwnerIDDelegated))
+	log.Check(err)
+	if ok {
+		res.DelegatedOwner = delegated.String()
+	}
 
-		delegated, ok, err := codec.DecodeAgentID(info.MustGet(root.VarChainOwnerIDDelegated))
-		log.Check(err)
-		if ok {
-			log.Printf("Delegated owner: %s\n", delegated)
-		}
+	feeColor, defaultOwnerFee, defaultValidatorFee, err := root.GetDefaultFeeInfo(info)
+	log.Check(err)
+	res.FeeColor = feeColor.String()
+	res.DefaultOwnerFee = defaultOwnerFee
+	res.DefaultValidatorFee = defaultValidatorFee
 
-		feeColor, defaultOwnerFee, defaultValidatorFee, err := root.GetDefaultFeeInfo(info)
-		log.Check(err)
-		log.Printf("Default owner fee: %d %s\n", defaultOwnerFee, feeColor)
-		log.Printf("Default validator fee: %d %s\n", defaultValidatorFee, feeColor)
-	}
+	log.PrintResult(res, func() {
+		log.Printf("Chain ID: %s\n", res.ChainID)
+		log.Printf("Chain Color: %s\n", res.Color)
+		log.Printf("Chain Address: %s\n", res.Address)
+		log.Printf("Description: %s\n", res.Description)
+		log.Printf("#Contracts: %d\n", res.Contracts)
+		log.Printf("Owner: %s\n", res.Owner)
+		if res.DelegatedOwner != "" {
+			log.Printf("Delegated owner: %s\n", res.DelegatedOwner)
+		}
+		log.Printf("Default owner fee: %d %s\n", res.DefaultOwnerFee, res.FeeColor)
+		log.Printf("Default validator fee: %d %s\n", res.DefaultValidatorFee, res.FeeColor)
+	})
 }
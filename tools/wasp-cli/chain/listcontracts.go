@@ -6,9 +6,10 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
 )
 
-func listContractsCmd(args []string) {
+func listContractsCmd(cmd *cobra.Command, args []string) {
 	info, err := SCClient(root.Interface.Hname()).CallView(root.FuncGetChainInfo, nil)
 	log.Check(err)
 
@@ -28,6 +29,7 @@ func listContractsCmd(args []string) {
 		"creator",
 		"owner fee",
 		"validator fee",
+		"status",
 	}
 	rows := make([][]string, len(contracts))
 	i := 0
@@ -46,6 +48,11 @@ func listContractsCmd(args []string) {
 			validatorFee = defaultValidatorFee
 		}
 
+		status := "active"
+		if c.Deactivated {
+			status = "deactivated"
+		}
+
 		rows[i] = []string{
 			hname.String(),
 			c.Name,
@@ -54,6 +61,7 @@ func listContractsCmd(args []string) {
 			creator,
 			fmt.Sprintf("%d %s", ownerFee, feeColor),
 			fmt.Sprintf("%d %s", validatorFee, feeColor),
+			status,
 		}
 		i++
 	}
@@ -1,7 +1,6 @@
 package chain
 
 import (
-	"os"
 	"time"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
@@ -9,22 +8,30 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
 )
 
-func logCmd(args []string) {
-	if len(args) != 1 {
-		log.Fatal("Usage: %s chain log <name>", os.Args[0])
-	}
+type logRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	Data      string    `json:"data"`
+}
+
+func logCmd(cmd *cobra.Command, args []string) {
 	r, err := SCClient(eventlog.Interface.Hname()).CallView(eventlog.FuncGetRecords, codec.MakeDict(map[string]interface{}{
 		eventlog.ParamContractHname: codec.EncodeHname(coretypes.Hn(args[0])),
 	}))
 	log.Check(err)
 
 	records := collections.NewArrayReadOnly(r, eventlog.ParamRecords)
-	for i := uint16(0); i < records.MustLen(); i++ {
-		b := records.MustGetAt(i)
-		rec, err := collections.ParseRawLogRecord(b)
+	res := make([]logRecord, records.MustLen())
+	for i := range res {
+		rec, err := collections.ParseRawLogRecord(records.MustGetAt(uint16(i)))
 		log.Check(err)
-		log.Printf("%s %s\n", time.Unix(0, rec.Timestamp), string(rec.Data))
+		res[i] = logRecord{Timestamp: time.Unix(0, rec.Timestamp), Data: string(rec.Data)}
 	}
+	log.PrintResult(res, func() {
+		for _, rec := range res {
+			log.Printf("%s %s\n", rec.Timestamp, rec.Data)
+		}
+	})
 }
@@ -1,26 +1,51 @@
 package chain
 
 import (
-	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
-	"os"
-
 	"github.com/iotaledger/wasp/client/chainclient"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/iotaledger/wasp/tools/wasp-cli/wallet"
+	"github.com/spf13/cobra"
 )
 
-func postRequestCmd(args []string) {
-	if len(args) < 2 {
-		log.Fatal("Usage: %s chain post-request <name> <funcname> [params]", os.Args[0])
+func postRequestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "post-request <name> <funcname> [params]",
+		Short: "Post a request to a contract",
+		Long: `Post a request to a contract.
+
+The params are either <name>=<value> ..., encoded with the types declared
+in the schema of the contract, or <type> <key> <type> <value> ...`,
+		Args: cobra.MinimumNArgs(2),
+		Run:  postRequestCmd,
 	}
-	util.WithSCTransaction(func() (*sctransaction.Transaction, error) {
-		return SCClient(coretypes.Hn(args[0])).PostRequest(
-			args[1],
-			chainclient.PostRequestParams{
-				Args: requestargs.New().AddEncodeSimpleMany(util.EncodeParams(args[2:])),
-			},
-		)
+	wallet.InitUnsignedFlag(cmd)
+	return cmd
+}
+
+func postRequestCmd(cmd *cobra.Command, args []string) {
+	var s *schema.Schema
+	if isNamedArgs(args[2:]) {
+		s = getSchema(coretypes.Hn(args[0]))
+	}
+	params := chainclient.PostRequestParams{
+		Args: requestargs.New().AddEncodeSimpleMany(encodeArgs(s, args[0], args[1], args[2:])),
+	}
+
+	if wallet.UnsignedFile() != "" {
+		client := Client()
+		tx, err := client.CreateUnsignedRequest(coretypes.Hn(args[0]), coretypes.Hn(args[1]), params)
+		log.Check(err)
+		wallet.SaveUnsignedTx(tx.Transaction, client.SigScheme.Address())
+		return
+	}
+
+	tx := util.WithSCTransaction(func() (*sctransaction.Transaction, error) {
+		return SCClient(coretypes.Hn(args[0])).PostRequest(args[1], params)
 	})
+	log.PrintResult(util.TxResult{TxID: tx.ID().String()}, func() {})
 }
@@ -0,0 +1,73 @@
+package chain
+
+import (
+	"github.com/iotaledger/wasp/client/chainclient"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sweepTarget string
+	eraseState  bool
+)
+
+func removeContractCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove-contract <name>",
+		Short: "Remove a contract from the chain, moving its tokens to another account",
+		Long: `Remove a contract from the chain, moving its tokens to another account.
+
+The tokens in the on-chain account of the contract are moved to the account
+given with --to, by default to the account of the sender. The state of the
+contract is kept, unless --erase is given.`,
+		Args: cobra.ExactArgs(1),
+		Run:  removeContractCmd,
+	}
+	cmd.Flags().StringVarP(&sweepTarget, "to", "", "", "agent ID which receives the tokens of the contract")
+	cmd.Flags().BoolVarP(&eraseState, "erase", "", false, "erase the state of the contract")
+	return cmd
+}
+
+func deactivateContractCmd(cmd *cobra.Command, args []string) {
+	postManageContractRequest(root.FuncDeactivateContract, args[0], dict.New())
+}
+
+func activateContractCmd(cmd *cobra.Command, args []string) {
+	postManageContractRequest(root.FuncActivateContract, args[0], dict.New())
+}
+
+func removeContractCmd(cmd *cobra.Command, args []string) {
+	params := dict.New()
+	if sweepTarget != "" {
+		target, err := coretypes.NewAgentIDFromString(sweepTarget)
+		log.Check(err)
+		params.Set(root.ParamSweepTarget, codec.EncodeAgentID(target))
+	}
+	if eraseState {
+		params.Set(root.ParamEraseState, codec.EncodeInt64(1))
+	}
+	postManageContractRequest(root.FuncRemoveContract, args[0], params)
+}
+
+// postManageContractRequest posts the request to the function of the 'root' contract
+// which changes the status of the contract
+func postManageContractRequest(fname string, name string, params dict.Dict) {
+	params.Set(root.ParamHname, coretypes.Hn(name).Bytes())
+	tx := util.WithSCTransaction(func() (*sctransaction.Transaction, error) {
+		return Client().PostRequest(
+			root.Interface.Hname(),
+			coretypes.Hn(fname),
+			chainclient.PostRequestParams{
+				Args: requestargs.New().AddEncodeSimpleMany(params),
+			},
+		)
+	})
+	log.PrintResult(util.TxResult{TxID: tx.ID().String()}, func() {})
+}
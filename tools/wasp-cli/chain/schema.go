@@ -0,0 +1,105 @@
+package chain
+
+import (
+	"strings"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/schema"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
+)
+
+func schemaCmd(cmd *cobra.Command, args []string) {
+	s := getSchema(coretypes.Hn(args[0]))
+	if s == nil {
+		log.Fatal("contract '%s' has no schema", args[0])
+	}
+	log.PrintResult(s, func() {
+		if s.Description != "" {
+			log.Printf("%s: %s\n", s.Name, s.Description)
+		}
+		header := []string{"function", "hname", "kind", "params", "results"}
+		rows := make([][]string, len(s.Funcs))
+		for i := range s.Funcs {
+			f := &s.Funcs[i]
+			kind := "full"
+			if f.View {
+				kind = "view"
+			}
+			rows[i] = []string{f.Name, coretypes.Hn(f.Name).String(), kind, fieldsString(f.Params), fieldsString(f.Results)}
+		}
+		log.PrintTable(header, rows)
+
+		if len(s.State) == 0 {
+			return
+		}
+		log.Printf("\nState:\n")
+		header = []string{"variable", "kind", "key", "type"}
+		rows = make([][]string, len(s.State))
+		for i, v := range s.State {
+			rows[i] = []string{v.Name, v.Kind, v.Key, v.Type}
+		}
+		log.PrintTable(header, rows)
+	})
+}
+
+func fieldsString(fields []schema.FieldDef) string {
+	ret := make([]string, len(fields))
+	for i, f := range fields {
+		ret[i] = f.Name + ":" + f.Type
+		if f.Optional {
+			ret[i] += "?"
+		}
+	}
+	return strings.Join(ret, " ")
+}
+
+func getSchema(contract coretypes.Hname) *schema.Schema {
+	s, err := Client().GetContractSchema(contract)
+	log.Check(err)
+	return s
+}
+
+// isNamedArgs checks if the arguments are given as name=value
+func isNamedArgs(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	for _, arg := range args {
+		if !strings.Contains(arg, "=") {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeArgs encodes the arguments of the call to the function of the contract.
+// Arguments given as name=value are encoded with the types declared in the schema
+// of the contract, otherwise the format is <type> <key> <type> <value> ...
+func encodeArgs(s *schema.Schema, contract string, fname string, args []string) dict.Dict {
+	if !isNamedArgs(args) {
+		return util.EncodeParams(args)
+	}
+	f := getFuncDef(s, contract, fname)
+	named := make(map[string]string)
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		named[kv[0]] = kv[1]
+	}
+	ret, err := f.EncodeParams(named)
+	log.Check(err)
+	return ret
+}
+
+func getFuncDef(s *schema.Schema, contract string, fname string) *schema.FuncDef {
+	if s == nil {
+		log.Fatal("contract '%s' has no schema, use the format <type> <key> <type> <value> ...", contract)
+	}
+	f, ok := s.Func(fname)
+	if !ok {
+		log.Fatal("function '%s' is not in the schema of contract '%s'", fname, contract)
+	}
+	return f
+}
@@ -0,0 +1,62 @@
+package chain
+
+import (
+	"strings"
+
+	"github.com/iotaledger/wasp/client/chainclient"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
+)
+
+func upgradeContractCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade-contract <vmtype> <name> <description> <filename> [params]",
+		Short: "Replace the program of a contract in the chain, keeping its state",
+		Long: `Replace the program of a contract in the chain, keeping its state.
+
+The params, in the format <type> <key> <type> <value> ..., are passed to the
+'migrate' entry point of the new program, if it has one.`,
+		Args: cobra.MinimumNArgs(4),
+		Run:  upgradeContractCmd,
+	}
+	initUploadFlags(cmd)
+	cmd.Flags().StringVarP(&schemaFile, "schema", "", "", "JSON file with the schema of the contract, published with the program")
+	return cmd
+}
+
+func upgradeContractCmd(cmd *cobra.Command, args []string) {
+	vmtype := args[0]
+	name := args[1]
+	description := args[2]
+	filename := args[3]
+
+	progHash, exports := uploadProgram(vmtype, description, filename)
+
+	params := util.EncodeParams(args[4:])
+	params.Set(root.ParamHname, coretypes.Hn(name).Bytes())
+	params.Set(root.ParamProgramHash, progHash[:])
+	tx := util.WithSCTransaction(func() (*sctransaction.Transaction, error) {
+		return Client().PostRequest(
+			root.Interface.Hname(),
+			coretypes.Hn(root.FuncUpgradeContract),
+			chainclient.PostRequestParams{
+				Args: requestargs.New().AddEncodeSimpleMany(params),
+			},
+		)
+	})
+	log.PrintResult(deployContractResult{
+		Hname:       coretypes.Hn(name).String(),
+		ProgramHash: progHash.String(),
+		TxID:        tx.ID().String(),
+		Exports:     exports,
+	}, func() {
+		if len(exports) > 0 {
+			log.Printf("entry points: %s\n", strings.Join(exports, ", "))
+		}
+	})
+}
@@ -1,9 +1,9 @@
 package chain
 
-import "github.com/spf13/pflag"
+import "github.com/spf13/cobra"
 
 var uploadQuorum int
 
-func initUploadFlags(flags *pflag.FlagSet) {
-	flags.IntVarP(&uploadQuorum, "upload-quorum", "", 3, "quorum for blob upload")
+func initUploadFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&uploadQuorum, "upload-quorum", "", 3, "quorum for blob upload")
 }
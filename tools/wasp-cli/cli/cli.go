@@ -0,0 +1,65 @@
+// Package cli is the command framework shared by wasp-cli and wasp-admin.
+package cli
+
+import (
+	"os"
+
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// NewRootCmd creates the root command of a tool with the flags common to all commands
+// (config file, wait, verbose, debug, output format), the `set` and the `completion` commands
+func NewRootCmd(use string, short string) *cobra.Command {
+	root := &cobra.Command{
+		Use:          use,
+		Short:        short,
+		SilenceUsage: true,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			log.Check(log.CheckOutputFlag())
+			config.Read()
+		},
+	}
+	log.InitCommands(root)
+	config.InitCommands(root)
+	root.AddCommand(completionCmd(root))
+	return root
+}
+
+// Execute runs the command selected by the command line arguments
+func Execute(root *cobra.Command) {
+	// cobra adds the global flag set to every command, but the flags registered there
+	// by the imported goshimmer packages are node options, irrelevant to the tools
+	pflag.CommandLine = pflag.NewFlagSet(os.Args[0], pflag.ExitOnError)
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func completionCmd(root *cobra.Command) *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion <bash|zsh|fish|powershell>",
+		Short: "Generate the shell completion script",
+		Long: `Generate the shell completion script. For example, to load the completions in the current bash session:
+
+  source <(` + root.Name() + ` completion bash)`,
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		Args:      cobra.ExactValidArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			switch args[0] {
+			case "bash":
+				err = root.GenBashCompletion(os.Stdout)
+			case "zsh":
+				err = root.GenZshCompletion(os.Stdout)
+			case "fish":
+				err = root.GenFishCompletion(os.Stdout, true)
+			case "powershell":
+				err = root.GenPowerShellCompletion(os.Stdout)
+			}
+			log.Check(err)
+		},
+	}
+}
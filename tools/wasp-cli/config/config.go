@@ -8,9 +8,10 @@ import (
 	"github.com/iotaledger/wasp/client"
 	"github.com/iotaledger/wasp/client/level1"
 	"github.com/iotaledger/wasp/client/level1/goshimmer"
+	"github.com/iotaledger/wasp/client/multiclient"
 	"github.com/iotaledger/wasp/packages/testutil"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
-	"github.com/spf13/pflag"
+	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
@@ -23,25 +24,25 @@ const (
 	HostKindNanomsg = "nanomsg"
 )
 
-func InitCommands(commands map[string]func([]string), flags *pflag.FlagSet) {
-	commands["set"] = setCmd
+func InitCommands(root *cobra.Command) {
+	root.PersistentFlags().StringVarP(&ConfigPath, "config", "c", "wasp-cli.json", "path to wasp-cli.json")
+	root.PersistentFlags().BoolVarP(&WaitForCompletion, "wait", "w", true, "wait for request completion")
 
-	fs := pflag.NewFlagSet("config", pflag.ExitOnError)
-	fs.StringVarP(&ConfigPath, "config", "c", "wasp-cli.json", "path to wasp-cli.json")
-	fs.BoolVarP(&WaitForCompletion, "wait", "w", true, "wait for request completion")
-	flags.AddFlagSet(fs)
+	root.AddCommand(&cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a configuration value",
+		Args:  cobra.ExactArgs(2),
+		Run:   setCmd,
+	})
 }
 
-func setCmd(args []string) {
-	if len(args) != 2 {
-		log.Usage("%s set <key> <value>\n", os.Args[0])
-	}
+func setCmd(cmd *cobra.Command, args []string) {
 	v := args[1]
 	switch v {
 	case "true":
 		Set(args[0], true)
 	case "false":
-		Set(args[0], true)
+		Set(args[0], false)
 	default:
 		Set(args[0], v)
 	}
@@ -78,9 +79,27 @@ func GoshimmerClient() level1.Level1Client {
 }
 
 func WaspClient() *client.WaspClient {
-	// TODO: add authentication for /adm
 	log.Verbose("using Wasp host %s\n", WaspApi())
-	return client.NewWaspClient(WaspApi())
+	return client.NewWaspClient(WaspApi()).WithToken(WaspToken()).WithAPIKey(WaspAPIKey())
+}
+
+func MultiClient(hosts []string) *multiclient.MultiClient {
+	return multiclient.New(hosts).WithToken(WaspToken()).WithAPIKey(WaspAPIKey())
+}
+
+const (
+	WaspTokenConfigVar  = "wasp.token"
+	WaspAPIKeyConfigVar = "wasp.apikey"
+)
+
+// WaspToken returns the token stored by the login command, if any
+func WaspToken() string {
+	return viper.GetString(WaspTokenConfigVar)
+}
+
+// WaspAPIKey returns the API key set with `set wasp.apikey`, if any
+func WaspAPIKey() string {
+	return viper.GetString(WaspAPIKeyConfigVar)
 }
 
 func WaspApi() string {
@@ -1,20 +1,35 @@
 package decode
 
 import (
-	"os"
-
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/iotaledger/wasp/tools/wasp-cli/util"
-	"github.com/spf13/pflag"
+	"github.com/spf13/cobra"
 )
 
-func InitCommands(commands map[string]func([]string), flags *pflag.FlagSet) {
-	commands["decode"] = decodeCmd
+func InitCommands(root *cobra.Command) {
+	root.AddCommand(&cobra.Command{
+		Use:   "decode <type> <key> <type> [...]",
+		Short: "Decode the JSON dict read from stdin",
+		Long: `Decode the JSON dict read from stdin, e.g. the output of call-view.
+
+With two arguments <keytype> <valuetype> all the entries are decoded.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 2 {
+				return nil
+			}
+			if len(args) < 3 || len(args)%3 != 0 {
+				return cobra.ExactArgs(3)(cmd, args)
+			}
+			return nil
+		},
+		Run: decodeCmd,
+	})
 }
 
-func decodeCmd(args []string) {
+func decodeCmd(cmd *cobra.Command, args []string) {
 	d := util.UnmarshalDict()
+	res := make(map[string]interface{})
 
 	if len(args) == 2 {
 		ktype := args[0]
@@ -23,15 +38,17 @@ func decodeCmd(args []string) {
 		for key, value := range d {
 			skey := util.ValueToString(ktype, []byte(key))
 			sval := util.ValueToString(vtype, value)
-			log.Printf("%s: %s\n", skey, sval)
+			res[skey] = sval
+			if !log.JSONOutput() {
+				log.Printf("%s: %s\n", skey, sval)
+			}
+		}
+		if log.JSONOutput() {
+			log.PrintJSON(res)
 		}
 		return
 	}
 
-	if len(args) < 3 || len(args)%3 != 0 {
-		log.Usage("%s decode <type> <key> <type> [...]\n", os.Args[0])
-	}
-
 	for i := 0; i < len(args)/2; i++ {
 		ktype := args[i*2]
 		skey := args[i*2+1]
@@ -40,9 +57,19 @@ func decodeCmd(args []string) {
 		key := kv.Key(util.ValueFromString(ktype, skey))
 		val := d.MustGet(key)
 		if val == nil {
-			log.Printf("%s: <nil>\n", skey)
+			res[skey] = nil
+			if !log.JSONOutput() {
+				log.Printf("%s: <nil>\n", skey)
+			}
 		} else {
-			log.Printf("%s: %s\n", skey, util.ValueToString(vtype, val))
+			sval := util.ValueToString(vtype, val)
+			res[skey] = sval
+			if !log.JSONOutput() {
+				log.Printf("%s: %s\n", skey, sval)
+			}
 		}
 	}
+	if log.JSONOutput() {
+		log.PrintJSON(res)
+	}
 }
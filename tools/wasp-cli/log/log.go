@@ -1,24 +1,56 @@
 package log
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"text/tabwriter"
 
-	"github.com/spf13/pflag"
+	"github.com/spf13/cobra"
+)
+
+const (
+	OutputText = "text"
+	OutputJSON = "json"
 )
 
 var VerboseFlag bool
 var DebugFlag bool
+var OutputFlag string
+
+func InitCommands(root *cobra.Command) {
+	root.PersistentFlags().BoolVarP(&VerboseFlag, "verbose", "v", false, "verbose")
+	root.PersistentFlags().BoolVarP(&DebugFlag, "debug", "d", false, "debug")
+	root.PersistentFlags().StringVarP(&OutputFlag, "output", "o", OutputText, "output format: text or json")
+}
+
+// CheckOutputFlag validates the --output flag
+func CheckOutputFlag() error {
+	switch OutputFlag {
+	case OutputText, OutputJSON:
+		return nil
+	}
+	return fmt.Errorf("invalid output format %q: must be %s or %s", OutputFlag, OutputText, OutputJSON)
+}
+
+// JSONOutput returns true if the results must be printed as JSON
+func JSONOutput() bool {
+	return OutputFlag == OutputJSON
+}
 
-func InitCommands(commands map[string]func([]string), flags *pflag.FlagSet) {
-	flags.BoolVarP(&VerboseFlag, "verbose", "v", false, "verbose")
-	flags.BoolVarP(&DebugFlag, "debug", "d", false, "debug")
+// Out is where the human-readable messages are written to. With JSON output they go
+// to stderr, so that stdout only contains the JSON result
+func Out() io.Writer {
+	if JSONOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
 }
 
 func Printf(format string, args ...interface{}) {
-	fmt.Printf(format, args...)
+	fmt.Fprintf(Out(), format, args...)
 }
 
 func Verbose(format string, args ...interface{}) {
@@ -54,7 +86,37 @@ func Check(err error) {
 	}
 }
 
+// PrintJSON writes the value to stdout as JSON
+func PrintJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	Check(enc.Encode(v))
+}
+
+// PrintResult prints the result of the command: as JSON with --output=json,
+// otherwise by calling printText
+func PrintResult(result interface{}, printText func()) {
+	if JSONOutput() {
+		PrintJSON(result)
+		return
+	}
+	printText()
+}
+
+// PrintTable prints the rows as a table. With --output=json the rows are printed
+// as an array of objects with the header as keys
 func PrintTable(header []string, rows [][]string) {
+	if JSONOutput() {
+		objs := make([]map[string]string, len(rows))
+		for i, row := range rows {
+			objs[i] = make(map[string]string)
+			for j, s := range row {
+				objs[i][header[j]] = s
+			}
+		}
+		PrintJSON(objs)
+		return
+	}
 	if len(rows) == 0 {
 		return
 	}
@@ -0,0 +1,64 @@
+package login
+
+import (
+	"time"
+
+	"github.com/iotaledger/wasp/client"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/wallet"
+	"github.com/spf13/cobra"
+)
+
+func InitCommands(root *cobra.Command) {
+	root.AddCommand(
+		&cobra.Command{
+			Use:   "login [<username> <password>]",
+			Short: "Log in to the wasp node",
+			Long: `Log in to the wasp node. Without arguments, the challenge of the node is signed with the
+wallet key pair, which must be authorized in the node.`,
+			Args: func(cmd *cobra.Command, args []string) error {
+				if len(args) == 0 {
+					return nil
+				}
+				return cobra.ExactArgs(2)(cmd, args)
+			},
+			Run: loginCmd,
+		},
+		&cobra.Command{
+			Use:   "logout",
+			Short: "Forget the token of the wasp node",
+			Args:  cobra.NoArgs,
+			Run:   logoutCmd,
+		},
+	)
+}
+
+type loginResult struct {
+	Role    string    `json:"role"`
+	Expires time.Time `json:"expires"`
+}
+
+func loginCmd(cmd *cobra.Command, args []string) {
+	waspClient := client.NewWaspClient(config.WaspApi())
+	var res *model.LoginResponse
+	var err error
+	if len(args) == 0 {
+		// sign the challenge with the wallet key pair, which must be authorized in the node
+		res, err = waspClient.LoginWithKeyPair(wallet.Load().KeyPair())
+	} else {
+		res, err = waspClient.Login(args[0], args[1])
+	}
+	log.Check(err)
+
+	config.Set(config.WaspTokenConfigVar, res.Token)
+	log.PrintResult(loginResult{Role: res.Role, Expires: res.Expires}, func() {
+		log.Printf("Logged in to %s as %s, the token expires at %s\n", config.WaspApi(), res.Role, res.Expires)
+	})
+}
+
+func logoutCmd(cmd *cobra.Command, args []string) {
+	config.Set(config.WaspTokenConfigVar, "")
+	log.Printf("Logged out\n")
+}
@@ -32,7 +32,7 @@ func (c *Config) MakeClient(sigScheme signaturescheme.SignatureScheme) *chaincli
 	var timeout time.Duration
 	client := chainclient.New(
 		config.GoshimmerClient(),
-		client.NewWaspClient(config.WaspApi()),
+		config.WaspClient(),
 		chain.GetCurrentChainID(),
 		sigScheme,
 	)
@@ -20,7 +20,7 @@ func activateCmd(args []string) {
 	log.Check(err)
 	committee := parseIntList(args[1])
 
-	log.Check(multiclient.New(config.CommitteeApi(committee)).ActivateChain(&scAddress))
+	log.Check(config.MultiClient(config.CommitteeApi(committee)).ActivateChain(&scAddress))
 }
 
 func activateUsage() {
@@ -8,6 +8,6 @@ fi
 wasp-cli -d set utxodb true	
 wasp-cli -d init
 wasp-cli -w -d request-funds
-wasp-cli -w -d chain deploy --chain=chain1 --committee=0,1,2,3 --quorum=3
+wasp-admin -w -d chain deploy --chain=chain1 --committee=0,1,2,3 --quorum=3
 wasp-cli -w -d chain deploy-contract wasmtimevm inccounter "inccounter SC" tools/cluster/tests/wasm/inccounter_bg.wasm
 wasp-cli -w -d chain post-request inccounter increment
@@ -38,3 +38,8 @@ func WithSCTransaction(f func() (*sctransaction.Transaction, error), forceWait .
 
 	return tx
 }
+
+// TxResult is the JSON output of the commands posting a transaction
+type TxResult struct {
+	TxID string `json:"txID"`
+}
@@ -0,0 +1,225 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/goshimmer/client/wallet/packages/seed"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/mr-tron/base58"
+	"github.com/spf13/cobra"
+)
+
+var accountIndex uint64
+
+func accountCommand() *cobra.Command {
+	accountCmd := &cobra.Command{
+		Use:   "account",
+		Short: "Manage the accounts of the wallet keystore",
+	}
+
+	newCmd := &cobra.Command{
+		Use:   "new <name>",
+		Short: "Create an account with a new seed",
+		Args:  cobra.ExactArgs(1),
+		Run:   newAccountCmd,
+	}
+	newCmd.Flags().Uint64VarP(&accountIndex, "index", "", 0, "address index of the seed")
+
+	importSeedCmd := &cobra.Command{
+		Use:   "import-seed <name> <seed>",
+		Short: "Import a base58 encoded seed as an account",
+		Args:  cobra.ExactArgs(2),
+		Run:   importSeedCmd,
+	}
+	importSeedCmd.Flags().Uint64VarP(&accountIndex, "index", "", 0, "address index of the seed")
+
+	accountCmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List the accounts",
+			Args:  cobra.NoArgs,
+			Run:   listAccountsCmd,
+		},
+		newCmd,
+		importSeedCmd,
+		&cobra.Command{
+			Use:   "import-key <name> <private-key>",
+			Short: "Import a base58 encoded ed25519 private key as an account",
+			Args:  cobra.ExactArgs(2),
+			Run:   importKeyCmd,
+		},
+		&cobra.Command{
+			Use:   "import-address <name> <address>",
+			Short: "Import a watch-only account, which can only build unsigned transactions",
+			Args:  cobra.ExactArgs(2),
+			Run:   importAddressCmd,
+		},
+		&cobra.Command{
+			Use:   "export <name>",
+			Short: "Show the seed or the private key of the account",
+			Args:  cobra.ExactArgs(1),
+			Run:   exportCmd,
+		},
+		&cobra.Command{
+			Use:   "default <name>",
+			Short: "Set the account used when --account is not given",
+			Args:  cobra.ExactArgs(1),
+			Run:   setDefaultCmd,
+		},
+		&cobra.Command{
+			Use:   "remove <name>",
+			Short: "Remove the account from the keystore",
+			Args:  cobra.ExactArgs(1),
+			Run:   removeCmd,
+		},
+		&cobra.Command{
+			Use:   "change-passphrase",
+			Short: "Change the passphrase of the keystore",
+			Args:  cobra.NoArgs,
+			Run:   changePassphraseCmd,
+		},
+	)
+	return accountCmd
+}
+
+func listAccountsCmd(cmd *cobra.Command, args []string) {
+	ks := loadKeystore()
+	header := []string{"name", "kind", "address", "index", "default"}
+	rows := make([][]string, 0, len(ks.Accounts))
+	for _, name := range ks.names() {
+		acc := ks.Accounts[name]
+		index := ""
+		if acc.kind() == secretKindSeed {
+			index = fmt.Sprintf("%d", acc.Index)
+		}
+		rows = append(rows, []string{name, acc.kind(), acc.Address, index, fmt.Sprintf("%v", name == ks.Default)})
+	}
+	log.Printf("Total %d account(s) in %s\n", len(rows), keystorePath)
+	log.PrintTable(header, rows)
+}
+
+func newAccountCmd(cmd *cobra.Command, args []string) {
+	addAccount(args[0], func(ks *keystore) (*account, error) {
+		return newSeedAccount(seed.NewSeed(), accountIndex, keystorePassphrase(ks))
+	})
+}
+
+func importSeedCmd(cmd *cobra.Command, args []string) {
+	seedBytes, err := base58.Decode(args[1])
+	log.Check(err)
+	addAccount(args[0], func(ks *keystore) (*account, error) {
+		return newSeedAccount(seed.NewSeed(seedBytes), accountIndex, keystorePassphrase(ks))
+	})
+}
+
+func importKeyCmd(cmd *cobra.Command, args []string) {
+	keyBytes, err := base58.Decode(args[1])
+	log.Check(err)
+	privateKey, err, _ := ed25519.PrivateKeyFromBytes(keyBytes)
+	log.Check(err)
+	addAccount(args[0], func(ks *keystore) (*account, error) {
+		return newKeyAccount(privateKey, keystorePassphrase(ks))
+	})
+}
+
+func importAddressCmd(cmd *cobra.Command, args []string) {
+	addr, err := address.FromBase58(args[1])
+	log.Check(err)
+	addAccount(args[0], func(ks *keystore) (*account, error) {
+		return newWatchOnlyAccount(addr), nil
+	})
+}
+
+func addAccount(name string, create func(ks *keystore) (*account, error)) {
+	ks := loadKeystore()
+	if _, ok := ks.Accounts[name]; ok {
+		log.Fatal("account %q already exists", name)
+	}
+	acc, err := create(ks)
+	log.Check(err)
+	ks.Accounts[name] = acc
+	if ks.Default == "" {
+		ks.Default = name
+	}
+	saveKeystore(ks)
+	log.PrintResult(map[string]string{"name": name, "address": acc.Address}, func() {
+		log.Printf("Added account %q, address %s\n", name, acc.Address)
+	})
+}
+
+type exportResult struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Address    string `json:"address"`
+	Seed       string `json:"seed,omitempty"`
+	Index      uint64 `json:"index,omitempty"`
+	PrivateKey string `json:"privateKey,omitempty"`
+}
+
+func exportCmd(cmd *cobra.Command, args []string) {
+	ks := loadKeystore()
+	acc, ok := ks.Accounts[args[0]]
+	if !ok {
+		log.Fatal("account %q not found in %s", args[0], keystorePath)
+	}
+	res := exportResult{Name: args[0], Kind: acc.kind(), Address: acc.Address}
+	if acc.Secret != nil {
+		data, err := acc.Secret.decrypt(getPassphrase())
+		log.Check(err)
+		switch acc.Secret.Kind {
+		case secretKindSeed:
+			res.Seed = base58.Encode(data)
+			res.Index = acc.Index
+		case secretKindKey:
+			res.PrivateKey = base58.Encode(data)
+		}
+	}
+	log.PrintResult(res, func() {
+		log.Printf("Account %s (%s)\n", res.Name, res.Kind)
+		log.Printf("  Address:     %s\n", res.Address)
+		if res.Seed != "" {
+			log.Printf("  Seed:        %s\n", res.Seed)
+			log.Printf("  Index:       %d\n", res.Index)
+		}
+		if res.PrivateKey != "" {
+			log.Printf("  Private key: %s\n", res.PrivateKey)
+		}
+	})
+}
+
+func setDefaultCmd(cmd *cobra.Command, args []string) {
+	ks := loadKeystore()
+	if _, ok := ks.Accounts[args[0]]; !ok {
+		log.Fatal("account %q not found in %s", args[0], keystorePath)
+	}
+	ks.Default = args[0]
+	saveKeystore(ks)
+}
+
+func removeCmd(cmd *cobra.Command, args []string) {
+	ks := loadKeystore()
+	if _, ok := ks.Accounts[args[0]]; !ok {
+		log.Fatal("account %q not found in %s", args[0], keystorePath)
+	}
+	delete(ks.Accounts, args[0])
+	if ks.Default == args[0] {
+		ks.Default = ""
+	}
+	saveKeystore(ks)
+	log.Printf("Removed account %q\n", args[0])
+}
+
+func changePassphraseCmd(cmd *cobra.Command, args []string) {
+	ks := loadKeystore()
+	oldPassphrase := getPassphrase()
+	log.Check(ks.checkPassphrase(oldPassphrase))
+	// the new passphrase is always read from the terminal
+	newPassphrase := promptNewPassphrase()
+	for _, acc := range ks.Accounts {
+		log.Check(acc.reencrypt(oldPassphrase, newPassphrase))
+	}
+	saveKeystore(ks)
+	log.Printf("Keystore passphrase changed\n")
+}
@@ -1,18 +1,61 @@
 package wallet
 
 import (
-	"github.com/spf13/pflag"
+	"github.com/spf13/cobra"
 )
 
-func InitCommands(commands map[string]func([]string), flags *pflag.FlagSet) {
-	commands["init"] = initCmd
-	commands["address"] = addressCmd
-	commands["balance"] = balanceCmd
-	commands["mint"] = mintCmd
-	commands["send-funds"] = sendFundsCmd
-	commands["request-funds"] = requestFundsCmd
+func InitCommands(root *cobra.Command) {
+	InitFlags(root)
+	root.AddCommand(
+		&cobra.Command{
+			Use:   "init",
+			Short: "Create the keystore with a new seed in the default account",
+			Args:  cobra.NoArgs,
+			Run:   initCmd,
+		},
+		&cobra.Command{
+			Use:   "address",
+			Short: "Show the wallet address",
+			Args:  cobra.NoArgs,
+			Run:   addressCmd,
+		},
+		&cobra.Command{
+			Use:   "balance",
+			Short: "Show the balance of the wallet address",
+			Args:  cobra.NoArgs,
+			Run:   balanceCmd,
+		},
+		&cobra.Command{
+			Use:   "mint <amount>",
+			Short: "Mint colored tokens",
+			Args:  cobra.ExactArgs(1),
+			Run:   mintCmd,
+		},
+		sendFundsCommand(),
+		&cobra.Command{
+			Use:   "request-funds",
+			Short: "Request funds from the faucet",
+			Args:  cobra.NoArgs,
+			Run:   requestFundsCmd,
+		},
+		&cobra.Command{
+			Use:   "sign <file>",
+			Short: "Sign a transaction built with --unsigned",
+			Args:  cobra.ExactArgs(1),
+			Run:   signCmd,
+		},
+		&cobra.Command{
+			Use:   "post-tx <file>",
+			Short: "Post a transaction signed with `sign`",
+			Args:  cobra.ExactArgs(1),
+			Run:   postTxCmd,
+		},
+		accountCommand(),
+	)
+}
 
-	fs := pflag.NewFlagSet("wallet", pflag.ExitOnError)
-	fs.IntVarP(&addressIndex, "address-index", "i", 0, "address index")
-	flags.AddFlagSet(fs)
+// InitFlags adds the flags needed by the commands using the wallet
+func InitFlags(root *cobra.Command) {
+	root.PersistentFlags().StringVarP(&accountName, "account", "", "", "wallet account (default: the default account of the keystore)")
+	root.PersistentFlags().StringVarP(&keystorePath, "keystore", "", "wasp-wallet.json", "path to the wallet keystore")
 }
@@ -3,28 +3,71 @@ package wallet
 import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/wasp/packages/txutil"
 	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
 )
 
-func addressCmd(args []string) {
+type addressResult struct {
+	Account   string `json:"account"`
+	Address   string `json:"address"`
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+func addressCmd(cmd *cobra.Command, args []string) {
 	wallet := Load()
-	kp := wallet.KeyPair()
-	log.Printf("Address index %d\n", addressIndex)
-	log.Verbose("  Private key: %s\n", kp.PrivateKey)
-	log.Verbose("  Public key:  %s\n", kp.PublicKey)
-	log.Printf("  Address:     %s\n", wallet.Address())
+	res := addressResult{
+		Account: wallet.Name(),
+		Address: wallet.Address().String(),
+	}
+	// the keys are decrypted only if requested
+	var kp *ed25519.KeyPair
+	if log.VerboseFlag && !wallet.WatchOnly() {
+		kp = wallet.KeyPair()
+		res.PublicKey = kp.PublicKey.String()
+	}
+	log.PrintResult(res, func() {
+		log.Printf("Account %s\n", wallet.Name())
+		if kp != nil {
+			log.Printf("  Private key: %s\n", kp.PrivateKey)
+			log.Printf("  Public key:  %s\n", kp.PublicKey)
+		}
+		log.Printf("  Address:     %s\n", wallet.Address())
+	})
 }
 
-func balanceCmd(args []string) {
+type balanceResult struct {
+	Account  string           `json:"account"`
+	Address  string           `json:"address"`
+	Balances map[string]int64 `json:"balances"`
+	Total    int64            `json:"total"`
+}
+
+func balanceCmd(cmd *cobra.Command, args []string) {
 	wallet := Load()
 	address := wallet.Address()
 
 	outs, err := config.GoshimmerClient().GetConfirmedAccountOutputs(&address)
 	log.Check(err)
 
-	log.Printf("Address index %d\n", addressIndex)
+	if log.JSONOutput() {
+		byColor, total := txutil.OutputBalancesByColor(outs)
+		res := balanceResult{
+			Account:  wallet.Name(),
+			Address:  address.String(),
+			Balances: make(map[string]int64),
+			Total:    total,
+		}
+		for color, value := range byColor {
+			res.Balances[color.String()] = value
+		}
+		log.PrintJSON(res)
+		return
+	}
+
+	log.Printf("Account %s\n", wallet.Name())
 	log.Printf("  Address: %s\n", address)
 	log.Printf("  Balance:\n")
 	var total int64
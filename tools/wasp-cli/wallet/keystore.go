@@ -0,0 +1,235 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/iotaledger/goshimmer/client/wallet/packages/seed"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keystoreVersion = 1
+
+	secretKindSeed = "seed"
+	secretKindKey  = "key"
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// keystore is the content of the keystore file. The addresses of the accounts are
+// in plain text, the seeds and private keys are encrypted with the passphrase
+type keystore struct {
+	Version  int                 `json:"version"`
+	Default  string              `json:"default"`
+	Accounts map[string]*account `json:"accounts"`
+}
+
+// account is either a seed and an index, an imported key pair, or a watch-only address
+// (without secret), which can only build unsigned transactions
+type account struct {
+	Address string           `json:"address"`
+	Index   uint64           `json:"index,omitempty"`
+	Secret  *encryptedSecret `json:"secret,omitempty"`
+}
+
+type encryptedSecret struct {
+	Kind       string `json:"kind"`
+	Salt       []byte `json:"salt"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+var errWrongPassphrase = errors.New("wrong passphrase")
+
+func newKeystore() *keystore {
+	return &keystore{
+		Version:  keystoreVersion,
+		Accounts: make(map[string]*account),
+	}
+}
+
+func readKeystore(fname string) (*keystore, error) {
+	data, err := ioutil.ReadFile(fname)
+	if os.IsNotExist(err) {
+		return newKeystore(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ks := &keystore{}
+	if err := json.Unmarshal(data, ks); err != nil {
+		return nil, fmt.Errorf("%s: %v", fname, err)
+	}
+	if ks.Version != keystoreVersion {
+		return nil, fmt.Errorf("%s: unsupported keystore version %d", fname, ks.Version)
+	}
+	if ks.Accounts == nil {
+		ks.Accounts = make(map[string]*account)
+	}
+	return ks, nil
+}
+
+func (ks *keystore) write(fname string) error {
+	data, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fname, data, 0600)
+}
+
+func (ks *keystore) names() []string {
+	ret := make([]string, 0, len(ks.Accounts))
+	for name := range ks.Accounts {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// checkPassphrase verifies the passphrase against one of the encrypted accounts, so that
+// all the accounts in the keystore are encrypted with the same passphrase
+func (ks *keystore) checkPassphrase(passphrase []byte) error {
+	for _, acc := range ks.Accounts {
+		if acc.Secret != nil {
+			_, err := acc.Secret.decrypt(passphrase)
+			return err
+		}
+	}
+	return nil
+}
+
+func newSeedAccount(s *seed.Seed, index uint64, passphrase []byte) (*account, error) {
+	secret, err := encryptSecret(secretKindSeed, s.Bytes(), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &account{
+		Address: s.Address(index).Address.String(),
+		Index:   index,
+		Secret:  secret,
+	}, nil
+}
+
+func newKeyAccount(privateKey ed25519.PrivateKey, passphrase []byte) (*account, error) {
+	secret, err := encryptSecret(secretKindKey, privateKey.Bytes(), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &account{
+		Address: address.FromED25519PubKey(privateKey.Public()).String(),
+		Secret:  secret,
+	}, nil
+}
+
+func newWatchOnlyAccount(addr address.Address) *account {
+	return &account{Address: addr.String()}
+}
+
+func (acc *account) kind() string {
+	if acc.Secret == nil {
+		return "watch-only"
+	}
+	return acc.Secret.Kind
+}
+
+func (acc *account) address() (address.Address, error) {
+	return address.FromBase58(acc.Address)
+}
+
+// keyPair decrypts the secret of the account
+func (acc *account) keyPair(passphrase []byte) (*ed25519.KeyPair, error) {
+	if acc.Secret == nil {
+		return nil, errors.New("watch-only account has no keys")
+	}
+	data, err := acc.Secret.decrypt(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	switch acc.Secret.Kind {
+	case secretKindSeed:
+		return seed.NewSeed(data).KeyPair(acc.Index), nil
+	case secretKindKey:
+		privateKey, err, _ := ed25519.PrivateKeyFromBytes(data)
+		if err != nil {
+			return nil, err
+		}
+		return &ed25519.KeyPair{PrivateKey: privateKey, PublicKey: privateKey.Public()}, nil
+	}
+	return nil, fmt.Errorf("unknown secret kind %q", acc.Secret.Kind)
+}
+
+// reencrypt changes the passphrase of the account
+func (acc *account) reencrypt(oldPassphrase, newPassphrase []byte) error {
+	if acc.Secret == nil {
+		return nil
+	}
+	data, err := acc.Secret.decrypt(oldPassphrase)
+	if err != nil {
+		return err
+	}
+	acc.Secret, err = encryptSecret(acc.Secret.Kind, data, newPassphrase)
+	return err
+}
+
+func encryptSecret(kind string, data []byte, passphrase []byte) (*encryptedSecret, error) {
+	ret := &encryptedSecret{
+		Kind:  kind,
+		Salt:  make([]byte, 32),
+		N:     scryptN,
+		R:     scryptR,
+		P:     scryptP,
+		Nonce: make([]byte, 24),
+	}
+	if _, err := rand.Read(ret.Salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(ret.Nonce); err != nil {
+		return nil, err
+	}
+	key, err := ret.key(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [24]byte
+	copy(nonce[:], ret.Nonce)
+	ret.Ciphertext = secretbox.Seal(nil, data, &nonce, key)
+	return ret, nil
+}
+
+func (s *encryptedSecret) key(passphrase []byte) (*[32]byte, error) {
+	k, err := scrypt.Key(passphrase, s.Salt, s.N, s.R, s.P, 32)
+	if err != nil {
+		return nil, err
+	}
+	var key [32]byte
+	copy(key[:], k)
+	return &key, nil
+}
+
+func (s *encryptedSecret) decrypt(passphrase []byte) ([]byte, error) {
+	key, err := s.key(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [24]byte
+	copy(nonce[:], s.Nonce)
+	data, ok := secretbox.Open(nil, s.Ciphertext, &nonce, key)
+	if !ok {
+		return nil, errWrongPassphrase
+	}
+	return data, nil
+}
@@ -1,7 +1,6 @@
 package wallet
 
 import (
-	"os"
 	"strconv"
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
@@ -9,13 +8,15 @@ import (
 	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
 )
 
-func mintCmd(args []string) {
-	if len(args) < 1 {
-		log.Usage("%s mint <amount>\n", os.Args[0])
-	}
+type mintResult struct {
+	Color  string `json:"color"`
+	Amount int    `json:"amount"`
+}
 
+func mintCmd(cmd *cobra.Command, args []string) {
 	wallet := Load()
 
 	amount, err := strconv.Atoi(args[0])
@@ -25,5 +26,7 @@ func mintCmd(args []string) {
 		return vtxbuilder.NewColoredTokensTransaction(config.GoshimmerClient(), wallet.SignatureScheme(), int64(amount))
 	})
 
-	log.Printf("Minted %d tokens of color %s\n", amount, tx.ID())
+	log.PrintResult(mintResult{Color: tx.ID().String(), Amount: amount}, func() {
+		log.Printf("Minted %d tokens of color %s\n", amount, tx.ID())
+	})
 }
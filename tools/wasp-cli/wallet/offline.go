@@ -0,0 +1,112 @@
+package wallet
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	clientutil "github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/mr-tron/base58"
+	"github.com/spf13/cobra"
+)
+
+// offlineTx is the content of the file written by the commands called with --unsigned.
+// The transaction is signed with `sign` by the owner of the signer address, possibly
+// on another machine, and posted with `post-tx`
+type offlineTx struct {
+	Signer string `json:"signer"`
+	Signed bool   `json:"signed"`
+	Tx     string `json:"tx"`
+}
+
+var unsignedFile string
+
+// InitUnsignedFlag adds the --unsigned flag to a command posting a transaction
+func InitUnsignedFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&unsignedFile, "unsigned", "", "", "write the transaction to the file without signing nor posting it")
+}
+
+// UnsignedFile is the value of the --unsigned flag
+func UnsignedFile() string {
+	return unsignedFile
+}
+
+// SaveUnsignedTx writes the unsigned transaction to the file given with --unsigned
+func SaveUnsignedTx(tx *valuetransaction.Transaction, signer address.Address) {
+	writeOfflineTx(unsignedFile, &offlineTx{
+		Signer: signer.String(),
+		Tx:     base58.Encode(tx.Bytes()),
+	})
+	log.Printf("Unsigned transaction written to %s. Sign it with `sign %s`\n", unsignedFile, unsignedFile)
+}
+
+func readOfflineTx(fname string) (*offlineTx, *valuetransaction.Transaction) {
+	data, err := ioutil.ReadFile(fname)
+	log.Check(err)
+	otx := &offlineTx{}
+	log.Check(json.Unmarshal(data, otx))
+	txBytes, err := base58.Decode(otx.Tx)
+	log.Check(err)
+	tx, _, err := valuetransaction.FromBytes(txBytes)
+	log.Check(err)
+	return otx, tx
+}
+
+func writeOfflineTx(fname string, otx *offlineTx) {
+	data, err := json.MarshalIndent(otx, "", "  ")
+	log.Check(err)
+	log.Check(ioutil.WriteFile(fname, data, 0644))
+}
+
+func signCmd(cmd *cobra.Command, args []string) {
+	otx, tx := readOfflineTx(args[0])
+	if otx.Signed {
+		log.Fatal("%s is already signed", args[0])
+	}
+	payload := tx.GetDataPayload()
+	wallet := Load()
+	if wallet.Address().String() != otx.Signer {
+		log.Fatal("the transaction must be signed by %s, the address of account %q is %s",
+			otx.Signer, wallet.Name(), wallet.Address())
+	}
+	// the parsed transaction caches the bytes of its (empty) signatures, so it is rebuilt before signing
+	tx = valuetransaction.New(tx.Inputs(), tx.Outputs())
+	log.Check(tx.SetDataPayload(payload))
+	tx.Sign(wallet.SignatureScheme())
+	if !tx.SignaturesValid() {
+		log.Fatal("invalid signature")
+	}
+	otx.Signed = true
+	otx.Tx = base58.Encode(tx.Bytes())
+	writeOfflineTx(args[0], otx)
+
+	log.PrintResult(clientutil.TxResult{TxID: tx.ID().String()}, func() {
+		log.Printf("Signed transaction %s. Post it with `post-tx %s`\n", tx.ID(), args[0])
+	})
+}
+
+func postTxCmd(cmd *cobra.Command, args []string) {
+	otx, tx := readOfflineTx(args[0])
+	if !otx.Signed {
+		log.Fatal("%s is not signed: call `sign %s` first", args[0], args[0])
+	}
+	res := clientutil.TxResult{TxID: tx.ID().String()}
+	if len(tx.GetDataPayload()) == 0 {
+		clientutil.PostTransaction(tx)
+		log.PrintResult(res, func() {
+			log.Printf("Posted transaction %s\n", tx.ID())
+		})
+		return
+	}
+	// request transaction: wait for the requests to be processed
+	sctx, err := sctransaction.ParseValueTransaction(tx)
+	log.Check(err)
+	clientutil.WithSCTransaction(func() (*sctransaction.Transaction, error) {
+		return sctx, config.GoshimmerClient().PostTransaction(tx)
+	})
+	log.PrintResult(res, func() {})
+}
@@ -0,0 +1,59 @@
+package wallet
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// PassphraseEnvVar is the environment variable holding the keystore passphrase, for
+// non-interactive use. Otherwise the passphrase is read from the terminal
+const PassphraseEnvVar = "WASP_WALLET_PASSPHRASE"
+
+var passphrase []byte
+
+func getPassphrase() []byte {
+	if passphrase == nil {
+		passphrase = readPassphrase("Keystore passphrase: ")
+	}
+	return passphrase
+}
+
+// newPassphrase asks the passphrase twice when creating the keystore
+func newPassphrase() []byte {
+	if _, ok := os.LookupEnv(PassphraseEnvVar); ok {
+		return getPassphrase()
+	}
+	passphrase = promptNewPassphrase()
+	return passphrase
+}
+
+func promptNewPassphrase() []byte {
+	p := promptPassphrase("New keystore passphrase: ")
+	if !bytes.Equal(p, promptPassphrase("Repeat the passphrase: ")) {
+		log.Fatal("the passphrases do not match")
+	}
+	return p
+}
+
+func readPassphrase(prompt string) []byte {
+	if p, ok := os.LookupEnv(PassphraseEnvVar); ok {
+		return []byte(p)
+	}
+	return promptPassphrase(prompt)
+}
+
+func promptPassphrase(prompt string) []byte {
+	fd := int(os.Stdin.Fd())
+	if !terminal.IsTerminal(fd) {
+		log.Fatal("cannot read the keystore passphrase: set %s", PassphraseEnvVar)
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	p, err := terminal.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	log.Check(err)
+	return p
+}
@@ -3,9 +3,10 @@ package wallet
 import (
 	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
 )
 
-func requestFundsCmd(args []string) {
+func requestFundsCmd(cmd *cobra.Command, args []string) {
 	address := Load().Address()
 	// automatically waits for confirmation:
 	log.Check(config.GoshimmerClient().RequestFunds(&address))
@@ -1,7 +1,6 @@
 package wallet
 
 import (
-	"os"
 	"strconv"
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
@@ -11,13 +10,21 @@ import (
 	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	clientutil "github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
 )
 
-func sendFundsCmd(args []string) {
-	if len(args) < 3 {
-		log.Usage("%s send-funds <target-address> <color> <amount>\n", os.Args[0])
+func sendFundsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "send-funds <target-address> <color> <amount>",
+		Short: "Transfer tokens to an address",
+		Args:  cobra.ExactArgs(3),
+		Run:   sendFundsCmd,
 	}
+	InitUnsignedFlag(cmd)
+	return cmd
+}
 
+func sendFundsCmd(cmd *cobra.Command, args []string) {
 	wallet := Load()
 	sourceAddress := wallet.Address()
 
@@ -38,9 +45,16 @@ func sendFundsCmd(args []string) {
 	log.Check(vtxb.MoveTokensToAddress(targetAddress, *color, int64(amount)))
 
 	tx := vtxb.Build(false)
+	if UnsignedFile() != "" {
+		SaveUnsignedTx(tx, sourceAddress)
+		return
+	}
 	tx.Sign(wallet.SignatureScheme())
 
 	clientutil.PostTransaction(tx)
+	log.PrintResult(clientutil.TxResult{TxID: tx.ID().String()}, func() {
+		log.Printf("Posted transaction %s\n", tx.ID())
+	})
 }
 
 func decodeColor(s string) *balance.Color {
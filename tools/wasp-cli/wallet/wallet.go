@@ -8,46 +8,143 @@ import (
 	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/mr-tron/base58"
+	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-type WalletConfig struct {
-	Seed []byte
-}
+const defaultAccountName = "default"
+
+// legacySeedConfigVar is where the seed was stored in plain text before the keystore
+const legacySeedConfigVar = "wallet.seed"
+
+var accountName string
+var keystorePath string
 
+// Wallet is the account of the keystore selected with --account. The keys are
+// decrypted only when they are needed to sign
 type Wallet struct {
-	seed *seed.Seed
+	name    string
+	account *account
+	keyPair *ed25519.KeyPair
 }
 
-func initCmd(args []string) {
-	seed := base58.Encode(seed.NewSeed().Bytes())
-	viper.Set("wallet.seed", seed)
-	log.Check(viper.WriteConfig())
+func initCmd(cmd *cobra.Command, args []string) {
+	ks := loadKeystore()
+	if _, ok := ks.Accounts[defaultAccountName]; ok {
+		log.Fatal("wallet already initialized in %s", keystorePath)
+	}
+
+	s := seed.NewSeed()
+	if legacy := viper.GetString(legacySeedConfigVar); legacy != "" {
+		seedBytes, err := base58.Decode(legacy)
+		log.Check(err)
+		s = seed.NewSeed(seedBytes)
+		log.Printf("Importing the wallet seed from %s\n", config.ConfigPath)
+	}
+
+	acc, err := newSeedAccount(s, 0, keystorePassphrase(ks))
+	log.Check(err)
+	ks.Accounts[defaultAccountName] = acc
+	if ks.Default == "" {
+		ks.Default = defaultAccountName
+	}
+	saveKeystore(ks)
+	if viper.GetString(legacySeedConfigVar) != "" {
+		config.Set(legacySeedConfigVar, "")
+	}
 
-	log.Printf("Initialized wallet seed in %s\n", config.ConfigPath)
-	log.Verbose("Seed: %s\n", seed)
+	log.Printf("Initialized wallet in %s, account %q\n", keystorePath, defaultAccountName)
+	log.Verbose("Seed: %s\n", base58.Encode(s.Bytes()))
 }
 
 func Load() *Wallet {
-	seedb58 := viper.GetString("wallet.seed")
-	if len(seedb58) == 0 {
+	ks := loadKeystore()
+	name := accountName
+	if name == "" {
+		name = ks.Default
+	}
+	if name == "" {
+		if viper.GetString(legacySeedConfigVar) != "" {
+			log.Fatal("the wallet seed in %s must be moved to the encrypted keystore: call `init`", config.ConfigPath)
+		}
 		log.Fatal("call `init` first")
 	}
-	seedBytes, err := base58.Decode(seedb58)
-	log.Check(err)
-	return &Wallet{seed.NewSeed(seedBytes)}
+	acc, ok := ks.Accounts[name]
+	if !ok {
+		log.Fatal("account %q not found in %s", name, keystorePath)
+	}
+	return &Wallet{name: name, account: acc}
 }
 
-var addressIndex int
+func (w *Wallet) Name() string {
+	return w.name
+}
+
+// WatchOnly returns true if the account has no keys, i.e. it can only build unsigned transactions
+func (w *Wallet) WatchOnly() bool {
+	return w.account.Secret == nil
+}
 
 func (w *Wallet) KeyPair() *ed25519.KeyPair {
-	return w.seed.KeyPair(uint64(addressIndex))
+	if w.keyPair != nil {
+		return w.keyPair
+	}
+	if w.WatchOnly() {
+		log.Fatal("account %q is watch-only: build the transaction with --unsigned and sign it offline", w.name)
+	}
+	kp, err := w.account.keyPair(getPassphrase())
+	log.Check(err)
+	w.keyPair = kp
+	return kp
 }
 
 func (w *Wallet) Address() address.Address {
-	return w.seed.Address(uint64(addressIndex)).Address
+	addr, err := w.account.address()
+	log.Check(err)
+	return addr
 }
 
+// SignatureScheme returns the signature scheme of the account. The address is known
+// without the passphrase, which is asked only when signing
 func (w *Wallet) SignatureScheme() signaturescheme.SignatureScheme {
-	return signaturescheme.ED25519(*w.KeyPair())
+	return &walletSigScheme{w}
+}
+
+type walletSigScheme struct {
+	w *Wallet
+}
+
+func (s *walletSigScheme) Version() byte {
+	return address.VersionED25519
+}
+
+func (s *walletSigScheme) Address() address.Address {
+	return s.w.Address()
+}
+
+func (s *walletSigScheme) Sign(data []byte) signaturescheme.Signature {
+	return signaturescheme.ED25519(*s.w.KeyPair()).Sign(data)
+}
+
+func loadKeystore() *keystore {
+	ks, err := readKeystore(keystorePath)
+	log.Check(err)
+	return ks
+}
+
+func saveKeystore(ks *keystore) {
+	log.Check(ks.write(keystorePath))
+}
+
+// keystorePassphrase returns the passphrase to encrypt a new account with. It must be the same
+// as the one of the accounts already in the keystore
+func keystorePassphrase(ks *keystore) []byte {
+	for _, acc := range ks.Accounts {
+		if acc.Secret != nil {
+			passphrase := getPassphrase()
+			log.Check(ks.checkPassphrase(passphrase))
+			return passphrase
+		}
+	}
+	return newPassphrase()
 }